@@ -0,0 +1,181 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+// Package changelog derives Keep a Changelog entries from commit messages
+// and inserts them into CHANGELOG.md files.
+package changelog
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/yourorg/arc-commit/internal/message"
+)
+
+// UnreleasedHeading is the section new entries are added under.
+const UnreleasedHeading = "## [Unreleased]"
+
+// Entry is a single changelog bullet.
+type Entry struct {
+	// Section is the Keep a Changelog category, e.g. "Added" or "Fixed".
+	Section string
+	// Text is the bullet text without the leading "- ".
+	Text string
+	// Breaking marks a breaking change. Its Text carries a bold
+	// "Breaking:" label.
+	Breaking bool
+}
+
+// String renders the entry as a markdown bullet.
+func (e Entry) String() string {
+	return "- " + e.Text
+}
+
+// sections maps conventional commit types to Keep a Changelog categories.
+// Types without user-facing changes are absent.
+var sections = map[string]string{
+	"feat":     "Added",
+	"fix":      "Fixed",
+	"perf":     "Changed",
+	"refactor": "Changed",
+	"revert":   "Changed",
+}
+
+// EntryFor derives a changelog entry from a commit message's header. It
+// returns false for changes that do not belong in a changelog, such as
+// tests or chores.
+func EntryFor(msg string) (Entry, bool) {
+	m := message.Parse(msg)
+	section, ok := sections[m.Type]
+	if m.Breaking {
+		section, ok = "Changed", true
+	}
+	if !ok || m.Subject == "" {
+		return Entry{}, false
+	}
+
+	text := capitalize(m.Subject)
+	if m.Scope != "" {
+		text = "**" + m.Scope + "**: " + text
+	}
+	if m.Breaking {
+		text = breakingLabel + text
+	}
+	return Entry{Section: section, Text: text, Breaking: m.Breaking}, true
+}
+
+// breakingLabel starts the text of breaking entries.
+const breakingLabel = "**Breaking:** "
+
+// capitalize upper-cases the first letter, as changelog bullets are
+// sentence case.
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+// Insert adds the entry to a changelog file under the Unreleased section,
+// creating the file, section, or category heading as needed.
+func Insert(path string, entry Entry) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	content := InsertInto(string(data), entry)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// InsertInto returns the changelog content with the entry added under the
+// Unreleased section.
+func InsertInto(content string, entry Entry) string {
+	if strings.TrimSpace(content) == "" {
+		content = "# Changelog\n"
+	}
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+
+	isUnreleased := func(l string) bool {
+		return strings.HasPrefix(strings.ToLower(l), strings.ToLower(UnreleasedHeading))
+	}
+	unreleased := indexOf(lines, 0, len(lines), isUnreleased)
+	if unreleased < 0 {
+		// Place the section before the first release, or after the title
+		at := indexOf(lines, 0, len(lines), func(l string) bool { return strings.HasPrefix(l, "## ") })
+		if at < 0 {
+			at = len(lines)
+		}
+		lines = splice(lines, at, "", UnreleasedHeading, "")
+		unreleased = indexOf(lines, at, len(lines), isUnreleased)
+	}
+
+	end := indexOf(lines, unreleased+1, len(lines), func(l string) bool { return strings.HasPrefix(l, "## ") })
+	if end < 0 {
+		end = len(lines)
+	}
+
+	heading := "### " + entry.Section
+	category := indexOf(lines, unreleased+1, end, func(l string) bool { return strings.TrimSpace(l) == heading })
+	if category < 0 {
+		at := end
+		for at > unreleased+1 && strings.TrimSpace(lines[at-1]) == "" {
+			at--
+		}
+		lines = splice(lines, at, "", heading, entry.String())
+		return finish(lines)
+	}
+
+	// Append after the category's existing bullets
+	at := category + 1
+	for at < end && strings.TrimSpace(lines[at]) == "" {
+		at++
+	}
+	if at == end || strings.HasPrefix(lines[at], "#") {
+		at = category + 1
+	}
+	for at < end && strings.TrimSpace(lines[at]) != "" && !strings.HasPrefix(lines[at], "#") {
+		at++
+	}
+	lines = splice(lines, at, entry.String())
+	return finish(lines)
+}
+
+// indexOf returns the first index in [from, to) whose line matches, or -1.
+func indexOf(lines []string, from, to int, match func(string) bool) int {
+	for i := from; i < to; i++ {
+		if match(lines[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// splice inserts lines at index i. A blank line at either end of insert
+// is dropped when the line next to it is already blank, so the inserted
+// text is set off by single blank lines; the rest of the file is left as
+// the author formatted it.
+func splice(lines []string, i int, insert ...string) []string {
+	if len(insert) > 0 && insert[0] == "" && i > 0 && strings.TrimSpace(lines[i-1]) == "" {
+		insert = insert[1:]
+	}
+	if len(insert) > 0 && insert[len(insert)-1] == "" && i < len(lines) && strings.TrimSpace(lines[i]) == "" {
+		insert = insert[:len(insert)-1]
+	}
+	out := make([]string, 0, len(lines)+len(insert))
+	out = append(out, lines[:i]...)
+	out = append(out, insert...)
+	return append(out, lines[i:]...)
+}
+
+// finish joins lines into the file's content, ending in a single newline.
+func finish(lines []string) string {
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n") + "\n"
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package changelog
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestEntryFor(t *testing.T) {
+	tests := []struct {
+		msg  string
+		want Entry
+		ok   bool
+	}{
+		{"feat: add dark mode", Entry{Section: "Added", Text: "Add dark mode"}, true},
+		{"fix(api): handle nil", Entry{Section: "Fixed", Text: "**api**: Handle nil"}, true},
+		{"feat!: drop v1", Entry{Section: "Changed", Text: "**Breaking:** Drop v1", Breaking: true}, true},
+		{"fix: éviter le double envoi", Entry{Section: "Fixed", Text: "Éviter le double envoi"}, true},
+		{"chore: bump deps", Entry{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.msg, func(t *testing.T) {
+			got, ok := EntryFor(tt.msg)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("EntryFor(%q) = %+v, %v, want %+v, %v", tt.msg, got, ok, tt.want, tt.ok)
+			}
+			if !utf8.ValidString(got.Text) {
+				t.Errorf("EntryFor(%q) text %q is not valid UTF-8", tt.msg, got.Text)
+			}
+		})
+	}
+}
+
+func TestParseEntriesCapitalizesNonASCII(t *testing.T) {
+	got := ParseEntries("Fixed: ørsted units\nBreaking: ändere die API")
+	want := []Entry{
+		{Section: "Fixed", Text: "Ørsted units"},
+		{Section: "Changed", Text: "**Breaking:** Ändere die API", Breaking: true},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ParseEntries = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestInsertInto(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		entry   Entry
+		want    string
+	}{
+		{
+			name:  "new file",
+			entry: Entry{Section: "Added", Text: "Add x"},
+			want:  "# Changelog\n\n## [Unreleased]\n\n### Added\n- Add x\n",
+		},
+		{
+			name:    "new unreleased section",
+			content: "# Changelog\n\n## [1.0.0] - 2025-01-01\n\n### Added\n- First\n",
+			entry:   Entry{Section: "Fixed", Text: "Fix y"},
+			want:    "# Changelog\n\n## [Unreleased]\n\n### Fixed\n- Fix y\n\n## [1.0.0] - 2025-01-01\n\n### Added\n- First\n",
+		},
+		{
+			name:    "existing category",
+			content: "# Changelog\n\n## [Unreleased]\n\n### Added\n- Add x\n\n### Fixed\n- Fix y\n",
+			entry:   Entry{Section: "Added", Text: "Add z"},
+			want:    "# Changelog\n\n## [Unreleased]\n\n### Added\n- Add x\n- Add z\n\n### Fixed\n- Fix y\n",
+		},
+		{
+			name: "double blank lines elsewhere are kept",
+			content: "# Changelog\n\nAll notable changes.\n\n\n## [Unreleased]\n\n### Added\n- Add x\n\n" +
+				"## [1.0.0]\n\n### Fixed\n- Old fix\n\n\n- Spaced out by hand\n",
+			entry: Entry{Section: "Fixed", Text: "Fix y"},
+			want: "# Changelog\n\nAll notable changes.\n\n\n## [Unreleased]\n\n### Added\n- Add x\n\n### Fixed\n- Fix y\n\n" +
+				"## [1.0.0]\n\n### Fixed\n- Old fix\n\n\n- Spaced out by hand\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InsertInto(tt.content, tt.entry); got != tt.want {
+				t.Errorf("InsertInto() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrependKeepsBlankLinesElsewhere(t *testing.T) {
+	content := "# Changelog\n\n\nIntro.\n\n## [Unreleased]\n\n### Added\n- Add x\n\n## [1.0.0]\n\n\n### Fixed\n- Old fix\n"
+	release := NewRelease("1.1.0", "2025-02-01", []Entry{{Section: "Fixed", Text: "Fix y"}}).String()
+	want := "# Changelog\n\n\nIntro.\n\n## [Unreleased]\n\n### Added\n- Add x\n\n" +
+		"## [1.1.0] - 2025-02-01\n\n### Fixed\n- Fix y\n\n" +
+		"## [1.0.0]\n\n\n### Fixed\n- Old fix\n"
+	if got := Prepend(content, release); got != want {
+		t.Errorf("Prepend() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package changelog
+
+import (
+	"slices"
+	"strings"
+)
+
+// Categories lists the Keep a Changelog categories in the order they are
+// rendered.
+var Categories = []string{"Added", "Changed", "Deprecated", "Removed", "Fixed", "Security"}
+
+// Release is the changelog section of one version.
+type Release struct {
+	// Version names the release, e.g. "1.3.0" or "Unreleased".
+	Version string
+	// Date is the release date as YYYY-MM-DD; empty for unreleased changes.
+	Date string
+	// Sections holds the entries grouped by category, in Categories order.
+	Sections []Section
+}
+
+// Section is one category of a release and its entries.
+type Section struct {
+	Name    string
+	Entries []Entry
+}
+
+// NewRelease groups entries into their categories. Categories without
+// entries are left out.
+func NewRelease(version, date string, entries []Entry) Release {
+	r := Release{Version: version, Date: date}
+	for _, name := range Categories {
+		var in []Entry
+		for _, e := range entries {
+			if e.Section == name {
+				in = append(in, e)
+			}
+		}
+		if len(in) > 0 {
+			r.Sections = append(r.Sections, Section{Name: name, Entries: in})
+		}
+	}
+	return r
+}
+
+// Breaking returns the breaking entries of every category.
+func (r Release) Breaking() []Entry {
+	var breaking []Entry
+	for _, s := range r.Sections {
+		for _, e := range s.Entries {
+			if e.Breaking {
+				breaking = append(breaking, e)
+			}
+		}
+	}
+	return breaking
+}
+
+// Heading returns the release's "## [version] - date" heading.
+func (r Release) Heading() string {
+	heading := "## [" + r.Version + "]"
+	if r.Date != "" {
+		heading += " - " + r.Date
+	}
+	return heading
+}
+
+// String renders the release in Keep a Changelog format.
+func (r Release) String() string {
+	lines := []string{r.Heading()}
+	for _, s := range r.Sections {
+		lines = append(lines, "", "### "+s.Name)
+		for _, e := range s.Entries {
+			lines = append(lines, e.String())
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// Prepend returns the changelog content with the rendered release text
+// added above the latest release. Unreleased changes stay on top. The
+// release text should start with its own "## " heading.
+func Prepend(content, release string) string {
+	if strings.TrimSpace(content) == "" {
+		content = "# Changelog\n"
+	}
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+
+	at := indexOf(lines, 0, len(lines), func(l string) bool {
+		return strings.HasPrefix(l, "## ") &&
+			!strings.HasPrefix(strings.ToLower(l), strings.ToLower(UnreleasedHeading))
+	})
+	if at < 0 {
+		at = len(lines)
+	}
+	insert := strings.Split(strings.TrimRight(release, "\n"), "\n")
+	insert = append(append([]string{""}, insert...), "")
+	return finish(splice(lines, at, insert...))
+}
+
+// ParseEntries reads entries written one per line as "Category: text",
+// e.g. "Fixed: Crash on empty input". "Breaking:" marks a breaking change,
+// filed under Changed. Other lines are ignored.
+func ParseEntries(text string) []Entry {
+	var entries []Entry
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "- "))
+		name, rest, ok := strings.Cut(line, ":")
+		rest = strings.TrimSpace(rest)
+		if !ok || rest == "" {
+			continue
+		}
+		switch name = strings.TrimSpace(name); {
+		case strings.EqualFold(name, "Breaking"):
+			entries = append(entries, Entry{Section: "Changed", Text: breakingLabel + capitalize(rest), Breaking: true})
+		case slices.Contains(Categories, name):
+			entries = append(entries, Entry{Section: name, Text: capitalize(rest)})
+		}
+	}
+	return entries
+}
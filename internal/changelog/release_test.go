@@ -0,0 +1,103 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package changelog
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestReleaseString(t *testing.T) {
+	entries := []Entry{
+		{Section: "Fixed", Text: "Handle nil"},
+		{Section: "Added", Text: "Add dark mode"},
+		{Section: "Changed", Text: "**Breaking:** Drop v1", Breaking: true},
+		{Section: "Added", Text: "Add CSV export"},
+	}
+	tests := []struct {
+		name    string
+		version string
+		date    string
+		entries []Entry
+		want    string
+	}{
+		{
+			name:    "categories in order",
+			version: "1.3.0",
+			date:    "2025-03-01",
+			entries: entries,
+			want: "## [1.3.0] - 2025-03-01\n\n### Added\n- Add dark mode\n- Add CSV export\n\n" +
+				"### Changed\n- **Breaking:** Drop v1\n\n### Fixed\n- Handle nil\n",
+		},
+		{
+			name:    "unreleased without date",
+			version: "Unreleased",
+			entries: entries[:1],
+			want:    "## [Unreleased]\n\n### Fixed\n- Handle nil\n",
+		},
+		{
+			name:    "no entries",
+			version: "1.3.0",
+			want:    "## [1.3.0]\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewRelease(tt.version, tt.date, tt.entries).String(); got != tt.want {
+				t.Errorf("String() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReleaseBreaking(t *testing.T) {
+	breaking := Entry{Section: "Changed", Text: "**Breaking:** Drop v1", Breaking: true}
+	r := NewRelease("2.0.0", "", []Entry{{Section: "Added", Text: "Add v2"}, breaking})
+	if got := r.Breaking(); !slices.Equal(got, []Entry{breaking}) {
+		t.Errorf("Breaking() = %+v, want %+v", got, []Entry{breaking})
+	}
+}
+
+func TestPrepend(t *testing.T) {
+	release := "## [1.1.0]\n\n### Fixed\n- Fix y\n"
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name: "empty file",
+			want: "# Changelog\n\n## [1.1.0]\n\n### Fixed\n- Fix y\n",
+		},
+		{
+			name:    "above the latest release",
+			content: "# Changelog\n\n## [1.0.0]\n\n### Added\n- Add x\n",
+			want:    "# Changelog\n\n## [1.1.0]\n\n### Fixed\n- Fix y\n\n## [1.0.0]\n\n### Added\n- Add x\n",
+		},
+		{
+			name:    "no release yet",
+			content: "# Changelog\n\nIntro.\n",
+			want:    "# Changelog\n\nIntro.\n\n## [1.1.0]\n\n### Fixed\n- Fix y\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Prepend(tt.content, release); got != tt.want {
+				t.Errorf("Prepend() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseEntries(t *testing.T) {
+	text := "Here are the entries:\n- Added: export reports as CSV\nFixed: Crash on empty config\nTested: more cases\nChanged:\nBreaking: drop the v1 API\n"
+	want := []Entry{
+		{Section: "Added", Text: "Export reports as CSV"},
+		{Section: "Fixed", Text: "Crash on empty config"},
+		{Section: "Changed", Text: "**Breaking:** Drop the v1 API", Breaking: true},
+	}
+	if got := ParseEntries(text); !slices.Equal(got, want) {
+		t.Errorf("ParseEntries() = %+v, want %+v", got, want)
+	}
+}
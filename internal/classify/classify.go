@@ -0,0 +1,137 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+// Package classify sorts changed files into broad kinds (source, test,
+// docs, config, build) so the commit type and scope can follow from what
+// a change touches.
+package classify
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/yourorg/arc-commit/internal/diff"
+)
+
+// Kind is the broad role of a file in the repository.
+type Kind string
+
+// Kinds, in the order rules are checked. A file matching no rule is Source.
+const (
+	Test   Kind = "test"
+	Docs   Kind = "docs"
+	Build  Kind = "build"
+	Config Kind = "config"
+	Source Kind = "source"
+)
+
+// Kinds lists every kind in the order rules are checked.
+var Kinds = []Kind{Test, Docs, Build, Config, Source}
+
+// Rules maps a kind to the path patterns that select it. A pattern without
+// a slash is matched against the file name ("*_test.go"), a pattern ending
+// in a slash matches files below a directory of that name at any depth
+// ("docs/"), and any other pattern is matched against the whole path.
+// Patterns use path.Match syntax.
+type Rules map[Kind][]string
+
+// DefaultRules are the built-in classification rules.
+var DefaultRules = Rules{
+	Test: {"*_test.go", "*.test.*", "*.spec.*", "test_*.py", "test/", "tests/", "testdata/", "__tests__/"},
+	Docs: {"*.md", "*.rst", "*.adoc", "docs/", "doc/", "LICENSE*"},
+	Build: {"Makefile", "*.mk", "Dockerfile", "go.mod", "go.sum", "package.json", "package-lock.json",
+		"Cargo.toml", "Cargo.lock", ".github/", ".gitlab-ci.yml"},
+	Config: {"*.yaml", "*.yml", "*.toml", "*.json", "*.ini", ".*rc", ".gitignore", ".editorconfig"},
+}
+
+// Classifier assigns kinds to paths.
+type Classifier struct {
+	rules []Rules
+}
+
+// New returns a Classifier that checks each set of extra rules in turn
+// before the default rules, so configured patterns take precedence. Unknown
+// kinds are rejected.
+func New(extra ...Rules) (*Classifier, error) {
+	for _, rules := range extra {
+		for kind := range rules {
+			if !slices.Contains(Kinds, kind) {
+				return nil, fmt.Errorf("unknown file kind %q", kind)
+			}
+		}
+	}
+	return &Classifier{rules: append(extra, DefaultRules)}, nil
+}
+
+// Classify returns the kind of the file at p.
+func (c *Classifier) Classify(p string) Kind {
+	for _, rules := range c.rules {
+		for _, kind := range Kinds {
+			for _, pattern := range rules[kind] {
+				if Match(pattern, p) {
+					return kind
+				}
+			}
+		}
+	}
+	return Source
+}
+
+// File is a changed file and its kind.
+type File struct {
+	Path string
+	Kind Kind
+}
+
+// Diff classifies every file in the diff, in diff order.
+func (c *Classifier) Diff(d *diff.Diff) []File {
+	files := make([]File, len(d.Files))
+	for i, f := range d.Files {
+		files[i] = File{Path: f.Path(), Kind: c.Classify(f.Path())}
+	}
+	return files
+}
+
+// Only reports whether every file has the given kind. It is false for an
+// empty list.
+func Only(files []File, kind Kind) bool {
+	for _, f := range files {
+		if f.Kind != kind {
+			return false
+		}
+	}
+	return len(files) > 0
+}
+
+// SuggestedType returns the conventional commit type implied by the files'
+// kinds, or an empty string when they do not point to one.
+func SuggestedType(files []File) string {
+	for _, kind := range []Kind{Test, Docs, Build} {
+		if Only(files, kind) {
+			return string(kind)
+		}
+	}
+	return ""
+}
+
+// Match reports whether pattern selects the path p. A pattern without a
+// slash matches file names, one ending in a slash (or "/**") matches
+// everything below a directory of that name, and anything else matches the
+// whole path.
+func Match(pattern, p string) bool {
+	if dir, ok := strings.CutSuffix(pattern, "/**"); ok {
+		pattern = dir + "/"
+	}
+	switch {
+	case strings.HasSuffix(pattern, "/"):
+		return strings.HasPrefix(p, pattern) || strings.Contains(p, "/"+pattern)
+	case !strings.Contains(pattern, "/"):
+		ok, _ := path.Match(pattern, path.Base(p))
+		return ok
+	default:
+		ok, _ := path.Match(pattern, p)
+		return ok
+	}
+}
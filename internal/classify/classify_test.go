@@ -0,0 +1,112 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package classify
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/yourorg/arc-commit/internal/diff"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*_test.go", "internal/app/app_test.go", true},
+		{"*_test.go", "internal/app/app.go", false},
+		{"docs/", "docs/guide.md", true},
+		{"docs/", "internal/docs/guide.md", true},
+		{"docs/", "mydocs/guide.md", false},
+		{"docs/**", "docs/guide.md", true},
+		{"dist/**", "web/dist/app.min.js", true},
+		{"dist/**", "distribution/app.js", false},
+		{"internal/*.go", "internal/app.go", true},
+		{"internal/*.go", "internal/app/app.go", false},
+		{"Makefile", "sub/Makefile", true},
+	}
+	for _, tt := range tests {
+		if got := Match(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("Match(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestClassify(t *testing.T) {
+	c, err := New(Rules{Test: {"*_spec.rb"}, Source: {"docs/api/"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path string
+		want Kind
+	}{
+		{"main.go", Source},
+		{"main_test.go", Test},
+		{"testdata/input.json", Test},
+		{"README.md", Docs},
+		{"go.mod", Build},
+		{".github/workflows/ci.yml", Build},
+		{"config.yaml", Config},
+		{".eslintrc", Config},
+		{"user_spec.rb", Test},
+		{"docs/api/index.md", Source},
+	}
+	for _, tt := range tests {
+		if got := c.Classify(tt.path); got != tt.want {
+			t.Errorf("Classify(%q) = %s, want %s", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestNewRejectsUnknownKind(t *testing.T) {
+	if _, err := New(Rules{"fixtures": {"fixtures/"}}); err == nil {
+		t.Error("New() accepted an unknown kind")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	d := diff.Parse(`diff --git a/app.go b/app.go
+--- a/app.go
++++ b/app.go
+@@ -1 +1 @@
+-package app
++package app // changed
+diff --git a/README.md b/README.md
+--- a/README.md
++++ b/README.md
+@@ -1 +1 @@
+-# App
++# The app
+`)
+	c, _ := New()
+	want := []File{{Path: "app.go", Kind: Source}, {Path: "README.md", Kind: Docs}}
+	if got := c.Diff(d); !slices.Equal(got, want) {
+		t.Errorf("Diff() = %v, want %v", got, want)
+	}
+}
+
+func TestSuggestedType(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []File
+		want  string
+	}{
+		{"no files", nil, ""},
+		{"only tests", []File{{"a_test.go", Test}, {"b_test.go", Test}}, "test"},
+		{"only docs", []File{{"README.md", Docs}}, "docs"},
+		{"only build", []File{{"go.mod", Build}, {"go.sum", Build}}, "build"},
+		{"only config", []File{{"config.yaml", Config}}, ""},
+		{"mixed", []File{{"a.go", Source}, {"a_test.go", Test}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SuggestedType(tt.files); got != tt.want {
+				t.Errorf("SuggestedType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
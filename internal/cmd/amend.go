@@ -0,0 +1,180 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-commit/internal/config"
+	"github.com/yourorg/arc-commit/internal/diff"
+	commitmsg "github.com/yourorg/arc-commit/internal/message"
+	"github.com/yourorg/arc-commit/internal/prompt"
+	"github.com/yourorg/arc-sdk/ai"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// emptyTree is git's well-known hash of the empty tree, the parent an
+// amended root commit is compared against.
+const emptyTree = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+
+// amendOptions holds the flag values of the amend subcommand.
+type amendOptions struct {
+	keepMessage bool
+	autoYes     bool
+	dryRun      bool
+}
+
+// newAmendCmd creates the amend subcommand.
+func newAmendCmd(aiCfg *ai.Config) *cobra.Command {
+	var (
+		opts      amendOptions
+		model     string
+		provider  providerOptions
+		redaction redactOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "amend",
+		Short: "Fold staged changes into HEAD and update its message",
+		Long: `Fold staged changes into the last commit and update its message.
+
+The message of HEAD and the diff of HEAD plus anything staged are sent to
+the AI, which rewrites the message to cover the whole change, keeping what
+still applies. After confirmation the commit is amended with git commit
+--amend. Nothing needs to be staged just to reword HEAD.
+
+With --no-edit-message the message is kept as it is and no AI is used.`,
+		Example: `  # Fold a fix-up into the last commit and refresh its message
+  git add -p && arc-commit amend
+
+  # Only fold the staged changes in, keeping the message
+  arc-commit amend --no-edit-message`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			out := cmd.OutOrStdout()
+
+			if opts.keepMessage {
+				return amendKeepingMessage(out, opts)
+			}
+
+			settings, err := resolveConfig(cmd)
+			if err != nil {
+				return err
+			}
+			if settings.blocksProvider(provider) {
+				return errors.NewCLIError("amend is disabled in sensitive repositories").
+					WithHint("Pass --no-edit-message to amend without AI, or use a local model, e.g. --provider ollama; sensitive-repo is set in " + config.FileName + " or the global config")
+			}
+			if err := validateProvider(provider); err != nil {
+				return err
+			}
+
+			cfg := *aiCfg
+			gen, _, err := newGenerator(&cfg, provider, model)
+			if err != nil {
+				return err
+			}
+			if gen, err = withRedaction(gen, settings, redaction, out); err != nil {
+				return err
+			}
+			return runAmend(bufio.NewReader(cmd.InOrStdin()), out, gen, opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.keepMessage, "no-edit-message", false, "Keep HEAD's message and only fold in the staged changes")
+	cmd.Flags().BoolVarP(&opts.autoYes, "yes", "y", false, "Amend without asking for confirmation")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Show the updated message without amending")
+	cmd.Flags().StringVarP(&model, "model", "m", "", "Model to use (default: "+prompt.CommitMessageModel+")")
+	cmd.RegisterFlagCompletionFunc("model", completeModels)
+	addProviderFlags(cmd.Flags(), &provider)
+	addRedactFlags(cmd.Flags(), &redaction)
+
+	return cmd
+}
+
+// amendKeepingMessage folds the staged changes into HEAD as they are.
+func amendKeepingMessage(out io.Writer, opts amendOptions) error {
+	if err := checkStagedChanges(); err != nil {
+		return errors.NewCLIError("no staged changes to fold into HEAD").
+			WithHint("Stage changes first: git add <files>")
+	}
+	if opts.dryRun {
+		fmt.Fprintln(out, "Dry run: the staged changes would be folded into HEAD, keeping its message.")
+		return nil
+	}
+	cmd := exec.Command("git", "commit", "--amend", "--no-edit")
+	cmd.Stdout, cmd.Stderr = out, out
+	if err := cmd.Run(); err != nil {
+		return errors.NewCLIError("git commit --amend failed").WithCause(err)
+	}
+	return nil
+}
+
+// runAmend regenerates HEAD's message for HEAD plus the staged changes with
+// gen and amends the commit once the message is accepted.
+func runAmend(reader *bufio.Reader, out io.Writer, gen Generator, opts amendOptions) error {
+	old, err := exec.Command("git", "log", "-1", "--format=%B").Output()
+	if err != nil {
+		return errors.NewCLIError("failed to read the HEAD commit").WithCause(err).
+			WithHint("There must be a commit to amend")
+	}
+	parent := "HEAD~1"
+	if exec.Command("git", "rev-parse", "--verify", "--quiet", parent).Run() != nil {
+		parent = emptyTree
+	}
+	rawDiff, err := getStagedDiff("--no-color", parent)
+	if err != nil {
+		return errors.NewCLIError("failed to get diff").WithCause(err)
+	}
+
+	fmt.Fprintln(out, "Updating the message with AI...")
+	message, err := generateCommitMessage(out, gen, diff.Parse(rawDiff), amendFeedback(strings.TrimSpace(string(old))), prompt.CommitOptions{}, GenerateRequest{})
+	if err != nil {
+		return errors.NewCLIError("failed to generate commit message").WithCause(err)
+	}
+	message = commitmsg.StripArtifacts(message)
+
+	fmt.Fprintf(out, "\nUpdated message:\n\n%s\n\n", message)
+	if opts.dryRun {
+		return nil
+	}
+	for !opts.autoYes {
+		fmt.Fprint(out, "Amend HEAD with this message? [y]es / [e]dit / [n]o: ")
+		answer, err := reader.ReadString('\n')
+		if err != nil {
+			return errors.NewCLIError("failed to read input").WithCause(err)
+		}
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "y", "yes":
+			opts.autoYes = true
+		case "e", "edit":
+			if message, err = editInEditor(message); err != nil {
+				return errors.NewCLIError("failed to edit message").WithCause(err)
+			}
+			fmt.Fprintf(out, "\nUpdated message:\n\n%s\n\n", message)
+		case "n", "no":
+			fmt.Fprintln(out, "Amend cancelled; HEAD is unchanged.")
+			return nil
+		}
+	}
+
+	if err := createCommit(out, message, []string{"--amend"}, nil); err != nil {
+		return errors.NewCLIError("failed to amend commit").WithCause(err)
+	}
+	return nil
+}
+
+// amendFeedback asks the model to update the message of the commit being
+// amended rather than start from scratch.
+func amendFeedback(old string) string {
+	return "This amends an existing commit whose message is:\n\n" + old +
+		"\n\nUpdate it to describe the whole diff: keep what still applies, including its" +
+		" wording and any issue references or trailers, and change only what the diff no longer matches."
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestRunAmend(t *testing.T) {
+	const updated = "feat: add the app package\n\nRun starts the app."
+	tests := []struct {
+		name  string
+		input string
+		opts  amendOptions
+		// root amends the first commit of the repository.
+		root        bool
+		wantMessage string
+		wantFiles   string
+	}{
+		{name: "confirmed", input: "y\n", wantMessage: updated, wantFiles: "app.go\nrun.go"},
+		{name: "yes", opts: amendOptions{autoYes: true}, wantMessage: updated, wantFiles: "app.go\nrun.go"},
+		{name: "root commit", root: true, opts: amendOptions{autoYes: true}, wantMessage: updated, wantFiles: "app.go\nrun.go"},
+		{name: "declined", input: "n\n", wantMessage: "feat: add app", wantFiles: "app.go"},
+		{name: "dry run", opts: amendOptions{dryRun: true}, wantMessage: "feat: add app", wantFiles: "app.go"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			if !tt.root {
+				commitFile(t, "README.md", "app\n", "docs: add readme")
+			}
+			commitFile(t, "app.go", "package app\n", "feat: add app")
+			writeFile(t, "run.go", "package app\n\nfunc Run() {}\n")
+			git(t, "add", "run.go")
+			commits := git(t, "rev-list", "--count", "HEAD")
+
+			gen := &fakeGenerator{replies: []string{updated}}
+			if err := runAmend(bufio.NewReader(strings.NewReader(tt.input)), io.Discard, gen, tt.opts); err != nil {
+				t.Fatal(err)
+			}
+			if got := git(t, "log", "-1", "--format=%B"); got != tt.wantMessage {
+				t.Errorf("HEAD message = %q, want %q", got, tt.wantMessage)
+			}
+			if got := git(t, "rev-list", "--count", "HEAD"); got != commits {
+				t.Errorf("%s commits, want %s", got, commits)
+			}
+			if got := git(t, "show", "--format=", "--name-only", "HEAD"); got != tt.wantFiles {
+				t.Errorf("HEAD changes %q, want %q", got, tt.wantFiles)
+			}
+
+			req := gen.requests[0]
+			if !strings.Contains(req.Prompt, "whose message is:\n\nfeat: add app\n") {
+				t.Errorf("the prompt does not carry HEAD's message:\n%s", req.Prompt)
+			}
+			if !strings.Contains(req.Prompt, "app.go") || !strings.Contains(req.Prompt, "run.go") || strings.Contains(req.Prompt, "README.md") {
+				t.Errorf("the prompt does not cover exactly HEAD and the staged changes:\n%s", req.Prompt)
+			}
+		})
+	}
+}
+
+func TestAmendKeepingMessage(t *testing.T) {
+	testRepo(t)
+	commitFile(t, "app.go", "package app\n", "feat: add app")
+	if err := amendKeepingMessage(io.Discard, amendOptions{}); err == nil || !strings.Contains(err.Error(), "no staged changes") {
+		t.Errorf("err = %v, want no staged changes", err)
+	}
+
+	writeFile(t, "run.go", "package app\n")
+	git(t, "add", "run.go")
+	if err := amendKeepingMessage(io.Discard, amendOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := git(t, "log", "--format=%s"); got != "feat: add app" {
+		t.Errorf("log = %q, want the one commit with its message", got)
+	}
+	if got := git(t, "show", "--format=", "--name-only", "HEAD"); got != "app.go\nrun.go" {
+		t.Errorf("HEAD changes %q, want app.go and run.go", got)
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// stageBranch stages everything branch adds on top of HEAD, squashed into
+// one change, so the usual flow commits it on the current branch. The
+// branch must build on HEAD: once the branches have diverged, squashing
+// would mean resolving a merge, which is refused.
+func stageBranch(out io.Writer, branch string) error {
+	if _, err := gitLines("rev-parse", "--verify", "--quiet", branch+"^{commit}"); err != nil {
+		return errors.NewCLIError("unknown branch: " + branch).
+			WithHint("List branches with: git branch --all")
+	}
+	if err := checkStagedChanges(); err == nil {
+		return errors.NewCLIError("--from-branch needs an empty index").
+			WithHint("Commit or unstage your staged changes first: git restore --staged .")
+	}
+
+	current := currentBranch()
+	if current == "" {
+		current = "HEAD"
+	}
+	if err := exec.Command("git", "merge-base", "--is-ancestor", "HEAD", branch).Run(); err != nil {
+		return errors.NewCLIError(branch + " has diverged from " + current).
+			WithHint(fmt.Sprintf("Rebase it first: git rebase %s %s", current, branch))
+	}
+	commits, _ := gitLines("rev-list", "HEAD.."+branch)
+	if len(commits) == 0 {
+		return errors.NewCLIError(branch + " has no commits that are not on " + current)
+	}
+
+	cmd := exec.Command("git", "merge", "--squash", "--quiet", branch)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return errors.NewCLIError("failed to squash " + branch).
+			WithCause(fmt.Errorf("%s", strings.TrimSpace(stderr.String()))).
+			WithHint("Commit or stash local changes to the files " + branch + " touches")
+	}
+	fmt.Fprintf(out, "Staged %d commit(s) from %s as one change; undo with: git reset --merge\n", len(commits), branch)
+	return nil
+}
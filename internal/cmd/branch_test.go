@@ -0,0 +1,82 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStageBranch(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(t *testing.T)
+		wantErr    string
+		wantStaged string
+	}{
+		{
+			name: "stacked branch",
+			setup: func(t *testing.T) {
+				git(t, "checkout", "-q", "-b", "feature")
+				commitFile(t, "a.go", "package a\n", "Add a")
+				commitFile(t, "b.go", "package b\n", "Add b")
+				git(t, "checkout", "-q", "main")
+			},
+			wantStaged: "a.go\nb.go",
+		},
+		{
+			name:    "unknown branch",
+			setup:   func(t *testing.T) {},
+			wantErr: "unknown branch: feature",
+		},
+		{
+			name: "staged changes",
+			setup: func(t *testing.T) {
+				git(t, "branch", "feature")
+				writeFile(t, "README.md", "# Changed\n")
+				git(t, "add", "README.md")
+			},
+			wantErr:    "--from-branch needs an empty index",
+			wantStaged: "README.md",
+		},
+		{
+			name: "diverged",
+			setup: func(t *testing.T) {
+				git(t, "checkout", "-q", "-b", "feature")
+				commitFile(t, "a.go", "package a\n", "Add a")
+				git(t, "checkout", "-q", "main")
+				commitFile(t, "c.go", "package c\n", "Add c")
+			},
+			wantErr: "feature has diverged from main",
+		},
+		{
+			name:    "nothing new",
+			setup:   func(t *testing.T) { git(t, "branch", "feature") },
+			wantErr: "feature has no commits that are not on main",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			commitFile(t, "README.md", "# App\n", "Initial commit")
+			tt.setup(t)
+
+			var out strings.Builder
+			err := stageBranch(&out, "feature")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("stageBranch() error = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if got := git(t, "diff", "--cached", "--name-only"); got != tt.wantStaged {
+				t.Errorf("staged %q, want %q", got, tt.wantStaged)
+			}
+			if err == nil && !strings.Contains(out.String(), "Staged 2 commit(s) from feature as one change") {
+				t.Errorf("unexpected output:\n%s", out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,262 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-commit/internal/config"
+	commitmsg "github.com/yourorg/arc-commit/internal/message"
+	"github.com/yourorg/arc-commit/internal/prompt"
+	"github.com/yourorg/arc-sdk/ai"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// defaultBranchPattern is the default --branch-pattern. Placeholders left
+// empty, such as {ticket} without a ticket, are dropped with their
+// separator.
+const defaultBranchPattern = "{type}/{ticket}-{slug}"
+
+// maxSlugLength caps the summary part of a generated branch name.
+const maxSlugLength = 40
+
+// branchOptions holds the flag values of the branch subcommand.
+type branchOptions struct {
+	pattern  string
+	ticket   string
+	issue    string
+	kind     string
+	checkout bool
+}
+
+// newBranchCmd creates the branch subcommand.
+func newBranchCmd(aiCfg *ai.Config) *cobra.Command {
+	var (
+		opts      branchOptions
+		model     string
+		provider  providerOptions
+		redaction redactOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "branch [description]",
+		Short: "Name a branch for a task and optionally create it",
+		Long: `Name a branch for a task and optionally create it.
+
+A short description of the task, or with --issue the title and body of a
+GitHub issue fetched with the GitHub CLI (gh), is sent to the AI, which
+picks a conventional commit type and summarizes the task in a few words.
+The name is built from --branch-pattern, where {type}, {ticket} and {slug}
+stand for the type, the ticket and the kebab-case summary.
+
+The ticket is --ticket, the --issue number, or the first issue reference
+in the description, such as "#42" or "ABC-123". Placeholders that end up
+empty are dropped with their separator.
+
+The name is printed, or with --checkout used to create and switch to the
+branch.`,
+		Example: `  # Print a name such as "fix/retry-limit-for-uploads"
+  arc-commit branch "uploads retry forever when the server is down"
+
+  # Name the branch after GitHub issue 42 and switch to it
+  arc-commit branch --issue 42 --checkout
+
+  # Use a team pattern, e.g. "alice/ABC-123-login-redirect"
+  arc-commit branch --branch-pattern "alice/{ticket}-{slug}" "ABC-123 login redirect loops"`,
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			settings, err := resolveConfig(cmd)
+			if err != nil {
+				return err
+			}
+			if settings.blocksProvider(provider) {
+				return errors.NewCLIError("branch is disabled in sensitive repositories").
+					WithHint("Use a local model, e.g. --provider ollama; sensitive-repo is set in " + config.FileName + " or the global config")
+			}
+			if err := validateProvider(provider); err != nil {
+				return err
+			}
+			if !strings.Contains(opts.pattern, "{slug}") {
+				return errors.NewCLIError("--branch-pattern must contain {slug}").
+					WithHint("The default is " + defaultBranchPattern)
+			}
+			if opts.kind != "" && !slices.Contains(commitmsg.DefaultTypes, opts.kind) {
+				return errors.NewCLIError("unknown --type: " + opts.kind).
+					WithHint("Use one of " + strings.Join(commitmsg.DefaultTypes, ", "))
+			}
+
+			task := strings.TrimSpace(strings.Join(args, " "))
+			if opts.issue != "" {
+				if task != "" {
+					return errors.NewCLIError("pass either a description or --issue, not both")
+				}
+				number, text, err := issueTask(opts.issue)
+				if err != nil {
+					return err
+				}
+				task = text
+				if opts.ticket == "" {
+					opts.ticket = number
+				}
+			}
+			if task == "" {
+				return errors.NewCLIError("nothing to name the branch after").
+					WithHint("Describe the task, e.g. arc-commit branch \"fix login redirect\", or pass --issue")
+			}
+
+			cfg := *aiCfg
+			gen, _, err := newGenerator(&cfg, provider, model)
+			if err != nil {
+				return err
+			}
+			if gen, err = withRedaction(gen, settings, redaction, cmd.ErrOrStderr()); err != nil {
+				return err
+			}
+			return runBranch(cmd.OutOrStdout(), cmd.ErrOrStderr(), gen, task, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.pattern, "branch-pattern", defaultBranchPattern, "Pattern of the branch name, using {type}, {ticket} and {slug}")
+	cmd.Flags().StringVar(&opts.ticket, "ticket", "", "Ticket for {ticket}, e.g. ABC-123 (default: from --issue or the description)")
+	cmd.Flags().StringVar(&opts.issue, "issue", "", "Name the branch after this GitHub issue, fetched with gh")
+	cmd.Flags().StringVar(&opts.kind, "type", "", "Type for {type} instead of the one the model picks")
+	cmd.Flags().BoolVar(&opts.checkout, "checkout", false, "Create the branch and switch to it instead of printing the name")
+	cmd.Flags().StringVarP(&model, "model", "m", "", "Model to use (default: "+prompt.CommitMessageModel+")")
+	cmd.RegisterFlagCompletionFunc("model", completeModels)
+	cmd.RegisterFlagCompletionFunc("type", cobra.FixedCompletions(commitmsg.DefaultTypes, cobra.ShellCompDirectiveNoFileComp))
+	addProviderFlags(cmd.Flags(), &provider)
+	addRedactFlags(cmd.Flags(), &redaction)
+
+	return cmd
+}
+
+// runBranch names a branch for task with gen, then prints the name to out
+// or creates and checks out the branch. Progress goes to status.
+func runBranch(out, status io.Writer, gen Generator, task string, opts branchOptions) error {
+	fmt.Fprintln(status, "Naming branch with AI...")
+	system, user := prompt.BranchName(task)
+	text, err := gen.Generate(context.Background(), GenerateRequest{System: system, Prompt: user})
+	if err != nil {
+		return errors.NewCLIError("failed to generate branch name").WithCause(err)
+	}
+	kind, slug := parseBranchName(text)
+	if slug == "" {
+		return errors.NewCLIError("the model returned an empty branch name")
+	}
+	if opts.kind != "" {
+		kind = opts.kind
+	}
+	ticket := opts.ticket
+	if ticket == "" {
+		if refs := commitmsg.IssueRefs(task); len(refs) > 0 {
+			ticket = refs[0]
+		}
+	}
+
+	name := expandBranchPattern(opts.pattern, kind, branchTicket(ticket), slug)
+	if err := exec.Command("git", "check-ref-format", "--branch", name).Run(); err != nil {
+		return errors.NewCLIError("not a valid branch name: " + name).
+			WithHint("Check --branch-pattern and --ticket")
+	}
+
+	if !opts.checkout {
+		fmt.Fprintln(out, name)
+		return nil
+	}
+	if exec.Command("git", "rev-parse", "--verify", "--quiet", "refs/heads/"+name).Run() == nil {
+		return errors.NewCLIError("branch " + name + " already exists").
+			WithHint("Switch to it with: git switch " + name)
+	}
+	cmd := exec.Command("git", "switch", "-c", name)
+	cmd.Stdout, cmd.Stderr = status, status
+	if err := cmd.Run(); err != nil {
+		return errors.NewCLIError("failed to create branch " + name).WithCause(err)
+	}
+	return nil
+}
+
+// parseBranchName splits a "type: summary" answer into the type, empty
+// when it is not a known one, and the summary as a kebab-case slug of at
+// most maxSlugLength characters.
+func parseBranchName(text string) (kind, slug string) {
+	line, _, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	line = strings.Trim(line, "`\"' ")
+	summary := line
+	if prefix, rest, ok := strings.Cut(line, ":"); ok {
+		prefix, _, _ = strings.Cut(strings.ToLower(strings.TrimSpace(prefix)), "(")
+		if slices.Contains(commitmsg.DefaultTypes, prefix) {
+			kind, summary = prefix, rest
+		}
+	}
+	slug = slugify(summary)
+	for len(slug) > maxSlugLength {
+		i := strings.LastIndex(slug[:maxSlugLength+1], "-")
+		if i <= 0 {
+			slug = slug[:maxSlugLength]
+			break
+		}
+		slug = slug[:i]
+	}
+	return kind, slug
+}
+
+// branchTicket returns ticket as it goes into a branch name: issue
+// references such as "#42" or "owner/repo#42" become "42".
+func branchTicket(ticket string) string {
+	if _, number, ok := strings.Cut(ticket, "#"); ok {
+		return number
+	}
+	return ticket
+}
+
+// repeatedDashes matches runs of dashes left where placeholders were empty.
+var repeatedDashes = regexp.MustCompile(`-{2,}`)
+
+// expandBranchPattern fills in the placeholders of pattern. Empty
+// placeholders are dropped together with the separators around them.
+func expandBranchPattern(pattern, kind, ticket, slug string) string {
+	name := strings.NewReplacer("{type}", kind, "{ticket}", ticket, "{slug}", slug).Replace(pattern)
+	var segments []string
+	for _, segment := range strings.Split(name, "/") {
+		segment = strings.Trim(repeatedDashes.ReplaceAllString(segment, "-"), "-_.")
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// issueTask fetches the title and body of a GitHub issue with gh, returning
+// its number and the text to name a branch after.
+func issueTask(issue string) (number, task string, err error) {
+	if _, err := exec.LookPath("gh"); err != nil {
+		return "", "", errors.NewCLIError("--issue needs the GitHub CLI (gh)").
+			WithHint("Install it from https://cli.github.com, or pass the issue title as the description")
+	}
+	output, err := exec.Command("gh", "issue", "view", issue, "--json", "number,title,body").Output()
+	if err != nil {
+		return "", "", errors.NewCLIError("failed to fetch issue " + issue).WithCause(err).
+			WithHint("Check the issue number and gh auth status")
+	}
+	var fetched struct {
+		Number int    `json:"number"`
+		Title  string `json:"title"`
+		Body   string `json:"body"`
+	}
+	if err := json.Unmarshal(output, &fetched); err != nil {
+		return "", "", errors.NewCLIError("unexpected output from gh issue view").WithCause(err)
+	}
+	return strconv.Itoa(fetched.Number), strings.TrimSpace(fetched.Title + "\n\n" + fetched.Body), nil
+}
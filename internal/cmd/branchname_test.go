@@ -0,0 +1,169 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/arc-sdk/ai"
+)
+
+func TestParseBranchName(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		wantKind string
+		wantSlug string
+	}{
+		{name: "type and summary", text: "fix: retry limit for uploads", wantKind: "fix", wantSlug: "retry-limit-for-uploads"},
+		{name: "scope", text: "feat(api): Paginated Search", wantKind: "feat", wantSlug: "paginated-search"},
+		{name: "quoted with extra lines", text: "`docs: install guide`\n\nThe guide is missing.", wantKind: "docs", wantSlug: "install-guide"},
+		{name: "unknown type", text: "bugfix: login redirect", wantSlug: "bugfix-login-redirect"},
+		{name: "no type", text: "login redirect loops", wantSlug: "login-redirect-loops"},
+		{
+			name:     "long summary cut at a word",
+			text:     "refactor: split the configuration loader into smaller focused pieces",
+			wantKind: "refactor",
+			wantSlug: "split-the-configuration-loader-into",
+		},
+		{name: "one long word", text: "chore: " + strings.Repeat("x", 50), wantKind: "chore", wantSlug: strings.Repeat("x", maxSlugLength)},
+		{name: "empty", text: "fix: ...", wantKind: "fix"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind, slug := parseBranchName(tt.text)
+			if kind != tt.wantKind || slug != tt.wantSlug {
+				t.Errorf("parseBranchName(%q) = %q, %q, want %q, %q", tt.text, kind, slug, tt.wantKind, tt.wantSlug)
+			}
+		})
+	}
+}
+
+func TestBranchTicket(t *testing.T) {
+	tests := []struct {
+		ticket string
+		want   string
+	}{
+		{ticket: "ABC-123", want: "ABC-123"},
+		{ticket: "#42", want: "42"},
+		{ticket: "owner/repo#42", want: "42"},
+		{ticket: "", want: ""},
+	}
+	for _, tt := range tests {
+		if got := branchTicket(tt.ticket); got != tt.want {
+			t.Errorf("branchTicket(%q) = %q, want %q", tt.ticket, got, tt.want)
+		}
+	}
+}
+
+func TestExpandBranchPattern(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		kind    string
+		ticket  string
+		want    string
+	}{
+		{name: "all placeholders", pattern: defaultBranchPattern, kind: "fix", ticket: "42", want: "fix/42-login-redirect"},
+		{name: "no ticket", pattern: defaultBranchPattern, kind: "fix", want: "fix/login-redirect"},
+		{name: "no type", pattern: defaultBranchPattern, ticket: "ABC-1", want: "ABC-1-login-redirect"},
+		{name: "nothing but the slug", pattern: defaultBranchPattern, want: "login-redirect"},
+		{name: "empty in the middle", pattern: "alice/{type}--{ticket}--{slug}", kind: "feat", want: "alice/feat-login-redirect"},
+		{name: "fixed prefix", pattern: "alice/{ticket}-{slug}", ticket: "ABC-1", want: "alice/ABC-1-login-redirect"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := expandBranchPattern(tt.pattern, tt.kind, tt.ticket, "login-redirect"); got != tt.want {
+				t.Errorf("expandBranchPattern() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunBranch(t *testing.T) {
+	tests := []struct {
+		name  string
+		task  string
+		reply string
+		opts  branchOptions
+		// existing is a branch created before naming.
+		existing   string
+		wantOut    string
+		wantBranch string
+		wantErr    string
+	}{
+		{name: "print", task: "uploads retry forever", reply: "fix: retry limit for uploads", wantOut: "fix/retry-limit-for-uploads\n", wantBranch: "main"},
+		{name: "ticket from the description", task: "ABC-123 login redirect loops", reply: "fix: login redirect", wantOut: "fix/ABC-123-login-redirect\n", wantBranch: "main"},
+		{name: "issue reference", task: "see #42: login redirect loops", reply: "fix: login redirect", wantOut: "fix/42-login-redirect\n", wantBranch: "main"},
+		{name: "ticket flag wins", task: "ABC-123 login redirect", reply: "fix: login redirect", opts: branchOptions{ticket: "XYZ-9"}, wantOut: "fix/XYZ-9-login-redirect\n", wantBranch: "main"},
+		{name: "type flag wins", task: "login redirect", reply: "fix: login redirect", opts: branchOptions{kind: "refactor"}, wantOut: "refactor/login-redirect\n", wantBranch: "main"},
+		{name: "checkout", task: "login redirect", reply: "fix: login redirect", opts: branchOptions{checkout: true}, wantBranch: "fix/login-redirect"},
+		{
+			name: "already exists", task: "login redirect", reply: "fix: login redirect", opts: branchOptions{checkout: true},
+			existing: "fix/login-redirect", wantBranch: "main", wantErr: "branch fix/login-redirect already exists",
+		},
+		{name: "invalid name", task: "login redirect", reply: "fix: login redirect", opts: branchOptions{ticket: "a..b"}, wantBranch: "main", wantErr: "not a valid branch name"},
+		{name: "empty reply", task: "login redirect", reply: "fix:", wantBranch: "main", wantErr: "empty branch name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			commitFile(t, "app.go", "package app\n", "feat: add app")
+			if tt.existing != "" {
+				git(t, "branch", tt.existing)
+			}
+			if tt.opts.pattern == "" {
+				tt.opts.pattern = defaultBranchPattern
+			}
+
+			gen := &fakeGenerator{replies: []string{tt.reply}}
+			var out bytes.Buffer
+			err := runBranch(&out, io.Discard, gen, tt.task, tt.opts)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != tt.wantOut {
+				t.Errorf("output = %q, want %q", out.String(), tt.wantOut)
+			}
+			if got := git(t, "branch", "--show-current"); got != tt.wantBranch {
+				t.Errorf("current branch = %q, want %q", got, tt.wantBranch)
+			}
+			if !strings.Contains(gen.requests[0].Prompt, tt.task) {
+				t.Errorf("the prompt does not carry the task:\n%s", gen.requests[0].Prompt)
+			}
+		})
+	}
+}
+
+func TestBranchFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{name: "no task", args: nil, wantErr: "nothing to name the branch after"},
+		{name: "pattern without slug", args: []string{"--branch-pattern", "{type}/{ticket}", "login"}, wantErr: "--branch-pattern must contain {slug}"},
+		{name: "unknown type", args: []string{"--type", "bugfix", "login"}, wantErr: "unknown --type: bugfix"},
+		{name: "description and issue", args: []string{"--issue", "42", "login"}, wantErr: "either a description or --issue"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			cmd := newBranchCmd(&ai.Config{})
+			cmd.SetArgs(tt.args)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
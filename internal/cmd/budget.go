@@ -0,0 +1,119 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/yourorg/arc-commit/internal/diff"
+)
+
+// defaultGeneratedPaths match lockfiles and generated code: often huge,
+// and of little use in describing a change. Patterns are as in
+// classify-rules.
+var defaultGeneratedPaths = []string{
+	"package-lock.json", "yarn.lock", "pnpm-lock.yaml", "go.sum",
+	"Cargo.lock", "poetry.lock", "Gemfile.lock", "composer.lock",
+	"*.pb.go", "*_generated.go", "*.min.js", "*.min.css", "*.map",
+	"dist/",
+}
+
+// oversizedShare is the fraction of the token budget one file's diff may
+// take before it is reduced to stats.
+const oversizedShare = 4
+
+// budgetedDiff splits a diff that is over the token budget by how the
+// prompt shows each file.
+type budgetedDiff struct {
+	// full holds the files shown in full.
+	full *diff.Diff
+	// stats holds lockfiles, generated and oversized files, shown as
+	// stats only.
+	stats []*diff.File
+	// parts holds the remaining files in groups that each fit the budget,
+	// to be outlined separately.
+	parts []*diff.Diff
+}
+
+// fitBudget splits d so the prompt stays within budget estimated tokens:
+// files matching the generated patterns and files taking more than a
+// quarter of the budget become stats; if the rest is still too large, the
+// largest files that fit half the budget stay in full and the others are
+// grouped into parts of at most budget tokens each.
+func fitBudget(d *diff.Diff, budget int, generated []string) budgetedDiff {
+	tokens := func(f *diff.File) int { return estimateTokens(f.Patch()) }
+	total := 0
+	for _, f := range d.Files {
+		total += tokens(f)
+	}
+	if budget <= 0 || total <= budget {
+		return budgetedDiff{full: d}
+	}
+
+	b := budgetedDiff{full: &diff.Diff{}}
+	var keep []*diff.File
+	kept := 0
+	for _, f := range d.Files {
+		if excludedPath(f.Path(), generated) || tokens(f) > budget/oversizedShare {
+			b.stats = append(b.stats, f)
+		} else {
+			keep = append(keep, f)
+			kept += tokens(f)
+		}
+	}
+	if kept <= budget {
+		b.full.Files = keep
+		return b
+	}
+
+	// Show the most informative files in full, in their original order
+	bySize := slices.Clone(keep)
+	slices.SortStableFunc(bySize, func(a, b *diff.File) int {
+		return cmp.Compare(b.Changes(), a.Changes())
+	})
+	inFull := map[*diff.File]bool{}
+	used := 0
+	for _, f := range bySize {
+		if used+tokens(f) <= budget/2 {
+			inFull[f] = true
+			used += tokens(f)
+		}
+	}
+
+	part, partTokens := &diff.Diff{}, 0
+	for _, f := range keep {
+		if inFull[f] {
+			b.full.Files = append(b.full.Files, f)
+			continue
+		}
+		if partTokens+tokens(f) > budget && len(part.Files) > 0 {
+			b.parts = append(b.parts, part)
+			part, partTokens = &diff.Diff{}, 0
+		}
+		part.Files = append(part.Files, f)
+		partTokens += tokens(f)
+	}
+	if len(part.Files) > 0 {
+		b.parts = append(b.parts, part)
+	}
+	return b
+}
+
+// outlineParts outlines each part of a diff that did not fit the prompt,
+// writing progress to out.
+func outlineParts(out io.Writer, gen Generator, parts []*diff.Diff, params GenerateRequest) ([]string, error) {
+	outlines := make([]string, len(parts))
+	for i, part := range parts {
+		fmt.Fprintf(out, "Outlining part %d/%d of the diff (%d file(s))...\n", i+1, len(parts), len(part.Files))
+		outline, err := outlineChanges(gen, part, params)
+		if err != nil {
+			return nil, err
+		}
+		outlines[i] = outline
+	}
+	return outlines, nil
+}
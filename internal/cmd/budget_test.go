@@ -0,0 +1,113 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/arc-commit/internal/diff"
+)
+
+// sizedFile is a new file of lines added lines, each about ten tokens.
+type sizedFile struct {
+	path  string
+	lines int
+}
+
+// sizedDiff returns a diff that adds the files.
+func sizedDiff(files ...sizedFile) *diff.Diff {
+	var b strings.Builder
+	for _, f := range files {
+		fmt.Fprintf(&b, "diff --git a/%s b/%s\nnew file mode 100644\n--- /dev/null\n+++ b/%s\n@@ -0,0 +1,%d @@\n", f.path, f.path, f.path, f.lines)
+		for range f.lines {
+			b.WriteString("+" + strings.Repeat("x", 39) + "\n")
+		}
+	}
+	return diff.Parse(b.String())
+}
+
+// manyFiles returns n files of lines lines each, named f00.go and up.
+func manyFiles(n, lines int) []sizedFile {
+	files := make([]sizedFile, n)
+	for i := range files {
+		files[i] = sizedFile{path: fmt.Sprintf("f%02d.go", i), lines: lines}
+	}
+	return files
+}
+
+func TestFitBudget(t *testing.T) {
+	tests := []struct {
+		name      string
+		files     []sizedFile
+		budget    int
+		wantFull  []string
+		wantStats []string
+		// wantParts is the number of files in each outlined part.
+		wantParts []int
+	}{
+		{
+			name:     "under the budget",
+			files:    []sizedFile{{"a.go", 10}, {"go.sum", 10}},
+			budget:   1000,
+			wantFull: []string{"a.go", "go.sum"},
+		},
+		{
+			name:     "no budget",
+			files:    []sizedFile{{"a.go", 10}, {"huge.go", 500}},
+			budget:   0,
+			wantFull: []string{"a.go", "huge.go"},
+		},
+		{
+			name:      "lockfiles and oversized files become stats",
+			files:     []sizedFile{{"a.go", 10}, {"go.sum", 10}, {"huge.go", 80}},
+			budget:    1000,
+			wantFull:  []string{"a.go"},
+			wantStats: []string{"go.sum", "huge.go"},
+		},
+		{
+			name:      "the rest is outlined in parts",
+			files:     manyFiles(11, 20),
+			budget:    1000,
+			wantFull:  []string{"f00.go", "f01.go"},
+			wantParts: []int{4, 4, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := fitBudget(sizedDiff(tt.files...), tt.budget, defaultGeneratedPaths)
+			if paths := filePaths(got.full); !slices.Equal(paths, tt.wantFull) {
+				t.Errorf("full = %v, want %v", paths, tt.wantFull)
+			}
+			if paths := filePaths(&diff.Diff{Files: got.stats}); !slices.Equal(paths, tt.wantStats) {
+				t.Errorf("stats = %v, want %v", paths, tt.wantStats)
+			}
+			var parts []int
+			for _, part := range got.parts {
+				parts = append(parts, len(part.Files))
+			}
+			if !slices.Equal(parts, tt.wantParts) {
+				t.Errorf("parts of %v file(s), want %v", parts, tt.wantParts)
+			}
+		})
+	}
+}
+
+func TestOutlineParts(t *testing.T) {
+	gen := &fakeGenerator{replies: []string{"- adds f00 and f01\n", "- adds f02"}}
+	parts := []*diff.Diff{sizedDiff(manyFiles(2, 1)...), sizedDiff(sizedFile{"f02.go", 1})}
+	outlines, err := outlineParts(io.Discard, gen, parts, GenerateRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"- adds f00 and f01", "- adds f02"}; !slices.Equal(outlines, want) {
+		t.Errorf("outlines = %q, want %q", outlines, want)
+	}
+	if !strings.Contains(gen.requests[1].Prompt, "== f02.go") || strings.Contains(gen.requests[1].Prompt, "== f00.go") {
+		t.Errorf("the second request does not outline only the second part:\n%s", gen.requests[1].Prompt)
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// pickCandidate lists the --candidates messages, numbered and with their
+// validation errors, and returns the one chosen. An empty answer picks the
+// first.
+func pickCandidate(reader *bufio.Reader, out io.Writer, candidates []string, opts commitOptions) (string, error) {
+	fmt.Fprintf(out, "\nGenerated %d candidate messages:\n", len(candidates))
+	for i, message := range candidates {
+		fmt.Fprintf(out, "\n[%d]\n%s\n", i+1, message)
+		if problems := validationErrors(message, opts); len(problems) > 0 {
+			fmt.Fprintf(out, "  (%d validation error(s): %s)\n", len(problems), strings.Join(problems, "; "))
+		}
+	}
+
+	for {
+		fmt.Fprintf(out, "\nPick a message [1-%d] (Enter for 1): ", len(candidates))
+		answer, err := reader.ReadString('\n')
+		if err != nil {
+			return "", errors.NewCLIError("failed to read input").WithCause(err)
+		}
+		answer = strings.TrimSpace(answer)
+		if answer == "" {
+			return candidates[0], nil
+		}
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(candidates) {
+			return candidates[n-1], nil
+		}
+		fmt.Fprintf(out, "Please enter a number from 1 to %d.\n", len(candidates))
+	}
+}
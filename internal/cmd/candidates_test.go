@@ -0,0 +1,57 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPickCandidate(t *testing.T) {
+	candidates := []string{"feat: add the app package", "Added the app package", "feat(app): add Run"}
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantOut string
+		wantErr bool
+	}{
+		{name: "enter picks the first", input: "\n", want: candidates[0]},
+		{name: "number", input: "3\n", want: candidates[2]},
+		{name: "asks again", input: "4\nx\n2\n", want: candidates[1], wantOut: "Please enter a number from 1 to 3."},
+		{name: "end of input", input: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			got, err := pickCandidate(bufio.NewReader(strings.NewReader(tt.input)), &out, candidates, testCommitOptions(t))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("picked %q, want %q", got, tt.want)
+			}
+			if !strings.Contains(out.String(), tt.wantOut) {
+				t.Errorf("output does not contain %q:\n%s", tt.wantOut, out.String())
+			}
+		})
+	}
+}
+
+func TestPickCandidateListsValidationErrors(t *testing.T) {
+	var out bytes.Buffer
+	candidates := []string{"feat: add the app package", "Added the app package"}
+	if _, err := pickCandidate(bufio.NewReader(strings.NewReader("\n")), &out, candidates, testCommitOptions(t)); err != nil {
+		t.Fatal(err)
+	}
+	got := out.String()
+	if !strings.Contains(got, "[1]\nfeat: add the app package\n\n[2]\nAdded the app package\n  (1 validation error(s): ") {
+		t.Errorf("the candidates are not listed with their errors:\n%s", got)
+	}
+	if strings.Count(got, "validation error(s)") != 1 {
+		t.Errorf("want validation errors for the second candidate only:\n%s", got)
+	}
+}
@@ -0,0 +1,329 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-commit/internal/changelog"
+	"github.com/yourorg/arc-commit/internal/config"
+	"github.com/yourorg/arc-commit/internal/prompt"
+	"github.com/yourorg/arc-sdk/ai"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// changelogOptions holds the flag values of the changelog subcommand.
+type changelogOptions struct {
+	from        string
+	to          string
+	version     string
+	date        string
+	template    string
+	prepend     bool
+	file        string
+	summarize   bool
+	firstParent bool
+}
+
+// newChangelogCmd creates the changelog subcommand.
+func newChangelogCmd(aiCfg *ai.Config) *cobra.Command {
+	var (
+		opts      changelogOptions
+		model     string
+		provider  providerOptions
+		redaction redactOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "changelog",
+		Short: "Write a changelog section for a range of commits",
+		Long: `Write a changelog section for the commits between two refs.
+
+By default every conventional commit with a user-facing type (feat, fix,
+perf, refactor, revert, or a breaking change) becomes an entry, grouped
+into Keep a Changelog categories. With --summarize the commit messages
+and the changed files are sent to the AI instead, which merges related
+commits and also covers history that is not conventional.
+
+The section is printed, or with --prepend added to the changelog file
+above the latest release.
+
+In a repository where every change lands through a merge, --first-parent
+follows only the mainline: the merge commits are used instead of every
+commit on the merged branches. Merge messages that are not conventional
+commits, such as "Merge pull request #12", need --summarize.`,
+		Example: `  # Print the changes since v1.2.0
+  arc-commit changelog --from v1.2.0
+
+  # Add a 1.3.0 section to CHANGELOG.md, written by the AI
+  arc-commit changelog --from v1.2.0 --version 1.3.0 --summarize --prepend
+
+  # Render the release with your own Go template
+  arc-commit changelog --from v1.2.0 --template release-notes.tmpl`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			settings, err := resolveConfig(cmd)
+			if err != nil {
+				return err
+			}
+			if opts.from == "" {
+				return errors.NewCLIError("--from is required").
+					WithHint("Pass the previous release, e.g. --from v1.2.0")
+			}
+			if opts.prepend && opts.template != "" {
+				return errors.NewCLIError("--prepend cannot be combined with --template")
+			}
+
+			var gen Generator
+			if opts.summarize {
+				if settings.blocksProvider(provider) {
+					return errors.NewCLIError("--summarize is disabled in sensitive repositories").
+						WithHint("Use a local model, e.g. --provider ollama; sensitive-repo is set in " + config.FileName + " or the global config")
+				}
+				if err := validateProvider(provider); err != nil {
+					return err
+				}
+				cfg := *aiCfg
+				if gen, _, err = newGenerator(&cfg, provider, model); err != nil {
+					return err
+				}
+				if gen, err = withRedaction(gen, settings, redaction, cmd.ErrOrStderr()); err != nil {
+					return err
+				}
+			}
+			return runChangelog(cmd.OutOrStdout(), cmd.ErrOrStderr(), gen, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.from, "from", "", "Ref of the previous release; its commits are not included")
+	cmd.Flags().StringVar(&opts.to, "to", "HEAD", "Ref of the last commit to include")
+	cmd.Flags().StringVar(&opts.version, "version", changelogUnreleased, "Version the section is for")
+	cmd.Flags().StringVar(&opts.date, "date", "", "Release date (default: today, unless the version is "+changelogUnreleased+")")
+	cmd.Flags().StringVar(&opts.template, "template", "", "Render the section with this Go template instead of Keep a Changelog format")
+	cmd.Flags().BoolVar(&opts.prepend, "prepend", false, "Add the section to the changelog file instead of printing it")
+	cmd.Flags().StringVar(&opts.file, "file", "CHANGELOG.md", "Changelog file for --prepend")
+	cmd.Flags().BoolVar(&opts.summarize, "summarize", false, "Have the AI write the entries from the commit messages and changed files")
+	cmd.Flags().BoolVar(&opts.firstParent, "first-parent", false, "Follow only the mainline, using merge commits instead of the commits they merged")
+	cmd.Flags().StringVarP(&model, "model", "m", "", "Model to use with --summarize (default: "+prompt.CommitMessageModel+")")
+	cmd.RegisterFlagCompletionFunc("model", completeModels)
+	addProviderFlags(cmd.Flags(), &provider)
+	addRedactFlags(cmd.Flags(), &redaction)
+
+	return cmd
+}
+
+// changelogUnreleased is the version of changes not yet released.
+const changelogUnreleased = "Unreleased"
+
+// runChangelog writes the changelog section for opts.from..opts.to to out,
+// or to opts.file with --prepend. A non-nil gen writes the entries.
+// Progress goes to status.
+func runChangelog(out, status io.Writer, gen Generator, opts changelogOptions) error {
+	rangeSpec := opts.from + ".." + opts.to
+	args := append([]string{"log", "--reverse"}, historyArgs(opts.firstParent)...)
+	raw, err := exec.Command("git", append(args, "--format=%B%x00", rangeSpec)...).Output()
+	if err != nil {
+		return errors.NewCLIError("failed to read commits in " + rangeSpec).WithCause(err).
+			WithHint("Check that --from and --to name existing refs")
+	}
+	var messages []string
+	for _, m := range strings.Split(string(raw), "\x00") {
+		if m = strings.TrimSpace(m); m != "" {
+			messages = append(messages, m)
+		}
+	}
+	if len(messages) == 0 {
+		return errors.NewCLIError("no commits in " + rangeSpec)
+	}
+
+	var entries []changelog.Entry
+	if gen != nil {
+		fmt.Fprintf(status, "Summarizing %d commit(s) with AI...\n", len(messages))
+		if entries, err = summarizeChangelog(gen, messages, rangeSpec); err != nil {
+			return err
+		}
+	} else {
+		for _, m := range messages {
+			if entry, ok := changelog.EntryFor(m); ok {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	if len(entries) == 0 {
+		fmt.Fprintf(status, "No user-facing changes in %d commit(s).\n", len(messages))
+		return nil
+	}
+
+	date := opts.date
+	if date == "" && opts.version != changelogUnreleased {
+		date = time.Now().Format(time.DateOnly)
+	}
+	release := changelog.NewRelease(opts.version, date, entries)
+
+	switch {
+	case opts.template != "":
+		return renderChangelog(out, opts.template, release)
+	case opts.prepend:
+		return prependChangelog(out, opts.file, release, entries)
+	default:
+		fmt.Fprint(out, release)
+		return nil
+	}
+}
+
+// summarizeChangelog has gen write changelog entries for the commit
+// messages, with the diff stat of rangeSpec for context.
+func summarizeChangelog(gen Generator, messages []string, rangeSpec string) ([]changelog.Entry, error) {
+	stat, err := exec.Command("git", "diff", "--stat", rangeSpec).Output()
+	if err != nil {
+		return nil, errors.NewCLIError("failed to diff " + rangeSpec).WithCause(err)
+	}
+	system, user := prompt.ChangelogEntries(strings.Join(messages, "\n---\n"), string(stat))
+	text, err := gen.Generate(context.Background(), GenerateRequest{System: system, Prompt: user})
+	if err != nil {
+		return nil, errors.NewCLIError("failed to summarize commits").WithCause(err)
+	}
+	return changelog.ParseEntries(text), nil
+}
+
+// renderChangelog renders release with the Go template at path.
+func renderChangelog(out io.Writer, path string, release changelog.Release) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return errors.NewCLIError("failed to read --template").WithCause(err)
+	}
+	tmpl, err := template.New(filepath.Base(path)).Parse(string(data))
+	if err != nil {
+		return errors.NewCLIError("invalid --template").WithCause(err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, release); err != nil {
+		return errors.NewCLIError("failed to render --template").WithCause(err)
+	}
+	_, err = out.Write(buf.Bytes())
+	return err
+}
+
+// prependChangelog adds release to the changelog at path. Unreleased
+// entries are merged into an existing Unreleased section.
+func prependChangelog(out io.Writer, path string, release changelog.Release, entries []changelog.Entry) error {
+	if release.Version == changelogUnreleased {
+		for _, e := range entries {
+			if err := changelog.Insert(path, e); err != nil {
+				return err
+			}
+		}
+	} else {
+		data, err := os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		content := changelog.Prepend(string(data), release.String())
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	fmt.Fprintf(out, "Added %d entries to %s\n", len(entries), path)
+	return nil
+}
+
+// changelogEntry prints the changelog entry derived from the message and,
+// when write is set and --changelog-file is given, adds it to that file and
+// optionally stages it so it lands in the same commit.
+func changelogEntry(out io.Writer, message string, opts commitOptions, write bool) error {
+	entry, ok := changelog.EntryFor(message)
+	if !ok {
+		fmt.Fprintln(out, "\nNo changelog entry for this type of change.")
+		return nil
+	}
+
+	fmt.Fprintf(out, "\nChangelog entry (%s):\n%s\n", entry.Section, entry)
+	if !write || opts.changelogFile == "" {
+		return nil
+	}
+
+	if err := changelog.Insert(opts.changelogFile, entry); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Added to %s\n", opts.changelogFile)
+
+	if opts.stageChangelog {
+		if err := exec.Command("git", "add", "--", opts.changelogFile).Run(); err != nil {
+			return fmt.Errorf("failed to stage %s: %w", opts.changelogFile, err)
+		}
+	}
+	return nil
+}
+
+// changelogBackup is the --changelog-file as it was before an entry was
+// added, so a commit that fails does not leave the entry behind.
+type changelogBackup struct {
+	path    string
+	data    []byte
+	existed bool
+	// index is the file's "mode,hash,path" index entry with
+	// --stage-changelog, empty when it was not in the index.
+	index  string
+	staged bool
+}
+
+// backupChangelog saves the --changelog-file and, with --stage-changelog,
+// its index entry. It returns nil when no file is written.
+func backupChangelog(opts commitOptions) (*changelogBackup, error) {
+	if opts.changelogFile == "" {
+		return nil, nil
+	}
+	b := &changelogBackup{path: opts.changelogFile, staged: opts.stageChangelog}
+	data, err := os.ReadFile(b.path)
+	switch {
+	case err == nil:
+		b.data, b.existed = data, true
+	case !os.IsNotExist(err):
+		return nil, fmt.Errorf("failed to read %s: %w", b.path, err)
+	}
+	if b.staged {
+		output, err := exec.Command("git", "ls-files", "--stage", "--", b.path).Output()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the index entry of %s: %w", b.path, err)
+		}
+		// "<mode> <hash> <stage>\t<path>"
+		if fields := strings.Fields(string(output)); len(fields) >= 2 {
+			b.index = fields[0] + "," + fields[1] + "," + b.path
+		}
+	}
+	return b, nil
+}
+
+// restore puts the changelog and its index entry back as they were.
+func (b *changelogBackup) restore() error {
+	if b == nil {
+		return nil
+	}
+	if b.existed {
+		if err := os.WriteFile(b.path, b.data, 0o644); err != nil {
+			return err
+		}
+	} else if err := os.Remove(b.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if !b.staged {
+		return nil
+	}
+	if b.index == "" {
+		return exec.Command("git", "rm", "--cached", "--quiet", "--ignore-unmatch", "--", b.path).Run()
+	}
+	return exec.Command("git", "update-index", "--cacheinfo", b.index).Run()
+}
@@ -0,0 +1,182 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/arc-sdk/ai"
+)
+
+// mergeHistory makes a repository where a feature branch with two commits
+// was merged into main with a conventional merge message, and returns the
+// commit before the merge.
+func mergeHistory(t *testing.T) string {
+	t.Helper()
+	testRepo(t)
+	commitFile(t, "README.md", "app\n", "chore: initial commit")
+	base := git(t, "rev-parse", "HEAD")
+	git(t, "checkout", "-q", "-b", "dark-mode")
+	commitFile(t, "theme.go", "package app\n", "feat: add a dark theme")
+	commitFile(t, "toggle.go", "package app\n", "fix: keep the toggle in sync")
+	git(t, "checkout", "-q", "main")
+	git(t, "merge", "-q", "--no-ff", "-m", "feat: add dark mode", "dark-mode")
+	return base
+}
+
+func TestRunChangelogFirstParent(t *testing.T) {
+	tests := []struct {
+		name        string
+		firstParent bool
+		want        []string
+		notWant     []string
+	}{
+		{
+			name:    "merged commits",
+			want:    []string{"- Add a dark theme", "- Keep the toggle in sync"},
+			notWant: []string{"- Add dark mode"},
+		},
+		{
+			name:        "first parent",
+			firstParent: true,
+			want:        []string{"- Add dark mode"},
+			notWant:     []string{"- Add a dark theme", "- Keep the toggle in sync"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := mergeHistory(t)
+			var out bytes.Buffer
+			opts := changelogOptions{from: base, to: "HEAD", version: changelogUnreleased, firstParent: tt.firstParent}
+			if err := runChangelog(&out, io.Discard, nil, opts); err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("changelog is missing %q:\n%s", want, out.String())
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(out.String(), notWant) {
+					t.Errorf("changelog has %q:\n%s", notWant, out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestRunChangelog(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  changelogOptions
+		gen   Generator
+		setup func(t *testing.T)
+		// want is the output, or the changelog file with prepend.
+		want string
+	}{
+		{
+			name: "unreleased",
+			opts: changelogOptions{version: changelogUnreleased},
+			want: "## [Unreleased]\n\n### Added\n- Add a dark theme\n\n### Fixed\n- Keep the toggle in sync\n",
+		},
+		{
+			name: "version and date",
+			opts: changelogOptions{version: "1.3.0", date: "2025-03-01"},
+			want: "## [1.3.0] - 2025-03-01\n\n### Added\n- Add a dark theme\n\n### Fixed\n- Keep the toggle in sync\n",
+		},
+		{
+			name: "summarized",
+			opts: changelogOptions{version: changelogUnreleased},
+			gen:  &fakeGenerator{replies: []string{"Added: Dark mode, kept in sync with the system"}},
+			want: "## [Unreleased]\n\n### Added\n- Dark mode, kept in sync with the system\n",
+		},
+		{
+			name: "template",
+			opts: changelogOptions{version: "1.3.0", date: "2025-03-01", template: "notes.tmpl"},
+			setup: func(t *testing.T) {
+				writeFile(t, "notes.tmpl", "{{.Version}}:{{range .Sections}} {{.Name}}={{len .Entries}}{{end}}\n")
+			},
+			want: "1.3.0: Added=1 Fixed=1\n",
+		},
+		{
+			name: "prepend release",
+			opts: changelogOptions{version: "1.3.0", date: "2025-03-01", prepend: true, file: "CHANGELOG.md"},
+			setup: func(t *testing.T) {
+				writeFile(t, "CHANGELOG.md", "# Changelog\n\n## [1.2.0]\n\n### Fixed\n- Old fix\n")
+			},
+			want: "# Changelog\n\n## [1.3.0] - 2025-03-01\n\n### Added\n- Add a dark theme\n\n" +
+				"### Fixed\n- Keep the toggle in sync\n\n## [1.2.0]\n\n### Fixed\n- Old fix\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := mergeHistory(t)
+			if tt.setup != nil {
+				tt.setup(t)
+			}
+			tt.opts.from, tt.opts.to = base, "HEAD"
+
+			var out bytes.Buffer
+			if err := runChangelog(&out, io.Discard, tt.gen, tt.opts); err != nil {
+				t.Fatal(err)
+			}
+			got := out.String()
+			if tt.opts.prepend {
+				data, err := os.ReadFile(tt.opts.file)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = string(data)
+			}
+			if got != tt.want {
+				t.Errorf("changelog =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunChangelogWithoutUserFacingChanges(t *testing.T) {
+	testRepo(t)
+	commitFile(t, "README.md", "app\n", "chore: initial commit")
+	base := git(t, "rev-parse", "HEAD")
+	commitFile(t, "ci.yml", "on: push\n", "ci: run the tests on push")
+
+	var out, status bytes.Buffer
+	if err := runChangelog(&out, &status, nil, changelogOptions{from: base, to: "HEAD", version: changelogUnreleased}); err != nil {
+		t.Fatal(err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("output = %q, want none", out.String())
+	}
+	if !strings.Contains(status.String(), "No user-facing changes in 1 commit(s).") {
+		t.Errorf("status = %q", status.String())
+	}
+}
+
+func TestChangelogFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{name: "no from", args: nil, wantErr: "--from is required"},
+		{name: "prepend with template", args: []string{"--from", "HEAD", "--prepend", "--template", "notes.tmpl"}, wantErr: "--prepend cannot be combined with --template"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			cmd := newChangelogCmd(&ai.Config{})
+			cmd.SetArgs(tt.args)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
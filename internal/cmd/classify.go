@@ -0,0 +1,74 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/yourorg/arc-commit/internal/classify"
+	"github.com/yourorg/arc-commit/internal/config"
+	"github.com/yourorg/arc-commit/internal/diff"
+	"github.com/yourorg/arc-commit/internal/prompt"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// classifyRulesKey is the config key holding extra classification patterns,
+// e.g. "classify-rules: {test: ['*_spec.rb']}".
+const classifyRulesKey = "classify-rules"
+
+// classifier builds the file classifier from the classify-rules in the
+// config files. Repository rules take precedence over global ones, and both
+// over the defaults.
+func (s *settings) classifier() (*classify.Classifier, error) {
+	c, err := classify.New(classifyRules(s.repo), classifyRules(s.global))
+	if err != nil {
+		return nil, errors.NewCLIError("invalid " + classifyRulesKey).WithCause(err)
+	}
+	return c, nil
+}
+
+// classifyRules reads the classification patterns from a config file.
+func classifyRules(f *config.File) classify.Rules {
+	rules := classify.Rules{}
+	for kind, patterns := range f.StringLists(classifyRulesKey) {
+		rules[classify.Kind(kind)] = patterns
+	}
+	return rules
+}
+
+// fileKinds converts a classification for the prompt.
+func fileKinds(files []classify.File) []prompt.FileKind {
+	kinds := make([]prompt.FileKind, len(files))
+	for i, f := range files {
+		kinds[i] = prompt.FileKind{Path: f.Path, Kind: string(f.Kind)}
+	}
+	return kinds
+}
+
+// printClassification shows how the staged files are classified, for
+// debugging classify-rules.
+func printClassification(out io.Writer, c *classify.Classifier) error {
+	rawDiff, err := getStagedDiff()
+	if err != nil {
+		return errors.NewCLIError("failed to get diff").WithCause(err)
+	}
+	files := c.Diff(diff.Parse(rawDiff))
+	if len(files) == 0 {
+		return errors.NewCLIError("no staged changes found").
+			WithHint("Stage changes first: git add <files>")
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	for _, f := range files {
+		fmt.Fprintf(tw, "%s\t%s\n", f.Kind, f.Path)
+	}
+	tw.Flush()
+
+	if t := classify.SuggestedType(files); t != "" {
+		fmt.Fprintf(out, "\nSuggested type: %s\n", t)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/arc-commit/internal/classify"
+	"github.com/yourorg/arc-commit/internal/config"
+)
+
+func TestPrintClassification(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    []string
+		rules    map[string]any
+		want     []string
+		wantType string
+		wantErr  bool
+	}{
+		{
+			name:     "docs only",
+			files:    []string{"README.md", "docs/guide.md"},
+			want:     []string{"docs  README.md", "docs  docs/guide.md"},
+			wantType: "docs",
+		},
+		{
+			name:  "mixed",
+			files: []string{"app.go", "app_test.go"},
+			want:  []string{"source  app.go", "test    app_test.go"},
+		},
+		{
+			name:     "configured rules",
+			files:    []string{"user_spec.rb"},
+			rules:    map[string]any{"test": []any{"*_spec.rb"}},
+			want:     []string{"test  user_spec.rb"},
+			wantType: "test",
+		},
+		{
+			name:    "nothing staged",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			for _, path := range tt.files {
+				writeFile(t, path, "content\n")
+				git(t, "add", path)
+			}
+			repo := &config.File{Values: map[string]any{classifyRulesKey: tt.rules}}
+			c, err := classify.New(classifyRules(repo))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var out bytes.Buffer
+			err = printClassification(&out, c)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("printClassification() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("output does not contain %q:\n%s", want, out.String())
+				}
+			}
+			_, suggested, _ := strings.Cut(out.String(), "Suggested type: ")
+			if got := strings.TrimSpace(suggested); got != tt.wantType {
+				t.Errorf("suggested type %q, want %q", got, tt.wantType)
+			}
+		})
+	}
+}
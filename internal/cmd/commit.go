@@ -5,13 +5,24 @@ package cmd
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"slices"
+	"strconv"
 	"strings"
+	"text/template"
+	"time"
+	"unicode"
 
 	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-commit/internal/classify"
+	"github.com/yourorg/arc-commit/internal/config"
+	"github.com/yourorg/arc-commit/internal/diff"
+	commitmsg "github.com/yourorg/arc-commit/internal/message"
 	"github.com/yourorg/arc-commit/internal/prompt"
 	"github.com/yourorg/arc-sdk/ai"
 	"github.com/yourorg/arc-sdk/errors"
@@ -20,9 +31,20 @@ import (
 // newCommitCmd creates the commit subcommand.
 func newCommitCmd(aiCfg *ai.Config) *cobra.Command {
 	var (
-		autoYes bool
-		dryRun  bool
-		model   string
+		opts         commitOptions
+		model        string
+		dumpCfg      bool
+		classifyOnly bool
+		profile      string
+		temperature  float64
+		emitEvents   bool
+
+		checkModelFirst bool
+		templatePerType bool
+		patch           bool
+		noSign          bool
+		provider        providerOptions
+		redaction       redactOptions
 	)
 
 	cmd := &cobra.Command{
@@ -45,142 +67,1043 @@ This command provides a guided workflow:
   arc-commit commit --dry-run
 
   # Override the default model
-  arc-commit commit --model claude-sonnet-4-5-20250929`,
+  arc-commit commit --model claude-sonnet-4-5-20250929
+
+  # Never send the diff to an AI provider
+  arc-commit commit --no-ai
+
+  # Show which settings are in effect and where they come from
+  arc-commit commit --dump-config
+
+  # Pick up the message from a run that was interrupted before committing
+  arc-commit commit --resume`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			settings, err := resolveConfig(cmd)
+			if err != nil {
+				return err
+			}
+			if dumpCfg {
+				dumpConfig(cmd.OutOrStdout(), cmd.Flags(), settings)
+				return nil
+			}
+			opts.stageHunks = opts.stageHunks || patch
+			if noSign {
+				opts.signKey = noSigningKey
+			}
+			if opts.ticket != "" && opts.ticketFormat == "" {
+				opts.ticketFormat = defaultTicketFormat
+			}
+
+			// Only a configured temperature overrides the provider default
+			if settings.sources["temperature"] != config.SourceDefault {
+				opts.temperature = &temperature
+			}
+			if err := opts.validate(); err != nil {
+				return err
+			}
+			if err := validateProvider(provider); err != nil {
+				return err
+			}
+			if opts.classifier, err = settings.classifier(); err != nil {
+				return err
+			}
+			if templatePerType {
+				opts.bodyTemplates = settings.bodyTemplates()
+			}
+			if classifyOnly {
+				return printClassification(cmd.OutOrStdout(), opts.classifier)
+			}
+			if opts.trailers, err = extraTrailers(opts.coAuthors, opts.trailerLines, opts.signoff); err != nil {
+				return err
+			}
+			if opts.changelogFile != "" {
+				opts.changelogEntry = true
+			}
+
+			// With --emit-events stdout carries the events and the human
+			// flow moves to stderr
+			out := cmd.OutOrStdout()
+			if emitEvents {
+				if opts.subjectOnly || opts.outputTemplate != "" || opts.output == outputJSON || opts.candidates > 1 {
+					return errors.NewCLIError("--emit-events cannot be combined with --output-subject-only, --output-template-file, --output json or --candidates")
+				}
+				opts.events = newEventWriter(out)
+				out = cmd.ErrOrStderr()
+			}
+
 			// Build effective config with flag overrides
 			cfg := *aiCfg
-			if model != "" {
-				cfg.DefaultModel = model
+			opts.baseModel = effectiveModel(cfg.DefaultModel)
+
+			// Create the AI generator, unless the diff must stay local
+			if opts.sensitive && !opts.noAI && !provider.local() {
+				fmt.Fprintln(out, "Sensitive repository: the diff will not be sent to an AI provider.")
+				opts.noAI = true
+			}
+			var gen Generator
+			if !opts.noAI {
+				gen, opts.model, err = newGenerator(&cfg, provider, model)
+				if err != nil {
+					return err
+				}
+				if gen, err = withRedaction(gen, settings, redaction, out); err != nil {
+					return err
+				}
+				gen = &retryingGenerator{
+					gen:        gen,
+					maxRetries: opts.maxRetries,
+					budget:     newRetryBudget(opts.retryBudget),
+					out:        out,
+					backoff:    time.Second,
+				}
+				if checkModelFirst {
+					if err := checkModel(out, gen, provider, effectiveModel(opts.model)); err != nil {
+						return err
+					}
+				}
 			}
 
-			return runInteractiveCommit(&cfg, autoYes, dryRun)
+			if opts.outputTemplate != "" || opts.output == outputJSON {
+				return printDraftJSON(cmd, gen, opts)
+			}
+			if opts.exitAfterGenerate {
+				return writeDraft(cmd, gen, opts)
+			}
+
+			if opts.subjectOnly {
+				// Progress goes to stderr so stdout carries only the subject
+				d, err := draftMessage(gen, opts, bufio.NewReader(cmd.InOrStdin()), cmd.ErrOrStderr())
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), subjectLine(d.message, opts.noType))
+				return nil
+			}
+
+			err = runInteractiveCommit(gen, opts, cmd.InOrStdin(), out)
+			if err != nil {
+				opts.events.emit(event{Event: eventFinished, Status: statusError, Error: err.Error()})
+			}
+			return err
 		},
 	}
 
-	cmd.Flags().BoolVarP(&autoYes, "yes", "y", false, "Skip confirmation prompt")
-	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Generate message but don't commit")
+	cmd.Flags().BoolVarP(&opts.autoYes, "yes", "y", false, "Skip confirmation prompt")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Generate message but don't commit")
+	cmd.Flags().StringVar(&opts.writePath, "write", "", "Write the final message to this file instead of committing, e.g. for git commit -F")
+	cmd.Flags().BoolVar(&opts.overwrite, "overwrite", false, "Let --write replace an existing file")
+	cmd.Flags().BoolVar(&opts.exitAfterGenerate, "exit-after-generate", false, "With --write, write the first generated message without any prompts and exit, e.g. from a hook (replaces the file)")
+	cmd.Flags().StringVar(&opts.intent, "intent", "", "The author's own description of the change, which the message keeps, with its issue references")
+	cmd.Flags().MarkHidden("intent")
+	cmd.Flags().BoolVar(&opts.subjectOnly, "output-subject-only", false, "Print only the generated subject line to stdout and exit, e.g. to name a branch")
+	cmd.Flags().StringVar(&opts.outputTemplate, "output-template-file", "", "Print the generated message as JSON rendered with this Go template, with run metadata, and exit")
+	cmd.Flags().StringVar(&opts.output, "output", outputText, "Output format: text runs the interactive workflow, json prints the generated message, model, token usage, timing and diff stats as JSON without prompts and exits")
+	cmd.Flags().BoolVar(&opts.noType, "no-type", false, "With --output-subject-only, drop the type prefix and print a branch-friendly slug")
+	cmd.Flags().BoolVar(&opts.resume, "resume", false, "Resume an interrupted session if the staged diff is unchanged")
+	cmd.Flags().BoolVar(&opts.noAI, "no-ai", false, "Build the message from the diff without calling an AI provider")
+	cmd.Flags().BoolVar(&opts.networkFallback, "no-network-fallback-message", false, "When the AI provider cannot be reached, build the message from the diff as with --no-ai")
+	cmd.Flags().BoolVar(&opts.sensitive, "sensitive-repo", false, "Refuse to send anything to external AI providers (forced by sensitive-repo in "+config.FileName+")")
+	cmd.Flags().BoolVar(&opts.review, "review", false, "Have the model point out obvious problems in the diff, e.g. debug prints, before writing the message")
+	cmd.Flags().BoolVar(&opts.twoPhase, "two-phase", false, "Have the model outline the key changes first, then write the message from the outline")
+	cmd.Flags().BoolVar(&opts.diffStats, "verbose-diff-stats", false, "Report on stderr how much of the staged diff the model receives and the prompt size")
+	cmd.Flags().BoolVar(&opts.verbose, "verbose", false, "Show intermediate results, such as the --two-phase outline")
+	cmd.Flags().BoolVar(&opts.weightBySize, "weight-by-size", false, "Order the prompt by change size so the message leads with the biggest changes")
+	cmd.Flags().BoolVar(&opts.ownershipScope, "scope-from-ownership", false, "Use the CODEOWNERS area owning most changed files as the scope, else their shared directory")
+	cmd.Flags().StringVar(&opts.fromBranch, "from-branch", "", "Squash the commits a branch adds on top of the current one and commit them as one")
+	cmd.Flags().BoolVar(&opts.stageHunks, "stage-hunks", false, "Stage changes hunk by hunk with git add -p before generating the message")
+	cmd.Flags().BoolVarP(&patch, "patch", "p", false, "Like git commit --patch: same as --stage-hunks")
+	cmd.Flags().BoolVarP(&opts.all, "all", "a", false, "Like git commit -a: include changes to tracked files that are not staged, staging them when committing")
+	cmd.Flags().IntVar(&opts.maxFilesInPrompt, "max-files-in-prompt", 0, "Show the model full diffs for only the N largest files and stats for the rest (0 means all)")
+	cmd.Flags().IntVar(&opts.tokenBudget, "prompt-token-budget", 30000, "Estimated tokens of diff sent as is; beyond that lockfiles and huge files become stats and the rest is outlined in parts (0 disables)")
+	cmd.Flags().BoolVar(&opts.selectHunks, "select-hunks", false, "Pick which hunks the model sees; everything staged is still committed")
+	cmd.Flags().BoolVar(&opts.ignoreWhitespace, "ignore-whitespace", false, "Leave whitespace-only changes out of the prompt")
+	cmd.Flags().BoolVar(&opts.includeSubmodules, "include-submodule-changes", false, "Describe submodule bumps using the submodule's commit log")
+	cmd.Flags().BoolVar(&opts.enclosingContext, "diff-context-from-blame", false, "Add the code around each hunk (e.g. the enclosing function) for small diffs")
+	cmd.Flags().IntVar(&opts.contextMaxChanges, "diff-context-max-changes", 30, "Largest diff, in changed lines, that gets surrounding code")
+	cmd.Flags().IntVar(&opts.contextLines, "diff-context-lines", 40, "How far above each hunk to look for the enclosing definition")
+	cmd.Flags().BoolVar(&opts.fileTree, "prompt-include-file-tree", false, "Show the model the layout of the directories holding changed files")
+	cmd.Flags().IntVar(&opts.fileTreeDepth, "file-tree-depth", 2, "How many levels below each changed directory the file tree lists")
+	cmd.Flags().IntVar(&opts.fileTreeMaxEntries, "file-tree-max-entries", 80, "Most lines of file tree included in the prompt")
+	cmd.Flags().BoolVar(&opts.summarizeDeps, "summarize-dependencies", false, "List dependency version changes from go.mod and package.json in the body")
+	cmd.Flags().BoolVar(&opts.summarizeConflicts, "summarize-conflicts", false, "When concluding a merge, summarize it and the files that had conflicts")
+	cmd.Flags().BoolVar(&opts.stripSubjectMarkdown, "strip-markdown-from-subject", false, "Remove backticks and bold or italic markers from generated subjects; the body keeps its markdown")
+	cmd.Flags().StringVar(&opts.languageStyle, "prompt-language-style", "", "Tone of the message: "+strings.Join(prompt.LanguageStyles, ", ")+" (default: clear and professional)")
+	cmd.Flags().BoolVar(&opts.keepArtifacts, "no-strip-artifacts", false, "Keep model preambles, code fences and quotes around the message")
+	cmd.Flags().Float64Var(&temperature, "temperature", 0, "Sampling temperature; higher is more varied (default: the provider's)")
+	cmd.Flags().IntVar(&opts.maxRetries, "max-retries", 2, "Retries for each request that fails to reach the AI provider")
+	cmd.Flags().IntVar(&opts.retryBudget, "retry-budget", 0, "Most retries across all requests in one run, e.g. on a flaky connection (0 means unlimited)")
+	cmd.Flags().IntVar(&opts.maxTokens, "prompt-max-tokens", 0, "Cap the model's output tokens (0 uses the provider default); --annotate-why and --commit-template-per-type bodies need more")
+	cmd.Flags().BoolVar(&templatePerType, "commit-template-per-type", false, "Ask for a body structured for the commit type, e.g. root cause and fix for fix: (see "+bodyTemplatesKey+" in "+config.FileName+")")
+	cmd.Flags().BoolVar(&opts.annotateWhy, "annotate-why", false, "Require the body to explain why the change was made")
+	cmd.Flags().BoolVar(&opts.testPlan, "include-test-plan", false, "Add a Test-plan trailer saying how the change is verified, based on the changed tests")
+	cmd.Flags().IntVar(&opts.minWhyLength, "min-why-length", commitmsg.DefaultMinWhyLength, "Shortest body accepted as a rationale with --annotate-why")
+	cmd.Flags().StringSliceVar(&opts.disabledRules, "disable-rule", nil, "Validation rules to skip: "+strings.Join(commitmsg.RuleNames, ", "))
+	cmd.Flags().StringVar(&opts.subjectCase, "commit-subject-case", commitmsg.SubjectCasePreserve, "Case of the subject's first letter after the type: "+strings.Join(commitmsg.SubjectCases, ", "))
+	cmd.Flags().IntVar(&opts.maxSubjectLength, "max-subject-length", commitmsg.DefaultMaxSubjectLength, "Longest header accepted, in characters")
+	cmd.Flags().StringSliceVar(&opts.scopes, "scopes", nil, "Scopes the header may use, e.g. cli,config (default: any)")
+	cmd.Flags().StringSliceVar(&opts.excludePaths, "exclude-path", nil, "Leave files matching these patterns out of the prompt, e.g. generated code (same patterns as classify-rules)")
+	cmd.Flags().StringSliceVar(&opts.generatedPaths, "generated-path", defaultGeneratedPaths, "Lockfiles and generated files, shown to the model as stats only (same patterns as classify-rules)")
+	cmd.Flags().BoolVar(&opts.includeAll, "include-all", false, "Send every staged file in full, ignoring --exclude-path and --generated-path")
+	cmd.Flags().StringVar(&opts.instructions, "prompt-instructions", "", "Team guidelines added to the prompt, e.g. \"Name the affected service in the subject\"")
+	cmd.Flags().IntVar(&opts.styleExamples, "style-examples", 0, "Show the model the last N commit messages so it matches the project's style, e.g. gitmoji or ticket prefixes (0 disables)")
+	cmd.Flags().StringSliceVar(&opts.styleExcludes, "style-examples-exclude", defaultStyleExcludes, "Regular expressions for commit messages never used as style examples")
+	cmd.Flags().StringVar(&opts.ticket, "ticket", "", "Ticket the change is for, e.g. JIRA-1234 or #42 (default: found in the branch name with --ticket-pattern)")
+	cmd.Flags().StringVar(&opts.ticketPattern, "ticket-pattern", defaultTicketPattern, "Regular expression finding the ticket in the branch name; the first matching group is the ticket")
+	cmd.Flags().StringVar(&opts.ticketFormat, "ticket-format", "", "Add the ticket to the message: a trailer such as \"Refs: {ticket}\", or a header such as \"[{ticket}] {subject}\" (default: "+defaultTicketFormat+" with --ticket, else off)")
+	cmd.Flags().BoolVar(&opts.ticketLookup, "ticket-lookup", false, "Fetch the ticket's title for the prompt from GitHub (gh), Jira ($JIRA_BASE_URL) or Linear ($LINEAR_API_KEY)")
+	cmd.Flags().StringVar(&opts.promptTemplate, "prompt-template", "", "Go text/template file replacing the prompts, relative to the repository root, e.g. .arc/commit-prompt.tmpl")
+	cmd.Flags().BoolVar(&opts.strict, "strict", false, "Refuse to commit messages with validation errors, without asking the model to fix them")
+	cmd.Flags().IntVar(&opts.validationRetries, "validation-retries", 2, "Times to hand validation errors back to the model for a corrected message (0 disables)")
+	cmd.Flags().BoolVar(&opts.detectMismatch, "detect-intent-mismatch", false, "Warn when the type contradicts the diff, e.g. feat: for a change that only deletes code (blocks with --strict)")
+	cmd.Flags().BoolVar(&opts.failOnInvalid, "fail-on-invalid", false, "With --dry-run, exit non-zero when the message has validation errors, e.g. as a CI check")
+	cmd.Flags().StringVar(&opts.diversify, "diversify", diversifyLow, "How hard [n] pushes for a different message: "+strings.Join(diversifyLevels, ", "))
+	cmd.Flags().IntVar(&opts.candidates, "candidates", 1, "Generate this many alternative messages at once and pick one; [n] does the same")
+	cmd.Flags().IntVar(&opts.maxRegenerations, "max-regenerations", 0, "Most times [n] may regenerate the message (0 means unlimited)")
+	cmd.Flags().BoolVar(&opts.editFeedback, "interactive-edit-feedback", false, "Write [n] feedback in the editor, starting from the previous feedback")
+	cmd.Flags().StringVar(&opts.feedbackQuestion, "feedback-question", defaultFeedbackQuestion, "Question asked for feedback when regenerating")
+	cmd.Flags().StringArrayVar(&opts.coAuthors, "co-author", nil, "Credit a co-author with a Co-authored-by trailer, e.g. \"Jane Doe <jane@example.com>\" (repeatable)")
+	cmd.Flags().StringArrayVar(&opts.trailerLines, "trailer", nil, "Add a trailer to the message, e.g. \"Reviewed-by: Jane Doe <jane@example.com>\" (repeatable)")
+	cmd.Flags().BoolVarP(&opts.signoff, "signoff", "s", false, "Add a Signed-off-by trailer for your git identity, like git commit --signoff")
+	cmd.Flags().StringArrayVar(&opts.feedbackOptions, "feedback-option", nil, "Canned feedback offered as a numbered quick pick when regenerating (repeatable)")
+	cmd.Flags().BoolVar(&opts.noStream, "no-stream", false, "Do not show the message as it is generated in a terminal or as --emit-events token events, or a spinner when the provider cannot stream")
+	cmd.Flags().BoolVar(&opts.noTUI, "no-tui", false, "Show the message and an action menu instead of the full-screen review with the scrollable diff")
+	cmd.Flags().BoolVar(&opts.simplePrompt, "simple-prompt", false, "Use the [y]es/[n]o/... letter prompt even in a terminal that supports the arrow-key menu")
+	cmd.Flags().BoolVar(&opts.requireEdit, "require-edit", false, "Refuse to commit an edited message that was saved unchanged")
+	cmd.Flags().StringVar(&opts.separatorStyle, "separator-style", separatorEquals, "Lines around the message preview: "+strings.Join(separatorStyles, ", "))
+	cmd.Flags().BoolVar(&opts.keepBlankLines, "keep-blank-lines-in-body", false, "Preserve runs of blank lines in the body instead of collapsing them")
+	cmd.Flags().StringVar(&opts.lineEndings, "line-endings", commitmsg.LineEndingsLF, "Line endings of the committed message: "+strings.Join(commitmsg.LineEndingModes, ", "))
+	cmd.Flags().StringVar(&opts.encoding, "output-encoding", "", "Encoding to write the message in and record in the commit (default: i18n.commitEncoding, else UTF-8)")
+	cmd.Flags().BoolVar(&opts.basedOnFooter, "abbrev-commit-in-footer", false, "Add a Based-on trailer with the short hash of the merge-base with --footer-base")
+	cmd.Flags().StringVar(&opts.footerBase, "footer-base", "main", "Branch the work started from, for --abbrev-commit-in-footer")
+	cmd.Flags().BoolVar(&opts.runMetadata, "append-run-metadata", false, "Add a Generated-by trailer naming the model and time, for auditing")
+	cmd.Flags().BoolVar(&opts.changelogEntry, "changelog-entry", false, "Derive a Keep a Changelog entry from the message")
+	cmd.Flags().StringVar(&opts.changelogFile, "changelog-file", "", "Changelog to add the entry to under [Unreleased] (implies --changelog-entry)")
+	cmd.Flags().BoolVar(&opts.stageChangelog, "stage-changelog", false, "Stage the updated changelog so it is part of the commit")
+	cmd.Flags().StringSliceVar(&opts.skipHooks, "skip-hook", nil, "Ask cooperating hooks to skip themselves, via "+skipHooksEnv+" (repeatable)")
+	cmd.Flags().StringVarP(&opts.signKey, "gpg-sign", "S", "", "Sign the commit, optionally with the given key id (GPG or SSH per gpg.format)")
+	cmd.Flags().Lookup("gpg-sign").NoOptDefVal = defaultSigningKey
+	cmd.Flags().BoolVar(&noSign, "no-gpg-sign", false, "Do not sign the commit, overriding --gpg-sign and commit.gpgsign")
+	cmd.Flags().BoolVar(&opts.verifySignature, "verify-signature", false, "After a signed commit, check the signature with git verify-commit")
+	cmd.Flags().IntVar(&opts.countGuard, "commit-count-guard", 50, "Ask before committing more files than this, to catch accidental staging (0 disables)")
+	cmd.Flags().BoolVar(&opts.autoAcceptValid, "auto-accept-valid", false, "Commit without prompting when the change is small and the message passes validation")
+	cmd.Flags().IntVar(&opts.autoAcceptMaxFiles, "auto-accept-max-files", 3, "Most files a change may touch to be auto-accepted")
+	cmd.Flags().IntVar(&opts.autoAcceptMaxLines, "auto-accept-max-lines", 20, "Most changed lines a change may have to be auto-accepted")
 	cmd.Flags().StringVarP(&model, "model", "m", "", "Model to use (default: "+prompt.CommitMessageModel+")")
+	cmd.RegisterFlagCompletionFunc("model", completeModels)
+	addProviderFlags(cmd.Flags(), &provider)
+	addRedactFlags(cmd.Flags(), &redaction)
+	cmd.Flags().BoolVar(&checkModelFirst, "check-model", false, "Check that the provider serves the model before starting, caching success for a day")
+	cmd.Flags().BoolVar(&opts.confirmModelSwitch, "confirm-model-switch", false, "Ask before using a --model that costs more per call than the default")
+	cmd.Flags().StringVar(&profile, "profile", "", "Apply a preset of settings from the profiles in the config files")
+	cmd.RegisterFlagCompletionFunc("profile", completeProfiles)
+	cmd.Flags().BoolVar(&classifyOnly, "classify", false, "Print how the staged files are classified (source, test, docs, ...) and exit")
+	cmd.Flags().BoolVar(&emitEvents, "emit-events", false, "Stream progress as JSON lines on stdout for editor integrations; the usual output goes to stderr")
+	cmd.Flags().BoolVar(&dumpCfg, "dump-config", false, "Print the effective configuration and where each value came from")
 
 	return cmd
 }
 
-// runInteractiveCommit implements the interactive commit workflow.
-func runInteractiveCommit(cfg *ai.Config, autoYes, dryRun bool) error {
-	// 1. Check for staged changes
-	fmt.Println("Checking for staged changes...")
-	if err := checkStagedChanges(); err != nil {
-		return errors.NewCLIError("no staged changes found").
-			WithHint("Stage changes first: git add <files>")
-	}
+// commitOptions holds the flag values that shape the commit workflow.
+type commitOptions struct {
+	autoYes bool
+	dryRun  bool
+	resume  bool
 
-	// 2. Get diff
-	fmt.Println("Generating diff...")
-	diff, err := getStagedDiff()
-	if err != nil {
-		return errors.NewCLIError("failed to get diff").WithCause(err)
-	}
+	subjectOnly    bool
+	noType         bool
+	outputTemplate string
+	// output is outputText or outputJSON.
+	output string
+
+	writePath         string
+	overwrite         bool
+	exitAfterGenerate bool
+	// intent is what the author already wrote about the change, e.g. with
+	// git commit -m; the message keeps it and its issue references.
+	intent string
+
+	noAI            bool
+	networkFallback bool
+	sensitive       bool
+	review          bool
 
-	if len(diff) == 0 {
-		return errors.NewCLIError("no changes to commit").
-			WithHint("Stage changes first: git add <files>")
+	confirmModelSwitch bool
+
+	weightBySize         bool
+	maxFilesInPrompt     int
+	twoPhase             bool
+	verbose              bool
+	diffStats            bool
+	ownershipScope       bool
+	ignoreWhitespace     bool
+	selectHunks          bool
+	stageHunks           bool
+	all                  bool
+	fromBranch           string
+	includeSubmodules    bool
+	summarizeConflicts   bool
+	summarizeDeps        bool
+	maxTokens            int
+	maxRetries           int
+	retryBudget          int
+	temperature          *float64
+	keepArtifacts        bool
+	stripSubjectMarkdown bool
+	languageStyle        string
+	maxSubjectLength     int
+	validationRetries    int
+	candidates           int
+	noStream             bool
+	tokenBudget          int
+	generatedPaths       []string
+	includeAll           bool
+	scopes               []string
+	excludePaths         []string
+	instructions         string
+	promptTemplate       string
+	styleExamples        int
+	styleExcludes        []string
+	ticket               string
+	ticketPattern        string
+	ticketFormat         string
+	ticketLookup         bool
+	coAuthors            []string
+	trailerLines         []string
+	signoff              bool
+	// trailers are added to every message: --co-author, --trailer and
+	// --signoff, in that order.
+	trailers []commitmsg.Trailer
+
+	enclosingContext  bool
+	contextMaxChanges int
+	contextLines      int
+
+	fileTree           bool
+	fileTreeDepth      int
+	fileTreeMaxEntries int
+
+	annotateWhy    bool
+	testPlan       bool
+	minWhyLength   int
+	disabledRules  []string
+	subjectCase    string
+	strict         bool
+	detectMismatch bool
+	failOnInvalid  bool
+
+	maxRegenerations int
+	editFeedback     bool
+	feedbackQuestion string
+	feedbackOptions  []string
+	diversify        string
+	requireEdit      bool
+	simplePrompt     bool
+	noTUI            bool
+	separatorStyle   string
+
+	keepBlankLines  bool
+	lineEndings     string
+	encoding        string
+	signKey         string
+	verifySignature bool
+	skipHooks       []string
+
+	basedOnFooter bool
+	footerBase    string
+	runMetadata   bool
+
+	changelogEntry bool
+	changelogFile  string
+	stageChangelog bool
+
+	countGuard int
+
+	autoAcceptValid    bool
+	autoAcceptMaxFiles int
+	autoAcceptMaxLines int
+
+	// classifier sorts changed files into kinds; built from the config files.
+	classifier *classify.Classifier
+	// bodyTemplates maps commit types to body structures, with
+	// --commit-template-per-type.
+	bodyTemplates map[string]string
+	// model is the model messages are generated with, for --append-run-metadata.
+	model string
+	// baseModel is the model used without --model, for --confirm-model-switch.
+	baseModel string
+	// events receives the --emit-events protocol; nil when it is off.
+	events *eventWriter
+}
+
+// validate rejects invalid flag combinations and values.
+func (o commitOptions) validate() error {
+	if err := checkRuleNames(o.disabledRules); err != nil {
+		return err
+	}
+	if err := checkSubjectCase(o.subjectCase); err != nil {
+		return err
+	}
+	if !slices.Contains(commitmsg.LineEndingModes, o.lineEndings) {
+		return errors.NewCLIError("invalid --line-endings value: " + o.lineEndings).
+			WithHint("Use one of: " + strings.Join(commitmsg.LineEndingModes, ", "))
+	}
+	if o.temperature != nil && *o.temperature < 0 {
+		return errors.NewCLIError("--temperature must not be negative")
+	}
+	if o.languageStyle != "" && !slices.Contains(prompt.LanguageStyles, o.languageStyle) {
+		return errors.NewCLIError("invalid --prompt-language-style value: " + o.languageStyle).
+			WithHint("Use one of: " + strings.Join(prompt.LanguageStyles, ", "))
+	}
+	if !slices.Contains(diversifyLevels, o.diversify) {
+		return errors.NewCLIError("invalid --diversify value: " + o.diversify).
+			WithHint("Use one of: " + strings.Join(diversifyLevels, ", "))
+	}
+	if !slices.Contains(separatorStyles, o.separatorStyle) {
+		return errors.NewCLIError("invalid --separator-style value: " + o.separatorStyle).
+			WithHint("Use one of: " + strings.Join(separatorStyles, ", "))
+	}
+	if o.candidates < 1 {
+		return errors.NewCLIError("--candidates must be at least 1")
+	}
+	if !slices.Contains(outputFormats, o.output) {
+		return errors.NewCLIError("invalid --output value: " + o.output).
+			WithHint("Use one of: " + strings.Join(outputFormats, ", "))
+	}
+	if o.candidates > 1 && (o.autoYes || o.subjectOnly || o.outputTemplate != "" || o.output == outputJSON || o.exitAfterGenerate) {
+		return errors.NewCLIError("--candidates needs the interactive prompt").
+			WithHint("Drop --yes, --output-subject-only, --output-template-file, --output json or --exit-after-generate")
+	}
+	if o.validationRetries < 0 {
+		return errors.NewCLIError("--validation-retries must not be negative")
+	}
+	if o.maxSubjectLength < 1 {
+		return errors.NewCLIError("--max-subject-length must be at least 1")
+	}
+	if o.countGuard < 0 {
+		return errors.NewCLIError("--commit-count-guard must not be negative")
+	}
+	if o.fileTreeDepth < 1 || o.fileTreeMaxEntries < 1 {
+		return errors.NewCLIError("--file-tree-depth and --file-tree-max-entries must be at least 1")
+	}
+	if o.tokenBudget < 0 {
+		return errors.NewCLIError("--prompt-token-budget must not be negative")
+	}
+	if o.maxFilesInPrompt < 0 {
+		return errors.NewCLIError("--max-files-in-prompt must not be negative")
+	}
+	if o.maxRetries < 0 || o.retryBudget < 0 {
+		return errors.NewCLIError("--max-retries and --retry-budget must not be negative")
+	}
+	if o.maxRegenerations < 0 {
+		return errors.NewCLIError("--max-regenerations must not be negative")
+	}
+	if o.fromBranch != "" && (o.dryRun || o.subjectOnly) {
+		// Squashing stages the branch, which a preview must not leave behind
+		return errors.NewCLIError("--from-branch cannot be combined with --dry-run or --output-subject-only")
+	}
+	if o.all && (o.stageHunks || o.fromBranch != "" || o.writePath != "") {
+		// --write leaves the committing, and so the staging, to others
+		return errors.NewCLIError("--all cannot be combined with --patch, --stage-hunks, --from-branch or --write")
+	}
+	if o.writePath != "" && (o.dryRun || o.subjectOnly) {
+		return errors.NewCLIError("--write cannot be combined with --dry-run or --output-subject-only")
+	}
+	if o.outputTemplate != "" && (o.subjectOnly || o.writePath != "" || o.fromBranch != "") {
+		return errors.NewCLIError("--output-template-file cannot be combined with --output-subject-only, --write or --from-branch")
+	}
+	if o.output == outputJSON && (o.subjectOnly || o.outputTemplate != "" || o.writePath != "" || o.fromBranch != "") {
+		return errors.NewCLIError("--output json cannot be combined with --output-subject-only, --output-template-file, --write or --from-branch")
 	}
+	if o.exitAfterGenerate && o.writePath == "" {
+		return errors.NewCLIError("--exit-after-generate requires --write").
+			WithHint("Name the file to fill, e.g. --write .git/COMMIT_EDITMSG")
+	}
+	if o.overwrite && o.writePath == "" {
+		return errors.NewCLIError("--overwrite requires --write")
+	}
+	if o.ticketFormat != "" {
+		if err := checkTicketFormat(o.ticketFormat); err != nil {
+			return errors.NewCLIError("invalid --ticket-format").WithCause(err).
+				WithHint("Use a trailer such as \"Refs: {ticket}\" or a header such as \"[{ticket}] {subject}\"")
+		}
+	}
+	if o.stageChangelog && o.changelogFile == "" {
+		return errors.NewCLIError("--stage-changelog requires --changelog-file")
+	}
+	return nil
+}
 
-	// 3. Create AI client and service
-	client, err := ai.NewClient(*cfg)
+// runInteractiveCommit implements the interactive commit workflow, reading
+// choices from in and writing all output to out. Messages are generated with
+// gen; a nil gen builds them from the diff without AI.
+func runInteractiveCommit(gen Generator, opts commitOptions, in io.Reader, out io.Writer) error {
+	reader := bufio.NewReader(in)
+	d, err := draftMessage(gen, opts, reader, out)
 	if err != nil {
-		return errors.NewCLIError("failed to create AI client").WithCause(err)
+		return err
 	}
+	message := d.message
 
-	// Set default model if not specified
-	if cfg.DefaultModel == "" {
-		cfg.DefaultModel = prompt.CommitMessageModel
+	// mismatch explains why the message type looks wrong for the diff, with
+	// --detect-intent-mismatch
+	var kinds []classify.File
+	if opts.detectMismatch {
+		kinds = opts.classifier.Diff(d.changes)
+	}
+	mismatch := func(message string) string {
+		if !opts.detectMismatch {
+			return ""
+		}
+		return intentMismatch(d.changes, kinds, message)
 	}
 
-	service := ai.NewService(client, *cfg)
+	commit := func(message string) error {
+		if opts.strict && commitmsg.HasErrors(validateMessage(message, opts)) {
+			return errors.NewCLIError("message failed validation").
+				WithHint("Fix the errors above or drop --strict")
+		}
+		if reason := mismatch(message); opts.strict && reason != "" {
+			return errors.NewCLIError("message type does not match the change: " + reason).
+				WithHint("Fix the type or drop --strict")
+		}
+		final := assembleMessage(message, opts, gen != nil)
+		enc := commitEncoding(opts.encoding)
+		encoded, err := encodeMessage(final, enc)
+		if err != nil {
+			return errors.NewCLIError("failed to encode the message as " + enc).WithCause(err).
+				WithHint("Edit the message, or commit in UTF-8: --output-encoding " + defaultEncoding)
+		}
+		if opts.writePath != "" {
+			// Nothing is committed, so the changelog is left alone
+			if opts.changelogEntry {
+				changelogEntry(out, final, opts, false)
+			}
+			if err := writeMessageFile(out, opts.writePath, encoded, opts.overwrite); err != nil {
+				return err
+			}
+			opts.events.finished(statusWritten, final)
+			return nil
+		}
+		// The changelog is only touched once the message is final, and put
+		// back if the commit fails
+		var backup *changelogBackup
+		if opts.changelogEntry {
+			if backup, err = backupChangelog(opts); err != nil {
+				return errors.NewCLIError("failed to update changelog").WithCause(err)
+			}
+			if err := changelogEntry(out, final, opts, true); err != nil {
+				backup.restore()
+				return errors.NewCLIError("failed to update changelog").WithCause(err)
+			}
+		}
+		if err := commitAndClearSession(out, encoded, commitArgs(final, opts), commitEnv(opts)); err != nil {
+			if rerr := backup.restore(); rerr != nil {
+				fmt.Fprintf(out, "Warning: could not restore %s: %v\n", opts.changelogFile, rerr)
+			} else if backup != nil {
+				fmt.Fprintf(out, "Restored %s.\n", opts.changelogFile)
+			}
+			return err
+		}
+		if opts.verifySignature {
+			if !signingRequested(opts.signKey) {
+				fmt.Fprintln(out, "Note: signing was not requested; nothing to verify (--verify-signature).")
+			} else if err := verifyCommitSignature(out); err != nil {
+				return err
+			}
+		}
+		opts.events.finished(statusCommitted, final)
+		return nil
+	}
 
-	// 4. Initial message generation
-	fmt.Println("Generating commit message with AI...")
-	message, err := generateCommitMessage(service, diff, "")
-	if err != nil {
-		return errors.NewCLIError("failed to generate commit message").WithCause(err)
+	if len(d.candidates) > 1 {
+		if message, err = pickCandidate(reader, out, d.candidates, opts); err != nil {
+			return err
+		}
+		persistSession(out, d.diffHash, message)
 	}
 
-	// 5. Interactive loop
-	reader := bufio.NewReader(os.Stdin)
+	// 4. Interactive loop
+	arrowMenu := !opts.simplePrompt && opts.events == nil && arrowMenuUsable(in, out)
+	tui := arrowMenu && !opts.noTUI
+	stagedDiff := diff.Format(d.changes)
+	autoAccept := opts.autoAcceptValid
+	regenerations := 0
+	lastFeedback := ""
 	for {
 		// Display message
-		fmt.Println("\n" + strings.Repeat("=", 70))
-		fmt.Println(message)
-		fmt.Println(strings.Repeat("=", 70))
+		printPreview(out, message, opts.separatorStyle)
+		issues := validateMessage(message, opts)
+		printIssues(out, issues)
+		reason := mismatch(message)
+		if reason != "" {
+			fmt.Fprintf(out, "\nWarning: the type may not match the change: %s\n", reason)
+		}
 
 		// Dry run: show and exit
-		if dryRun {
-			fmt.Println("\n(Dry run - no commit created)")
+		if opts.dryRun {
+			if opts.changelogEntry {
+				changelogEntry(out, finalizeMessage(message, opts), opts, false)
+			}
+			fmt.Fprintln(out, "\n(Dry run - no commit created)")
+			if opts.failOnInvalid && commitmsg.HasErrors(issues) {
+				return errors.NewCLIError("generated message failed validation").
+					WithHint("See the errors above; --fail-on-invalid makes this fail the run")
+			}
+			opts.events.finished(statusDryRun, finalizeMessage(message, opts))
 			return nil
 		}
 
 		// Auto-yes: commit without prompting
-		if autoYes {
-			fmt.Println("\nAuto-committing...")
-			return createCommit(message)
+		if opts.autoYes {
+			fmt.Fprintln(out, "\nAuto-committing...")
+			return commit(message)
 		}
 
-		// Prompt user
-		fmt.Print("\n[y]es, [n]o (regenerate), [e]dit, [c]ancel: ")
+		// Auto-accept: commit small, valid changes on the first suggestion only
+		if autoAccept {
+			autoAccept = false
+			if blocker := autoAcceptBlocker(d.changes, message, opts); blocker != "" || reason != "" {
+				if blocker == "" {
+					blocker = "the type may not match the change"
+				}
+				fmt.Fprintf(out, "\nNot auto-accepting: %s.\n", blocker)
+			} else {
+				fmt.Fprintln(out, "\nSmall change with a valid message; auto-committing...")
+				return commit(message)
+			}
+		}
 
-		choice, err := reader.ReadString('\n')
-		if err != nil {
-			return errors.NewCLIError("failed to read input").WithCause(err)
+		// Prompt user, without [n] once the regeneration budget is spent
+		regenLeft := opts.maxRegenerations == 0 || regenerations < opts.maxRegenerations
+		var choice, tuiFeedback string
+		if tui {
+			view := reviewView(stagedDiff, message, issues, reason, opts, gen != nil, regenLeft, regenerations)
+			if choice, tuiFeedback, err = reviewInTUI(reader, out, view); err != nil {
+				// The terminal cannot do full-screen; use the menu
+				tui = false
+			}
+		}
+		if arrowMenu && !tui {
+			if choice, err = selectMenuItem(reader, out, actionMenu(regenLeft), "c"); err != nil {
+				// The terminal cannot do raw input; stay with letters
+				arrowMenu = false
+			}
+		}
+		if !arrowMenu {
+			if regenLeft {
+				fmt.Fprint(out, "\n[y]es, [n]o (regenerate), [e]dit, [t]railers, [p]review, [c]ancel, [?]help: ")
+				opts.events.awaiting(inputAction, "y", "n", "e", "t", "p", "c", "?")
+			} else {
+				fmt.Fprint(out, "\n[y]es, [e]dit, [t]railers, [p]review, [c]ancel, [?]help: ")
+				opts.events.awaiting(inputAction, "y", "e", "t", "p", "c", "?")
+			}
+
+			choice, err = reader.ReadString('\n')
+			if err != nil {
+				return errors.NewCLIError("failed to read input").WithCause(err)
+			}
 		}
 
 		choice = strings.ToLower(strings.TrimSpace(choice))
 
 		switch choice {
 		case "y", "yes":
-			return createCommit(message)
+			if opts.strict && commitmsg.HasErrors(issues) {
+				fmt.Fprintln(out, "\nThe message has validation errors (--strict); regenerate or edit it.")
+				continue
+			}
+			if opts.strict && reason != "" {
+				fmt.Fprintln(out, "\nThe type does not match the change (--strict); regenerate or edit it.")
+				continue
+			}
+			return commit(message)
 
 		case "n", "no":
-			fmt.Print("\nWhat would you like improved? (or press Enter for generic): ")
-			feedback, _ := reader.ReadString('\n')
-			feedback = strings.TrimSpace(feedback)
+			if gen == nil {
+				fmt.Fprintln(out, "\nRegeneration needs AI; edit the message instead.")
+				continue
+			}
+			if !regenLeft {
+				fmt.Fprintf(out, "\nNo regenerations left (--max-regenerations %d); accept, edit or cancel.\n", opts.maxRegenerations)
+				continue
+			}
+
+			var feedback string
+			if tui && inlineFeedback(opts) {
+				// Typed on the status line of the review
+				feedback = tuiFeedback
+			} else if opts.editFeedback {
+				if feedback, err = editFeedback(lastFeedback); err != nil {
+					fmt.Fprintf(out, "\nFeedback not edited: %v\n", err)
+					continue
+				}
+			} else {
+				feedback = askFeedback(reader, out, opts)
+			}
+			lastFeedback = feedback
 
-			fmt.Println("\nRegenerating...")
-			message, err = generateCommitMessage(service, diff, feedback)
+			fmt.Fprintln(out, "\nRegenerating...")
+			regenerations++
+			if opts.candidates > 1 {
+				var messages []string
+				if messages, err = d.generateN(feedback, opts.candidates); err == nil {
+					message = messages[0]
+					if len(messages) > 1 {
+						message, err = pickCandidate(reader, out, messages, opts)
+					}
+				}
+			} else {
+				message, err = d.generate(feedback)
+			}
 			if err != nil {
 				return errors.NewCLIError("failed to regenerate message").WithCause(err)
 			}
+			persistSession(out, d.diffHash, message)
+			if opts.maxRegenerations > 0 {
+				fmt.Fprintf(out, "%d regeneration(s) left.\n", opts.maxRegenerations-regenerations)
+			}
 
 		case "e", "edit":
-			edited, err := editInEditor(message)
+			edited, err := editMessage(reader, out, opts.events, message, opts.requireEdit)
 			if err != nil {
-				return errors.NewCLIError("failed to open editor").WithCause(err)
+				return err
+			}
+			if edited == "" {
+				continue
+			}
+			if opts.strict && commitmsg.HasErrors(validateMessage(edited, opts)) {
+				fmt.Fprintln(out, "\nThe edited message has validation errors (--strict).")
+				message = edited
+				persistSession(out, d.diffHash, message)
+				continue
 			}
-			return createCommit(edited)
+			return commit(edited)
+
+		case "t", "trailers":
+			updated, err := editTrailers(message)
+			if err != nil {
+				fmt.Fprintf(out, "\nTrailers not changed: %v\n", err)
+				continue
+			}
+			message = updated
+			persistSession(out, d.diffHash, message)
+
+		case "p", "preview":
+			printLogPreview(out, assembleMessage(message, opts, gen != nil))
 
 		case "c", "cancel":
-			fmt.Println("\nCommit cancelled.")
+			clearSession()
+			fmt.Fprintln(out, "\nCommit cancelled.")
+			opts.events.finished(statusCancelled, "")
 			return nil
 
+		case "?", "h", "help":
+			left := -1
+			if opts.maxRegenerations > 0 {
+				left = opts.maxRegenerations - regenerations
+			}
+			printMenuHelp(out, opts, gen != nil, left)
+
 		default:
-			fmt.Println("\nInvalid choice. Please enter y/n/e/c.")
+			fmt.Fprintln(out, "\nInvalid choice. Please enter y/n/e/t/p/c, or ? for help.")
 		}
 	}
 }
 
-// generateCommitMessage generates a commit message from diff and optional feedback.
-func generateCommitMessage(service *ai.Service, diff, feedback string) (string, error) {
-	systemPrompt, userPrompt := prompt.CommitMessage(diff, feedback)
+// writeDraft implements --exit-after-generate: it writes the first
+// generated message to the --write file, exactly as it would have been
+// committed, without the interactive loop or a commit. Progress goes to
+// stderr.
+func writeDraft(cmd *cobra.Command, gen Generator, opts commitOptions) error {
+	// Nothing may wait for an answer
+	opts.autoYes = true
+	out := cmd.ErrOrStderr()
+	d, err := draftMessage(gen, opts, bufio.NewReader(cmd.InOrStdin()), out)
+	if err != nil {
+		return err
+	}
 
-	ctx := context.Background()
-	resp, err := service.Run(ctx, ai.RunOptions{
-		System: systemPrompt,
-		Prompt: userPrompt,
+	final := assembleMessage(d.message, opts, gen != nil)
+	enc := commitEncoding(opts.encoding)
+	encoded, err := encodeMessage(final, enc)
+	if err != nil {
+		return errors.NewCLIError("failed to encode the message as " + enc).WithCause(err)
+	}
+	return writeMessageFile(out, opts.writePath, encoded, true)
+}
+
+// printDraftJSON generates a message without committing and prints it as
+// JSON: rendered with the --output-template-file template, or as a
+// draftDocument for --output json, which also skips every question. Progress
+// goes to stderr so stdout carries only the JSON.
+func printDraftJSON(cmd *cobra.Command, gen Generator, opts commitOptions) error {
+	start := time.Now()
+	var tmpl *template.Template
+	if opts.outputTemplate != "" {
+		var err error
+		if tmpl, err = loadOutputTemplate(opts.outputTemplate, gen != nil); err != nil {
+			return err
+		}
+	} else {
+		opts.autoYes = true
+	}
+	var meter *meteringGenerator
+	if gen != nil {
+		meter = &meteringGenerator{gen: gen, run: runInfo{Model: opts.model}}
+		gen = meter
+	}
+	d, err := draftMessage(gen, opts, bufio.NewReader(cmd.InOrStdin()), cmd.ErrOrStderr())
+	if err != nil {
+		return err
+	}
+
+	final := commitmsg.ToLF(assembleMessage(d.message, opts, gen != nil))
+	data := jsonOutput{Message: commitmsg.Structure(final, validateMessage(final, opts))}
+	if meter != nil {
+		data.Run = &meter.run
+	}
+	if tmpl == nil {
+		return printDraftDocument(cmd.OutOrStdout(), final, data, d.changes, time.Since(start))
+	}
+	return printTemplateJSON(cmd.OutOrStdout(), tmpl, data)
+}
+
+// newService creates the AI service, falling back to the commit message
+// model when no default model is configured.
+func newService(cfg *ai.Config) (*ai.Service, error) {
+	client, err := ai.NewClient(*cfg)
+	if err != nil {
+		return nil, errors.NewCLIError("failed to create AI client").WithCause(err)
+	}
+
+	cfg.DefaultModel = effectiveModel(cfg.DefaultModel)
+	return ai.NewService(client, *cfg), nil
+}
+
+// effectiveModel returns the model actually used for a configured model
+// name: the commit message model when none is configured.
+func effectiveModel(model string) string {
+	if model == "" {
+		return prompt.CommitMessageModel
+	}
+	return model
+}
+
+// subjectLine returns the header of a message. With noType the type and
+// scope are dropped and the subject is turned into a slug such as
+// "add-retry-limit", suitable for a branch name.
+func subjectLine(message string, noType bool) string {
+	m := commitmsg.Parse(commitmsg.ToLF(message))
+	if !noType {
+		return m.Header
+	}
+	subject := m.Header
+	if m.Conventional() {
+		subject = m.Subject
+	}
+	return slugify(subject)
+}
+
+// slugify lower-cases text and joins its words with hyphens, keeping only
+// letters and digits.
+func slugify(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
 	})
+	return strings.Join(words, "-")
+}
+
+// assembleMessage returns the message exactly as it will be committed: with
+// the --append-run-metadata trailer when the message was generated, and
+// finalized.
+func assembleMessage(message string, opts commitOptions, generated bool) string {
+	if opts.runMetadata && generated {
+		message = commitmsg.AppendTrailers(message, []commitmsg.Trailer{generatedByTrailer(opts.model, time.Now())})
+	}
+	return finalizeMessage(message, opts)
+}
+
+// finalizeMessage applies the post-processing every message goes through
+// right before it is committed.
+func finalizeMessage(message string, opts commitOptions) string {
+	final := commitmsg.Normalize(commitmsg.ToLF(message), commitmsg.NormalizeOptions{
+		KeepBlankLines: opts.keepBlankLines,
+	})
+	return commitmsg.ConvertLineEndings(final, message, opts.lineEndings)
+}
+
+// validationRules returns the message rules implied by the options.
+func validationRules(opts commitOptions) commitmsg.Rules {
+	return commitmsg.Rules{
+		RequireWhy:       opts.annotateWhy,
+		MinWhyLength:     opts.minWhyLength,
+		SubjectCase:      opts.subjectCase,
+		MaxSubjectLength: opts.maxSubjectLength,
+		Scopes:           opts.scopes,
+		Disabled:         opts.disabledRules,
+	}
+}
+
+// validateMessage checks a message against the configured rules.
+func validateMessage(message string, opts commitOptions) []commitmsg.Issue {
+	return commitmsg.Validate(commitmsg.ToLF(message), validationRules(opts))
+}
+
+// validationErrors returns the messages of the validation errors in
+// message; warnings are left out.
+func validationErrors(message string, opts commitOptions) []string {
+	var problems []string
+	for _, issue := range validateMessage(message, opts) {
+		if issue.Severity == commitmsg.Error {
+			problems = append(problems, issue.Message+" ("+issue.Rule+")")
+		}
+	}
+	return problems
+}
+
+// printIssues lists validation issues below the message preview.
+func printIssues(out io.Writer, issues []commitmsg.Issue) {
+	if len(issues) == 0 {
+		return
+	}
+	fmt.Fprintln(out, "\nValidation:")
+	for _, issue := range issues {
+		fmt.Fprintln(out, "  "+issue.String())
+	}
+}
+
+// commitArgs returns the extra git commit arguments for committing the
+// finalized message with the given options.
+func commitArgs(message string, opts commitOptions) []string {
+	var args []string
+	if opts.all {
+		args = append(args, "--all")
+	}
+	if opts.keepBlankLines || commitmsg.HasCRLF(message) {
+		// git's default cleanup would collapse blank lines and strip the
+		// carriage returns again
+		args = append(args, "--cleanup=verbatim")
+	}
+	args = append(args, signingArgs(opts.signKey)...)
+	return args
+}
+
+// skipHooksEnv is the environment variable listing the hooks --skip-hook
+// asks to be skipped. Hooks must check it themselves; git ignores it.
+const skipHooksEnv = "ARC_SKIP_HOOKS"
+
+// commitEnv returns the extra environment for git commit.
+func commitEnv(opts commitOptions) []string {
+	env := encodingEnv(opts.encoding)
+	if len(opts.skipHooks) > 0 {
+		env = append(env, skipHooksEnv+"="+strings.Join(opts.skipHooks, ","))
+	}
+	return env
+}
+
+// autoAcceptBlocker explains why a message cannot be auto-accepted, or
+// returns an empty string when it can.
+func autoAcceptBlocker(changes *diff.Diff, message string, opts commitOptions) string {
+	files, lines := len(changes.Files), changes.Added()+changes.Removed()
+	if files > opts.autoAcceptMaxFiles {
+		return fmt.Sprintf("%d files changed (max %d)", files, opts.autoAcceptMaxFiles)
+	}
+	if lines > opts.autoAcceptMaxLines {
+		return fmt.Sprintf("%d lines changed (max %d)", lines, opts.autoAcceptMaxLines)
+	}
+	if issues := validateMessage(message, opts); len(issues) > 0 {
+		return "message did not pass validation: " + issues[0].Message
+	}
+	return ""
+}
+
+// generateCommitMessage generates a commit message from diff and optional
+// feedback. params carries the sampling settings and any earlier turns
+// about the same diff; its prompts are filled in here. A positive
+// params.MaxTokens caps the length of the model's output. Warnings are
+// written to out.
+func generateCommitMessage(out io.Writer, gen Generator, changes *diff.Diff, feedback string, promptOpts prompt.CommitOptions, params GenerateRequest) (string, error) {
+	params.System, params.Prompt = commitPrompts(changes, feedback, promptOpts, params.History)
+	maxTokens := params.MaxTokens
+
+	ctx := context.Background()
+	// run generates a message, reporting whether the cap cut it short:
+	// as the provider says when it reports why it stopped, else judged by
+	// the length
+	run := func(maxTokens int) (message string, truncated bool, err error) {
+		req := params
+		req.MaxTokens = maxTokens
+		var reason string
+		req.FinishReason = &reason
+		text, err := gen.Generate(ctx, req)
+		if err != nil {
+			return "", false, fmt.Errorf("AI request failed: %w", err)
+		}
+		message = strings.TrimSpace(text)
+		if reason != "" {
+			return message, maxTokens > 0 && reason == finishLength, nil
+		}
+		return message, looksTruncated(message, maxTokens), nil
+	}
+
+	message, truncated, err := run(maxTokens)
+	if err != nil || !truncated {
+		return message, err
+	}
+
+	// The cap cut the message short: retry once with more room, then keep
+	// only the complete lines
+	fmt.Fprintf(out, "Warning: the message hit the %d token cap; retrying with %d.\n", maxTokens, maxTokens*2)
+	message, truncated, err = run(maxTokens * 2)
+	if err != nil || !truncated {
+		return message, err
+	}
+	fmt.Fprintln(out, "Warning: the message is still truncated; dropping the incomplete last line.")
+	return trimIncompleteLine(message), nil
+}
+
+// commitPrompts returns the system and user prompts for a commit message.
+// The diff is only part of the first turn; later turns follow up on the
+// messages in history.
+func commitPrompts(changes *diff.Diff, feedback string, promptOpts prompt.CommitOptions, history []Turn) (system, user string) {
+	system, user = prompt.CommitMessage(diff.Format(changes), feedback, promptOpts)
+	if len(history) > 0 {
+		user = prompt.CommitFollowUp(feedback, promptOpts)
+	}
+	return system, user
+}
+
+// outlineChanges runs the first phase of --two-phase generation, listing
+// the key changes. params carries the sampling settings.
+func outlineChanges(gen Generator, changes *diff.Diff, params GenerateRequest) (string, error) {
+	params.System, params.Prompt = prompt.OutlineChanges(diff.Format(changes))
+	text, err := gen.Generate(context.Background(), params)
 	if err != nil {
-		return "", fmt.Errorf("AI request failed: %w", err)
+		return "", fmt.Errorf("outline request failed: %w", err)
 	}
+	return strings.TrimSpace(text), nil
+}
+
+// estimateTokens approximates the number of tokens in text, at roughly four
+// characters per token.
+func estimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
 
-	return strings.TrimSpace(resp.Text), nil
+// looksTruncated reports whether output probably stopped at the token cap
+// rather than where the model meant to end, for providers that do not say.
+func looksTruncated(text string, maxTokens int) bool {
+	return maxTokens > 0 && estimateTokens(text) >= maxTokens*9/10
+}
+
+// trimIncompleteLine drops the last line of a truncated message. A message
+// cut within its header is kept as is, since there is nothing to fall back to.
+func trimIncompleteLine(text string) string {
+	i := strings.LastIndex(text, "\n")
+	if i < 0 {
+		return text
+	}
+	return strings.TrimSpace(text[:i])
 }
 
 // checkStagedChanges checks if there are staged changes in git.
@@ -197,9 +1120,22 @@ func checkStagedChanges() error {
 	return fmt.Errorf("no staged changes")
 }
 
-// getStagedDiff gets the diff of staged changes.
-func getStagedDiff() (string, error) {
-	cmd := exec.Command("git", "diff", "--staged")
+// getStagedDiff gets the diff of staged changes, with any extra git diff
+// arguments.
+func getStagedDiff(args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"diff", "--staged"}, args...)...)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get diff: %w", err)
+	}
+	return string(output), nil
+}
+
+// getTrackedDiff gets the diff of the staged and unstaged changes to
+// tracked files, as git commit --all would commit them, with any extra git
+// diff arguments.
+func getTrackedDiff(args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"diff", "HEAD"}, args...)...)
 	output, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("failed to get diff: %w", err)
@@ -207,6 +1143,59 @@ func getStagedDiff() (string, error) {
 	return string(output), nil
 }
 
+// showUnstaged lists the tracked files --all will stage when committing.
+func showUnstaged(out io.Writer) error {
+	if exec.Command("git", "rev-parse", "--verify", "--quiet", "HEAD").Run() != nil {
+		return errors.NewCLIError("--all needs an existing commit").
+			WithHint("Stage the files for the first commit: git add <files>")
+	}
+	files, err := gitLines("diff", "--name-only")
+	if err != nil {
+		return errors.NewCLIError("failed to list unstaged changes").WithCause(err)
+	}
+	if len(files) > 0 {
+		fmt.Fprintln(out, "Will stage when committing (--all): "+strings.Join(files, ", "))
+	}
+	return nil
+}
+
+// filePaths lists the paths of the files in a diff.
+func filePaths(d *diff.Diff) []string {
+	paths := make([]string, len(d.Files))
+	for i, f := range d.Files {
+		paths[i] = f.Path()
+	}
+	return paths
+}
+
+// excludedPath reports whether p matches one of the --exclude-path
+// patterns.
+func excludedPath(p string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if classify.Match(pattern, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// whitespaceOnly reports whether the staged changes only alter whitespace:
+// no file is added, removed or renamed, and the diff readDiff returns is
+// empty once whitespace is ignored.
+func whitespaceOnly(changes *diff.Diff, readDiff func(args ...string) (string, error)) bool {
+	for _, f := range changes.Files {
+		if f.New || f.Deleted || f.Renamed || f.Binary {
+			return false
+		}
+	}
+	wsDiff, err := readDiff("-w")
+	if err != nil {
+		return false
+	}
+	ignored := diff.Parse(wsDiff)
+	return ignored.Added()+ignored.Removed() == 0
+}
+
 // editInEditor opens the message in the user's editor.
 func editInEditor(message string) (string, error) {
 	editor := os.Getenv("EDITOR")
@@ -219,7 +1208,13 @@ func editInEditor(message string) (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("failed to create temp file: %w", err)
 	}
-	defer os.Remove(tmpFile.Name())
+	// Kept when the editor fails, as it may hold unsaved work
+	keep := false
+	defer func() {
+		if !keep {
+			os.Remove(tmpFile.Name())
+		}
+	}()
 
 	// Write message to temp file
 	if _, err := tmpFile.WriteString(message); err != nil {
@@ -234,7 +1229,8 @@ func editInEditor(message string) (string, error) {
 	editCmd.Stderr = os.Stderr
 
 	if err := editCmd.Run(); err != nil {
-		return "", fmt.Errorf("editor failed: %w", err)
+		keep = true
+		return "", &editorFailure{Path: tmpFile.Name(), Err: err}
 	}
 
 	// Read edited content
@@ -243,17 +1239,149 @@ func editInEditor(message string) (string, error) {
 		return "", fmt.Errorf("failed to read edited file: %w", err)
 	}
 
+	// Editors on Windows may save CRLF. The content is returned as saved so
+	// finalizeMessage can honor --line-endings=preserve.
 	return string(edited), nil
 }
 
-// createCommit creates a git commit with the given message.
-func createCommit(message string) error {
-	cmd := exec.Command("git", "commit", "-F", "-")
+// defaultFeedbackQuestion asks for regeneration feedback unless
+// --feedback-question replaces it.
+const defaultFeedbackQuestion = "What would you like improved?"
+
+// askFeedback asks what a regenerated message should improve. The canned
+// --feedback-option answers are offered by number; anything else is used
+// as typed, and an empty answer asks for a generic retry.
+func askFeedback(reader *bufio.Reader, out io.Writer, opts commitOptions) string {
+	choices := make([]string, len(opts.feedbackOptions))
+	if len(opts.feedbackOptions) > 0 {
+		fmt.Fprintln(out)
+		for i, option := range opts.feedbackOptions {
+			choices[i] = strconv.Itoa(i + 1)
+			fmt.Fprintf(out, "  %d) %s\n", i+1, option)
+		}
+		fmt.Fprintf(out, "%s (number, your own words, or Enter for generic): ", opts.feedbackQuestion)
+	} else {
+		fmt.Fprintf(out, "\n%s (or press Enter for generic): ", opts.feedbackQuestion)
+	}
+	opts.events.awaiting(inputFeedback, choices...)
+
+	answer, _ := reader.ReadString('\n')
+	answer = strings.TrimSpace(answer)
+	if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(opts.feedbackOptions) {
+		return opts.feedbackOptions[n-1]
+	}
+	return answer
+}
+
+// feedbackEditorHelp is shown at the top of the feedback editing buffer,
+// commented out with git's comment character char.
+func feedbackEditorHelp(char string) string {
+	return char + " Describe what the regenerated message should improve.\n" +
+		char + " Lines starting with '" + char + "' are ignored; leave it empty for a generic retry.\n"
+}
+
+// editFeedback opens the regeneration feedback in the editor, starting from
+// the previous feedback, and returns it without comment lines. Comments use
+// core.commentChar, as in git's own editor buffers, so feedback may start
+// with "#", e.g. to name an issue.
+func editFeedback(previous string) (string, error) {
+	char := commentChar()
+	text := feedbackEditorHelp(char)
+	if previous != "" {
+		text += previous + "\n"
+	}
+	edited, err := editInEditor(text)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(stripComments(commitmsg.ToLF(edited), char)), nil
+}
+
+// editMessage opens message in the editor and returns the text to commit.
+// Saving an empty message aborts the commit, as git does. Saving it unchanged
+// asks whether to commit it as-is or edit again; with requireEdit an empty
+// string is returned instead, sending the user back to the menu.
+func editMessage(reader *bufio.Reader, out io.Writer, events *eventWriter, message string, requireEdit bool) (string, error) {
+	for {
+		edited, err := editInEditor(message)
+		if failure, ok := asEditorFailure(err); ok {
+			retry, err := recoverEdit(reader, out, events, failure)
+			if err != nil || retry == "" {
+				return "", err
+			}
+			message = retry
+			continue
+		}
+		if err != nil {
+			return "", errors.NewCLIError("failed to open editor").WithCause(err)
+		}
+
+		text := strings.TrimSpace(commitmsg.ToLF(edited))
+		switch {
+		case text == "":
+			return "", errors.NewCLIError("aborting commit due to empty commit message").
+				WithHint("Run arc-commit --resume to pick up the last generated message")
+		case text != strings.TrimSpace(commitmsg.ToLF(message)):
+			return edited, nil
+		case requireEdit:
+			fmt.Fprintln(out, "\nThe message was not changed (--require-edit).")
+			return "", nil
+		}
+
+		fmt.Fprint(out, "\nThe message was not changed. [c]ommit as-is or [r]e-edit: ")
+		events.awaiting(inputConfirm, "c", "r")
+		choice, err := reader.ReadString('\n')
+		if err != nil {
+			return "", errors.NewCLIError("failed to read input").WithCause(err)
+		}
+		if choice = strings.ToLower(strings.TrimSpace(choice)); choice != "r" && choice != "re-edit" {
+			return edited, nil
+		}
+	}
+}
+
+// recoverEdit handles an editor that crashed or was killed during [e]dit.
+// The edited file is kept and its path printed, and the user may re-edit
+// from what it holds or go back to the generated message. It returns the
+// text to re-edit, or "" to go back.
+func recoverEdit(reader *bufio.Reader, out io.Writer, events *eventWriter, failure *editorFailure) (string, error) {
+	fmt.Fprintf(out, "\nThe editor failed (%v). Your edits, as far as they were saved, are kept in:\n  %s\n", failure.Err, failure.Path)
+	saved, err := os.ReadFile(failure.Path)
+	if err != nil || strings.TrimSpace(string(saved)) == "" {
+		fmt.Fprintln(out, "The file is empty; going back to the generated message.")
+		return "", nil
+	}
+
+	fmt.Fprint(out, "[r]etry editing from the saved edits, or [b]ack to the generated message: ")
+	events.awaiting(inputConfirm, "r", "b")
+	choice, err := reader.ReadString('\n')
+	if err != nil {
+		return "", errors.NewCLIError("failed to read input").WithCause(err)
+	}
+	if choice = strings.ToLower(strings.TrimSpace(choice)); choice != "r" && choice != "retry" {
+		return "", nil
+	}
+	// The retry works on a fresh copy, so the kept file stays as a backup
+	return string(saved), nil
+}
+
+// createCommit creates a git commit with the given message, any extra git
+// commit arguments and extra environment variables for git and its hooks.
+// Git's output is written to out.
+func createCommit(out io.Writer, message string, args, env []string) error {
+	cmd := exec.Command("git", append([]string{"commit", "-F", "-"}, args...)...)
+	cmd.Env = append(os.Environ(), env...)
 	cmd.Stdin = strings.NewReader(message)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	cmd.Stdout = out
+	var stderr bytes.Buffer
+	cmd.Stderr = io.MultiWriter(os.Stderr, &stderr)
 
 	if err := cmd.Run(); err != nil {
+		if signingRequested(signingKeyFromArgs(args)) {
+			if signErr := signingError(stderr.String(), err); signErr != nil {
+				return signErr
+			}
+		}
 		return fmt.Errorf("failed to create commit: %w", err)
 	}
 
@@ -0,0 +1,915 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/yourorg/arc-commit/internal/classify"
+	"github.com/yourorg/arc-commit/internal/diff"
+	commitmsg "github.com/yourorg/arc-commit/internal/message"
+	"github.com/yourorg/arc-commit/internal/prompt"
+	"github.com/yourorg/arc-sdk/ai"
+)
+
+// runCommitCmd runs the commit command with args and input, returning what
+// it printed to stdout and stderr.
+func runCommitCmd(t *testing.T, input string, args ...string) (string, error) {
+	t.Helper()
+	cmd := newCommitCmd(&ai.Config{})
+	var out bytes.Buffer
+	cmd.SetArgs(args)
+	cmd.SetIn(strings.NewReader(input))
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+// fakeOllama serves reply to every chat request and counts the requests.
+func fakeOllama(t *testing.T, reply string) (string, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		io.Copy(io.Discard, r.Body)
+		json.NewEncoder(w).Encode(ollamaResponse{Message: openAIMessage{Content: reply}, Done: true})
+	}))
+	t.Cleanup(server.Close)
+	return server.URL, &calls
+}
+
+func TestCommitInSensitiveRepo(t *testing.T) {
+	tests := []struct {
+		name string
+		// url is the provider URL; empty points at the fake local server.
+		url       string
+		wantCalls int32
+	}{
+		{name: "local model", wantCalls: 1},
+		{name: "remote provider-url", url: "http://models.example.com:11434", wantCalls: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			writeFile(t, ".arc-commit.yaml", "sensitive-repo: true\n")
+			commitFile(t, "README.md", "hello\n", "docs: add readme")
+			writeFile(t, "app.go", "package app\n")
+			git(t, "add", "app.go")
+
+			local, calls := fakeOllama(t, "feat: add the app package")
+			url := tt.url
+			if url == "" {
+				url = local
+			}
+			out, err := runCommitCmd(t, "", "--provider", "ollama", "--provider-url", url, "--model", "llama3.2",
+				"--write", "MSG", "--exit-after-generate")
+			if err != nil {
+				t.Fatalf("commit failed: %v\n%s", err, out)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("the provider got %d requests, want %d\n%s", got, tt.wantCalls, out)
+			}
+			data, err := os.ReadFile("MSG")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantCalls > 0 && !strings.HasPrefix(string(data), "feat: add the app package") {
+				t.Errorf("message = %q, want the local model's", data)
+			}
+		})
+	}
+}
+
+// testCommitOptions returns the options the commit command runs with when
+// no flags are given, except that nothing streams or takes over the
+// terminal.
+func testCommitOptions(t *testing.T) commitOptions {
+	t.Helper()
+	classifier, err := classify.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return commitOptions{
+		output:             outputText,
+		tokenBudget:        30000,
+		generatedPaths:     defaultGeneratedPaths,
+		styleExcludes:      defaultStyleExcludes,
+		maxRetries:         2,
+		contextMaxChanges:  30,
+		contextLines:       40,
+		fileTreeDepth:      2,
+		fileTreeMaxEntries: 80,
+		minWhyLength:       commitmsg.DefaultMinWhyLength,
+		subjectCase:        commitmsg.SubjectCasePreserve,
+		maxSubjectLength:   commitmsg.DefaultMaxSubjectLength,
+		ticketPattern:      defaultTicketPattern,
+		validationRetries:  2,
+		diversify:          diversifyLow,
+		candidates:         1,
+		feedbackQuestion:   defaultFeedbackQuestion,
+		separatorStyle:     separatorEquals,
+		lineEndings:        commitmsg.LineEndingsLF,
+		footerBase:         "main",
+		countGuard:         50,
+		autoAcceptMaxFiles: 3,
+		autoAcceptMaxLines: 20,
+		classifier:         classifier,
+		noStream:           true,
+		noTUI:              true,
+		simplePrompt:       true,
+	}
+}
+
+func TestRunInteractiveCommit(t *testing.T) {
+	tests := []struct {
+		name             string
+		input            string
+		maxRegenerations int
+		// want is the committed message; empty means nothing is committed.
+		want      string
+		wantCalls int
+		wantOut   string
+		// feedback must appear in the prompt of the last request.
+		feedback string
+	}{
+		{
+			name:      "commit",
+			input:     "y\n",
+			want:      "feat: add the app package",
+			wantCalls: 1,
+		},
+		{
+			name:      "yes spelled out",
+			input:     "YES\n",
+			want:      "feat: add the app package",
+			wantCalls: 1,
+		},
+		{
+			name:      "abort",
+			input:     "c\n",
+			wantCalls: 1,
+			wantOut:   "Commit cancelled.",
+		},
+		{
+			name:      "regenerate with feedback",
+			input:     "n\nmention the tests\ny\n",
+			want:      "feat: add the app package with tests",
+			wantCalls: 2,
+			feedback:  "mention the tests",
+		},
+		{
+			name:      "invalid choice asks again",
+			input:     "x\ny\n",
+			want:      "feat: add the app package",
+			wantCalls: 1,
+			wantOut:   "Invalid choice.",
+		},
+		{
+			name:      "help",
+			input:     "?\ny\n",
+			want:      "feat: add the app package",
+			wantCalls: 1,
+			wantOut:   "  e  edit the message in $EDITOR, then commit it",
+		},
+		{
+			name:      "log preview",
+			input:     "p\ny\n",
+			want:      "feat: add the app package",
+			wantCalls: 1,
+			wantOut:   "git log --oneline:\n  1234567 (HEAD -> main) feat: add the app package",
+		},
+		{
+			name:             "no regenerations left",
+			input:            "n\n\nn\nc\n",
+			maxRegenerations: 1,
+			wantCalls:        2,
+			wantOut:          "No regenerations left (--max-regenerations 1)",
+		},
+		{
+			name:             "regenerations counted down",
+			input:            "n\n\ny\n",
+			maxRegenerations: 2,
+			want:             "feat: add the app package with tests",
+			wantCalls:        2,
+			wantOut:          "1 regeneration(s) left.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			writeFile(t, "app.go", "package app\n")
+			git(t, "add", "app.go")
+
+			gen := &fakeGenerator{replies: []string{"feat: add the app package", "feat: add the app package with tests"}}
+			opts := testCommitOptions(t)
+			opts.maxRegenerations = tt.maxRegenerations
+			var out bytes.Buffer
+			if err := runInteractiveCommit(gen, opts, strings.NewReader(tt.input), &out); err != nil {
+				t.Fatalf("runInteractiveCommit() error = %v\n%s", err, out.String())
+			}
+
+			if gen.calls() != tt.wantCalls {
+				t.Errorf("%d generate requests, want %d", gen.calls(), tt.wantCalls)
+			}
+			if tt.feedback != "" && !strings.Contains(gen.requests[len(gen.requests)-1].Prompt, tt.feedback) {
+				t.Errorf("the regeneration prompt does not carry the feedback %q", tt.feedback)
+			}
+			if !strings.Contains(out.String(), tt.wantOut) {
+				t.Errorf("output does not contain %q:\n%s", tt.wantOut, out.String())
+			}
+			committed := ""
+			if exec.Command("git", "rev-parse", "--verify", "-q", "HEAD").Run() == nil {
+				committed = git(t, "log", "-1", "--format=%B")
+			}
+			if strings.TrimSpace(committed) != tt.want {
+				t.Errorf("committed %q, want %q", committed, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateCommitMessageTruncation(t *testing.T) {
+	const clipped = "feat: add the app package\n\nRun starts the server and\nwaits for"
+	tests := []struct {
+		name      string
+		maxTokens int
+		replies   []string
+		reasons   []string
+		want      string
+		// wantCaps are the token caps of the requests made.
+		wantCaps []int
+	}{
+		{
+			name:      "complete",
+			maxTokens: 100,
+			replies:   []string{"feat: add the app package"},
+			reasons:   []string{"stop"},
+			want:      "feat: add the app package",
+			wantCaps:  []int{100},
+		},
+		{
+			name:      "retried at double the cap",
+			maxTokens: 100,
+			replies:   []string{clipped, "feat: add the app package\n\nRun starts the server."},
+			reasons:   []string{finishLength, "stop"},
+			want:      "feat: add the app package\n\nRun starts the server.",
+			wantCaps:  []int{100, 200},
+		},
+		{
+			name:      "still truncated, then trimmed",
+			maxTokens: 100,
+			replies:   []string{clipped},
+			reasons:   []string{finishLength, finishLength},
+			want:      "feat: add the app package\n\nRun starts the server and",
+			wantCaps:  []int{100, 200},
+		},
+		{
+			name:      "judged by length without a reason",
+			maxTokens: 8,
+			replies:   []string{clipped},
+			want:      "feat: add the app package\n\nRun starts the server and",
+			wantCaps:  []int{8, 16},
+		},
+		{
+			name:     "no cap",
+			replies:  []string{clipped},
+			reasons:  []string{finishLength},
+			want:     clipped,
+			wantCaps: []int{0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := &fakeGenerator{replies: tt.replies, reasons: tt.reasons}
+			got, err := generateCommitMessage(io.Discard, gen, diff.Parse(testDiff), "", prompt.CommitOptions{}, GenerateRequest{MaxTokens: tt.maxTokens})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("message =\n%s\nwant\n%s", got, tt.want)
+			}
+			var caps []int
+			for _, req := range gen.requests {
+				caps = append(caps, req.MaxTokens)
+			}
+			if fmt.Sprint(caps) != fmt.Sprint(tt.wantCaps) {
+				t.Errorf("token caps = %v, want %v", caps, tt.wantCaps)
+			}
+		})
+	}
+}
+
+func TestAutoAcceptBlocker(t *testing.T) {
+	changes := diff.Parse(testDiff)
+	tests := []struct {
+		name     string
+		message  string
+		maxFiles int
+		maxLines int
+		want     string
+	}{
+		{name: "small and valid", message: "feat: add the app package", maxFiles: 10, maxLines: 100},
+		{name: "too many files", message: "feat: add the app package", maxFiles: 2, maxLines: 100, want: "4 files changed (max 2)"},
+		{name: "too many lines", message: "feat: add the app package", maxFiles: 10, maxLines: 3, want: "lines changed (max 3)"},
+		{name: "invalid message", message: "Add the app package", maxFiles: 10, maxLines: 100, want: "message did not pass validation"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := testCommitOptions(t)
+			opts.autoAcceptMaxFiles, opts.autoAcceptMaxLines = tt.maxFiles, tt.maxLines
+			got := autoAcceptBlocker(changes, tt.message, opts)
+			if (tt.want == "") != (got == "") || !strings.Contains(got, tt.want) {
+				t.Errorf("autoAcceptBlocker() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInteractiveCommitAutoAccepts(t *testing.T) {
+	tests := []struct {
+		name string
+		// reply is the generated message.
+		reply   string
+		input   string
+		wantOut string
+	}{
+		{name: "valid", reply: "feat: add the app package", wantOut: "auto-committing"},
+		{name: "invalid asks", reply: "Add the app package", input: "y\n", wantOut: "Not auto-accepting: message did not pass validation"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			writeFile(t, "app.go", "package app\n")
+			git(t, "add", "app.go")
+
+			opts := testCommitOptions(t)
+			opts.autoAcceptValid = true
+			var out bytes.Buffer
+			gen := &fakeGenerator{replies: []string{tt.reply}}
+			if err := runInteractiveCommit(gen, opts, strings.NewReader(tt.input), &out); err != nil {
+				t.Fatalf("runInteractiveCommit() error = %v\n%s", err, out.String())
+			}
+			if !strings.Contains(out.String(), tt.wantOut) {
+				t.Errorf("output does not contain %q:\n%s", tt.wantOut, out.String())
+			}
+			if got := git(t, "log", "-1", "--format=%s"); got != tt.reply {
+				t.Errorf("committed %q, want %q", got, tt.reply)
+			}
+		})
+	}
+}
+
+func TestCommitKeepsBlankLines(t *testing.T) {
+	const reply = "feat: add the app package\n\nRun starts the server.\n\n\nRefs: #42"
+	tests := []struct {
+		name string
+		keep bool
+		want string
+	}{
+		{name: "collapsed", want: "feat: add the app package\n\nRun starts the server.\n\nRefs: #42"},
+		{name: "kept", keep: true, want: reply},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			writeFile(t, "app.go", "package app\n")
+			git(t, "add", "app.go")
+
+			opts := testCommitOptions(t)
+			opts.keepBlankLines = tt.keep
+			gen := &fakeGenerator{replies: []string{reply}}
+			if err := runInteractiveCommit(gen, opts, strings.NewReader("y\n"), io.Discard); err != nil {
+				t.Fatal(err)
+			}
+			if got := git(t, "log", "-1", "--format=%B"); got != tt.want {
+				t.Errorf("committed %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFinalizeMessage(t *testing.T) {
+	tests := []struct {
+		name        string
+		message     string
+		lineEndings string
+		want        string
+	}{
+		{name: "crlf to lf", message: "feat: a  \r\n\r\n\r\nbody\r\n", lineEndings: commitmsg.LineEndingsLF, want: "feat: a\n\nbody"},
+		{name: "lf to crlf", message: "feat: a\n\nbody\n", lineEndings: commitmsg.LineEndingsCRLF, want: "feat: a\r\n\r\nbody"},
+		{name: "preserved crlf", message: "feat: a\r\nbody", lineEndings: commitmsg.LineEndingsPreserve, want: "feat: a\r\n\r\nbody"},
+		{name: "lone carriage returns", message: "feat: a\r\rbody", lineEndings: commitmsg.LineEndingsPreserve, want: "feat: a\n\nbody"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := testCommitOptions(t)
+			opts.lineEndings = tt.lineEndings
+			if got := finalizeMessage(tt.message, opts); got != tt.want {
+				t.Errorf("finalizeMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommitKeepsCRLF(t *testing.T) {
+	testRepo(t)
+	writeFile(t, "app.go", "package app\n")
+	git(t, "add", "app.go")
+
+	opts := testCommitOptions(t)
+	opts.lineEndings = commitmsg.LineEndingsCRLF
+	gen := &fakeGenerator{replies: []string{"feat: add the app package\n\nRun starts the server."}}
+	if err := runInteractiveCommit(gen, opts, strings.NewReader("y\n"), io.Discard); err != nil {
+		t.Fatal(err)
+	}
+	if got := git(t, "log", "-1", "--format=%B"); got != "feat: add the app package\r\n\r\nRun starts the server." {
+		t.Errorf("committed %q, want CRLF line endings", got)
+	}
+}
+
+func TestInteractiveCommitStrict(t *testing.T) {
+	tests := []struct {
+		name    string
+		replies []string
+		input   string
+		// want is the committed subject; empty means nothing is committed.
+		want    string
+		wantOut string
+	}{
+		{
+			name:    "invalid message refused",
+			replies: []string{"fix: retry uploads"},
+			input:   "y\nc\n",
+			wantOut: "The message has validation errors (--strict)",
+		},
+		{
+			name:    "regenerated message accepted",
+			replies: []string{"fix: retry uploads", "fix: retry dropped uploads\n\nUploads failed for good on a dropped connection because nothing retried them."},
+			input:   "y\nn\n\ny\n",
+			want:    "fix: retry dropped uploads",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			writeFile(t, "app.go", "package app\n")
+			git(t, "add", "app.go")
+
+			opts := testCommitOptions(t)
+			opts.strict, opts.annotateWhy = true, true
+			opts.validationRetries = 0
+			var out bytes.Buffer
+			gen := &fakeGenerator{replies: tt.replies}
+			if err := runInteractiveCommit(gen, opts, strings.NewReader(tt.input), &out); err != nil {
+				t.Fatalf("runInteractiveCommit() error = %v\n%s", err, out.String())
+			}
+			if !strings.Contains(out.String(), tt.wantOut) {
+				t.Errorf("output does not contain %q:\n%s", tt.wantOut, out.String())
+			}
+			committed := ""
+			if exec.Command("git", "rev-parse", "--verify", "-q", "HEAD").Run() == nil {
+				committed = git(t, "log", "-1", "--format=%s")
+			}
+			if committed != tt.want {
+				t.Errorf("committed %q, want %q", committed, tt.want)
+			}
+		})
+	}
+}
+
+func TestInteractiveCommitEditFeedback(t *testing.T) {
+	tests := []struct {
+		name   string
+		editor func(t *testing.T) string
+		input  string
+		// wantFeedback is the feedback sent with each regeneration.
+		wantFeedback []string
+		wantOut      string
+	}{
+		{
+			name:         "feedback written in the editor",
+			editor:       func(t *testing.T) string { return appendingEditor(t, "mention the retry limit") },
+			input:        "n\nc\n",
+			wantFeedback: []string{"mention the retry limit"},
+		},
+		{
+			name:         "previous feedback kept",
+			editor:       func(t *testing.T) string { return appendingEditor(t, "shorter") },
+			input:        "n\nn\nc\n",
+			wantFeedback: []string{"shorter", "shorter\nshorter"},
+		},
+		{
+			name:    "editor fails",
+			editor:  func(t *testing.T) string { return "false" },
+			input:   "n\nc\n",
+			wantOut: "Feedback not edited",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			writeFile(t, "app.go", "package app\n")
+			git(t, "add", "app.go")
+			t.Setenv("EDITOR", tt.editor(t))
+
+			opts := testCommitOptions(t)
+			opts.editFeedback = true
+			var out bytes.Buffer
+			gen := &fakeGenerator{replies: []string{"feat: add the app package"}}
+			if err := runInteractiveCommit(gen, opts, strings.NewReader(tt.input), &out); err != nil {
+				t.Fatalf("runInteractiveCommit() error = %v\n%s", err, out.String())
+			}
+			if got, want := gen.calls(), 1+len(tt.wantFeedback); got != want {
+				t.Fatalf("made %d calls, want %d", got, want)
+			}
+			for i, feedback := range tt.wantFeedback {
+				if p := gen.requests[i+1].Prompt; !strings.Contains(p, "User feedback for improvement: "+feedback+"\n") {
+					t.Errorf("regeneration %d does not carry feedback %q:\n%s", i+1, feedback, p)
+				}
+			}
+			if strings.Contains(out.String(), "What would you like improved?") {
+				t.Errorf("asked for feedback on the terminal:\n%s", out.String())
+			}
+			if !strings.Contains(out.String(), tt.wantOut) {
+				t.Errorf("output does not contain %q:\n%s", tt.wantOut, out.String())
+			}
+		})
+	}
+}
+
+func TestDryRunFailOnInvalid(t *testing.T) {
+	const (
+		invalid = "fix: retry uploads"
+		valid   = "fix: retry dropped uploads\n\nUploads failed for good on a dropped connection because nothing retried them."
+	)
+	tests := []struct {
+		name          string
+		reply         string
+		failOnInvalid bool
+		wantErr       bool
+	}{
+		{name: "invalid message fails", reply: invalid, failOnInvalid: true, wantErr: true},
+		{name: "valid message passes", reply: valid, failOnInvalid: true},
+		{name: "invalid message only reported", reply: invalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			writeFile(t, "app.go", "package app\n")
+			git(t, "add", "app.go")
+
+			opts := testCommitOptions(t)
+			opts.dryRun, opts.failOnInvalid = true, tt.failOnInvalid
+			opts.annotateWhy, opts.validationRetries = true, 0
+			var out bytes.Buffer
+			err := runInteractiveCommit(&fakeGenerator{replies: []string{tt.reply}}, opts, strings.NewReader(""), &out)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("runInteractiveCommit() error = %v, wantErr %v\n%s", err, tt.wantErr, out.String())
+			}
+			if !strings.Contains(out.String(), "(Dry run - no commit created)") {
+				t.Errorf("dry run not reported:\n%s", out.String())
+			}
+			if exec.Command("git", "rev-parse", "--verify", "-q", "HEAD").Run() == nil {
+				t.Error("the dry run created a commit")
+			}
+		})
+	}
+}
+
+func TestAskFeedback(t *testing.T) {
+	options := []string{"shorter subject", "explain why"}
+	tests := []struct {
+		name     string
+		question string
+		options  []string
+		input    string
+		want     string
+		wantOut  string
+	}{
+		{name: "typed", input: "mention retries\n", want: "mention retries", wantOut: "\nWhat would you like improved? (or press Enter for generic): "},
+		{name: "generic", input: "\n", want: ""},
+		{name: "custom question", question: "What is wrong with it?", input: "too long\n", want: "too long", wantOut: "What is wrong with it? (or press Enter"},
+		{
+			name:    "option picked",
+			options: options,
+			input:   "2\n",
+			want:    "explain why",
+			wantOut: "  1) shorter subject\n  2) explain why\nWhat would you like improved? (number, your own words, or Enter for generic): ",
+		},
+		{name: "own words with options", options: options, input: "use feat\n", want: "use feat"},
+		{name: "number out of range", options: options, input: "3\n", want: "3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := testCommitOptions(t)
+			opts.feedbackOptions = tt.options
+			if tt.question != "" {
+				opts.feedbackQuestion = tt.question
+			}
+			var out strings.Builder
+			if got := askFeedback(bufio.NewReader(strings.NewReader(tt.input)), &out, opts); got != tt.want {
+				t.Errorf("askFeedback() = %q, want %q", got, tt.want)
+			}
+			if !strings.Contains(out.String(), tt.wantOut) {
+				t.Errorf("output does not contain %q:\n%s", tt.wantOut, out.String())
+			}
+		})
+	}
+}
+
+func TestCommitOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*commitOptions)
+		wantErr string
+	}{
+		{name: "defaults", modify: func(*commitOptions) {}},
+		{
+			name:    "negative max regenerations",
+			modify:  func(o *commitOptions) { o.maxRegenerations = -1 },
+			wantErr: "--max-regenerations must not be negative",
+		},
+		{
+			name:    "unknown diversify level",
+			modify:  func(o *commitOptions) { o.diversify = "extreme" },
+			wantErr: "invalid --diversify value: extreme",
+		},
+		{
+			name:    "unknown separator style",
+			modify:  func(o *commitOptions) { o.separatorStyle = "stars" },
+			wantErr: "invalid --separator-style value: stars",
+		},
+		{
+			name:    "negative commit count guard",
+			modify:  func(o *commitOptions) { o.countGuard = -1 },
+			wantErr: "--commit-count-guard must not be negative",
+		},
+		{
+			name:    "file tree depth",
+			modify:  func(o *commitOptions) { o.fileTreeDepth = 0 },
+			wantErr: "--file-tree-depth and --file-tree-max-entries must be at least 1",
+		},
+		{
+			name:    "negative max files in prompt",
+			modify:  func(o *commitOptions) { o.maxFilesInPrompt = -1 },
+			wantErr: "--max-files-in-prompt must not be negative",
+		},
+		{
+			name:    "unknown subject case",
+			modify:  func(o *commitOptions) { o.subjectCase = "title" },
+			wantErr: "invalid --commit-subject-case value: title",
+		},
+		{
+			name:    "negative retry budget",
+			modify:  func(o *commitOptions) { o.retryBudget = -1 },
+			wantErr: "--max-retries and --retry-budget must not be negative",
+		},
+		{
+			name:    "unknown language style",
+			modify:  func(o *commitOptions) { o.languageStyle = "pirate" },
+			wantErr: "invalid --prompt-language-style value: pirate",
+		},
+		{
+			name:    "write with dry run",
+			modify:  func(o *commitOptions) { o.writePath, o.dryRun = "MSG", true },
+			wantErr: "--write cannot be combined with --dry-run or --output-subject-only",
+		},
+		{
+			name:    "overwrite without write",
+			modify:  func(o *commitOptions) { o.overwrite = true },
+			wantErr: "--overwrite requires --write",
+		},
+		{
+			name:    "output template with write",
+			modify:  func(o *commitOptions) { o.outputTemplate, o.writePath = "out.tmpl", "MSG" },
+			wantErr: "--output-template-file cannot be combined with --output-subject-only, --write or --from-branch",
+		},
+		{
+			name:    "zero max subject length",
+			modify:  func(o *commitOptions) { o.maxSubjectLength = 0 },
+			wantErr: "--max-subject-length must be at least 1",
+		},
+		{
+			name:    "negative validation retries",
+			modify:  func(o *commitOptions) { o.validationRetries = -1 },
+			wantErr: "--validation-retries must not be negative",
+		},
+		{
+			name:    "zero candidates",
+			modify:  func(o *commitOptions) { o.candidates = 0 },
+			wantErr: "--candidates must be at least 1",
+		},
+		{
+			name:    "candidates with yes",
+			modify:  func(o *commitOptions) { o.candidates, o.autoYes = 3, true },
+			wantErr: "--candidates needs the interactive prompt",
+		},
+		{
+			name:    "negative token budget",
+			modify:  func(o *commitOptions) { o.tokenBudget = -1 },
+			wantErr: "--prompt-token-budget must not be negative",
+		},
+		{
+			name:    "unknown output format",
+			modify:  func(o *commitOptions) { o.output = "yaml" },
+			wantErr: "invalid --output value: yaml",
+		},
+		{
+			name:    "json output with write",
+			modify:  func(o *commitOptions) { o.output, o.writePath = outputJSON, "MSG" },
+			wantErr: "--output json cannot be combined with",
+		},
+		{
+			name:    "ticket format without ticket",
+			modify:  func(o *commitOptions) { o.ticketFormat = "Refs: ABC-1" },
+			wantErr: "invalid --ticket-format",
+		},
+		{
+			name:    "ticket format neither header nor trailer",
+			modify:  func(o *commitOptions) { o.ticketFormat = "see {ticket}" },
+			wantErr: "invalid --ticket-format",
+		},
+		{
+			name:    "from branch with dry run",
+			modify:  func(o *commitOptions) { o.fromBranch, o.dryRun = "feature", true },
+			wantErr: "--from-branch cannot be combined with --dry-run or --output-subject-only",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := testCommitOptions(t)
+			tt.modify(&opts)
+			err := opts.validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSubjectLine(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		noType  bool
+		want    string
+	}{
+		{name: "header", message: "feat(retry): add a retry limit\n\nBody.", want: "feat(retry): add a retry limit"},
+		{name: "no type", message: "feat(retry): add a retry limit\n\nBody.", noType: true, want: "add-a-retry-limit"},
+		{name: "not conventional", message: "Add a retry limit", noType: true, want: "add-a-retry-limit"},
+		{name: "CRLF", message: "fix: handle CRLF\r\n\r\nBody.", want: "fix: handle CRLF"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := subjectLine(tt.message, tt.noType); got != tt.want {
+				t.Errorf("subjectLine() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommitOutputSubjectOnly(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "subject", want: "feat(retry): add a retry limit\n"},
+		{name: "branch name", args: []string{"--no-type"}, want: "add-a-retry-limit\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			writeFile(t, "app.go", "package app\n")
+			git(t, "add", "app.go")
+
+			url, _ := fakeOllama(t, "feat(retry): add a retry limit\n\nRequests gave up after one failure.")
+			cmd := newCommitCmd(&ai.Config{})
+			var stdout, stderr bytes.Buffer
+			cmd.SetArgs(append([]string{"--provider", "ollama", "--provider-url", url, "--model", "llama3.2", "--output-subject-only"}, tt.args...))
+			cmd.SetIn(strings.NewReader(""))
+			cmd.SetOut(&stdout)
+			cmd.SetErr(&stderr)
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("commit failed: %v\n%s", err, stderr.String())
+			}
+			if stdout.String() != tt.want {
+				t.Errorf("stdout = %q, want %q", stdout.String(), tt.want)
+			}
+			if err := exec.Command("git", "rev-parse", "--verify", "-q", "HEAD").Run(); err == nil {
+				t.Error("--output-subject-only committed")
+			}
+		})
+	}
+}
+
+func TestValidationErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		message     string
+		annotateWhy bool
+		want        []string
+	}{
+		{name: "valid", message: "feat: add the app package"},
+		{name: "error", message: "Added the app package", want: []string{`header must look like "type(scope): subject" (format)`}},
+		{
+			name:        "warnings are left out",
+			message:     "fix: retry uploads\n\nThe upload loop now wraps each request in a retry with backoff.",
+			annotateWhy: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := testCommitOptions(t)
+			opts.annotateWhy = tt.annotateWhy
+			if got := validationErrors(tt.message, opts); !slices.Equal(got, tt.want) {
+				t.Errorf("validationErrors() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommitPrompts(t *testing.T) {
+	changes := diff.Parse(testDiff)
+	_, first := commitPrompts(changes, "", prompt.CommitOptions{}, nil)
+	if !strings.Contains(first, "app/app.go") {
+		t.Errorf("the first prompt does not show the diff:\n%s", first)
+	}
+
+	history := []Turn{{Prompt: first, Reply: "feat: add the app package"}}
+	_, next := commitPrompts(changes, "mention the scope", prompt.CommitOptions{}, history)
+	if strings.Contains(next, "app/app.go") {
+		t.Errorf("a follow-up repeats the diff:\n%s", next)
+	}
+	if !strings.Contains(next, "mention the scope") {
+		t.Errorf("a follow-up lacks the feedback:\n%s", next)
+	}
+}
+
+func TestCommitAll(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+		// wantFiles are the files of the new commit.
+		wantFiles string
+	}{
+		{name: "tracked changes", args: []string{"--all"}, wantFiles: "app.go\nlib.go"},
+		{name: "short flag", args: []string{"-a"}, wantFiles: "app.go\nlib.go"},
+		{name: "with patch", args: []string{"-a", "-p"}, wantErr: "--all cannot be combined with --patch"},
+		{name: "with write", args: []string{"--all", "--write", "MSG"}, wantErr: "--all cannot be combined with"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			writeFile(t, "app.go", "package app\n")
+			writeFile(t, "lib.go", "package app\n")
+			git(t, "add", "app.go", "lib.go")
+			git(t, "commit", "-q", "-m", "feat: add app")
+			writeFile(t, "app.go", "package app\n\nfunc Run() {}\n")
+			writeFile(t, "lib.go", "package app\n\nfunc Lib() {}\n")
+			git(t, "add", "lib.go")
+			writeFile(t, "new.go", "package app\n")
+
+			out, err := runCommitCmd(t, "", append(tt.args, "--no-ai", "--yes")...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("commit failed: %v\n%s", err, out)
+			}
+			if !strings.Contains(out, "Will stage when committing (--all): app.go") {
+				t.Errorf("the unstaged files are not listed:\n%s", out)
+			}
+			if got := git(t, "show", "--format=", "--name-only", "HEAD"); got != tt.wantFiles {
+				t.Errorf("committed %q, want %q", got, tt.wantFiles)
+			}
+			if got := git(t, "status", "--porcelain"); got != "?? new.go" {
+				t.Errorf("status = %q, want only the untracked file left", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// newCompletionCmd creates the completion subcommand.
+func newCompletionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "completion <bash|zsh|fish|powershell>",
+		Short: "Generate shell completion scripts",
+		Long: `Generate a shell completion script for arc-commit.
+
+Completion covers subcommands and flags, including suggestions for
+--model values.`,
+		Example: `  # Load completions for the current bash session
+  source <(arc-commit completion bash)
+
+  # Install zsh completions
+  arc-commit completion zsh > "${fpath[1]}/_arc-commit"
+
+  # Install fish completions
+  arc-commit completion fish > ~/.config/fish/completions/arc-commit.fish
+
+  # Load completions in PowerShell
+  arc-commit completion powershell | Out-String | Invoke-Expression`,
+		Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, out := cmd.Root(), cmd.OutOrStdout()
+			switch args[0] {
+			case "bash":
+				return root.GenBashCompletionV2(out, true)
+			case "zsh":
+				return root.GenZshCompletion(out)
+			case "fish":
+				return root.GenFishCompletion(out, true)
+			default:
+				return root.GenPowerShellCompletionWithDesc(out)
+			}
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-commit/internal/prompt"
+	"github.com/yourorg/arc-sdk/ai"
+)
+
+func TestCompletionScripts(t *testing.T) {
+	tests := []struct {
+		shell string
+		want  string
+	}{
+		{shell: "bash", want: "__start_arc-commit"},
+		{shell: "zsh", want: "#compdef arc-commit"},
+		{shell: "fish", want: "complete -c arc-commit"},
+		{shell: "powershell", want: "Register-ArgumentCompleter"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			root := NewRootCmd(&ai.Config{})
+			var out bytes.Buffer
+			root.SetOut(&out)
+			root.SetArgs([]string{"completion", tt.shell})
+			if err := root.Execute(); err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(out.String(), tt.want) {
+				t.Errorf("the %s script does not contain %q", tt.shell, tt.want)
+			}
+		})
+	}
+
+	root := NewRootCmd(&ai.Config{})
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs([]string{"completion", "tcsh"})
+	if err := root.Execute(); err == nil {
+		t.Error("completion accepted an unsupported shell")
+	}
+}
+
+func TestCompleteModels(t *testing.T) {
+	all, directive := completeModels(nil, nil, "")
+	if len(all) != len(knownModels) {
+		t.Errorf("%d suggestions, want one per known model (%d)", len(all), len(knownModels))
+	}
+	if directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("directive = %v, want no file completion", directive)
+	}
+
+	defaults := 0
+	for _, s := range all {
+		name, description, _ := strings.Cut(s, "\t")
+		if strings.HasSuffix(description, " (default)") {
+			defaults++
+			if name != prompt.CommitMessageModel {
+				t.Errorf("%s is marked as the default, want %s", name, prompt.CommitMessageModel)
+			}
+		}
+	}
+	if defaults != 1 {
+		t.Errorf("%d models marked as the default, want 1", defaults)
+	}
+
+	prefix := prompt.CommitMessageModel[:len("claude-")+2]
+	some, _ := completeModels(nil, nil, prefix)
+	if len(some) == 0 || len(some) == len(all) {
+		t.Errorf("%d suggestions for %q, want only the matching models", len(some), prefix)
+	}
+	for _, s := range some {
+		if !strings.HasPrefix(s, prefix) {
+			t.Errorf("suggestion %q does not start with %q", s, prefix)
+		}
+	}
+	if none, _ := completeModels(nil, nil, "no-such-model"); len(none) != 0 {
+		t.Errorf("suggestions for an unknown prefix = %q", none)
+	}
+}
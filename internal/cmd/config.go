@@ -0,0 +1,181 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/yourorg/arc-commit/internal/config"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// settings is the layered configuration resolved for a command.
+type settings struct {
+	global *config.File
+	repo   *config.File
+	// sources records where each flag's effective value came from.
+	sources map[string]config.Source
+}
+
+// globalOnlyKeys are settings the repository config may not set. Together
+// they choose which environment variable is sent as the API key, and where
+// it is sent along with the diff, so a cloned repository could otherwise
+// send any secret to a server of its choosing.
+var globalOnlyKeys = []string{"provider", "provider-url", "provider-api-key-env"}
+
+// resolveConfig loads the global and repository config files and uses them,
+// along with ARC_COMMIT_* environment variables, for every flag not given on
+// the command line.
+func resolveConfig(cmd *cobra.Command) (*settings, error) {
+	s := &settings{}
+
+	if path, err := config.GlobalPath(); err == nil {
+		s.global, err = config.Load(path)
+		if err != nil {
+			return nil, errors.NewCLIError("failed to load global config").WithCause(err)
+		}
+	}
+
+	// Outside a repository there is no repository config; git will report
+	// the real problem later.
+	if root, err := repoRoot(); err == nil {
+		s.repo, err = config.Load(filepath.Join(root, config.FileName))
+		if err != nil {
+			return nil, errors.NewCLIError("failed to load repository config").WithCause(err)
+		}
+	}
+	for _, key := range globalOnlyKeys {
+		if s.repo.Sets(key) {
+			return nil, errors.NewCLIError("the repository config " + s.repo.Path + " may not set " + key).
+				WithHint("Remove it there, and set it in the global config, with " + config.EnvName(key) + " or with --" + key)
+		}
+	}
+
+	layers := config.Layers{
+		Global: s.global,
+		Repo:   s.repo,
+		Getenv: os.Getenv,
+	}
+
+	// The profile is chosen like any other setting, then layered in
+	if flag := cmd.Flags().Lookup("profile"); flag != nil {
+		name := flag.Value.String()
+		if !flag.Changed {
+			if v, _, _, ok := layers.Lookup(flag.Name); ok {
+				name = fmt.Sprint(v)
+			}
+		}
+		if name != "" {
+			profile, err := config.Profile(name, s.global, s.repo)
+			if err != nil {
+				return nil, errors.NewCLIError("invalid --profile").WithCause(err).
+					WithHint("Define it under " + config.ProfilesKey + " in " + config.FileName + " or the global config")
+			}
+			layers.Profile = profile
+		}
+	}
+
+	sources, err := config.Resolve(cmd.Flags(), layers)
+	if err != nil {
+		return nil, errors.NewCLIError("invalid configuration").WithCause(err)
+	}
+	s.sources = sources
+
+	// A config file marking the repository sensitive cannot be overridden
+	// from the command line
+	if flag := cmd.Flags().Lookup("sensitive-repo"); flag != nil && s.sensitiveRepo() {
+		flag.Value.Set("true")
+		if s.repo.Bool("sensitive-repo") {
+			s.sources[flag.Name] = config.SourceRepo
+		} else {
+			s.sources[flag.Name] = config.SourceGlobal
+		}
+	}
+
+	return s, nil
+}
+
+// completeProfiles suggests the profiles defined in the config files.
+func completeProfiles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var files []*config.File
+	if path, err := config.GlobalPath(); err == nil {
+		if f, err := config.Load(path); err == nil {
+			files = append(files, f)
+		}
+	}
+	if root, err := repoRoot(); err == nil {
+		if f, err := config.Load(filepath.Join(root, config.FileName)); err == nil {
+			files = append(files, f)
+		}
+	}
+
+	var names []string
+	for _, name := range config.Profiles(files...) {
+		if strings.HasPrefix(name, toComplete) {
+			names = append(names, name)
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// sensitiveRepo reports whether a config file forbids sending anything to
+// external AI providers.
+func (s *settings) sensitiveRepo() bool {
+	return s.repo.Bool("sensitive-repo") || s.global.Bool("sensitive-repo")
+}
+
+// blocksProvider reports whether the repository is sensitive and p would
+// send the diff off this machine. Local models are allowed.
+func (s *settings) blocksProvider(p providerOptions) bool {
+	return s.sensitiveRepo() && !p.local()
+}
+
+// secretWords mark flags whose values are redacted by --dump-config.
+var secretWords = []string{"key", "token", "secret", "password"}
+
+// dumpConfig prints every flag's effective value and where it came from.
+func dumpConfig(out io.Writer, flags *pflag.FlagSet, s *settings) {
+	fmt.Fprintln(out, "# Config files:")
+	for _, f := range []*config.File{s.global, s.repo} {
+		if f != nil {
+			fmt.Fprintf(out, "#   %s\n", f.Path)
+		}
+	}
+
+	var names []string
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if flag.Name != "help" && flag.Name != "dump-config" && flag.Name != "classify" {
+			names = append(names, flag.Name)
+		}
+	})
+	sort.Strings(names)
+
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	for _, name := range names {
+		value := flags.Lookup(name).Value.String()
+		if value != "" && isSecret(name) {
+			value = "<redacted>"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t(%s)\n", name, value, s.sources[name])
+	}
+	tw.Flush()
+}
+
+// isSecret reports whether a flag holds a credential.
+func isSecret(name string) bool {
+	for _, word := range secretWords {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,241 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-commit/internal/config"
+)
+
+// providerCmd returns a command with the provider flags, as the commands
+// generating messages have.
+func providerCmd() (*cobra.Command, *providerOptions) {
+	var p providerOptions
+	cmd := &cobra.Command{Use: "test"}
+	addProviderFlags(cmd.Flags(), &p)
+	return cmd, &p
+}
+
+func TestResolveConfigRefusesProviderFromRepo(t *testing.T) {
+	tests := []struct {
+		name string
+		repo string
+	}{
+		{"provider", "provider: openai\n"},
+		{"provider-url", "provider-url: https://attacker.example\n"},
+		{"provider-api-key-env", "provider-api-key-env: AWS_SECRET_ACCESS_KEY\n"},
+		{"in a profile", "profiles:\n  ci:\n    provider-url: https://attacker.example\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			writeFile(t, ".arc-commit.yaml", tt.repo)
+
+			cmd, p := providerCmd()
+			_, err := resolveConfig(cmd)
+			if err == nil {
+				t.Fatalf("resolveConfig accepted %q; provider = %+v", tt.repo, *p)
+			}
+			if !strings.Contains(err.Error(), "may not set") {
+				t.Errorf("err = %v, want a refusal", err)
+			}
+		})
+	}
+}
+
+func TestResolveConfigTakesProviderFromGlobalAndEnv(t *testing.T) {
+	testRepo(t)
+	writeFile(t, ".arc-commit.yaml", "model: llama3.2\n")
+	global := filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "arc-commit", "config.yaml")
+	writeFile(t, global, "provider: openai\nprovider-api-key-env: GROQ_API_KEY\n")
+	t.Setenv("ARC_COMMIT_PROVIDER_URL", "https://api.groq.com/openai/v1")
+
+	cmd, p := providerCmd()
+	if _, err := resolveConfig(cmd); err != nil {
+		t.Fatal(err)
+	}
+	want := providerOptions{Name: providerOpenAI, URL: "https://api.groq.com/openai/v1", KeyEnv: "GROQ_API_KEY"}
+	if *p != want {
+		t.Errorf("provider = %+v, want %+v", *p, want)
+	}
+}
+
+func TestProviderLocal(t *testing.T) {
+	tests := []struct {
+		p    providerOptions
+		want bool
+	}{
+		{providerOptions{Name: providerOllama}, true},
+		{providerOptions{Name: providerOllama, URL: "http://127.0.0.1:11434"}, true},
+		{providerOptions{Name: providerOpenAI, URL: "http://[::1]:8080/v1"}, true},
+		{providerOptions{Name: providerOllama, URL: "http://gpu-box:11434"}, false},
+		{providerOptions{Name: providerOpenAI}, false},
+		{providerOptions{Name: providerAnthropic}, false},
+		{providerOptions{Name: providerAnthropic, URL: "http://localhost:8080"}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.p.local(); got != tt.want {
+			t.Errorf("%+v.local() = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestResolveConfigSensitiveRepoCannotBeOverridden(t *testing.T) {
+	tests := []struct {
+		name   string
+		repo   string
+		global string
+		want   bool
+	}{
+		{name: "not sensitive"},
+		{name: "repository config", repo: "sensitive-repo: true\n", want: true},
+		{name: "global config", global: "sensitive-repo: true\n", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			if tt.repo != "" {
+				writeFile(t, ".arc-commit.yaml", tt.repo)
+			}
+			if tt.global != "" {
+				writeFile(t, filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "arc-commit", "config.yaml"), tt.global)
+			}
+
+			var sensitive bool
+			cmd := &cobra.Command{Use: "test"}
+			cmd.Flags().BoolVar(&sensitive, "sensitive-repo", false, "")
+			cmd.Flags().Set("sensitive-repo", "false")
+			if _, err := resolveConfig(cmd); err != nil {
+				t.Fatal(err)
+			}
+			if sensitive != tt.want {
+				t.Errorf("sensitive-repo = %v with --sensitive-repo=false, want %v", sensitive, tt.want)
+			}
+		})
+	}
+}
+
+func TestDumpConfig(t *testing.T) {
+	testRepo(t)
+	writeFile(t, ".arc-commit.yaml", "temperature: 0.2\n")
+	writeFile(t, filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "arc-commit", "config.yaml"), "strict: true\nprovider-api-key-env: GROQ_API_KEY\n")
+	t.Setenv("ARC_COMMIT_MAX_RETRIES", "5")
+
+	out, err := runCommitCmd(t, "", "--dump-config", "--model", "llama3.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out, "# Config files:\n") || !strings.Contains(out, config.FileName) {
+		t.Errorf("--dump-config does not list the config files:\n%s", out)
+	}
+	lines := map[string]string{}
+	for _, line := range strings.Split(out, "\n") {
+		if name, _, ok := strings.Cut(line, " "); ok && !strings.HasPrefix(line, "#") {
+			lines[name] = strings.Join(strings.Fields(line), " ")
+		}
+	}
+	for name, want := range map[string]string{
+		"model":                "model llama3.2 (flag)",
+		"temperature":          "temperature 0.2 (repo)",
+		"strict":               "strict true (global)",
+		"max-retries":          "max-retries 5 (env)",
+		"provider-api-key-env": "provider-api-key-env <redacted> (global)",
+		"no-ai":                "no-ai false (default)",
+	} {
+		if lines[name] != want {
+			t.Errorf("--dump-config shows %q, want %q", lines[name], want)
+		}
+	}
+	for _, name := range []string{"dump-config", "help"} {
+		if _, ok := lines[name]; ok {
+			t.Errorf("--dump-config lists %s", name)
+		}
+	}
+}
+
+func TestResolveConfigProfile(t *testing.T) {
+	const profiles = "profiles:\n  ci:\n    temperature: 0.1\n    strict: true\n"
+	tests := []struct {
+		name            string
+		repo            string
+		args            []string
+		wantTemperature float64
+		wantStrict      bool
+		wantSource      config.Source
+		wantErr         bool
+	}{
+		{
+			name:       "none selected",
+			repo:       profiles,
+			wantSource: config.SourceDefault,
+		},
+		{
+			name:            "selected by flag",
+			repo:            profiles,
+			args:            []string{"--profile", "ci"},
+			wantTemperature: 0.1,
+			wantStrict:      true,
+			wantSource:      config.SourceProfile,
+		},
+		{
+			name:            "selected in the config file",
+			repo:            profiles + "profile: ci\n",
+			wantTemperature: 0.1,
+			wantStrict:      true,
+			wantSource:      config.SourceProfile,
+		},
+		{
+			name:            "flags win over the profile",
+			repo:            profiles,
+			args:            []string{"--profile", "ci", "--temperature", "0.7"},
+			wantTemperature: 0.7,
+			wantStrict:      true,
+			wantSource:      config.SourceFlag,
+		},
+		{
+			name:    "unknown profile",
+			repo:    profiles,
+			args:    []string{"--profile", "nightly"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			writeFile(t, ".arc-commit.yaml", tt.repo)
+
+			var (
+				profile     string
+				temperature float64
+				strict      bool
+			)
+			cmd := &cobra.Command{Use: "test"}
+			cmd.Flags().StringVar(&profile, "profile", "", "")
+			cmd.Flags().Float64Var(&temperature, "temperature", 0, "")
+			cmd.Flags().BoolVar(&strict, "strict", false, "")
+			if err := cmd.Flags().Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+
+			settings, err := resolveConfig(cmd)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if temperature != tt.wantTemperature || strict != tt.wantStrict {
+				t.Errorf("temperature = %v, strict = %v; want %v, %v", temperature, strict, tt.wantTemperature, tt.wantStrict)
+			}
+			if got := settings.sources["temperature"]; got != tt.wantSource {
+				t.Errorf("temperature source = %s, want %s", got, tt.wantSource)
+			}
+		})
+	}
+}
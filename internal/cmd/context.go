@@ -0,0 +1,79 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/yourorg/arc-commit/internal/diff"
+	"github.com/yourorg/arc-commit/internal/prompt"
+)
+
+// maxContextTotalLines caps the surrounding code added across all hunks.
+const maxContextTotalLines = 300
+
+// definitionPattern matches lines that usually start a function, method, or
+// type in common languages.
+var definitionPattern = regexp.MustCompile(`^\s*(func |def |class |fn |pub fn |function |async function |type |struct |interface |impl |module |(public|private|protected|static|export)\s)`)
+
+// surroundingContext returns the code around each hunk in the staged
+// version of the file: from the nearest enclosing definition (looking back
+// at most maxLines lines) to the end of the hunk. Context is only gathered
+// when the diff has at most maxChanges changed lines.
+func surroundingContext(changes *diff.Diff, maxChanges, maxLines int) []prompt.CodeContext {
+	if changes.Added()+changes.Removed() > maxChanges {
+		return nil
+	}
+
+	var contexts []prompt.CodeContext
+	total := 0
+	for _, f := range changes.Files {
+		if f.Deleted || f.Binary || len(f.Hunks) == 0 {
+			continue
+		}
+
+		// The index holds exactly what will be committed
+		content, err := exec.Command("git", "show", ":"+f.Path()).Output()
+		if err != nil {
+			continue
+		}
+		lines := strings.Split(string(content), "\n")
+
+		for _, h := range f.Hunks {
+			start, end := enclosingRange(lines, h, maxLines)
+			if end <= start || total+(end-start) > maxContextTotalLines {
+				continue
+			}
+			total += end - start
+			contexts = append(contexts, prompt.CodeContext{
+				Path:      f.Path(),
+				StartLine: start + 1,
+				EndLine:   end,
+				Code:      strings.Join(lines[start:end], "\n"),
+			})
+		}
+	}
+	return contexts
+}
+
+// enclosingRange returns the zero-based, half-open line range from the
+// definition enclosing the hunk to the hunk's last line.
+func enclosingRange(lines []string, h *diff.Hunk, maxLines int) (start, end int) {
+	first := h.NewStart - 1
+	end = min(first+h.NewLines, len(lines))
+	if first < 0 {
+		first = 0
+	}
+
+	start = max(first-maxLines, 0)
+	for i := first; i >= start; i-- {
+		if i < len(lines) && definitionPattern.MatchString(lines[i]) {
+			return i, end
+		}
+	}
+	// No definition nearby: fall back to a few lines of leading context
+	return max(first-5, 0), end
+}
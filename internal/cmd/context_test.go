@@ -0,0 +1,96 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yourorg/arc-commit/internal/diff"
+)
+
+const contextSource = `package app
+
+func Add(a, b int) int {
+	sum := a + b
+	return sum
+}
+`
+
+func TestEnclosingRange(t *testing.T) {
+	source := strings.Split(contextSource, "\n")
+	plain := strings.Split(strings.Repeat("line\n", 20), "\n")
+
+	tests := []struct {
+		name      string
+		lines     []string
+		hunk      diff.Hunk
+		maxLines  int
+		wantStart int
+		wantEnd   int
+	}{
+		{
+			name:      "enclosing function",
+			lines:     source,
+			hunk:      diff.Hunk{NewStart: 5, NewLines: 1},
+			maxLines:  40,
+			wantStart: 2,
+			wantEnd:   5,
+		},
+		{
+			name:      "definition too far up",
+			lines:     source,
+			hunk:      diff.Hunk{NewStart: 5, NewLines: 1},
+			maxLines:  1,
+			wantStart: 0,
+			wantEnd:   5,
+		},
+		{
+			name:      "no definition",
+			lines:     plain,
+			hunk:      diff.Hunk{NewStart: 15, NewLines: 2},
+			maxLines:  40,
+			wantStart: 9,
+			wantEnd:   16,
+		},
+		{
+			name:      "hunk past the end",
+			lines:     source,
+			hunk:      diff.Hunk{NewStart: 4, NewLines: 10},
+			maxLines:  40,
+			wantStart: 2,
+			wantEnd:   len(source),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := enclosingRange(tt.lines, &tt.hunk, tt.maxLines)
+			if start != tt.wantStart || end != tt.wantEnd {
+				t.Errorf("enclosingRange() = %d, %d, want %d, %d", start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestSurroundingContext(t *testing.T) {
+	testRepo(t)
+	commitFile(t, "app.go", contextSource, "feat: add Add")
+	writeFile(t, "app.go", strings.Replace(contextSource, "return sum", "return a + b", 1))
+	git(t, "add", "app.go")
+	changes := diff.Parse(git(t, "diff", "--staged", "-U0") + "\n")
+
+	contexts := surroundingContext(changes, 30, 40)
+	if len(contexts) != 1 {
+		t.Fatalf("got %d contexts, want 1", len(contexts))
+	}
+	got := contexts[0]
+	wantCode := "func Add(a, b int) int {\n\tsum := a + b\n\treturn a + b"
+	if got.Path != "app.go" || got.StartLine != 3 || got.EndLine != 5 || got.Code != wantCode {
+		t.Errorf("context = %s:%d-%d %q, want app.go:3-5 %q", got.Path, got.StartLine, got.EndLine, got.Code, wantCode)
+	}
+
+	if contexts := surroundingContext(changes, 1, 40); contexts != nil {
+		t.Errorf("diff over the change limit got context: %v", contexts)
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/yourorg/arc-commit/internal/deps"
+	"github.com/yourorg/arc-commit/internal/diff"
+	"github.com/yourorg/arc-commit/internal/prompt"
+)
+
+// dependencyBumps lists the dependency version changes in the staged
+// manifests, comparing HEAD with the index. Manifests that cannot be parsed
+// are skipped with a warning.
+func dependencyBumps(out io.Writer, changes *diff.Diff) []prompt.DependencyBump {
+	var bumps []prompt.DependencyBump
+	for _, f := range changes.Files {
+		parse, ok := deps.For(f.Path())
+		if !ok {
+			continue
+		}
+
+		var before, after map[string]string
+		var err error
+		if !f.New {
+			before, err = manifestVersions(parse, "HEAD:"+f.OldPath)
+		}
+		if err == nil && !f.Deleted {
+			after, err = manifestVersions(parse, ":"+f.NewPath)
+		}
+		if err != nil {
+			fmt.Fprintf(out, "Warning: skipping %s: %v\n", f.Path(), err)
+			continue
+		}
+
+		for _, b := range deps.Compare(before, after) {
+			bumps = append(bumps, prompt.DependencyBump{
+				Manifest: f.Path(),
+				Name:     b.Name,
+				Old:      b.Old,
+				New:      b.New,
+			})
+		}
+	}
+	return bumps
+}
+
+// manifestVersions parses the manifest stored at a git object such as
+// "HEAD:go.mod" or ":package.json" (the index).
+func manifestVersions(parse deps.Parser, object string) (map[string]string, error) {
+	content, err := exec.Command("git", "show", object).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", object, err)
+	}
+	if strings.TrimSpace(string(content)) == "" {
+		return nil, nil
+	}
+	return parse(content)
+}
+
+// dependencyMessage builds a dependency update message without AI, listing
+// every version change in the body.
+func dependencyMessage(bumps []prompt.DependencyBump) string {
+	header := fmt.Sprintf("chore(deps): bump %d dependencies", len(bumps))
+	if len(bumps) == 1 {
+		header = "chore(deps): " + dependencySummary(bumps[0])
+	}
+
+	var body strings.Builder
+	for _, b := range bumps {
+		body.WriteString("- " + prompt.DependencyLine(b) + "\n")
+	}
+	return header + "\n\n" + strings.TrimSpace(body.String())
+}
+
+// dependencySummary describes a single dependency change for a header.
+func dependencySummary(b prompt.DependencyBump) string {
+	switch {
+	case b.Old == "":
+		return "add " + b.Name + " " + b.New
+	case b.New == "":
+		return "remove " + b.Name
+	default:
+		return "bump " + b.Name + " to " + b.New
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/arc-commit/internal/diff"
+	"github.com/yourorg/arc-commit/internal/prompt"
+)
+
+func TestDependencyBumps(t *testing.T) {
+	testRepo(t)
+	commitFile(t, "go.mod", "module example.com/app\n\nrequire (\n\tgithub.com/spf13/cobra v1.7.0\n\tgopkg.in/yaml.v3 v3.0.0\n)\n", "chore: add go.mod")
+	writeFile(t, "go.mod", "module example.com/app\n\nrequire (\n\tgithub.com/spf13/cobra v1.8.0\n\tgolang.org/x/sys v0.20.0\n)\n")
+	writeFile(t, "web/package.json", `{"dependencies": {"react": "^18.2.0"}}`)
+	writeFile(t, "broken/package.json", `{"dependencies": `)
+	writeFile(t, "app.go", "package app\n")
+	git(t, "add", ".")
+
+	var out strings.Builder
+	got := dependencyBumps(&out, diff.Parse(git(t, "diff", "--staged")+"\n"))
+	want := []prompt.DependencyBump{
+		{Manifest: "go.mod", Name: "github.com/spf13/cobra", Old: "v1.7.0", New: "v1.8.0"},
+		{Manifest: "go.mod", Name: "golang.org/x/sys", New: "v0.20.0"},
+		{Manifest: "go.mod", Name: "gopkg.in/yaml.v3", Old: "v3.0.0"},
+		{Manifest: "web/package.json", Name: "react", New: "^18.2.0"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("dependencyBumps() = %v, want %v", got, want)
+	}
+	if !strings.Contains(out.String(), "Warning: skipping broken/package.json") {
+		t.Errorf("no warning for the broken manifest:\n%s", out.String())
+	}
+}
+
+func TestDependencyMessage(t *testing.T) {
+	tests := []struct {
+		name  string
+		bumps []prompt.DependencyBump
+		want  string
+	}{
+		{
+			name:  "one bump",
+			bumps: []prompt.DependencyBump{{Manifest: "go.mod", Name: "cobra", Old: "v1.7.0", New: "v1.8.0"}},
+			want:  "chore(deps): bump cobra to v1.8.0\n\n- cobra v1.7.0 -> v1.8.0 (go.mod)",
+		},
+		{
+			name:  "added",
+			bumps: []prompt.DependencyBump{{Manifest: "package.json", Name: "react", New: "^18.2.0"}},
+			want:  "chore(deps): add react ^18.2.0\n\n- react ^18.2.0 (added, package.json)",
+		},
+		{
+			name:  "removed",
+			bumps: []prompt.DependencyBump{{Manifest: "go.mod", Name: "yaml", Old: "v3.0.0"}},
+			want:  "chore(deps): remove yaml\n\n- yaml v3.0.0 (removed, go.mod)",
+		},
+		{
+			name: "several",
+			bumps: []prompt.DependencyBump{
+				{Manifest: "go.mod", Name: "cobra", Old: "v1.7.0", New: "v1.8.0"},
+				{Manifest: "go.mod", Name: "sys", New: "v0.20.0"},
+			},
+			want: "chore(deps): bump 2 dependencies\n\n- cobra v1.7.0 -> v1.8.0 (go.mod)\n- sys v0.20.0 (added, go.mod)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dependencyMessage(tt.bumps); got != tt.want {
+				t.Errorf("dependencyMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
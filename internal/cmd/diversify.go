@@ -0,0 +1,53 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/yourorg/arc-commit/internal/prompt"
+)
+
+// Levels accepted by --diversify.
+const (
+	diversifyOff  = "off"
+	diversifyLow  = "low"
+	diversifyHigh = "high"
+)
+
+// diversifyLevels lists the valid --diversify values.
+var diversifyLevels = []string{diversifyOff, diversifyLow, diversifyHigh}
+
+// highTemperatureBoost is how much --diversify=high raises the temperature.
+const highTemperatureBoost = 0.3
+
+// diversify adjusts a regeneration so it differs from the earlier
+// suggestions. Low shows the model its last suggestion and a random nonce;
+// high shows every earlier suggestion and samples at a higher temperature.
+func diversify(level string, previous []string, promptOpts *prompt.CommitOptions, req *GenerateRequest) {
+	if level == diversifyOff || len(previous) == 0 {
+		return
+	}
+
+	promptOpts.Variation = nonce()
+	promptOpts.Avoid = previous[len(previous)-1:]
+	if level != diversifyHigh {
+		return
+	}
+
+	promptOpts.Avoid = previous
+	temperature := 1.0
+	if req.Temperature != nil {
+		temperature = min(*req.Temperature+highTemperatureBoost, 1.0)
+	}
+	req.Temperature = &temperature
+}
+
+// nonce returns a short random string that makes each request unique.
+func nonce() string {
+	b := make([]byte, 4)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
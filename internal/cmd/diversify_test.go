@@ -0,0 +1,90 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/yourorg/arc-commit/internal/prompt"
+)
+
+func TestDiversify(t *testing.T) {
+	previous := []string{"feat: add login", "feat: add the login page"}
+	tests := []struct {
+		name            string
+		level           string
+		previous        []string
+		temperature     *float64
+		wantAvoid       []string
+		wantVariation   bool
+		wantTemperature *float64
+	}{
+		{name: "off", level: diversifyOff, previous: previous},
+		{name: "first generation", level: diversifyHigh},
+		{
+			name:          "low",
+			level:         diversifyLow,
+			previous:      previous,
+			wantAvoid:     previous[1:],
+			wantVariation: true,
+		},
+		{
+			name:            "high",
+			level:           diversifyHigh,
+			previous:        previous,
+			wantAvoid:       previous,
+			wantVariation:   true,
+			wantTemperature: ptr(1.0),
+		},
+		{
+			name:            "high raises the configured temperature",
+			level:           diversifyHigh,
+			previous:        previous,
+			temperature:     ptr(0.5),
+			wantAvoid:       previous,
+			wantVariation:   true,
+			wantTemperature: ptr(0.8),
+		},
+		{
+			name:            "high caps the temperature",
+			level:           diversifyHigh,
+			previous:        previous,
+			temperature:     ptr(0.9),
+			wantAvoid:       previous,
+			wantVariation:   true,
+			wantTemperature: ptr(1.0),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts prompt.CommitOptions
+			req := GenerateRequest{Temperature: tt.temperature}
+			diversify(tt.level, tt.previous, &opts, &req)
+
+			if !slices.Equal(opts.Avoid, tt.wantAvoid) {
+				t.Errorf("Avoid = %q, want %q", opts.Avoid, tt.wantAvoid)
+			}
+			if (opts.Variation != "") != tt.wantVariation {
+				t.Errorf("Variation = %q, want one: %v", opts.Variation, tt.wantVariation)
+			}
+			if tt.wantTemperature == nil {
+				if req.Temperature != tt.temperature {
+					t.Errorf("temperature changed to %v", *req.Temperature)
+				}
+			} else if req.Temperature == nil || math.Abs(*req.Temperature-*tt.wantTemperature) > 1e-9 {
+				t.Errorf("temperature = %v, want %v", req.Temperature, *tt.wantTemperature)
+			}
+		})
+	}
+
+	if nonce() == nonce() {
+		t.Error("nonce() returned the same value twice")
+	}
+}
+
+func ptr(v float64) *float64 {
+	return &v
+}
@@ -0,0 +1,635 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/yourorg/arc-commit/internal/classify"
+	"github.com/yourorg/arc-commit/internal/diff"
+	commitmsg "github.com/yourorg/arc-commit/internal/message"
+	"github.com/yourorg/arc-commit/internal/prompt"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// draft is a generated message along with what it was generated from.
+type draft struct {
+	message string
+	// candidates holds every initial message with --candidates; the
+	// message is the first of them.
+	candidates []string
+	diffHash   string
+	changes    *diff.Diff
+	// generate produces a new message for the same changes, guided by
+	// optional feedback.
+	generate func(feedback string) (string, error)
+	// generateN produces n alternative messages at once.
+	generateN func(feedback string, n int) ([]string, error)
+}
+
+// draftMessage runs the first steps of the commit workflow: it checks and
+// reads the staged changes and generates the initial message, or restores
+// it from an interrupted session. Answers are read from reader and progress
+// is written to out.
+func draftMessage(gen Generator, opts commitOptions, reader *bufio.Reader, out io.Writer) (*draft, error) {
+	staged, err := readStagedChanges(gen, opts, reader, out)
+	if err != nil {
+		return nil, err
+	}
+	plan, gen, err := planPrompt(gen, opts, reader, out, staged)
+	if err != nil {
+		return nil, err
+	}
+	g := newDraftGenerator(gen, opts, out, staged.changes, plan)
+
+	// Initial message generation (or restore an interrupted session)
+	diffHash := hashDiff(staged.raw)
+	message := ""
+	var initial []string
+	if opts.resume {
+		if message = resumeSession(out, diffHash); message != "" {
+			g.previous = append(g.previous, message)
+		}
+	}
+	if message == "" {
+		if gen == nil {
+			fmt.Fprintln(out, "Generating commit message from the diff...")
+		} else {
+			fmt.Fprintln(out, "Generating commit message with AI...")
+		}
+		if opts.candidates > 1 {
+			initial, err = g.candidates(intentFeedback(opts.intent), opts.candidates)
+		} else {
+			message, err = g.generate(intentFeedback(opts.intent))
+		}
+		if err != nil {
+			return nil, errors.NewCLIError("failed to generate commit message").WithCause(err)
+		}
+		if len(initial) > 0 {
+			message = initial[0]
+		}
+		persistSession(out, diffHash, message)
+	}
+
+	return &draft{
+		message:    message,
+		candidates: initial,
+		diffHash:   diffHash,
+		changes:    staged.changes,
+		generate:   g.generate,
+		generateN:  g.candidates,
+	}, nil
+}
+
+// stagedChanges are the changes a message is drafted for.
+type stagedChanges struct {
+	raw     string
+	changes *diff.Diff
+	// read reads the diff again, with extra git diff arguments.
+	read func(args ...string) (string, error)
+}
+
+// readStagedChanges stages what the options ask for, checks that there is
+// something to commit and reads it. Checks that could stop the run, such
+// as the signing setup, the commit size and --review, come first so no AI
+// call is spent on a commit that will not happen.
+func readStagedChanges(gen Generator, opts commitOptions, reader *bufio.Reader, out io.Writer) (*stagedChanges, error) {
+	if opts.fromBranch != "" {
+		if err := stageBranch(out, opts.fromBranch); err != nil {
+			return nil, err
+		}
+	}
+	if opts.stageHunks {
+		if err := stageHunks(out); err != nil {
+			return nil, err
+		}
+	}
+
+	// With --all, tracked changes in the working tree count as staged;
+	// git commit --all stages them once the message is accepted
+	readDiff := getStagedDiff
+	if opts.all {
+		readDiff = getTrackedDiff
+		if err := showUnstaged(out); err != nil {
+			return nil, err
+		}
+	}
+
+	fmt.Fprintln(out, "Checking for staged changes...")
+	if err := checkStagedChanges(); err != nil && !opts.all {
+		return nil, errors.NewCLIError("no staged changes found").
+			WithHint("Stage changes first: git add <files>, or include all tracked changes with --all")
+	}
+
+	// Committing on a detached HEAD works, but nothing branch-based applies
+	if currentBranch() == "" {
+		fmt.Fprintln(out, "Note: HEAD is detached; the commit will not be on any branch, and branch-based context is skipped.")
+	}
+
+	// Catch signing misconfiguration before spending an AI call
+	if signingRequested(opts.signKey) && signingFormat() == "ssh" {
+		if err := checkSSHSigning(out, opts.signKey); err != nil {
+			return nil, err
+		}
+	}
+
+	fmt.Fprintln(out, "Generating diff...")
+	rawDiff, err := readDiff()
+	if err != nil {
+		return nil, errors.NewCLIError("failed to get diff").WithCause(err)
+	}
+
+	if len(rawDiff) == 0 {
+		return nil, errors.NewCLIError("no changes to commit").
+			WithHint("Stage changes first: git add <files>")
+	}
+	changes := diff.Parse(rawDiff)
+
+	// Catch accidental over-staging before spending an AI call
+	if !opts.autoYes {
+		if err := checkCommitSize(reader, out, opts.events, changes, opts.countGuard); err != nil {
+			return nil, err
+		}
+	}
+	// The diff is fenced off as data in the prompt, but a message steered
+	// by it should still get a closer look
+	if gen != nil {
+		if phrases := prompt.InjectionPhrases(rawDiff); len(phrases) > 0 {
+			fmt.Fprintf(out, "Warning: the diff contains text that looks like instructions to the model (%s); check the message carefully.\n", strings.Join(phrases, "; "))
+		}
+	}
+	if opts.review {
+		if gen == nil {
+			fmt.Fprintln(out, "Warning: --review needs AI; skipping the review.")
+		} else if err := reviewChanges(reader, out, opts.events, gen, changes, !opts.autoYes); err != nil {
+			return nil, err
+		}
+	}
+	return &stagedChanges{raw: rawDiff, changes: changes, read: readDiff}, nil
+}
+
+// promptPlan is what the model is asked to describe: the part of the
+// staged changes it sees and the prompt options for them.
+type promptPlan struct {
+	diff  *diff.Diff
+	opts  prompt.CommitOptions
+	kinds []classify.File
+	// formatting is set when only whitespace changed.
+	formatting bool
+	// ticket is the ticket the commit is for; nil when there is none.
+	ticket *prompt.Ticket
+}
+
+// planPrompt works out the prompt for staged. It returns the generator to
+// use, which is nil when the message is better built without AI, e.g. for
+// a whitespace-only change with --ignore-whitespace.
+func planPrompt(gen Generator, opts commitOptions, reader *bufio.Reader, out io.Writer, staged *stagedChanges) (*promptPlan, Generator, error) {
+	plan := &promptPlan{kinds: opts.classifier.Diff(staged.changes)}
+
+	// A reformatting commit is described as such, without an AI call when
+	// the prompt would ignore all of it anyway
+	plan.formatting = whitespaceOnly(staged.changes, staged.read)
+	if plan.formatting {
+		fmt.Fprintln(out, "Only whitespace changed; suggesting a style: commit.")
+		if opts.ignoreWhitespace {
+			gen = nil
+		}
+	}
+
+	promptDiff, summarized, partOutlines, err := selectPromptDiff(gen, opts, reader, out, staged, plan.formatting)
+	if err != nil {
+		return nil, nil, err
+	}
+	plan.diff = promptDiff
+
+	plan.opts, err = commitPromptOptions(gen, opts, out, staged.changes, plan)
+	if err != nil {
+		return nil, nil, err
+	}
+	plan.opts.PartOutlines = partOutlines
+	if summarized != nil {
+		for _, f := range summarized.Files {
+			plan.opts.Summarized = append(plan.opts.Summarized, diff.FileHeading(f))
+		}
+	}
+
+	if plan.ticket, err = commitTicket(out, opts, gen != nil); err != nil {
+		return nil, nil, err
+	}
+	plan.opts.Ticket = plan.ticket
+
+	if opts.promptTemplate != "" {
+		if plan.opts.Custom, err = loadPromptTemplate(opts.promptTemplate); err != nil {
+			return nil, nil, errors.NewCLIError("failed to load --prompt-template").WithCause(err)
+		}
+		if err := prompt.CheckCustom(diff.Format(promptDiff), plan.opts); err != nil {
+			return nil, nil, errors.NewCLIError("--prompt-template failed").WithCause(err).
+				WithHint("Check the fields it uses against the README's list of template variables")
+		}
+	}
+
+	// Diagnostics go to stderr whatever out is, so they never mix with
+	// machine-read output
+	if opts.diffStats {
+		if gen == nil {
+			fmt.Fprintln(os.Stderr, "Prompt composition (--verbose-diff-stats): nothing is sent; the message is built locally.")
+		} else {
+			printPromptStats(os.Stderr, staged.changes, promptDiff, summarized, plan.opts)
+		}
+	}
+
+	if opts.confirmModelSwitch && gen != nil && !opts.autoYes {
+		system, user := prompt.CommitMessage(diff.Format(promptDiff), "", plan.opts)
+		input, output := estimateTokens(system+user), opts.maxTokens
+		if output <= 0 {
+			output = assumedOutputTokens
+		}
+		if opts.twoPhase {
+			// The outline is a second call over the same diff
+			input, output = input*2, output*2
+		}
+		if err := confirmModelSwitch(reader, out, opts.events, opts.model, opts.baseModel, input, output); err != nil {
+			return nil, nil, err
+		}
+	}
+	return plan, gen, nil
+}
+
+// selectPromptDiff narrows the staged changes down to what the model sees
+// in full. Files it only gets the stats of are returned as summarized, and
+// with --prompt-token-budget the outlines of the parts that did not fit.
+// The prompt may see the changes in a different order than they apply.
+func selectPromptDiff(gen Generator, opts commitOptions, reader *bufio.Reader, out io.Writer, staged *stagedChanges, formatting bool) (promptDiff, summarized *diff.Diff, partOutlines []string, err error) {
+	promptDiff = staged.changes
+	if opts.ignoreWhitespace && !formatting {
+		if wsDiff, err := staged.read("-w"); err == nil {
+			promptDiff = diff.Parse(wsDiff)
+		}
+	}
+	if len(opts.excludePaths) > 0 && !opts.includeAll {
+		promptDiff = diff.Filter(promptDiff, func(f *diff.File, _ *diff.Hunk) bool {
+			return !excludedPath(f.Path(), opts.excludePaths)
+		})
+		if len(promptDiff.Files) == 0 && gen != nil {
+			return nil, nil, nil, errors.NewCLIError("every staged file matches --exclude-path").
+				WithHint("Stage other changes too, or write the message yourself with --no-ai")
+		}
+	}
+	// Lockfiles and generated code say little about a change; the model
+	// gets their stats, unless nothing else is staged
+	var generated []*diff.File
+	if len(opts.generatedPaths) > 0 && !opts.includeAll {
+		rest := diff.Filter(promptDiff, func(f *diff.File, _ *diff.Hunk) bool {
+			return !excludedPath(f.Path(), opts.generatedPaths)
+		})
+		if len(rest.Files) > 0 && len(rest.Files) < len(promptDiff.Files) {
+			for _, f := range promptDiff.Files {
+				if excludedPath(f.Path(), opts.generatedPaths) {
+					generated = append(generated, f)
+				}
+			}
+			fmt.Fprintln(out, "Stats only for lockfiles and generated files (--include-all sends them): "+strings.Join(filePaths(&diff.Diff{Files: generated}), ", "))
+			promptDiff = rest
+		}
+	}
+	if opts.selectHunks {
+		selected, err := selectHunks(reader, out, opts.events, promptDiff)
+		if err != nil {
+			return nil, nil, nil, errors.NewCLIError("hunk selection failed").WithCause(err)
+		}
+		if len(selected.Files) == 0 {
+			fmt.Fprintln(out, "Warning: no hunks selected; the model will see the whole diff.")
+		} else {
+			promptDiff = selected
+		}
+	}
+	if opts.maxFilesInPrompt > 0 && len(promptDiff.Files) > opts.maxFilesInPrompt {
+		promptDiff, summarized = diff.Largest(promptDiff, opts.maxFilesInPrompt)
+		fmt.Fprintf(out, "Prompt has full diffs for %d file(s) and stats only for %d (--max-files-in-prompt):\n", len(promptDiff.Files), len(summarized.Files))
+		fmt.Fprintln(out, "  full:  "+strings.Join(filePaths(promptDiff), ", "))
+		fmt.Fprintln(out, "  stats: "+strings.Join(filePaths(summarized), ", "))
+	}
+	if len(generated) > 0 {
+		if summarized == nil {
+			summarized = &diff.Diff{}
+		}
+		summarized.Files = append(summarized.Files, generated...)
+	}
+	if gen != nil && opts.tokenBudget > 0 {
+		fitted := fitBudget(promptDiff, opts.tokenBudget, opts.generatedPaths)
+		if len(fitted.stats) > 0 || len(fitted.parts) > 0 {
+			fmt.Fprintf(out, "The diff is over the --prompt-token-budget of %d tokens: %d file(s) in full, %d as stats only, %d outlined in %d part(s).\n",
+				opts.tokenBudget, len(fitted.full.Files), len(fitted.stats), len(promptDiff.Files)-len(fitted.full.Files)-len(fitted.stats), len(fitted.parts))
+		}
+		promptDiff = fitted.full
+		if len(fitted.stats) > 0 {
+			if summarized == nil {
+				summarized = &diff.Diff{}
+			}
+			summarized.Files = append(summarized.Files, fitted.stats...)
+		}
+		if len(fitted.parts) > 0 {
+			req := GenerateRequest{Temperature: opts.temperature}
+			if partOutlines, err = outlineParts(out, gen, fitted.parts, req); err != nil {
+				return nil, nil, nil, errors.NewCLIError("failed to outline the diff").WithCause(err)
+			}
+		}
+	}
+	if opts.weightBySize {
+		promptDiff = diff.SortBySize(promptDiff)
+	}
+	return promptDiff, summarized, partOutlines, nil
+}
+
+// commitPromptOptions collects the context the prompt gives the model
+// besides the diff, such as the commit template, the scope and the
+// dependency bumps, as the options ask for.
+func commitPromptOptions(gen Generator, opts commitOptions, out io.Writer, changes *diff.Diff, plan *promptPlan) (prompt.CommitOptions, error) {
+	promptOpts := prompt.CommitOptions{
+		WeightBySize:     opts.weightBySize,
+		RequireWhy:       opts.annotateWhy,
+		TestPlan:         opts.testPlan,
+		FileKinds:        fileKinds(plan.kinds),
+		FormattingOnly:   plan.formatting,
+		SubjectCase:      opts.subjectCase,
+		LanguageStyle:    opts.languageStyle,
+		BodyTemplates:    opts.bodyTemplates,
+		Type:             classify.SuggestedType(plan.kinds),
+		MaxSubjectLength: opts.maxSubjectLength,
+		Scopes:           opts.scopes,
+		Instructions:     opts.instructions,
+	}
+	// Respect the team's commit.template when one is configured
+	var err error
+	promptOpts.Template, err = loadCommitTemplate()
+	if err != nil {
+		fmt.Fprintf(out, "Warning: ignoring commit template: %v\n", err)
+	}
+	if opts.includeSubmodules {
+		promptOpts.Submodules = submoduleUpdates(changes)
+	}
+	if opts.enclosingContext {
+		promptOpts.Context = surroundingContext(changes, opts.contextMaxChanges, opts.contextLines)
+	}
+	if opts.ownershipScope {
+		if promptOpts.Scope = ownershipScope(changes); promptOpts.Scope != "" {
+			fmt.Fprintf(out, "Using scope %q from code ownership.\n", promptOpts.Scope)
+		}
+	}
+	if opts.fileTree {
+		promptOpts.FileTree = fileTree(changes, opts.fileTreeDepth, opts.fileTreeMaxEntries)
+	}
+	if opts.summarizeConflicts {
+		if promptOpts.Merge = mergeInProgress(); promptOpts.Merge != nil {
+			fmt.Fprintf(out, "Concluding a merge with %d conflicted file(s).\n", len(promptOpts.Merge.Conflicts))
+		}
+	}
+
+	if opts.summarizeDeps {
+		if promptOpts.Dependencies = dependencyBumps(out, changes); len(promptOpts.Dependencies) > 0 {
+			fmt.Fprintf(out, "Found %d dependency change(s).\n", len(promptOpts.Dependencies))
+		}
+	}
+
+	if opts.styleExamples > 0 && gen != nil {
+		if promptOpts.StyleExamples, err = styleExamples(opts.styleExamples, opts.styleExcludes); err != nil {
+			return promptOpts, errors.NewCLIError("failed to sample style examples").WithCause(err)
+		}
+	}
+	return promptOpts, nil
+}
+
+// draftGenerator generates the messages of one draft. It keeps what a
+// regeneration builds on: the suggestions so far, so regenerations can
+// differ, and the conversation that produced them, so the model sees every
+// message and all feedback it was given. The --two-phase outline is worked
+// out once and reused.
+type draftGenerator struct {
+	// gen is nil when messages are built from the diff without AI.
+	gen Generator
+	// msgGen is gen, showing the message as it is written when a person
+	// or a front-end is watching.
+	msgGen  Generator
+	opts    commitOptions
+	out     io.Writer
+	changes *diff.Diff
+	plan    *promptPlan
+	// footer holds the trailers added to every generated message.
+	footer []commitmsg.Trailer
+	// testPlan is added when the model did not write its own.
+	testPlan commitmsg.Trailer
+
+	previous []string
+	history  []Turn
+	outline  string
+	attempts int
+}
+
+// newDraftGenerator creates the generator of the messages for changes.
+func newDraftGenerator(gen Generator, opts commitOptions, out io.Writer, changes *diff.Diff, plan *promptPlan) *draftGenerator {
+	g := &draftGenerator{
+		gen:      gen,
+		msgGen:   gen,
+		opts:     opts,
+		out:      out,
+		changes:  changes,
+		plan:     plan,
+		testPlan: testPlanTrailer(plan.kinds),
+	}
+	switch {
+	case gen == nil || opts.noStream || opts.candidates > 1:
+	case opts.events != nil:
+		g.msgGen = &tokenGenerator{gen: gen, events: opts.events, attempt: &g.attempts}
+	case out == io.Writer(os.Stdout) && isTerminal(os.Stdout):
+		g.msgGen = &liveGenerator{gen: gen, out: out}
+	}
+	if opts.basedOnFooter {
+		if t, err := basedOnTrailer(opts.footerBase); err != nil {
+			fmt.Fprintf(out, "Warning: skipping Based-on trailer: %v\n", err)
+		} else {
+			g.footer = append(g.footer, t)
+		}
+	}
+	return g
+}
+
+// localMessage builds a message from the diff alone.
+func (g *draftGenerator) localMessage() string {
+	switch {
+	case g.plan.opts.Merge != nil:
+		return mergeHeuristicMessage(g.plan.opts.Merge)
+	case len(g.plan.opts.Dependencies) > 0:
+		return dependencyMessage(g.plan.opts.Dependencies)
+	default:
+		return heuristicMessage(g.changes, g.plan.kinds, g.plan.formatting, g.plan.opts.Scope)
+	}
+}
+
+// prepare returns the prompt options and request for the next message,
+// working out the --two-phase outline the first time.
+func (g *draftGenerator) prepare() (prompt.CommitOptions, GenerateRequest, error) {
+	regenOpts, req := g.plan.opts, GenerateRequest{
+		History:     g.history,
+		MaxTokens:   g.opts.maxTokens,
+		Temperature: g.opts.temperature,
+	}
+	diversify(g.opts.diversify, g.previous, &regenOpts, &req)
+	if g.opts.twoPhase {
+		if g.outline == "" {
+			var err error
+			if g.outline, err = outlineChanges(g.gen, g.plan.diff, req); err != nil {
+				return regenOpts, req, err
+			}
+			if g.opts.verbose {
+				fmt.Fprintf(g.out, "\nOutline:\n%s\n\n", g.outline)
+			}
+		}
+		regenOpts.Outline = g.outline
+	}
+	return regenOpts, req, nil
+}
+
+// complete generates a message from prepared options, handing validation
+// errors back to the model. It leaves the shared state alone, so
+// --candidates can run it in parallel.
+func (g *draftGenerator) complete(regenOpts prompt.CommitOptions, req GenerateRequest, feedback string) (string, error) {
+	message, err := generateCommitMessage(g.out, g.msgGen, g.plan.diff, feedback, regenOpts, req)
+	if err != nil {
+		return "", err
+	}
+	message = cleanMessage(message, g.opts)
+
+	// --strict refuses an invalid message instead
+	for fix := 1; fix <= g.opts.validationRetries && !g.opts.strict; fix++ {
+		problems := validationErrors(commitmsg.ApplySubjectCase(message, g.opts.subjectCase), g.opts)
+		if len(problems) == 0 {
+			break
+		}
+		fmt.Fprintf(g.out, "Message failed validation; asking the model to fix it (%d/%d)...\n", fix, g.opts.validationRetries)
+		regenOpts.Invalid, regenOpts.Problems = message, problems
+		fixed, err := generateCommitMessage(g.out, g.msgGen, g.plan.diff, feedback, regenOpts, req)
+		if err != nil {
+			return "", err
+		}
+		message = cleanMessage(fixed, g.opts)
+	}
+	return message, nil
+}
+
+// cleanMessage removes what models wrap messages in, as the options allow.
+func cleanMessage(message string, opts commitOptions) string {
+	if !opts.keepArtifacts {
+		message = commitmsg.StripArtifacts(message)
+	}
+	if opts.stripSubjectMarkdown {
+		message = commitmsg.StripSubjectMarkdown(message)
+	}
+	return message
+}
+
+// remember adds a generated message and what it was asked with to the
+// conversation.
+func (g *draftGenerator) remember(regenOpts prompt.CommitOptions, feedback, message string) {
+	_, asked := commitPrompts(g.plan.diff, feedback, regenOpts, g.history)
+	g.history = append(g.history, Turn{Prompt: asked, Reply: message})
+}
+
+// finish applies what every message gets whoever wrote it: the subject
+// case, the ticket, the author's issue references and the trailers.
+func (g *draftGenerator) finish(message string) string {
+	message = commitmsg.ApplySubjectCase(message, g.opts.subjectCase)
+	if g.plan.ticket != nil && g.plan.ticket.Added {
+		message = applyTicket(message, g.plan.ticket.ID, g.opts.ticketFormat)
+	}
+	message = mergeIntent(message, g.opts.intent)
+	trailers := append([]commitmsg.Trailer{}, g.footer...)
+	if g.opts.testPlan {
+		if _, existing := commitmsg.SplitTrailers(commitmsg.ToLF(message)); !hasTrailer(existing, g.testPlan.Key) {
+			trailers = append(trailers, g.testPlan)
+		}
+	}
+	return commitmsg.AppendTrailers(message, append(trailers, g.opts.trailers...))
+}
+
+// generate produces the next message, guided by optional feedback. With
+// --no-network-fallback-message an unreachable provider gets the message
+// built from the diff instead.
+func (g *draftGenerator) generate(feedback string) (string, error) {
+	g.attempts++
+	g.opts.events.emit(event{Event: eventGenerationStarted, Attempt: g.attempts})
+	var message string
+	if g.gen == nil {
+		message = g.localMessage()
+	} else {
+		regenOpts, req, err := g.prepare()
+		if err == nil {
+			message, err = g.complete(regenOpts, req, feedback)
+		}
+		switch {
+		case err != nil && g.opts.networkFallback && isNetworkError(err):
+			fmt.Fprintf(g.out, "Note: the AI provider is unreachable (%v); using a message built from the diff instead.\n", err)
+			message = g.localMessage()
+		case err != nil:
+			return "", err
+		default:
+			g.previous = append(g.previous, message)
+			g.remember(regenOpts, feedback, message)
+		}
+	}
+	message = g.finish(message)
+	g.opts.events.emit(event{Event: eventGenerationComplete, Attempt: g.attempts, Message: message})
+	return message, nil
+}
+
+// candidates generates n messages at once for --candidates, each with its
+// own nonce so the parallel requests are not answered identically.
+func (g *draftGenerator) candidates(feedback string, n int) ([]string, error) {
+	if g.gen == nil || n < 2 {
+		message, err := g.generate(feedback)
+		return []string{message}, err
+	}
+	g.attempts++
+	regenOpts, req, err := g.prepare()
+	messages := make([]string, n)
+	if err == nil {
+		errs := make([]error, n)
+		var wg sync.WaitGroup
+		for i := range messages {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				candidateOpts := regenOpts
+				candidateOpts.Variation = nonce()
+				messages[i], errs[i] = g.complete(candidateOpts, req, feedback)
+			}()
+		}
+		wg.Wait()
+		for _, e := range errs {
+			if e != nil {
+				err = e
+				break
+			}
+		}
+	}
+	switch {
+	case err != nil && g.opts.networkFallback && isNetworkError(err):
+		fmt.Fprintf(g.out, "Note: the AI provider is unreachable (%v); using a message built from the diff instead.\n", err)
+		return []string{g.finish(g.localMessage())}, nil
+	case err != nil:
+		return nil, err
+	}
+	g.previous = append(g.previous, messages...)
+	// Only one reply fits the conversation; the others are avoided
+	// through previous
+	g.remember(regenOpts, feedback, messages[0])
+	for i, m := range messages {
+		messages[i] = g.finish(m)
+	}
+	return messages, nil
+}
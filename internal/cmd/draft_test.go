@@ -0,0 +1,510 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/yourorg/arc-commit/internal/diff"
+	commitmsg "github.com/yourorg/arc-commit/internal/message"
+)
+
+// testDiff adds a source file, its test, a lockfile and a doc.
+const testDiff = `diff --git a/app/app.go b/app/app.go
+new file mode 100644
+index 0000000..1111111
+--- /dev/null
++++ b/app/app.go
+@@ -0,0 +1,3 @@
++package app
++
++func Run() {}
+diff --git a/app/app_test.go b/app/app_test.go
+new file mode 100644
+index 0000000..2222222
+--- /dev/null
++++ b/app/app_test.go
+@@ -0,0 +1,3 @@
++package app
++
++func TestRun(t *testing.T) {}
+diff --git a/go.sum b/go.sum
+index 3333333..4444444 100644
+--- a/go.sum
++++ b/go.sum
+@@ -1 +1,2 @@
+ example.com/a v1.0.0 h1:abc
++example.com/b v1.0.0 h1:def
+diff --git a/docs/app.md b/docs/app.md
+new file mode 100644
+index 0000000..5555555
+--- /dev/null
++++ b/docs/app.md
+@@ -0,0 +1 @@
++# App
+`
+
+// testDraftGenerator returns a draftGenerator for testDiff that asks gen.
+func testDraftGenerator(t *testing.T, gen Generator, opts commitOptions) *draftGenerator {
+	t.Helper()
+	changes := diff.Parse(testDiff)
+	plan := &promptPlan{diff: changes, kinds: opts.classifier.Diff(changes)}
+	return newDraftGenerator(gen, opts, io.Discard, changes, plan)
+}
+
+func TestCleanMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		opts    commitOptions
+		want    string
+	}{
+		{
+			name:    "artifacts",
+			message: "Here is the commit message:\n\n```\nfeat: add `Run`\n```",
+			want:    "feat: add `Run`",
+		},
+		{
+			name:    "artifacts kept",
+			message: "```\nfeat: add Run\n```",
+			opts:    commitOptions{keepArtifacts: true},
+			want:    "```\nfeat: add Run\n```",
+		},
+		{
+			name:    "subject markdown",
+			message: "feat: add `Run`\n\nCall `Run` to start.",
+			opts:    commitOptions{stripSubjectMarkdown: true},
+			want:    "feat: add Run\n\nCall `Run` to start.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cleanMessage(tt.message, tt.opts); got != tt.want {
+				t.Errorf("cleanMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDraftGeneratorValidationRetries(t *testing.T) {
+	tests := []struct {
+		name      string
+		replies   []string
+		strict    bool
+		retries   int
+		wantCalls int
+		want      string
+	}{
+		{name: "fixed by the model", retries: 2, wantCalls: 2, want: "feat: add the app package"},
+		{name: "retries disabled", retries: 0, wantCalls: 1, want: "Added the app package"},
+		{name: "strict", strict: true, retries: 2, wantCalls: 1, want: "Added the app package"},
+		{
+			name:      "gives up after the retries",
+			replies:   []string{"Added the app package", "Adds the app package"},
+			retries:   1,
+			wantCalls: 2,
+			want:      "Adds the app package",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replies := tt.replies
+			if replies == nil {
+				replies = []string{"Added the app package", "feat: add the app package"}
+			}
+			gen := &fakeGenerator{replies: replies}
+			opts := testCommitOptions(t)
+			opts.strict, opts.validationRetries = tt.strict, tt.retries
+
+			message, err := testDraftGenerator(t, gen, opts).generate("")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if message != tt.want {
+				t.Errorf("message = %q, want %q", message, tt.want)
+			}
+			if gen.calls() != tt.wantCalls {
+				t.Fatalf("%d requests, want %d", gen.calls(), tt.wantCalls)
+			}
+			if tt.wantCalls > 1 {
+				fix := gen.requests[1].Prompt
+				if !strings.Contains(fix, "Added the app package") || !strings.Contains(fix, "(format)") {
+					t.Errorf("the fix request does not show the invalid message and its problems:\n%s", fix)
+				}
+			}
+		})
+	}
+}
+
+func TestDraftGeneratorNetworkFallback(t *testing.T) {
+	unreachable := &fakeGenerator{err: syscall.ECONNREFUSED}
+	opts := testCommitOptions(t)
+	if _, err := testDraftGenerator(t, unreachable, opts).generate(""); !errors.Is(err, syscall.ECONNREFUSED) {
+		t.Errorf("without the fallback err = %v, want the network error", err)
+	}
+
+	opts.networkFallback = true
+	message, err := testDraftGenerator(t, unreachable, opts).generate("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !commitmsg.Parse(message).Conventional() {
+		t.Errorf("fallback message %q is not a conventional commit", message)
+	}
+}
+
+func TestDraftGeneratorRegenerationKeepsTheConversation(t *testing.T) {
+	gen := &fakeGenerator{replies: []string{"feat: add the app package", "feat(app): add Run"}}
+	g := testDraftGenerator(t, gen, testCommitOptions(t))
+
+	if _, err := g.generate(""); err != nil {
+		t.Fatal(err)
+	}
+	message, err := g.generate("mention the scope")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if message != "feat(app): add Run" {
+		t.Errorf("message = %q", message)
+	}
+	second := gen.requests[1]
+	if len(second.History) != 1 || second.History[0].Reply != "feat: add the app package" {
+		t.Errorf("history = %+v, want the first message", second.History)
+	}
+	if !strings.Contains(second.Prompt, "mention the scope") {
+		t.Errorf("the regeneration prompt lacks the feedback:\n%s", second.Prompt)
+	}
+	if !slices.Equal(g.previous, []string{"feat: add the app package", "feat(app): add Run"}) {
+		t.Errorf("previous = %q", g.previous)
+	}
+}
+
+func TestDraftGeneratorFinish(t *testing.T) {
+	opts := testCommitOptions(t)
+	opts.testPlan = true
+	opts.intent = "fix login, see #42"
+	opts.trailers = []commitmsg.Trailer{{Key: "Signed-off-by", Value: "Test Author <author@example.com>"}}
+	g := testDraftGenerator(t, nil, opts)
+
+	got := g.finish("feat: add the app package")
+	want := "feat: add the app package\n\nRefs: #42\nTest-plan: see app/app_test.go\nSigned-off-by: Test Author <author@example.com>"
+	if got != want {
+		t.Errorf("finish() =\n%s\nwant\n%s", got, want)
+	}
+
+	// A test plan the model wrote is kept instead
+	written := "feat: add the app package\n\nTest-plan: ran it by hand"
+	if got := g.finish(written); strings.Count(got, "Test-plan:") != 1 {
+		t.Errorf("finish() added a second Test-plan trailer:\n%s", got)
+	}
+}
+
+func TestDraftGeneratorCandidates(t *testing.T) {
+	gen := &fakeGenerator{replies: []string{"feat: add the app package"}}
+	opts := testCommitOptions(t)
+	opts.candidates = 3
+	g := testDraftGenerator(t, gen, opts)
+
+	messages, err := g.candidates("", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != 3 || gen.calls() != 3 {
+		t.Fatalf("%d messages from %d requests, want 3 of each", len(messages), gen.calls())
+	}
+	prompts := map[string]bool{}
+	for _, req := range gen.requests {
+		prompts[req.Prompt] = true
+	}
+	if len(prompts) != 3 {
+		t.Errorf("the candidate requests share prompts; each needs its own variation")
+	}
+	if len(g.history) != 1 {
+		t.Errorf("history has %d turns, want 1", len(g.history))
+	}
+}
+
+func TestSelectPromptDiff(t *testing.T) {
+	tests := []struct {
+		name           string
+		setup          func(*commitOptions)
+		wantFull       []string
+		wantSummarized []string
+	}{
+		{
+			name:           "lockfiles as stats",
+			wantFull:       []string{"app/app.go", "app/app_test.go", "docs/app.md"},
+			wantSummarized: []string{"go.sum"},
+		},
+		{
+			name:           "custom generated paths",
+			setup:          func(o *commitOptions) { o.generatedPaths = []string{"docs/**"} },
+			wantFull:       []string{"app/app.go", "app/app_test.go", "go.sum"},
+			wantSummarized: []string{"docs/app.md"},
+		},
+		{
+			name:     "only generated files",
+			setup:    func(o *commitOptions) { o.generatedPaths = []string{"*"} },
+			wantFull: []string{"app/app.go", "app/app_test.go", "go.sum", "docs/app.md"},
+		},
+		{
+			name:           "excluded paths",
+			setup:          func(o *commitOptions) { o.excludePaths = []string{"docs/"} },
+			wantFull:       []string{"app/app.go", "app/app_test.go"},
+			wantSummarized: []string{"go.sum"},
+		},
+		{
+			name:     "include all",
+			setup:    func(o *commitOptions) { o.excludePaths, o.includeAll = []string{"docs/"}, true },
+			wantFull: []string{"app/app.go", "app/app_test.go", "go.sum", "docs/app.md"},
+		},
+		{
+			name:           "largest files",
+			setup:          func(o *commitOptions) { o.maxFilesInPrompt = 2 },
+			wantFull:       []string{"app/app.go", "app/app_test.go"},
+			wantSummarized: []string{"docs/app.md", "go.sum"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := testCommitOptions(t)
+			if tt.setup != nil {
+				tt.setup(&opts)
+			}
+			staged := &stagedChanges{raw: testDiff, changes: diff.Parse(testDiff)}
+			full, summarized, _, err := selectPromptDiff(&fakeGenerator{}, opts, bufio.NewReader(strings.NewReader("")), io.Discard, staged, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := filePaths(full); !slices.Equal(got, tt.wantFull) {
+				t.Errorf("full = %q, want %q", got, tt.wantFull)
+			}
+			var got []string
+			if summarized != nil {
+				got = filePaths(summarized)
+			}
+			if !slices.Equal(got, tt.wantSummarized) {
+				t.Errorf("summarized = %q, want %q", got, tt.wantSummarized)
+			}
+		})
+	}
+}
+
+func TestSelectPromptDiffEverythingExcluded(t *testing.T) {
+	opts := testCommitOptions(t)
+	opts.excludePaths = []string{"*"}
+	staged := &stagedChanges{raw: testDiff, changes: diff.Parse(testDiff)}
+	if _, _, _, err := selectPromptDiff(&fakeGenerator{}, opts, nil, io.Discard, staged, false); err == nil {
+		t.Error("selectPromptDiff accepted a prompt without any file")
+	}
+}
+
+func TestReadStagedChangesNeedsStagedChanges(t *testing.T) {
+	testRepo(t)
+	commitFile(t, "README.md", "hello\n", "docs: add readme")
+	writeFile(t, "README.md", "hello, world\n")
+
+	opts := testCommitOptions(t)
+	if _, err := readStagedChanges(nil, opts, bufio.NewReader(strings.NewReader("")), io.Discard); err == nil {
+		t.Fatal("readStagedChanges accepted an empty index")
+	}
+
+	opts.all = true
+	staged, err := readStagedChanges(nil, opts, bufio.NewReader(strings.NewReader("")), io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := filePaths(staged.changes); !slices.Equal(got, []string{"README.md"}) {
+		t.Errorf("--all read %q, want the tracked change", got)
+	}
+}
+
+func TestDraftGeneratorTemperature(t *testing.T) {
+	zero := 0.0
+	tests := []struct {
+		name        string
+		temperature *float64
+	}{
+		{name: "provider default"},
+		{name: "configured", temperature: &zero},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := &fakeGenerator{replies: []string{"feat: add the app package"}}
+			opts := testCommitOptions(t)
+			opts.temperature = tt.temperature
+			if _, err := testDraftGenerator(t, gen, opts).generate(""); err != nil {
+				t.Fatal(err)
+			}
+			if got := gen.requests[0].Temperature; got != tt.temperature {
+				t.Errorf("request temperature = %v, want %v", got, tt.temperature)
+			}
+		})
+	}
+}
+
+func TestDraftGeneratorTwoPhase(t *testing.T) {
+	const outline = "- add the app package"
+	tests := []struct {
+		name        string
+		verbose     bool
+		wantOutline bool
+	}{
+		{name: "quiet"},
+		{name: "verbose", verbose: true, wantOutline: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := &fakeGenerator{replies: []string{outline, "feat: add the app package", "feat(app): add the package"}}
+			opts := testCommitOptions(t)
+			opts.twoPhase, opts.verbose = true, tt.verbose
+			changes := diff.Parse(testDiff)
+			var out strings.Builder
+			g := newDraftGenerator(gen, opts, &out, changes, &promptPlan{diff: changes, kinds: opts.classifier.Diff(changes)})
+			for _, feedback := range []string{"", "add a scope"} {
+				if _, err := g.generate(feedback); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if gen.calls() != 3 {
+				t.Fatalf("made %d calls, want the outline once and two messages", gen.calls())
+			}
+			if !strings.Contains(gen.requests[0].System, "List the key changes") {
+				t.Errorf("first call is not the outline:\n%s", gen.requests[0].System)
+			}
+			if first := gen.requests[1].Prompt; !strings.Contains(first, "Outline of the key changes") || !strings.Contains(first, outline) {
+				t.Errorf("message prompt does not carry the outline:\n%s", first)
+			}
+			// The regeneration sees it in the conversation so far
+			if history := gen.requests[2].History; len(history) == 0 || history[0].Prompt != gen.requests[1].Prompt {
+				t.Errorf("regeneration history = %+v, want the first prompt", history)
+			}
+			if got := strings.Contains(out.String(), "Outline:\n"+outline); got != tt.wantOutline {
+				t.Errorf("outline shown = %v, want %v:\n%s", got, tt.wantOutline, out.String())
+			}
+		})
+	}
+}
+
+func TestWhitespaceOnly(t *testing.T) {
+	const source = "package app\n\nfunc Run() {\n\treturn\n}\n"
+	tests := []struct {
+		name  string
+		stage func(t *testing.T)
+		want  bool
+	}{
+		{
+			name:  "reindented",
+			stage: func(t *testing.T) { writeFile(t, "app.go", "package app\n\nfunc Run() {\n    return\n}\n") },
+			want:  true,
+		},
+		{
+			name:  "trailing spaces",
+			stage: func(t *testing.T) { writeFile(t, "app.go", "package app \n\nfunc Run() {\n\treturn\n}\n") },
+			want:  true,
+		},
+		{
+			name:  "code changed",
+			stage: func(t *testing.T) { writeFile(t, "app.go", "package app\n\nfunc Run() {\n\tpanic(1)\n}\n") },
+		},
+		{
+			name: "reindented and a new file",
+			stage: func(t *testing.T) {
+				writeFile(t, "app.go", "package app\n\nfunc Run() {\n    return\n}\n")
+				writeFile(t, "empty.go", "package app\n")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			commitFile(t, "app.go", source, "feat: add Run")
+			tt.stage(t)
+			git(t, "add", ".")
+
+			raw, err := getStagedDiff()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := whitespaceOnly(diff.Parse(raw), getStagedDiff); got != tt.want {
+				t.Errorf("whitespaceOnly() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIgnoreWhitespace(t *testing.T) {
+	tests := []struct {
+		name             string
+		content          string
+		ignoreWhitespace bool
+		want             string
+		wantCalls        int
+		wantPrompt       string
+		notInPrompt      string
+	}{
+		{
+			name:             "formatting without AI",
+			content:          "package app\n\nfunc Run() {\n    return\n}\n",
+			ignoreWhitespace: true,
+			want:             "style: reformat app.go",
+		},
+		{
+			name:       "formatting described by the model",
+			content:    "package app\n\nfunc Run() {\n    return\n}\n",
+			want:       "feat: add the app package",
+			wantCalls:  1,
+			wantPrompt: "The changes only alter whitespace or formatting",
+		},
+		{
+			name:             "whitespace left out of the prompt",
+			content:          "package app\n\nfunc Run() {\n    return\n}\n\nfunc Stop() {}\n",
+			ignoreWhitespace: true,
+			want:             "feat: add the app package",
+			wantCalls:        1,
+			wantPrompt:       "+func Stop() {}",
+			notInPrompt:      "+    return",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			commitFile(t, "app.go", "package app\n\nfunc Run() {\n\treturn\n}\n", "feat: add Run")
+			writeFile(t, "app.go", tt.content)
+			git(t, "add", "app.go")
+
+			gen := &fakeGenerator{replies: []string{"feat: add the app package"}}
+			opts := testCommitOptions(t)
+			opts.ignoreWhitespace = tt.ignoreWhitespace
+			var out bytes.Buffer
+			if err := runInteractiveCommit(gen, opts, strings.NewReader("y\n"), &out); err != nil {
+				t.Fatalf("runInteractiveCommit() error = %v\n%s", err, out.String())
+			}
+			if got := git(t, "log", "-1", "--format=%s"); got != tt.want {
+				t.Errorf("committed %q, want %q", got, tt.want)
+			}
+			if gen.calls() != tt.wantCalls {
+				t.Fatalf("%d generate requests, want %d", gen.calls(), tt.wantCalls)
+			}
+			if tt.wantCalls == 0 {
+				return
+			}
+			asked := gen.requests[0].System + "\n" + gen.requests[0].Prompt
+			if !strings.Contains(asked, tt.wantPrompt) {
+				t.Errorf("prompt does not contain %q:\n%s", tt.wantPrompt, asked)
+			}
+			if tt.notInPrompt != "" && strings.Contains(asked, tt.notInPrompt) {
+				t.Errorf("prompt contains %q:\n%s", tt.notInPrompt, asked)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+)
+
+// editorFailure reports an editor that exited with an error, e.g. because
+// it crashed or was killed. The file being edited is kept at Path, since it
+// may hold edits that were saved before the failure.
+type editorFailure struct {
+	Path string
+	Err  error
+}
+
+func (e *editorFailure) Error() string {
+	return fmt.Sprintf("editor failed: %v (edits kept in %s)", e.Err, e.Path)
+}
+
+func (e *editorFailure) Unwrap() error {
+	return e.Err
+}
+
+// asEditorFailure returns the editorFailure in err's chain, if any.
+func asEditorFailure(err error) (*editorFailure, bool) {
+	var failure *editorFailure
+	ok := errors.As(err, &failure)
+	return failure, ok
+}
@@ -0,0 +1,258 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// crashingEditor writes an editor script that saves text and then fails,
+// like an editor killed after a save.
+func crashingEditor(t *testing.T, text string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "editor")
+	script := "#!/bin/sh\nprintf '%s' '" + text + "' > \"$1\"\nexit 1\n"
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestEditInEditorKeepsDraftOnFailure(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	t.Setenv("EDITOR", "false")
+	draft := "feat: add login page\n\nAdds the form and its handler."
+
+	_, err := editInEditor(draft)
+	failure, ok := asEditorFailure(err)
+	if !ok {
+		t.Fatalf("editInEditor() error = %v, want an editorFailure", err)
+	}
+
+	kept, err := os.ReadFile(failure.Path)
+	if err != nil {
+		t.Fatalf("the edited file was not kept: %v", err)
+	}
+	if string(kept) != draft {
+		t.Errorf("kept file = %q, want the draft %q", kept, draft)
+	}
+}
+
+func TestRecoverEdit(t *testing.T) {
+	tests := []struct {
+		name   string
+		answer string
+		want   string
+	}{
+		{name: "back to the generated message", answer: "b\n", want: ""},
+		{name: "retry from the saved edits", answer: "r\n", want: "feat: half-edited"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TMPDIR", t.TempDir())
+			t.Setenv("EDITOR", crashingEditor(t, "feat: half-edited"))
+			var out strings.Builder
+			reader := bufio.NewReader(strings.NewReader(tt.answer))
+
+			_, err := editInEditor("feat: add login page")
+			failure, ok := asEditorFailure(err)
+			if !ok {
+				t.Fatalf("editInEditor() error = %v, want an editorFailure", err)
+			}
+
+			got, err := recoverEdit(reader, &out, nil, failure)
+			if err != nil {
+				t.Fatalf("recoverEdit() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("recoverEdit() = %q, want %q", got, tt.want)
+			}
+			if !strings.Contains(out.String(), failure.Path) {
+				t.Errorf("output does not name the kept file %s:\n%s", failure.Path, out.String())
+			}
+			if _, err := os.Stat(failure.Path); err != nil {
+				t.Errorf("the kept file is gone after recovery: %v", err)
+			}
+		})
+	}
+}
+
+func TestEditMessageKeepsGeneratedMessageWhenEditorFails(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	t.Setenv("EDITOR", "false")
+	draft := "fix: handle empty config"
+	reader := bufio.NewReader(strings.NewReader("b\n"))
+
+	// An editor that saved nothing leaves the draft in the kept file
+	got, err := editMessage(reader, io.Discard, nil, draft, false)
+	if err != nil {
+		t.Fatalf("editMessage() error = %v", err)
+	}
+	if got != "" {
+		t.Errorf("editMessage() = %q, want \"\" to go back to the generated message", got)
+	}
+}
+
+// appendingEditor writes an editor script that adds line to the file.
+func appendingEditor(t *testing.T, line string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "editor")
+	script := "#!/bin/sh\nprintf '%s\\n' '" + line + "' >> \"$1\"\n"
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestEditFeedbackCommentChar(t *testing.T) {
+	tests := []struct {
+		name        string
+		commentChar string
+		want        string
+	}{
+		{name: "default", want: "mention the login page"},
+		{name: "semicolon", commentChar: ";", want: "mention the login page\n#42 is the issue"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			if tt.commentChar != "" {
+				git(t, "config", "core.commentChar", tt.commentChar)
+			}
+			t.Setenv("TMPDIR", t.TempDir())
+			t.Setenv("EDITOR", appendingEditor(t, "#42 is the issue"))
+
+			got, err := editFeedback("mention the login page")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("editFeedback() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// savingEditor writes an editor script that replaces the file with text.
+func savingEditor(t *testing.T, text string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "editor")
+	script := "#!/bin/sh\nprintf '%s' '" + text + "' > \"$1\"\n"
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestEditTrailers(t *testing.T) {
+	const message = "feat: add login page\n\nAdds the form.\n\nRefs: #123"
+	tests := []struct {
+		name    string
+		saved   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "edited",
+			saved: "# help\nRefs: #124\nCo-authored-by: Jane Doe <jane@example.com>\n",
+			want:  "feat: add login page\n\nAdds the form.\n\nRefs: #124\nCo-authored-by: Jane Doe <jane@example.com>",
+		},
+		{
+			name:  "all removed",
+			saved: "# help\n\n",
+			want:  "feat: add login page\n\nAdds the form.",
+		},
+		{
+			name:    "not a trailer",
+			saved:   "Refs #124\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TMPDIR", t.TempDir())
+			t.Setenv("EDITOR", savingEditor(t, tt.saved))
+			got, err := editTrailers(message)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("editTrailers() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("editTrailers() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEditMessageEmptyOrUnchanged(t *testing.T) {
+	const draft = "feat: add login page"
+	tests := []struct {
+		name        string
+		editor      func(t *testing.T) string
+		requireEdit bool
+		input       string
+		want        string
+		wantErr     string
+		wantPrompts int
+	}{
+		{
+			name:   "changed",
+			editor: func(t *testing.T) string { return savingEditor(t, "feat: add the login page") },
+			want:   "feat: add the login page",
+		},
+		{
+			name:    "emptied",
+			editor:  func(t *testing.T) string { return savingEditor(t, "\n\n") },
+			wantErr: "aborting commit due to empty commit message",
+		},
+		{
+			name:        "unchanged, committed as-is",
+			editor:      func(*testing.T) string { return "true" },
+			input:       "c\n",
+			want:        draft,
+			wantPrompts: 1,
+		},
+		{
+			name:        "unchanged, edited again",
+			editor:      func(*testing.T) string { return "true" },
+			input:       "r\nc\n",
+			want:        draft,
+			wantPrompts: 2,
+		},
+		{
+			name:        "unchanged with --require-edit",
+			editor:      func(*testing.T) string { return "true" },
+			requireEdit: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TMPDIR", t.TempDir())
+			t.Setenv("EDITOR", tt.editor(t))
+
+			var out strings.Builder
+			reader := bufio.NewReader(strings.NewReader(tt.input))
+			got, err := editMessage(reader, &out, nil, draft, tt.requireEdit)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("editMessage() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("editMessage() error = %v", err)
+			}
+			if strings.TrimSpace(got) != tt.want {
+				t.Errorf("editMessage() = %q, want %q", got, tt.want)
+			}
+			if n := strings.Count(out.String(), "[c]ommit as-is or [r]e-edit"); n != tt.wantPrompts {
+				t.Errorf("asked %d times, want %d:\n%s", n, tt.wantPrompts, out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// defaultEncoding is git's commit encoding when i18n.commitEncoding is unset.
+const defaultEncoding = "UTF-8"
+
+// commitEncoding returns the encoding commit messages are written in: the
+// --output-encoding value, else git's i18n.commitEncoding, else UTF-8.
+func commitEncoding(flag string) string {
+	if flag != "" {
+		return flag
+	}
+	if enc, err := gitConfig("i18n.commitEncoding"); err == nil && enc != "" {
+		return enc
+	}
+	return defaultEncoding
+}
+
+// normalizeEncoding folds an encoding name for comparison, so "utf8",
+// "UTF-8" and "utf_8" are the same.
+func normalizeEncoding(enc string) string {
+	return strings.NewReplacer("-", "", "_", "").Replace(strings.ToLower(enc))
+}
+
+// encodeMessage transcodes a UTF-8 message to enc. Latin-1 is converted
+// directly; other encodings go through iconv. Characters enc cannot
+// represent are an error rather than being replaced silently.
+func encodeMessage(message, enc string) (string, error) {
+	switch normalizeEncoding(enc) {
+	case "utf8":
+		return message, nil
+	case "iso88591", "latin1":
+		var b strings.Builder
+		for _, r := range message {
+			if r > 0xff {
+				return "", fmt.Errorf("%q cannot be represented in %s", r, enc)
+			}
+			b.WriteByte(byte(r))
+		}
+		return b.String(), nil
+	}
+
+	if !utf8.ValidString(message) {
+		return "", fmt.Errorf("message is not valid UTF-8")
+	}
+	cmd := exec.Command("iconv", "-f", defaultEncoding, "-t", enc)
+	cmd.Stdin = strings.NewReader(message)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("iconv: %s", msg)
+		}
+		return "", fmt.Errorf("iconv failed: %w", err)
+	}
+	return string(output), nil
+}
+
+// encodingEnv makes git record enc in the commit's encoding header when it
+// differs from i18n.commitEncoding, using git's GIT_CONFIG_* variables. The
+// setting is added after any the environment already passes that way.
+func encodingEnv(flag string) []string {
+	if flag == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(os.Getenv("GIT_CONFIG_COUNT"))
+	if err != nil || n < 0 {
+		n = 0
+	}
+	return []string{
+		fmt.Sprintf("GIT_CONFIG_COUNT=%d", n+1),
+		fmt.Sprintf("GIT_CONFIG_KEY_%d=i18n.commitEncoding", n),
+		fmt.Sprintf("GIT_CONFIG_VALUE_%d=%s", n, flag),
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEncodingEnv(t *testing.T) {
+	tests := []struct {
+		name  string
+		count string
+		want  []string
+	}{
+		{
+			name: "no config in the environment",
+			want: []string{"GIT_CONFIG_COUNT=1", "GIT_CONFIG_KEY_0=i18n.commitEncoding", "GIT_CONFIG_VALUE_0=ISO-8859-1"},
+		},
+		{
+			name:  "after existing config",
+			count: "2",
+			want:  []string{"GIT_CONFIG_COUNT=3", "GIT_CONFIG_KEY_2=i18n.commitEncoding", "GIT_CONFIG_VALUE_2=ISO-8859-1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GIT_CONFIG_COUNT", tt.count)
+			if got := encodingEnv("ISO-8859-1"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("encodingEnv() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
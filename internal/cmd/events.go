@@ -0,0 +1,90 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Events emitted with --emit-events, one JSON object per line.
+const (
+	eventReviewComplete     = "review-complete"
+	eventGenerationStarted  = "generation-started"
+	eventToken              = "token"
+	eventGenerationComplete = "generation-complete"
+	eventAwaitingInput      = "awaiting-input"
+	eventFinished           = "finished"
+)
+
+// Values of an awaiting-input event's prompt field.
+const (
+	inputAction   = "action"
+	inputFeedback = "feedback"
+	inputConfirm  = "confirm"
+	inputHunk     = "hunk"
+)
+
+// Values of a finished event's status field.
+const (
+	statusCommitted = "committed"
+	statusWritten   = "written"
+	statusCancelled = "cancelled"
+	statusDryRun    = "dry-run"
+	statusError     = "error"
+)
+
+// event is one line of the --emit-events protocol. Fields that do not apply
+// to an event are omitted.
+type event struct {
+	Event string `json:"event"`
+	// Attempt numbers generations, starting at 1.
+	Attempt int `json:"attempt,omitempty"`
+	// Prompt says what kind of answer is awaited; Choices lists the
+	// accepted answers.
+	Prompt  string   `json:"prompt,omitempty"`
+	Choices []string `json:"choices,omitempty"`
+	Status  string   `json:"status,omitempty"`
+	// Text is a chunk of a reply as the provider streams it.
+	Text    string `json:"text,omitempty"`
+	Message string `json:"message,omitempty"`
+	Commit  string `json:"commit,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// eventWriter streams events to a front-end such as an editor plugin. A nil
+// eventWriter discards everything, so callers need not check for one.
+type eventWriter struct {
+	enc *json.Encoder
+}
+
+// newEventWriter returns an eventWriter that writes JSON lines to w.
+func newEventWriter(w io.Writer) *eventWriter {
+	return &eventWriter{enc: json.NewEncoder(w)}
+}
+
+// emit writes e. Write errors are ignored: a front-end that stopped reading
+// must not break the commit.
+func (w *eventWriter) emit(e event) {
+	if w == nil {
+		return
+	}
+	w.enc.Encode(e)
+}
+
+// awaiting announces that an answer is about to be read from stdin.
+func (w *eventWriter) awaiting(prompt string, choices ...string) {
+	w.emit(event{Event: eventAwaitingInput, Prompt: prompt, Choices: choices})
+}
+
+// finished announces the outcome of the run; it is always the last event.
+func (w *eventWriter) finished(status, message string) {
+	e := event{Event: eventFinished, Status: status, Message: message}
+	if status == statusCommitted {
+		if lines, err := gitLines("rev-parse", "HEAD"); err == nil && len(lines) > 0 {
+			e.Commit = lines[0]
+		}
+	}
+	w.emit(e)
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// decodeEvents parses the JSON lines written by an eventWriter.
+func decodeEvents(t *testing.T, data []byte) []event {
+	t.Helper()
+	var events []event
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var e event
+		if err := dec.Decode(&e); err == io.EOF {
+			return events
+		} else if err != nil {
+			t.Fatalf("invalid event stream: %v\n%s", err, data)
+		}
+		events = append(events, e)
+	}
+}
+
+// summarize reduces an event to its name and the field that matters for it.
+func summarize(e event) string {
+	switch e.Event {
+	case eventGenerationStarted, eventGenerationComplete:
+		return fmt.Sprintf("%s %d", e.Event, e.Attempt)
+	case eventToken:
+		return fmt.Sprintf("%s %d %q", e.Event, e.Attempt, e.Text)
+	case eventAwaitingInput:
+		return e.Event + " " + e.Prompt
+	case eventFinished:
+		return e.Event + " " + e.Status
+	}
+	return e.Event
+}
+
+func TestEmitEvents(t *testing.T) {
+	generation := func(attempt int) []string {
+		return []string{
+			fmt.Sprintf("generation-started %d", attempt),
+			fmt.Sprintf(`token %d "feat: add "`, attempt),
+			fmt.Sprintf(`token %d "the app package"`, attempt),
+			fmt.Sprintf("generation-complete %d", attempt),
+		}
+	}
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "committed",
+			input: "y\n",
+			want:  append(generation(1), "awaiting-input action", "finished committed"),
+		},
+		{
+			name:  "cancelled",
+			input: "c\n",
+			want:  append(generation(1), "awaiting-input action", "finished cancelled"),
+		},
+		{
+			name:  "regenerated",
+			input: "n\n\ny\n",
+			want: slices.Concat(generation(1), []string{"awaiting-input action", "awaiting-input feedback"},
+				generation(2), []string{"awaiting-input action", "finished committed"}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			writeFile(t, "app.go", "package app\n")
+			git(t, "add", "app.go")
+
+			var stream bytes.Buffer
+			opts := testCommitOptions(t)
+			opts.events, opts.noStream = newEventWriter(&stream), false
+			gen := &stubStreamer{chunks: []string{"feat: add ", "the app package"}}
+			if err := runInteractiveCommit(gen, opts, strings.NewReader(tt.input), io.Discard); err != nil {
+				t.Fatal(err)
+			}
+
+			events := decodeEvents(t, stream.Bytes())
+			var got []string
+			for _, e := range events {
+				got = append(got, summarize(e))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("events:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+			if last := events[len(events)-1]; last.Status == statusCommitted {
+				if last.Commit != git(t, "rev-parse", "HEAD") || last.Message != "feat: add the app package" {
+					t.Errorf("finished event = %+v, want HEAD and its message", last)
+				}
+			}
+		})
+	}
+}
+
+func TestNilEventWriter(t *testing.T) {
+	var w *eventWriter
+	w.emit(event{Event: eventToken})
+	w.awaiting(inputAction, "y")
+	w.finished(statusCancelled, "")
+}
+
+func TestEmitEventsConflicts(t *testing.T) {
+	for _, flag := range []string{"--output-subject-only", "--output=json", "--candidates=2"} {
+		t.Run(flag, func(t *testing.T) {
+			testRepo(t)
+			out, err := runCommitCmd(t, "", "--emit-events", "--no-ai", flag)
+			if err == nil || !strings.Contains(err.Error(), "--emit-events cannot be combined") {
+				t.Errorf("err = %v, want a conflict\n%s", err, out)
+			}
+		})
+	}
+}
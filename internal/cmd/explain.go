@@ -0,0 +1,105 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-commit/internal/config"
+	"github.com/yourorg/arc-commit/internal/prompt"
+	"github.com/yourorg/arc-sdk/ai"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// newExplainCmd creates the explain subcommand.
+func newExplainCmd(aiCfg *ai.Config) *cobra.Command {
+	var (
+		model     string
+		provider  providerOptions
+		redaction redactOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "explain <ref>",
+		Short: "Explain what an existing commit did",
+		Long: `Describe an existing commit in plain English.
+
+The commit's message and diff (as shown by git show) are sent to the AI,
+which explains what changed and why. Nothing in the repository is modified.`,
+		Example: `  # Explain the latest commit
+  arc-commit explain HEAD
+
+  # Explain a commit from a release branch with a different model
+  arc-commit explain v1.2.0~3 --model claude-sonnet-4-5-20250929`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			settings, err := resolveConfig(cmd)
+			if err != nil {
+				return err
+			}
+			if settings.blocksProvider(provider) {
+				return errors.NewCLIError("explain is disabled in sensitive repositories").
+					WithHint("Use a local model, e.g. --provider ollama; sensitive-repo is set in " + config.FileName + " or the global config")
+			}
+
+			if err := validateProvider(provider); err != nil {
+				return err
+			}
+
+			cfg := *aiCfg
+			gen, _, err := newGenerator(&cfg, provider, model)
+			if err != nil {
+				return err
+			}
+			if gen, err = withRedaction(gen, settings, redaction, cmd.ErrOrStderr()); err != nil {
+				return err
+			}
+			return runExplain(cmd.OutOrStdout(), gen, args[0])
+		},
+	}
+
+	cmd.Flags().StringVarP(&model, "model", "m", "", "Model to use (default: "+prompt.CommitMessageModel+")")
+	cmd.RegisterFlagCompletionFunc("model", completeModels)
+	addProviderFlags(cmd.Flags(), &provider)
+	addRedactFlags(cmd.Flags(), &redaction)
+
+	return cmd
+}
+
+// runExplain prints an explanation of the given commit generated by gen
+// to out.
+func runExplain(out io.Writer, gen Generator, ref string) error {
+	show, err := getCommitShow(ref)
+	if err != nil {
+		return errors.NewCLIError("failed to read commit " + ref).WithCause(err).
+			WithHint("Pass a valid commit ref, e.g. HEAD or a commit hash")
+	}
+
+	systemPrompt, userPrompt := prompt.ExplainCommit(show)
+	explanation, err := gen.Generate(context.Background(), GenerateRequest{
+		System: systemPrompt,
+		Prompt: userPrompt,
+	})
+	if err != nil {
+		return errors.NewCLIError("failed to explain commit").WithCause(err)
+	}
+
+	fmt.Fprintln(out, strings.TrimSpace(explanation))
+	return nil
+}
+
+// getCommitShow returns the message and diff of a commit.
+func getCommitShow(ref string) (string, error) {
+	cmd := exec.Command("git", "show", "--no-color", "--format=medium", ref, "--")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git show failed: %w", err)
+	}
+	return string(output), nil
+}
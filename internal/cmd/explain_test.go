@@ -0,0 +1,64 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRunExplain(t *testing.T) {
+	testRepo(t)
+	commitFile(t, "README.md", "app\n", "docs: add a readme")
+	commitFile(t, "app.go", "package app\n", "feat: add the app package")
+
+	tests := []struct {
+		name string
+		ref  string
+		// wantPrompt must all be in the prompt sent for the commit.
+		wantPrompt []string
+		wantErr    bool
+	}{
+		{
+			name:       "latest commit",
+			ref:        "HEAD",
+			wantPrompt: []string{"feat: add the app package", "+package app"},
+		},
+		{
+			name:       "earlier commit",
+			ref:        "HEAD~1",
+			wantPrompt: []string{"docs: add a readme", "+app"},
+		},
+		{
+			name:    "unknown ref",
+			ref:     "no-such-branch",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := &fakeGenerator{replies: []string{"  It adds the app package.\n\n"}}
+			var out bytes.Buffer
+			err := runExplain(&out, gen, tt.ref)
+			if tt.wantErr {
+				if err == nil || gen.calls() != 0 {
+					t.Errorf("runExplain() error = %v after %d requests, want an error before any", err, gen.calls())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.wantPrompt {
+				if !strings.Contains(gen.requests[0].Prompt, want) {
+					t.Errorf("the prompt does not contain %q", want)
+				}
+			}
+			if out.String() != "It adds the app package.\n" {
+				t.Errorf("printed %q, want the trimmed explanation", out.String())
+			}
+		})
+	}
+}
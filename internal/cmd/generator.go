@@ -0,0 +1,236 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yourorg/arc-sdk/ai"
+)
+
+// Generator produces model output for a prompt. Commands depend on this
+// interface rather than on the AI service directly, so tests and alternative
+// backends can supply their own implementation.
+type Generator interface {
+	Generate(ctx context.Context, req GenerateRequest) (string, error)
+}
+
+// errStreamEnded is returned by streaming generators when the connection
+// closes before the provider marked the reply complete. It counts as a
+// network failure, so the request is retried rather than a fragment used.
+var errStreamEnded = fmt.Errorf("the stream ended before the reply was complete: %w", io.ErrUnexpectedEOF)
+
+// GenerateRequest is a single prompt for a Generator.
+type GenerateRequest struct {
+	System string
+	// History holds the earlier turns of a conversation, oldest first;
+	// Prompt follows them.
+	History []Turn
+	Prompt  string
+	// MaxTokens caps the output length; zero uses the provider default.
+	MaxTokens int
+	// Temperature controls sampling randomness; nil uses the provider
+	// default.
+	Temperature *float64
+	// FinishReason, when set, receives why the provider ended the reply,
+	// e.g. finishLength when it hit MaxTokens. Providers that do not say
+	// leave it empty.
+	FinishReason *string
+}
+
+// finishLength is the finish reason of a reply cut off at the token cap,
+// as OpenAI-compatible APIs and Ollama report it.
+const finishLength = "length"
+
+// reportFinish passes reason on to the caller through r.FinishReason.
+func (r GenerateRequest) reportFinish(reason string) {
+	if r.FinishReason != nil {
+		*r.FinishReason = reason
+	}
+}
+
+// Turn is one earlier exchange in a conversation with the model.
+type Turn struct {
+	Prompt string
+	Reply  string
+}
+
+// StreamingGenerator is a Generator that can also deliver its output as
+// it is produced. onText receives each chunk; the full text is returned
+// as with Generate.
+type StreamingGenerator interface {
+	Generator
+	GenerateStream(ctx context.Context, req GenerateRequest, onText func(string)) (string, error)
+}
+
+// canStream reports whether gen, or the generator it wraps, streams.
+func canStream(gen Generator) bool {
+	switch g := gen.(type) {
+	case *retryingGenerator:
+		return canStream(g.gen)
+	case *redactingGenerator:
+		return canStream(g.gen)
+	case StreamingGenerator:
+		return true
+	}
+	return false
+}
+
+// serviceGenerator adapts the arc-sdk AI service to Generator.
+type serviceGenerator struct {
+	service *ai.Service
+}
+
+// newServiceGenerator creates a Generator backed by the AI service.
+func newServiceGenerator(cfg *ai.Config) (Generator, error) {
+	service, err := newService(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &serviceGenerator{service: service}, nil
+}
+
+// Generate runs the prompt through the AI service.
+func (g *serviceGenerator) Generate(ctx context.Context, req GenerateRequest) (string, error) {
+	resp, err := g.service.Run(ctx, ai.RunOptions{
+		System:      req.System,
+		Prompt:      transcript(req),
+		MaxTokens:   req.MaxTokens,
+		Temperature: req.Temperature,
+	})
+	if err != nil {
+		return "", err
+	}
+	return resp.Text, nil
+}
+
+// transcript renders req's conversation as a single prompt, for services
+// that take one prompt per call.
+func transcript(req GenerateRequest) string {
+	if len(req.History) == 0 {
+		return req.Prompt
+	}
+	var b strings.Builder
+	b.WriteString("This continues an earlier conversation with you, shown first.\n")
+	for _, t := range req.History {
+		b.WriteString("\n<user>\n" + t.Prompt + "\n</user>\n\n<assistant>\n" + t.Reply + "\n</assistant>\n")
+	}
+	b.WriteString("\n<user>\n" + req.Prompt + "\n</user>")
+	return b.String()
+}
+
+// retryBudget caps the retries made across every generation in one run.
+// It is safe for concurrent use, e.g. by --candidates.
+type retryBudget struct {
+	mu sync.Mutex
+	// remaining is the number of retries left; negative means unlimited.
+	remaining int
+	// exhausted is set once a retry was refused for lack of budget.
+	exhausted bool
+}
+
+// newRetryBudget returns a budget of n retries; zero means unlimited.
+func newRetryBudget(n int) *retryBudget {
+	if n == 0 {
+		return &retryBudget{remaining: -1}
+	}
+	return &retryBudget{remaining: n}
+}
+
+// take spends one retry, reporting false when none are left. first is
+// set on the first refusal only, so running out is reported once.
+func (b *retryBudget) take() (ok, first bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.remaining == 0 {
+		first = !b.exhausted
+		b.exhausted = true
+		return false, first
+	}
+	if b.remaining > 0 {
+		b.remaining--
+	}
+	return true, false
+}
+
+// retryingGenerator retries requests that failed to reach the provider, up
+// to maxRetries times per request and within a budget shared by all
+// requests. Errors from the provider itself are returned at once.
+type retryingGenerator struct {
+	gen        Generator
+	maxRetries int
+	budget     *retryBudget
+	// out receives a note for each retry and when the budget runs out.
+	out io.Writer
+	// backoff is the wait before the first retry; it doubles each time.
+	backoff time.Duration
+}
+
+// Generate runs req, retrying network failures.
+func (g *retryingGenerator) Generate(ctx context.Context, req GenerateRequest) (string, error) {
+	return g.retry(ctx, func() (string, bool, error) {
+		text, err := g.gen.Generate(ctx, req)
+		return text, true, err
+	})
+}
+
+// GenerateStream runs req on a streaming generator, retrying network
+// failures as long as no text was delivered yet. A stream that fails
+// after delivering text is generated again in full without streaming, as
+// retrying the stream would repeat what was already shown.
+func (g *retryingGenerator) GenerateStream(ctx context.Context, req GenerateRequest, onText func(string)) (string, error) {
+	streamer, ok := g.gen.(StreamingGenerator)
+	if !ok {
+		return g.Generate(ctx, req)
+	}
+	delivered := false
+	text, err := g.retry(ctx, func() (string, bool, error) {
+		text, err := streamer.GenerateStream(ctx, req, func(chunk string) {
+			delivered = true
+			onText(chunk)
+		})
+		return text, !delivered, err
+	})
+	if err == nil || !delivered || !isNetworkError(err) || g.maxRetries == 0 {
+		return text, err
+	}
+	if ok, first := g.budget.take(); !ok {
+		if first {
+			fmt.Fprintln(g.out, "Warning: the --retry-budget for this run is used up; failing requests are no longer retried.")
+		}
+		return "", err
+	}
+	fmt.Fprintf(g.out, "\nWarning: the reply broke off (%v); generating it again.\n", err)
+	return g.Generate(ctx, req)
+}
+
+// retry runs call, retrying network failures while call reports them
+// retryable.
+func (g *retryingGenerator) retry(ctx context.Context, call func() (text string, retryable bool, err error)) (string, error) {
+	wait := g.backoff
+	for attempt := 0; ; attempt++ {
+		text, retryable, err := call()
+		if err == nil || !retryable || !isNetworkError(err) || attempt >= g.maxRetries {
+			return text, err
+		}
+		if ok, first := g.budget.take(); !ok {
+			if first {
+				fmt.Fprintln(g.out, "Warning: the --retry-budget for this run is used up; failing requests are no longer retried.")
+			}
+			return text, err
+		}
+		fmt.Fprintf(g.out, "Warning: request failed (%v); retrying in %s (%d/%d).\n", err, wait, attempt+1, g.maxRetries)
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+}
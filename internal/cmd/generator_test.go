@@ -0,0 +1,179 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+)
+
+// fakeGenerator answers requests with canned replies in turn, repeating
+// the last one, and records every request it gets. With err set it fails
+// every request instead.
+type fakeGenerator struct {
+	replies []string
+	// reasons are the finish reasons reported with the replies, if any.
+	reasons []string
+	err     error
+
+	mu       sync.Mutex
+	requests []GenerateRequest
+}
+
+func (g *fakeGenerator) Generate(ctx context.Context, req GenerateRequest) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.requests = append(g.requests, req)
+	if g.err != nil {
+		return "", g.err
+	}
+	i := min(len(g.requests), len(g.replies)) - 1
+	if i < len(g.reasons) {
+		req.reportFinish(g.reasons[i])
+	}
+	return g.replies[i], nil
+}
+
+// calls returns the number of requests made so far.
+func (g *fakeGenerator) calls() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return len(g.requests)
+}
+
+// flakyGenerator fails its first failures requests with err, then answers.
+type flakyGenerator struct {
+	failures int
+	err      error
+	calls    int
+}
+
+func (g *flakyGenerator) Generate(ctx context.Context, req GenerateRequest) (string, error) {
+	g.calls++
+	if g.calls <= g.failures {
+		return "", g.err
+	}
+	return "feat: add login page", nil
+}
+
+func TestRetryingGenerator(t *testing.T) {
+	tests := []struct {
+		name       string
+		failures   int
+		err        error
+		maxRetries int
+		budget     int
+		wantErr    bool
+		wantCalls  int
+		wantOut    string
+	}{
+		{name: "recovers", failures: 2, err: syscall.ECONNREFUSED, maxRetries: 2, wantCalls: 3, wantOut: "retrying in 0s (2/2)"},
+		{name: "gives up", failures: 3, err: syscall.ECONNREFUSED, maxRetries: 2, wantErr: true, wantCalls: 3},
+		{name: "provider error", failures: 1, err: errors.New("401 Unauthorized"), maxRetries: 2, wantErr: true, wantCalls: 1},
+		{name: "retries off", failures: 1, err: syscall.ECONNREFUSED, wantErr: true, wantCalls: 1},
+		{
+			name:       "budget used up",
+			failures:   3,
+			err:        syscall.ECONNREFUSED,
+			maxRetries: 5,
+			budget:     1,
+			wantErr:    true,
+			wantCalls:  2,
+			wantOut:    "--retry-budget for this run is used up",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flaky := &flakyGenerator{failures: tt.failures, err: tt.err}
+			var out strings.Builder
+			gen := &retryingGenerator{gen: flaky, maxRetries: tt.maxRetries, budget: newRetryBudget(tt.budget), out: &out}
+			_, err := gen.Generate(context.Background(), GenerateRequest{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Generate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if flaky.calls != tt.wantCalls {
+				t.Errorf("made %d calls, want %d", flaky.calls, tt.wantCalls)
+			}
+			if !strings.Contains(out.String(), tt.wantOut) {
+				t.Errorf("output does not contain %q:\n%s", tt.wantOut, out.String())
+			}
+		})
+	}
+}
+
+func TestRetryBudgetIsShared(t *testing.T) {
+	budget := newRetryBudget(1)
+	var out strings.Builder
+	for i := range 2 {
+		flaky := &flakyGenerator{failures: 1, err: syscall.ECONNREFUSED}
+		gen := &retryingGenerator{gen: flaky, maxRetries: 1, budget: budget, out: &out}
+		_, err := gen.Generate(context.Background(), GenerateRequest{})
+		if (err != nil) != (i == 1) {
+			t.Errorf("request %d: error = %v; only the second should fail", i+1, err)
+		}
+	}
+	if n := strings.Count(out.String(), "used up"); n != 1 {
+		t.Errorf("budget exhaustion reported %d times, want once:\n%s", n, out.String())
+	}
+}
+
+func TestRetryBudgetConcurrentTake(t *testing.T) {
+	budget := newRetryBudget(5)
+	var (
+		mu        sync.Mutex
+		taken     int
+		refusals  int
+		waitGroup sync.WaitGroup
+	)
+	for range 20 {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			ok, first := budget.take()
+			mu.Lock()
+			defer mu.Unlock()
+			if ok {
+				taken++
+			}
+			if first {
+				refusals++
+			}
+		}()
+	}
+	waitGroup.Wait()
+	if taken != 5 || refusals != 1 {
+		t.Errorf("%d retries taken and %d first refusals, want 5 and 1", taken, refusals)
+	}
+}
+
+func TestTranscript(t *testing.T) {
+	tests := []struct {
+		name string
+		req  GenerateRequest
+		want string
+	}{
+		{name: "no history", req: GenerateRequest{Prompt: "write it"}, want: "write it"},
+		{
+			name: "history",
+			req: GenerateRequest{
+				History: []Turn{{Prompt: "write it", Reply: "feat: add app"}},
+				Prompt:  "shorter",
+			},
+			want: "This continues an earlier conversation with you, shown first.\n" +
+				"\n<user>\nwrite it\n</user>\n\n<assistant>\nfeat: add app\n</assistant>\n" +
+				"\n<user>\nshorter\n</user>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := transcript(tt.req); got != tt.want {
+				t.Errorf("transcript() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
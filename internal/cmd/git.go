@@ -0,0 +1,114 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// gitConfig returns the value of a git config key, or an empty string when
+// the key is not set.
+func gitConfig(key string) (string, error) {
+	cmd := exec.Command("git", "config", "--get", key)
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			// Exit code 1 means the key is not set
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read git config %s: %w", key, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// repoRoot returns the top-level directory of the current repository.
+func repoRoot() (string, error) {
+	output, err := exec.Command("git", "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate repository root: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// gitLines runs a git command and returns its non-empty output lines.
+func gitLines(args ...string) ([]string, error) {
+	output, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s failed: %w", args[0], err)
+	}
+	var lines []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
+
+// gitPath resolves a path inside the repository's git directory, such as
+// MERGE_MSG or hooks/commit-msg.
+func gitPath(name string) string {
+	output, err := exec.Command("git", "rev-parse", "--git-path", name).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// commentChar returns the character git uses to mark comment lines in
+// commit messages.
+func commentChar() string {
+	char, err := gitConfig("core.commentChar")
+	if err != nil || char == "" || char == "auto" {
+		return "#"
+	}
+	return char
+}
+
+// stripComments removes comment lines the way git does when cleaning up a
+// commit message.
+func stripComments(text, char string) string {
+	var kept []string
+	for _, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(line, char) {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return strings.Join(kept, "\n")
+}
+
+// cutScissors drops the scissors line git adds for "git commit --verbose"
+// and everything below it.
+func cutScissors(text, char string) string {
+	scissors := char + " ------------------------ >8 ------------------------"
+	if i := strings.Index(text, scissors+"\n"); i >= 0 && (i == 0 || text[i-1] == '\n') {
+		return text[:i]
+	}
+	return text
+}
+
+// currentBranch returns the name of the checked-out branch. It returns an
+// empty string on a detached HEAD, where features derived from the branch
+// name must be skipped rather than fed "HEAD".
+func currentBranch() string {
+	output, err := exec.Command("git", "symbolic-ref", "--quiet", "--short", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// historyArgs returns the git log arguments choosing which commits of a
+// range are summarized. By default merge commits are left out and the
+// commits they brought in are listed. With firstParent only the mainline
+// is followed: its merge commits stand in for the branches they merged.
+func historyArgs(firstParent bool) []string {
+	if firstParent {
+		return []string{"--first-parent"}
+	}
+	return []string{"--no-merges"}
+}
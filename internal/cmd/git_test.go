@@ -0,0 +1,128 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testRepo creates an empty git repository and makes it the working
+// directory for the rest of the test. Git and arc-commit see a home and
+// temp directory of their own, so no user config or saved session leaks in.
+func testRepo(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("TMPDIR", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_CONFIG_COUNT", "")
+	t.Setenv("GIT_AUTHOR_NAME", "Test Author")
+	t.Setenv("GIT_AUTHOR_EMAIL", "author@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test Author")
+	t.Setenv("GIT_COMMITTER_EMAIL", "author@example.com")
+
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	git(t, "init", "-q", "-b", "main")
+	return dir
+}
+
+// git runs a git command in the working directory and returns its trimmed
+// output, failing the test on error.
+func git(t *testing.T, args ...string) string {
+	t.Helper()
+	output, err := exec.Command("git", args...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// writeFile writes content to the file at path, relative to the working
+// directory, creating its directory.
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// commitFile writes content to path and commits it with message.
+func commitFile(t *testing.T, path, content, message string) {
+	t.Helper()
+	writeFile(t, path, content)
+	git(t, "add", path)
+	git(t, "commit", "-q", "-m", message)
+}
+
+func TestCurrentBranch(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(t *testing.T)
+		want  string
+	}{
+		{name: "unborn branch", setup: func(*testing.T) {}, want: "main"},
+		{
+			name: "branch",
+			setup: func(t *testing.T) {
+				commitFile(t, "app.go", "package app\n", "feat: add app")
+				git(t, "checkout", "-q", "-b", "feature/login")
+			},
+			want: "feature/login",
+		},
+		{
+			name: "detached",
+			setup: func(t *testing.T) {
+				commitFile(t, "app.go", "package app\n", "feat: add app")
+				git(t, "checkout", "-q", "--detach")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			tt.setup(t)
+			if got := currentBranch(); got != tt.want {
+				t.Errorf("currentBranch() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommitOnDetachedHead(t *testing.T) {
+	testRepo(t)
+	commitFile(t, "README.md", "# App\n", "docs: add readme")
+	git(t, "checkout", "-q", "--detach")
+	writeFile(t, "app.go", "package app\n")
+	git(t, "add", "app.go")
+
+	var out bytes.Buffer
+	gen := &fakeGenerator{replies: []string{"feat: add the app package"}}
+	if err := runInteractiveCommit(gen, testCommitOptions(t), strings.NewReader("y\n"), &out); err != nil {
+		t.Fatalf("runInteractiveCommit() error = %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "Note: HEAD is detached") {
+		t.Errorf("no detached HEAD note:\n%s", out.String())
+	}
+	if got := git(t, "log", "-1", "--format=%s"); got != "feat: add the app package" {
+		t.Errorf("committed %q", got)
+	}
+}
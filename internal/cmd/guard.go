@@ -0,0 +1,68 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/yourorg/arc-commit/internal/diff"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// guardTopDirs is how many of the busiest directories the guard lists.
+const guardTopDirs = 5
+
+// checkCommitSize asks for confirmation when the staged change touches more
+// than max files, which usually means something was staged by accident. A
+// max of zero or less disables the check.
+func checkCommitSize(reader *bufio.Reader, out io.Writer, events *eventWriter, changes *diff.Diff, max int) error {
+	if max <= 0 || len(changes.Files) <= max {
+		return nil
+	}
+
+	counts := make(map[string]int)
+	for _, f := range changes.Files {
+		counts[path.Dir(f.Path())]++
+	}
+	dirs := make([]string, 0, len(counts))
+	for dir := range counts {
+		dirs = append(dirs, dir)
+	}
+	sort.Slice(dirs, func(i, j int) bool {
+		if counts[dirs[i]] != counts[dirs[j]] {
+			return counts[dirs[i]] > counts[dirs[j]]
+		}
+		return dirs[i] < dirs[j]
+	})
+
+	fmt.Fprintf(out, "\nWarning: more than %d files are staged (--commit-count-guard).\n", max)
+	for i, dir := range dirs {
+		if i == guardTopDirs {
+			fmt.Fprintf(out, "  ... and %d more directories\n", len(dirs)-i)
+			break
+		}
+		label := dir + "/"
+		if dir == "." {
+			label = "(repository root)"
+		}
+		fmt.Fprintf(out, "  %4d  %s\n", counts[dir], label)
+	}
+	fmt.Fprintf(out, "You're about to commit %d files across %d directories - continue? [y/N]: ",
+		len(changes.Files), len(dirs))
+	events.awaiting(inputConfirm, "y", "n")
+
+	answer, _ := reader.ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	if answer != "y" && answer != "yes" {
+		fmt.Fprintln(out)
+		return errors.NewCLIError("commit cancelled: too many files staged").
+			WithHint("Unstage what you did not mean to commit: git restore --staged <path>")
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/arc-commit/internal/diff"
+)
+
+// guardDiff returns a diff touching n files in each of the directories.
+func guardDiff(n int, dirs ...string) *diff.Diff {
+	d := &diff.Diff{}
+	for _, dir := range dirs {
+		for i := range n {
+			d.Files = append(d.Files, &diff.File{OldPath: fmt.Sprintf("%s/f%d.go", dir, i), NewPath: fmt.Sprintf("%s/f%d.go", dir, i)})
+		}
+	}
+	return d
+}
+
+func TestCheckCommitSize(t *testing.T) {
+	tests := []struct {
+		name     string
+		changes  *diff.Diff
+		max      int
+		input    string
+		wantErr  bool
+		wantOut  []string
+		noPrompt bool
+	}{
+		{name: "within the limit", changes: guardDiff(3, "app"), max: 3, noPrompt: true},
+		{name: "disabled", changes: guardDiff(100, "app"), max: 0, noPrompt: true},
+		{
+			name:    "confirmed",
+			changes: guardDiff(2, "app", "docs", "."),
+			max:     5,
+			input:   "y\n",
+			wantOut: []string{"more than 5 files are staged", "     2  app/", "     2  (repository root)", "commit 6 files across 3 directories"},
+		},
+		{name: "declined", changes: guardDiff(4, "app"), max: 3, input: "\n", wantErr: true},
+		{name: "no answer", changes: guardDiff(4, "app"), max: 3, wantErr: true},
+		{
+			name:    "busiest directories first",
+			changes: guardDiff(1, "a", "b", "c", "d", "e", "f", "g"),
+			max:     3,
+			input:   "yes\n",
+			wantOut: []string{"     1  a/\n", "     1  e/\n", "  ... and 2 more directories"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			err := checkCommitSize(bufio.NewReader(strings.NewReader(tt.input)), &out, nil, tt.changes, tt.max)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkCommitSize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.noPrompt && out.Len() > 0 {
+				t.Errorf("asked for confirmation:\n%s", out.String())
+			}
+			for _, want := range tt.wantOut {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("output does not contain %q:\n%s", want, out.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/yourorg/arc-commit/internal/classify"
+	"github.com/yourorg/arc-commit/internal/diff"
+)
+
+// heuristicMessage builds a commit message from the diff and the kinds of
+// the changed files alone, without an AI call. It is used with --no-ai, in
+// sensitive repositories and for whitespace-only changes (formatting). A
+// non-empty scope is added to the header.
+func heuristicMessage(changes *diff.Diff, kinds []classify.File, formatting bool, scope string) string {
+	commitType, verb := heuristicType(kinds), "update"
+	if formatting {
+		commitType, verb = "style", "reformat"
+	}
+	if scope != "" {
+		commitType += "(" + scope + ")"
+	}
+
+	var subject string
+	if len(changes.Files) == 1 {
+		f := changes.Files[0]
+		if v, ok := map[string]string{
+			"added":   "add",
+			"deleted": "remove",
+			"renamed": "rename",
+		}[f.Status()]; ok {
+			verb = v
+		}
+		subject = verb + " " + path.Base(f.Path())
+	} else if dir := commonDir(changes); dir != "" {
+		subject = fmt.Sprintf("%s %d files in %s", verb, len(changes.Files), dir)
+	} else {
+		subject = fmt.Sprintf("%s %d files", verb, len(changes.Files))
+	}
+	if formatting {
+		return commitType + ": " + subject
+	}
+
+	var body strings.Builder
+	for _, f := range changes.Files {
+		fmt.Fprintf(&body, "- %s: %s", f.Path(), f.Status())
+		if !f.Binary {
+			fmt.Fprintf(&body, " (+%d -%d)", f.Added(), f.Removed())
+		}
+		body.WriteString("\n")
+	}
+
+	return commitType + ": " + subject + "\n\n" + strings.TrimSpace(body.String())
+}
+
+// heuristicType guesses a conventional commit type from the kinds of the
+// changed files.
+func heuristicType(kinds []classify.File) string {
+	if t := classify.SuggestedType(kinds); t != "" {
+		return t
+	}
+	return "chore"
+}
+
+// commonDir returns the directory shared by all changed paths, or an empty
+// string when they only share the repository root.
+func commonDir(changes *diff.Diff) string {
+	var common []string
+	for i, f := range changes.Files {
+		parts := strings.Split(path.Dir(f.Path()), "/")
+		if i == 0 {
+			common = parts
+			continue
+		}
+		n := 0
+		for n < len(common) && n < len(parts) && common[n] == parts[n] {
+			n++
+		}
+		common = common[:n]
+	}
+
+	dir := strings.Join(common, "/")
+	if dir == "." {
+		return ""
+	}
+	return dir
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/yourorg/arc-commit/internal/classify"
+	"github.com/yourorg/arc-commit/internal/diff"
+)
+
+func TestHeuristicMessage(t *testing.T) {
+	const (
+		addedDoc = "diff --git a/docs/setup.md b/docs/setup.md\nnew file mode 100644\n--- /dev/null\n+++ b/docs/setup.md\n@@ -0,0 +1,2 @@\n+# Setup\n+Run make.\n"
+		editedGo = "diff --git a/app/app.go b/app/app.go\n--- a/app/app.go\n+++ b/app/app.go\n@@ -1 +1 @@\n-package app\n+package app // import \"example.com/app\"\n"
+		removed  = "diff --git a/app/old.go b/app/old.go\ndeleted file mode 100644\n--- a/app/old.go\n+++ /dev/null\n@@ -1 +0,0 @@\n-package app\n"
+		binary   = "diff --git a/logo.png b/logo.png\nindex 1111111..2222222 100644\nBinary files a/logo.png and b/logo.png differ\n"
+	)
+	tests := []struct {
+		name       string
+		diff       string
+		formatting bool
+		scope      string
+		want       string
+	}{
+		{
+			name: "added doc",
+			diff: addedDoc,
+			want: "docs: add setup.md\n\n- docs/setup.md: added (+2 -0)",
+		},
+		{
+			name:  "scope",
+			diff:  editedGo,
+			scope: "app",
+			want:  "chore(app): update app.go\n\n- app/app.go: modified (+1 -1)",
+		},
+		{
+			name: "files in one directory",
+			diff: editedGo + removed,
+			want: "chore: update 2 files in app\n\n- app/app.go: modified (+1 -1)\n- app/old.go: deleted (+0 -1)",
+		},
+		{
+			name: "files across the repository",
+			diff: editedGo + binary,
+			want: "chore: update 2 files\n\n- app/app.go: modified (+1 -1)\n- logo.png: modified",
+		},
+		{
+			name:       "formatting",
+			diff:       editedGo + removed,
+			formatting: true,
+			want:       "style: reformat 2 files in app",
+		},
+	}
+	classifier, err := classify.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := diff.Parse(tt.diff)
+			got := heuristicMessage(changes, classifier.Diff(changes), tt.formatting, tt.scope)
+			if got != tt.want {
+				t.Errorf("heuristicMessage() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommonDir(t *testing.T) {
+	tests := []struct {
+		paths []string
+		want  string
+	}{
+		{paths: []string{"a/b/c.go", "a/b/d.go"}, want: "a/b"},
+		{paths: []string{"a/b/c.go", "a/e/d.go"}, want: "a"},
+		{paths: []string{"a/b/c.go", "ab/d.go"}, want: ""},
+		{paths: []string{"a/b/c.go", "README.md"}, want: ""},
+	}
+	for _, tt := range tests {
+		changes := &diff.Diff{}
+		for _, p := range tt.paths {
+			changes.Files = append(changes.Files, &diff.File{NewPath: p})
+		}
+		if got := commonDir(changes); got != tt.want {
+			t.Errorf("commonDir(%q) = %q, want %q", tt.paths, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,326 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	commitmsg "github.com/yourorg/arc-commit/internal/message"
+	"github.com/yourorg/arc-sdk/ai"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// hookMarker identifies hook scripts written by arc-commit, which may be
+// replaced without --force.
+const hookMarker = "# Installed by arc-commit"
+
+// hookScripts holds the body of each hook arc-commit can install. The
+// executable path is substituted for %s.
+var hookScripts = map[string]string{
+	"commit-msg":         `exec %s lint "$1"`,
+	"prepare-commit-msg": `exec %s hook prepare-commit-msg --append-issue-from-commit-msg-file "$@"`,
+}
+
+// hookNames lists the hooks arc-commit can install.
+var hookNames = []string{"commit-msg", "prepare-commit-msg"}
+
+// newHookCmd creates the hook subcommand.
+func newHookCmd(aiCfg *ai.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hook",
+		Short: "Manage git hooks that run arc-commit",
+	}
+	cmd.AddCommand(newHookInstallCmd(), newHookUninstallCmd(), newPrepareCommitMsgCmd(aiCfg))
+	return cmd
+}
+
+// newHookInstallCmd creates the hook install subcommand.
+func newHookInstallCmd() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "install [commit-msg|prepare-commit-msg]",
+		Short: "Install a git hook into the current repository",
+		Long: `Install a git hook into the current repository.
+
+The commit-msg hook runs "arc-commit lint" on every commit message,
+rejecting messages with validation errors however the commit was made,
+including plain "git commit". Which rules are enforced is taken from the
+lint settings in the config files, e.g. annotate-why or disable-rule.
+
+The prepare-commit-msg hook fills in a generated message when "git commit"
+opens the editor. A message given with -m is kept as the author's intent:
+it steers the generated message, and any issue references in it, such as
+#123 or ABC-123, are carried over. It is the hook installed when no name
+is given.`,
+		Example: `  # Validate every commit message in this repository
+  arc-commit hook install commit-msg
+
+  # Generate the message for plain "git commit"
+  arc-commit hook install`,
+		Args:      cobra.MatchAll(cobra.MaximumNArgs(1), cobra.OnlyValidArgs),
+		ValidArgs: hookNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := hookName(args)
+			path, err := installHook(name, force)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Installed %s hook at %s\n", name, path)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "Replace an existing hook not written by arc-commit")
+
+	return cmd
+}
+
+// newHookUninstallCmd creates the hook uninstall subcommand.
+func newHookUninstallCmd() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "uninstall [commit-msg|prepare-commit-msg]",
+		Short: "Remove a git hook installed by arc-commit",
+		Long: `Remove a git hook installed by arc-commit from the current repository.
+
+Without a name the prepare-commit-msg hook is removed. A hook that was not
+written by arc-commit is left alone unless --force is given.`,
+		Example: `  # Stop generating messages for plain "git commit"
+  arc-commit hook uninstall
+
+  # Stop validating commit messages
+  arc-commit hook uninstall commit-msg`,
+		Args:      cobra.MatchAll(cobra.MaximumNArgs(1), cobra.OnlyValidArgs),
+		ValidArgs: hookNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := hookName(args)
+			path, err := uninstallHook(name, force)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Removed %s hook at %s\n", name, path)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "Remove the hook even if it was not written by arc-commit")
+
+	return cmd
+}
+
+// hookName returns the hook named in args, prepare-commit-msg by default.
+func hookName(args []string) string {
+	if len(args) == 0 {
+		return "prepare-commit-msg"
+	}
+	return args[0]
+}
+
+// hookPath returns where git looks for the named hook.
+func hookPath(name string) (string, error) {
+	output, err := exec.Command("git", "rev-parse", "--git-path", "hooks/"+name).Output()
+	if err != nil {
+		return "", errors.NewCLIError("not in a git repository").WithCause(err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// installHook writes the named hook script and returns its path.
+func installHook(name string, force bool) (string, error) {
+	path, err := hookPath(name)
+	if err != nil {
+		return "", err
+	}
+
+	if existing, err := os.ReadFile(path); err == nil && !force && !strings.Contains(string(existing), hookMarker) {
+		return "", errors.NewCLIError("a " + name + " hook already exists at " + path).
+			WithHint("Pass --force to replace it")
+	}
+
+	self, err := os.Executable()
+	if err != nil {
+		self = "arc-commit"
+	}
+	// Honor "arc-commit --skip-hook <name>"
+	skip := fmt.Sprintf("case \",$%s,\" in *,%s,*) exit 0 ;; esac", skipHooksEnv, name)
+	script := "#!/bin/sh\n" + hookMarker + "\n" + skip + "\n" + fmt.Sprintf(hookScripts[name], shellQuote(self)) + "\n"
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", errors.NewCLIError("failed to create hooks directory").WithCause(err)
+	}
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		return "", errors.NewCLIError("failed to write hook").WithCause(err)
+	}
+	return path, nil
+}
+
+// uninstallHook removes the named hook script and returns its path.
+func uninstallHook(name string, force bool) (string, error) {
+	path, err := hookPath(name)
+	if err != nil {
+		return "", err
+	}
+	existing, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", errors.NewCLIError("no " + name + " hook is installed")
+	} else if err != nil {
+		return "", errors.NewCLIError("failed to read hook").WithCause(err)
+	}
+	if !force && !strings.Contains(string(existing), hookMarker) {
+		return "", errors.NewCLIError("the " + name + " hook at " + path + " was not installed by arc-commit").
+			WithHint("Pass --force to remove it anyway")
+	}
+	if err := os.Remove(path); err != nil {
+		return "", errors.NewCLIError("failed to remove hook").WithCause(err)
+	}
+	return path, nil
+}
+
+// newPrepareCommitMsgCmd creates the entry point run by the
+// prepare-commit-msg hook. It is the commit command with a different
+// front end, so the hook takes the same flags and settings, from the
+// provider to the prompt, and generates the message as
+// "arc-commit commit --write <file> --exit-after-generate" would.
+func newPrepareCommitMsgCmd(aiCfg *ai.Config) *cobra.Command {
+	var appendIssues bool
+
+	cmd := newCommitCmd(aiCfg)
+	generate := cmd.RunE
+	cmd.Use = "prepare-commit-msg <file> [source [commit]]"
+	cmd.Aliases = []string{"run"}
+	cmd.Short = "Write a generated message into git's message file"
+	cmd.Long = `Write a generated message into git's message file.
+
+This is run by the prepare-commit-msg hook with git's arguments, and can
+be called as "arc-commit hook run" from a hook script of your own, e.g.
+one managed by a hook framework. Merges, squashes and amends are left
+alone. When the hook cannot generate a message the file is kept as it is,
+so the commit can go ahead.
+
+The message is generated as by "arc-commit commit", with the same flags
+and config settings, e.g. --provider, --model or --exclude-path.`
+	cmd.Example = `  # In .git/hooks/prepare-commit-msg
+  arc-commit hook run "$@"
+
+  # Use a local model for the hook
+  arc-commit hook run --provider ollama --model llama3.1 "$@"`
+	cmd.Args = cobra.RangeArgs(1, 3)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		// The hook must never block a commit over its own problems
+		cmd.SilenceUsage = true
+
+		source := ""
+		if len(args) > 1 {
+			source = args[1]
+		}
+		err := prepareCommitMsg(cmd, args[0], source, appendIssues, generate)
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "arc-commit: not generating a message: %v\n", err)
+		}
+		return nil
+	}
+
+	cmd.Flags().BoolVar(&appendIssues, "append-issue-from-commit-msg-file", false, "Use a message already in the file (e.g. from -m) as intent and keep its issue references")
+
+	return cmd
+}
+
+// prepareCommitMsg replaces the message in git's message file with one
+// made by generate, the commit command's RunE. The comment block git
+// appends is kept. source is git's name for where the message came from.
+func prepareCommitMsg(cmd *cobra.Command, path, source string, appendIssues bool, generate func(*cobra.Command, []string) error) error {
+	switch source {
+	case "merge", "squash", "commit":
+		return nil
+	case "message":
+		// Without --append-issue-from-commit-msg-file, -m means the author
+		// wrote the message themselves
+		if !appendIssues {
+			return nil
+		}
+	}
+
+	settings, err := resolveConfig(cmd)
+	if err != nil {
+		return err
+	}
+	provider := providerOptions{
+		Name: cmd.Flags().Lookup("provider").Value.String(),
+		URL:  cmd.Flags().Lookup("provider-url").Value.String(),
+	}
+	if settings.blocksProvider(provider) {
+		return fmt.Errorf("sensitive-repo is set and the provider is not a local model")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	char := commentChar()
+	text := commitmsg.ToLF(string(data))
+	typed := ""
+	if source == "message" {
+		typed = strings.TrimSpace(stripComments(cutScissors(text, char), char))
+	}
+
+	for name, value := range map[string]string{"write": path, "exit-after-generate": "true", "intent": typed} {
+		if err := cmd.Flags().Set(name, value); err != nil {
+			return err
+		}
+	}
+	if err := generate(cmd, nil); err != nil {
+		// The file is only replaced once the message is complete
+		return err
+	}
+
+	block := commentBlock(text, char)
+	if block == "" {
+		return nil
+	}
+	written, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	content := strings.TrimRight(string(written), "\n") + "\n\n" + block
+	return os.WriteFile(path, []byte(content), 0o644)
+}
+
+// intentFeedback turns the message the author typed into feedback for the
+// model, so the generated message keeps their intent.
+func intentFeedback(typed string) string {
+	if typed == "" {
+		return ""
+	}
+	return "The author already described this change as:\n\n" + typed +
+		"\n\nKeep their intent and wording where it fits, and keep any issue references."
+}
+
+// mergeIntent adds the issue references from typed that message dropped,
+// as a Refs trailer next to any the message already has.
+func mergeIntent(message, typed string) string {
+	missing := commitmsg.MissingIssueRefs(message, commitmsg.IssueRefs(typed))
+	if len(missing) == 0 {
+		return message
+	}
+	return commitmsg.AppendTrailers(message, []commitmsg.Trailer{{Key: "Refs", Value: strings.Join(missing, ", ")}})
+}
+
+// commentBlock returns the part of a message file from its first comment
+// line on, which git shows in the editor and strips afterwards.
+func commentBlock(text, char string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, char) {
+			return strings.Join(lines[i:], "\n")
+		}
+	}
+	return ""
+}
@@ -0,0 +1,251 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/arc-sdk/ai"
+)
+
+func TestMergeIntent(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		typed   string
+		want    string
+	}{
+		{
+			name:    "nothing typed",
+			message: "fix: handle empty config",
+			want:    "fix: handle empty config",
+		},
+		{
+			name:    "references kept by the model",
+			message: "fix: handle empty config\n\nCloses #123.",
+			typed:   "fix crash on empty config, closes #123",
+			want:    "fix: handle empty config\n\nCloses #123.",
+		},
+		{
+			name:    "dropped reference",
+			message: "fix: handle empty config",
+			typed:   "fix crash, closes #123",
+			want:    "fix: handle empty config\n\nRefs: #123",
+		},
+		{
+			name:    "next to an existing Refs trailer",
+			message: "fix: handle empty config\n\nRefs: #9",
+			typed:   "fix crash for ABC-12",
+			want:    "fix: handle empty config\n\nRefs: #9\nRefs: ABC-12",
+		},
+		{
+			name:    "only the dropped ones",
+			message: "fix: handle empty config\n\nThe parser is fixed for #123.\n\nSigned-off-by: Jane Doe <jane@example.com>",
+			typed:   "fixes #123 and ABC-12, see owner/repo#7",
+			want:    "fix: handle empty config\n\nThe parser is fixed for #123.\n\nSigned-off-by: Jane Doe <jane@example.com>\nRefs: ABC-12, owner/repo#7",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergeIntent(tt.message, tt.typed); got != tt.want {
+				t.Errorf("mergeIntent() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIntentFeedback(t *testing.T) {
+	if got := intentFeedback(""); got != "" {
+		t.Errorf("intentFeedback(\"\") = %q, want empty", got)
+	}
+	got := intentFeedback("fix crash, closes #123")
+	if want := "fix crash, closes #123"; !strings.Contains(got, want) {
+		t.Errorf("intentFeedback() = %q, want it to quote %q", got, want)
+	}
+}
+
+func TestInstallHook(t *testing.T) {
+	testRepo(t)
+	hook := filepath.Join(".git", "hooks", "commit-msg")
+
+	path, err := installHook("commit-msg", false)
+	if err != nil {
+		t.Fatalf("installHook() error = %v", err)
+	}
+	if path != hook {
+		t.Errorf("installHook() path = %q, want %q", path, hook)
+	}
+	data, err := os.ReadFile(hook)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if script := string(data); !strings.HasPrefix(script, "#!/bin/sh\n"+hookMarker) || !strings.Contains(script, ` lint "$1"`) {
+		t.Errorf("hook script:\n%s", script)
+	}
+
+	// Our own hook is replaced freely
+	if _, err := installHook("commit-msg", false); err != nil {
+		t.Errorf("reinstalling: %v", err)
+	}
+
+	// Someone else's only with force
+	writeFile(t, hook, "#!/bin/sh\nexit 0\n")
+	if _, err := installHook("commit-msg", false); err == nil {
+		t.Error("replaced a foreign hook without --force")
+	}
+	if _, err := installHook("commit-msg", true); err != nil {
+		t.Errorf("installHook(force) error = %v", err)
+	}
+	if data, _ := os.ReadFile(hook); !strings.Contains(string(data), hookMarker) {
+		t.Errorf("foreign hook not replaced:\n%s", data)
+	}
+}
+
+func TestInstalledHookHonorsSkipHook(t *testing.T) {
+	testRepo(t)
+	path, err := installHook("commit-msg", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Skipped before arc-commit is run, so the hook never reaches it
+	for _, skip := range []string{"commit-msg", "pre-commit,commit-msg"} {
+		hook := exec.Command("sh", path, "MSG")
+		hook.Env = append(os.Environ(), skipHooksEnv+"="+skip)
+		if output, err := hook.CombinedOutput(); err != nil {
+			t.Errorf("%s=%s: hook failed: %v\n%s", skipHooksEnv, skip, err, output)
+		}
+	}
+}
+
+func TestCommitSkipHook(t *testing.T) {
+	tests := []struct {
+		name      string
+		skipHooks []string
+		wantErr   bool
+	}{
+		{name: "hook runs", wantErr: true},
+		{name: "hook skipped", skipHooks: []string{"pre-commit"}},
+		{name: "other hook skipped", skipHooks: []string{"commit-msg"}, wantErr: true},
+		{name: "among others", skipHooks: []string{"commit-msg", "pre-commit"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			// A cooperating hook that always fails unless asked to skip
+			writeFile(t, filepath.Join(".git", "hooks", "pre-commit"),
+				"#!/bin/sh\ncase \",$"+skipHooksEnv+",\" in *,pre-commit,*) exit 0 ;; esac\nexit 1\n")
+			if err := os.Chmod(filepath.Join(".git", "hooks", "pre-commit"), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			writeFile(t, "app.go", "package app\n")
+			git(t, "add", "app.go")
+
+			opts := testCommitOptions(t)
+			opts.skipHooks = tt.skipHooks
+			err := createCommit(io.Discard, "feat: add app", nil, commitEnv(opts))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("createCommit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUninstallHook(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+		force    bool
+		wantErr  string
+		wantGone bool
+	}{
+		{name: "not installed", wantErr: "no commit-msg hook is installed", wantGone: true},
+		{name: "installed by arc-commit", existing: "#!/bin/sh\n" + hookMarker + "\nexit 0\n", wantGone: true},
+		{name: "foreign hook", existing: "#!/bin/sh\nexit 0\n", wantErr: "was not installed by arc-commit"},
+		{name: "foreign hook with force", existing: "#!/bin/sh\nexit 0\n", force: true, wantGone: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			hook := filepath.Join(".git", "hooks", "commit-msg")
+			if tt.existing != "" {
+				writeFile(t, hook, tt.existing)
+			}
+
+			_, err := uninstallHook("commit-msg", tt.force)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := os.Stat(hook); os.IsNotExist(err) != tt.wantGone {
+				t.Errorf("hook removed = %v, want %v", os.IsNotExist(err), tt.wantGone)
+			}
+		})
+	}
+}
+
+func TestHookRun(t *testing.T) {
+	const comments = "# Please enter the commit message for your changes.\n#\n# Changes to be committed:\n#\tnew file:   app.go\n"
+	tests := []struct {
+		name    string
+		content string
+		args    []string
+		want    string
+	}{
+		{
+			name:    "plain git commit",
+			content: "\n" + comments,
+			want:    "chore: add app.go\n\n- app.go: added (+1 -0)\n\n" + comments,
+		},
+		{
+			name:    "merge",
+			content: "Merge branch 'topic'\n" + comments,
+			args:    []string{"merge"},
+			want:    "Merge branch 'topic'\n" + comments,
+		},
+		{
+			name:    "message from -m",
+			content: "wip\n",
+			args:    []string{"message"},
+			want:    "wip\n",
+		},
+		{
+			name:    "generation fails",
+			content: "\n" + comments,
+			args:    []string{"", "", "--candidates=0"},
+			want:    "\n" + comments,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			writeFile(t, "app.go", "package app\n")
+			git(t, "add", "app.go")
+			path := filepath.Join(".git", "COMMIT_EDITMSG")
+			writeFile(t, path, tt.content)
+
+			cmd := newHookCmd(&ai.Config{})
+			cmd.SetArgs(append([]string{"run", "--no-ai", path}, tt.args...))
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("the hook failed the commit: %v", err)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := string(data); got != tt.want {
+				t.Errorf("message file =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
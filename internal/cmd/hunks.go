@@ -0,0 +1,100 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/yourorg/arc-commit/internal/diff"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// hunkPickerHelp explains the answers accepted by selectHunks.
+const hunkPickerHelp = `y - show this hunk to the model
+n - leave this hunk out of the prompt
+a - show this and all remaining hunks
+d - leave out the rest of this file
+q - leave out all remaining hunks
+? - print help`
+
+// selectHunks asks, hunk by hunk, which changes the model should see. It
+// only shapes the prompt; everything staged is still committed.
+func selectHunks(reader *bufio.Reader, out io.Writer, events *eventWriter, changes *diff.Diff) (*diff.Diff, error) {
+	var (
+		all, quit bool
+		skipFile  *diff.File
+		readErr   error
+	)
+
+	selected := diff.Filter(changes, func(f *diff.File, h *diff.Hunk) bool {
+		switch {
+		case readErr != nil || quit || skipFile == f:
+			return false
+		case all:
+			return true
+		}
+
+		fmt.Fprintln(out, "\n"+diff.FileHeading(f))
+		what := "this file"
+		if h != nil {
+			fmt.Fprint(out, h.Patch())
+			what = "this hunk"
+		}
+
+		for {
+			fmt.Fprintf(out, "Show %s to the model [y,n,a,d,q,?]? ", what)
+			events.awaiting(inputHunk, "y", "n", "a", "d", "q", "?")
+			answer, err := reader.ReadString('\n')
+			if err != nil {
+				readErr = err
+				return false
+			}
+			switch strings.ToLower(strings.TrimSpace(answer)) {
+			case "y":
+				return true
+			case "n":
+				return false
+			case "a":
+				all = true
+				return true
+			case "d":
+				skipFile = f
+				return false
+			case "q":
+				quit = true
+				return false
+			default:
+				fmt.Fprintln(out, hunkPickerHelp)
+			}
+		}
+	})
+
+	if readErr != nil {
+		return nil, fmt.Errorf("failed to read input: %w", readErr)
+	}
+	return selected, nil
+}
+
+// stageHunks runs "git add -p" on the terminal so the commit can be built
+// hunk by hunk. Answering q ends staging normally; interrupting git or
+// leaving nothing staged aborts the commit.
+func stageHunks(out io.Writer) error {
+	cmd := exec.Command("git", "add", "--patch")
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = out
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return errors.NewCLIError("staging aborted").WithCause(err)
+	}
+	if err := checkStagedChanges(); err != nil {
+		return errors.NewCLIError("nothing was staged").
+			WithHint("Answer y for the hunks to commit, or stage them first: git add <files>")
+	}
+	return nil
+}
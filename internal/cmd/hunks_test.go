@@ -0,0 +1,115 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/arc-commit/internal/diff"
+)
+
+// hunksDiff has two hunks in a.go and one in b.go.
+const hunksDiff = `diff --git a/a.go b/a.go
+--- a/a.go
++++ b/a.go
+@@ -1 +1 @@
+-package a
++package a // first
+@@ -10 +10 @@
+-func A() {}
++func A() { return }
+diff --git a/b.go b/b.go
+--- a/b.go
++++ b/b.go
+@@ -1 +1 @@
+-package b
++package b // only
+`
+
+func TestSelectHunks(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		want     []string
+		wantHelp bool
+		wantErr  bool
+	}{
+		{name: "one by one", input: "y\nn\ny\n", want: []string{"a.go@1", "b.go@1"}},
+		{name: "all remaining", input: "n\na\n", want: []string{"a.go@10", "b.go@1"}},
+		{name: "rest of the file", input: "d\ny\n", want: []string{"b.go@1"}},
+		{name: "quit", input: "y\nq\n", want: []string{"a.go@1"}},
+		{name: "help", input: "?\ny\nq\n", want: []string{"a.go@1"}, wantHelp: true},
+		{name: "input ends", input: "y\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			selected, err := selectHunks(bufio.NewReader(strings.NewReader(tt.input)), &out, nil, diff.Parse(hunksDiff))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("selectHunks() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			var got []string
+			for _, f := range selected.Files {
+				for _, h := range f.Hunks {
+					got = append(got, fmt.Sprintf("%s@%d", f.Path(), h.NewStart))
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("selected %q, want %q", got, tt.want)
+			}
+			if strings.Contains(out.String(), hunkPickerHelp) != tt.wantHelp {
+				t.Errorf("help shown = %v, want %v:\n%s", !tt.wantHelp, tt.wantHelp, out.String())
+			}
+		})
+	}
+}
+
+func TestStageHunks(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		wantStaged string
+		wantErr    bool
+	}{
+		{name: "hunk accepted", input: "y\n", wantStaged: "app.go"},
+		{name: "hunk declined", input: "n\n", wantErr: true},
+		{name: "quit straight away", input: "q\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			commitFile(t, "app.go", "package app\n", "Add app")
+			writeFile(t, "app.go", "package app // changed\n")
+
+			stdin := filepath.Join(t.TempDir(), "stdin")
+			writeFile(t, stdin, tt.input)
+			f, err := os.Open(stdin)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			saved := os.Stdin
+			os.Stdin = f
+			t.Cleanup(func() { os.Stdin = saved })
+
+			var out strings.Builder
+			err = stageHunks(&out)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("stageHunks() error = %v, wantErr %v\n%s", err, tt.wantErr, out.String())
+			}
+			if got := git(t, "diff", "--cached", "--name-only"); got != tt.wantStaged {
+				t.Errorf("staged %q, want %q", got, tt.wantStaged)
+			}
+		})
+	}
+}
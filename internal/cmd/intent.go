@@ -0,0 +1,77 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yourorg/arc-commit/internal/classify"
+	"github.com/yourorg/arc-commit/internal/diff"
+	commitmsg "github.com/yourorg/arc-commit/internal/message"
+)
+
+// intentMismatch cross-checks the message type against what the diff
+// does, for --detect-intent-mismatch. It returns why the type looks wrong,
+// or "" when nothing stands out. Only strong disagreements are reported:
+// the check is a heuristic and the author may know better.
+func intentMismatch(changes *diff.Diff, kinds []classify.File, message string) string {
+	m := commitmsg.Parse(commitmsg.ToLF(message))
+	if !m.Conventional() || len(changes.Files) == 0 {
+		return ""
+	}
+	added, removed := changes.Added(), changes.Removed()
+
+	switch strings.ToLower(m.Type) {
+	case "feat":
+		if removed > added && !addsFiles(changes) && !addsDefinitions(changes) {
+			return fmt.Sprintf("feat: but the diff removes more than it adds (+%d -%d) and defines nothing new; refactor: or chore: may fit better", added, removed)
+		}
+	case "docs":
+		if n := countKind(kinds, classify.Source); n > 0 {
+			return fmt.Sprintf("docs: but %d source file(s) changed", n)
+		}
+	case "test":
+		if countKind(kinds, classify.Test) == 0 {
+			return "test: but no test files changed"
+		}
+	}
+	return ""
+}
+
+// addsFiles reports whether the diff creates any file.
+func addsFiles(changes *diff.Diff) bool {
+	for _, f := range changes.Files {
+		if f.New {
+			return true
+		}
+	}
+	return false
+}
+
+// addsDefinitions reports whether any added line starts a function, type
+// or similar definition.
+func addsDefinitions(changes *diff.Diff) bool {
+	for _, f := range changes.Files {
+		for _, h := range f.Hunks {
+			for _, line := range h.Lines {
+				if strings.HasPrefix(line, "+") && definitionPattern.MatchString(line[1:]) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// countKind counts the files of the given kind.
+func countKind(kinds []classify.File, kind classify.Kind) int {
+	n := 0
+	for _, f := range kinds {
+		if f.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yourorg/arc-commit/internal/classify"
+	"github.com/yourorg/arc-commit/internal/diff"
+)
+
+func TestIntentMismatch(t *testing.T) {
+	const (
+		deletesCode = "diff --git a/app/app.go b/app/app.go\n--- a/app/app.go\n+++ b/app/app.go\n@@ -1,4 +1,2 @@\n package app\n-\n-func Old() {}\n-var unused = 1\n+// Package app runs things.\n"
+		addsFunc    = "diff --git a/app/app.go b/app/app.go\n--- a/app/app.go\n+++ b/app/app.go\n@@ -1,4 +1,2 @@\n package app\n-var a = 1\n-var b = 2\n-var c = 3\n+func New() {}\n"
+		addsFile    = "diff --git a/app/new.txt b/app/new.txt\nnew file mode 100644\n--- /dev/null\n+++ b/app/new.txt\n@@ -0,0 +1 @@\n+x\n" + deletesCode
+		editsDocs   = "diff --git a/README.md b/README.md\n--- a/README.md\n+++ b/README.md\n@@ -1 +1 @@\n-# App\n+# The app\n"
+		editsTest   = "diff --git a/app/app_test.go b/app/app_test.go\n--- a/app/app_test.go\n+++ b/app/app_test.go\n@@ -1 +1 @@\n-package app\n+package app_test\n"
+	)
+	tests := []struct {
+		name    string
+		diff    string
+		message string
+		want    string
+	}{
+		{name: "feat removing code", diff: deletesCode, message: "feat: tidy the app package", want: "feat: but the diff removes more than it adds (+1 -3)"},
+		{name: "feat adding a definition", diff: addsFunc, message: "feat: add New"},
+		{name: "feat adding a file", diff: addsFile, message: "feat: add new.txt"},
+		{name: "refactor removing code", diff: deletesCode, message: "refactor: drop Old"},
+		{name: "docs touching source", diff: editsDocs + deletesCode, message: "docs: describe the app", want: "docs: but 1 source file(s) changed"},
+		{name: "docs only", diff: editsDocs, message: "docs: rename the title"},
+		{name: "test without tests", diff: deletesCode, message: "test: drop Old", want: "test: but no test files changed"},
+		{name: "test with tests", diff: editsTest, message: "test: use an external test package"},
+		{name: "not conventional", diff: deletesCode, message: "Tidy the app package"},
+	}
+	classifier, err := classify.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := diff.Parse(tt.diff)
+			got := intentMismatch(changes, classifier.Diff(changes), tt.message)
+			if tt.want == "" {
+				if got != "" {
+					t.Errorf("intentMismatch() = %q, want no mismatch", got)
+				}
+				return
+			}
+			if !strings.HasPrefix(got, tt.want) {
+				t.Errorf("intentMismatch() = %q, want it to start with %q", got, tt.want)
+			}
+		})
+	}
+}
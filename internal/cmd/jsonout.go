@@ -0,0 +1,158 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/template"
+	"time"
+
+	"github.com/yourorg/arc-commit/internal/diff"
+	commitmsg "github.com/yourorg/arc-commit/internal/message"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// jsonOutput is the data an --output-template-file template is rendered
+// with.
+type jsonOutput struct {
+	// Message is the message split into its parts, as printed by lint --json.
+	Message commitmsg.Structured
+	// Run describes how the message was generated; nil when it was not,
+	// e.g. for lint.
+	Run *runInfo
+}
+
+// runInfo is the metadata of the model calls made for a message. Token
+// counts are estimates, at roughly four characters per token.
+type runInfo struct {
+	Model        string `json:"model"`
+	Calls        int    `json:"calls"`
+	LatencyMS    int64  `json:"latency_ms"`
+	InputTokens  int    `json:"input_tokens"`
+	OutputTokens int    `json:"output_tokens"`
+}
+
+// Values of --output.
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+// outputFormats lists the valid --output values.
+var outputFormats = []string{outputText, outputJSON}
+
+// draftDocument is what --output json prints for a generated message.
+type draftDocument struct {
+	// Message is the final message as it would be committed.
+	Message string               `json:"message"`
+	Parts   commitmsg.Structured `json:"parts"`
+	// Run is null when no model was called, e.g. with --no-ai.
+	Run        *runInfo    `json:"run"`
+	Diff       diffSummary `json:"diff"`
+	DurationMS int64       `json:"duration_ms"`
+}
+
+// diffSummary gives the size of the staged change.
+type diffSummary struct {
+	Files   []string `json:"files"`
+	Added   int      `json:"added"`
+	Removed int      `json:"removed"`
+}
+
+// printDraftDocument writes the --output json document for message to out.
+// data holds its parts and run metadata, and took is the whole run.
+func printDraftDocument(out io.Writer, message string, data jsonOutput, changes *diff.Diff, took time.Duration) error {
+	doc := draftDocument{
+		Message: message,
+		Parts:   data.Message,
+		Run:     data.Run,
+		Diff: diffSummary{
+			Files:   filePaths(changes),
+			Added:   changes.Added(),
+			Removed: changes.Removed(),
+		},
+		DurationMS: took.Milliseconds(),
+	}
+	encoded, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return errors.NewCLIError("failed to encode output").WithCause(err)
+	}
+	fmt.Fprintln(out, string(encoded))
+	return nil
+}
+
+// loadOutputTemplate parses the template at path and checks that it
+// renders valid JSON before any model call is spent on it. withRun tells
+// whether it will be given run metadata.
+func loadOutputTemplate(path string, withRun bool) (*template.Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.NewCLIError("failed to read output template").WithCause(err)
+	}
+	tmpl, err := template.New(path).Funcs(template.FuncMap{"json": toJSON}).Parse(string(data))
+	if err != nil {
+		return nil, errors.NewCLIError("invalid output template").WithCause(err)
+	}
+
+	sample := jsonOutput{Message: commitmsg.Structure("feat(cli): add \"quoted\" flag\n\nWhy it helps.\n\nRefs: #1", nil)}
+	if withRun {
+		sample.Run = &runInfo{Model: "model", Calls: 1, LatencyMS: 1, InputTokens: 1, OutputTokens: 1}
+	}
+	if _, err := renderJSON(tmpl, sample); err != nil {
+		return nil, errors.NewCLIError("output template does not produce valid JSON").WithCause(err).
+			WithHint("Encode values with the json function, e.g. {\"title\": {{json .Message.Subject}}}")
+	}
+	return tmpl, nil
+}
+
+// toJSON encodes v as JSON, for use in templates as {{json .Message.Subject}}.
+func toJSON(v any) (string, error) {
+	data, err := json.Marshal(v)
+	return string(data), err
+}
+
+// renderJSON executes tmpl and checks that the result is valid JSON.
+func renderJSON(tmpl *template.Template, data jsonOutput) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	if !json.Valid(buf.Bytes()) {
+		return nil, fmt.Errorf("rendered output is not valid JSON: %s", bytes.TrimSpace(buf.Bytes()))
+	}
+	return buf.Bytes(), nil
+}
+
+// printTemplateJSON renders data with tmpl and writes it to out.
+func printTemplateJSON(out io.Writer, tmpl *template.Template, data jsonOutput) error {
+	rendered, err := renderJSON(tmpl, data)
+	if err != nil {
+		return errors.NewCLIError("failed to render output template").WithCause(err)
+	}
+	fmt.Fprintln(out, string(bytes.TrimSpace(rendered)))
+	return nil
+}
+
+// meteringGenerator records the calls made through it, for the run
+// metadata of --output-template-file.
+type meteringGenerator struct {
+	gen Generator
+	run runInfo
+}
+
+// Generate runs req and adds its latency and estimated tokens to the run.
+func (g *meteringGenerator) Generate(ctx context.Context, req GenerateRequest) (string, error) {
+	start := time.Now()
+	text, err := g.gen.Generate(ctx, req)
+	g.run.Calls++
+	g.run.LatencyMS += time.Since(start).Milliseconds()
+	g.run.InputTokens += estimateTokens(req.System + transcript(req))
+	g.run.OutputTokens += estimateTokens(text)
+	return text, err
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	commitmsg "github.com/yourorg/arc-commit/internal/message"
+	"github.com/yourorg/arc-sdk/ai"
+)
+
+func TestLoadOutputTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		withRun  bool
+		wantErr  string
+	}{
+		{name: "message only", template: `{"title": {{json .Message.Subject}}, "type": {{json .Message.Type}}}`},
+		{name: "with run", template: `{"model": {{json .Run.Model}}, "calls": {{.Run.Calls}}}`, withRun: true},
+		{name: "syntax error", template: `{"title": {{json .Message.Subject}`, wantErr: "invalid output template"},
+		{name: "unquoted value", template: `{"title": {{.Message.Subject}}}`, wantErr: "output template does not produce valid JSON"},
+		{name: "run without a model", template: `{"model": {{json .Run.Model}}}`, wantErr: "output template does not produce valid JSON"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "out.tmpl")
+			writeFile(t, path, tt.template)
+			_, err := loadOutputTemplate(path, tt.withRun)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("loadOutputTemplate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("loadOutputTemplate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPrintTemplateJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.tmpl")
+	writeFile(t, path, `{"title": {{json .Message.Subject}}, "trailers": {{json .Message.Trailers}}, "calls": {{.Run.Calls}}}`)
+	tmpl, err := loadOutputTemplate(path, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := jsonOutput{
+		Message: commitmsg.Structure("fix: handle \"quoted\" names\n\nRefs: #7", nil),
+		Run:     &runInfo{Calls: 2},
+	}
+	var out strings.Builder
+	if err := printTemplateJSON(&out, tmpl, data); err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Title    string
+		Trailers []commitmsg.StructuredTrailer
+		Calls    int
+	}
+	if err := json.Unmarshal([]byte(out.String()), &got); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, out.String())
+	}
+	if got.Title != `handle "quoted" names` || len(got.Trailers) != 1 || got.Trailers[0].Value != "#7" || got.Calls != 2 {
+		t.Errorf("rendered %+v", got)
+	}
+}
+
+func TestMeteringGenerator(t *testing.T) {
+	gen := &meteringGenerator{gen: &fakeGenerator{replies: []string{"feat: add the app package"}}, run: runInfo{Model: "m"}}
+	for range 2 {
+		if _, err := gen.Generate(context.Background(), GenerateRequest{System: "system", Prompt: strings.Repeat("x", 400)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if gen.run.Calls != 2 || gen.run.Model != "m" {
+		t.Errorf("run = %+v, want 2 calls with model m", gen.run)
+	}
+	if gen.run.InputTokens < 200 || gen.run.OutputTokens == 0 {
+		t.Errorf("run = %+v, want the tokens of both calls", gen.run)
+	}
+}
+
+func TestOutputJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		noAI        bool
+		wantMessage string
+		wantModel   string
+	}{
+		{name: "model", wantMessage: "feat: add the app package", wantModel: "llama3.2"},
+		{name: "no ai", noAI: true, wantMessage: "chore: add app.go"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			commitFile(t, "README.md", "hello\n", "docs: add readme")
+			writeFile(t, "app.go", "package app\n\nfunc Run() {}\n")
+			git(t, "add", "app.go")
+
+			url, _ := fakeOllama(t, "feat: add the app package")
+			args := []string{"--output", "json", "--provider", "ollama", "--provider-url", url, "--model", "llama3.2"}
+			if tt.noAI {
+				args = append(args, "--no-ai")
+			}
+			cmd := newCommitCmd(&ai.Config{})
+			var stdout, stderr bytes.Buffer
+			cmd.SetArgs(args)
+			cmd.SetIn(strings.NewReader(""))
+			cmd.SetOut(&stdout)
+			cmd.SetErr(&stderr)
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("commit failed: %v\n%s", err, stderr.String())
+			}
+
+			var doc draftDocument
+			if err := json.Unmarshal(stdout.Bytes(), &doc); err != nil {
+				t.Fatalf("stdout is not a JSON document: %v\n%s", err, stdout.String())
+			}
+			if !strings.HasPrefix(doc.Message, tt.wantMessage) || doc.Parts.Header != strings.SplitN(doc.Message, "\n", 2)[0] {
+				t.Errorf("message = %q, header = %q, want %q", doc.Message, doc.Parts.Header, tt.wantMessage)
+			}
+			if !slices.Equal(doc.Diff.Files, []string{"app.go"}) || doc.Diff.Added != 3 || doc.Diff.Removed != 0 {
+				t.Errorf("diff = %+v, want app.go +3 -0", doc.Diff)
+			}
+			switch {
+			case tt.wantModel == "" && doc.Run != nil:
+				t.Errorf("run = %+v, want null without a model", doc.Run)
+			case tt.wantModel != "" && (doc.Run == nil || doc.Run.Model != tt.wantModel || doc.Run.Calls != 1):
+				t.Errorf("run = %+v, want one call to %s", doc.Run, tt.wantModel)
+			}
+			if got := git(t, "rev-list", "--count", "HEAD"); got != "1" {
+				t.Errorf("%s commits, want --output json to leave the index uncommitted", got)
+			}
+		})
+	}
+}
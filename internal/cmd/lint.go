@@ -0,0 +1,180 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/cobra"
+	commitmsg "github.com/yourorg/arc-commit/internal/message"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// newLintCmd creates the lint subcommand.
+func newLintCmd() *cobra.Command {
+	var (
+		opts       commitOptions
+		asJSON     bool
+		jsonSchema bool
+		outputTmpl string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "lint [file]",
+		Short: "Validate a commit message",
+		Long: `Validate a commit message against the conventional commit rules.
+
+The message is read from file, or from standard input when no file is
+given. Comment lines and anything below git's scissors line are ignored,
+so the commit-msg hook can pass git's message file directly.
+
+Exits non-zero when the message has validation errors; warnings are
+reported but do not fail.
+
+With --json the message is printed split into its parts (type, scope,
+subject, body, trailers) along with the issues; --json-schema prints the
+JSON Schema of that output.`,
+		Example: `  # Check the message of the last commit
+  git log -1 --format=%B | arc-commit lint
+
+  # Enforce a rationale and skip the subject length check
+  arc-commit lint --annotate-why --disable-rule subject-length msg.txt
+
+  # Get the parts of the last commit message as JSON
+  git log -1 --format=%B | arc-commit lint --json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Failing validation is not a usage error; hooks only need the issues
+			cmd.SilenceUsage = true
+
+			if jsonSchema {
+				return printJSON(cmd.OutOrStdout(), commitmsg.Schema())
+			}
+
+			if _, err := resolveConfig(cmd); err != nil {
+				return err
+			}
+			if err := checkRuleNames(opts.disabledRules); err != nil {
+				return err
+			}
+			if err := checkSubjectCase(opts.subjectCase); err != nil {
+				return err
+			}
+
+			var data []byte
+			var err error
+			if len(args) == 1 {
+				data, err = os.ReadFile(args[0])
+			} else {
+				data, err = io.ReadAll(cmd.InOrStdin())
+			}
+			if err != nil {
+				return errors.NewCLIError("failed to read commit message").WithCause(err)
+			}
+
+			if outputTmpl != "" {
+				tmpl, err := loadOutputTemplate(outputTmpl, false)
+				if err != nil {
+					return err
+				}
+				return lintMessageJSON(cmd.OutOrStdout(), string(data), opts, tmpl)
+			}
+			if asJSON {
+				return lintMessageJSON(cmd.OutOrStdout(), string(data), opts, nil)
+			}
+			return lintMessage(cmd.ErrOrStderr(), string(data), opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.annotateWhy, "annotate-why", false, "Require the body to explain why the change was made")
+	cmd.Flags().IntVar(&opts.minWhyLength, "min-why-length", commitmsg.DefaultMinWhyLength, "Shortest body accepted as a rationale with --annotate-why")
+	cmd.Flags().StringSliceVar(&opts.disabledRules, "disable-rule", nil, "Validation rules to skip: "+strings.Join(commitmsg.RuleNames, ", "))
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print the message parts and issues as JSON on stdout")
+	cmd.Flags().StringVar(&outputTmpl, "output-template-file", "", "Print the message as JSON rendered with this Go template instead of --json's shape")
+	cmd.Flags().BoolVar(&jsonSchema, "json-schema", false, "Print the JSON Schema of the --json output and exit")
+	cmd.Flags().StringVar(&opts.subjectCase, "commit-subject-case", commitmsg.SubjectCasePreserve, "Required case of the subject's first letter after the type: "+strings.Join(commitmsg.SubjectCases, ", "))
+	cmd.Flags().IntVar(&opts.maxSubjectLength, "max-subject-length", commitmsg.DefaultMaxSubjectLength, "Longest header accepted, in characters")
+	cmd.Flags().StringSliceVar(&opts.scopes, "scopes", nil, "Scopes the header may use, e.g. cli,config (default: any)")
+
+	return cmd
+}
+
+// lintMessage validates a raw commit message file, writing any issues to out.
+func lintMessage(out io.Writer, text string, opts commitOptions) error {
+	text = cleanMessageFile(text)
+	issues := validateMessage(text, opts)
+	for _, issue := range issues {
+		fmt.Fprintln(out, "arc-commit: "+issue.String())
+	}
+	if commitmsg.HasErrors(issues) {
+		return errors.NewCLIError("commit message failed validation").
+			WithHint("Fix the errors above, or skip a rule with --disable-rule")
+	}
+	return nil
+}
+
+// lintMessageJSON validates a raw commit message file and writes its
+// structured form, issues included, to out. A non-nil tmpl renders it in
+// the --output-template-file shape.
+func lintMessageJSON(out io.Writer, text string, opts commitOptions, tmpl *template.Template) error {
+	text = cleanMessageFile(text)
+	issues := validateMessage(text, opts)
+	structured := commitmsg.Structure(text, issues)
+	var err error
+	if tmpl != nil {
+		err = printTemplateJSON(out, tmpl, jsonOutput{Message: structured})
+	} else {
+		err = printJSON(out, structured)
+	}
+	if err != nil {
+		return err
+	}
+	if commitmsg.HasErrors(issues) {
+		return errors.NewCLIError("commit message failed validation")
+	}
+	return nil
+}
+
+// cleanMessageFile drops what git removes from a message file: comment
+// lines and anything below the scissors line.
+func cleanMessageFile(text string) string {
+	char := commentChar()
+	return strings.TrimSpace(stripComments(cutScissors(commitmsg.ToLF(text), char), char))
+}
+
+// printJSON writes v to out as indented JSON.
+func printJSON(out io.Writer, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return errors.NewCLIError("failed to encode JSON").WithCause(err)
+	}
+	fmt.Fprintln(out, string(data))
+	return nil
+}
+
+// checkRuleNames rejects unknown validation rule names.
+func checkRuleNames(names []string) error {
+	for _, name := range names {
+		if !slices.Contains(commitmsg.RuleNames, name) {
+			return errors.NewCLIError("unknown validation rule: " + name).
+				WithHint("Use one of: " + strings.Join(commitmsg.RuleNames, ", "))
+		}
+	}
+	return nil
+}
+
+// checkSubjectCase rejects an unknown --commit-subject-case mode.
+func checkSubjectCase(mode string) error {
+	if !slices.Contains(commitmsg.SubjectCases, mode) {
+		return errors.NewCLIError("invalid --commit-subject-case value: " + mode).
+			WithHint("Use one of: " + strings.Join(commitmsg.SubjectCases, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	commitmsg "github.com/yourorg/arc-commit/internal/message"
+)
+
+func TestLintMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		opts    commitOptions
+		wantErr bool
+		wantOut string
+	}{
+		{
+			name: "valid",
+			text: "fix: handle empty config\n",
+		},
+		{
+			name: "comments and verbose diff ignored",
+			text: "fix: handle empty config\n\n# Please enter the commit message\n" +
+				"# ------------------------ >8 ------------------------\n" +
+				"diff --git a/app.go b/app.go\n",
+		},
+		{
+			name:    "invalid",
+			text:    "handle empty config\n",
+			wantErr: true,
+			wantOut: "arc-commit: ",
+		},
+		{
+			name:    "warnings only",
+			text:    "fix: handle empty config.\n",
+			wantOut: "subject should not end with a period",
+		},
+		{
+			name: "disabled rule",
+			text: "fix: handle empty config.\n",
+			opts: commitOptions{disabledRules: []string{"subject-period"}},
+		},
+		{
+			name:    "why required",
+			text:    "fix: handle empty config\n",
+			opts:    commitOptions{annotateWhy: true},
+			wantErr: true,
+			wantOut: "why",
+		},
+		{
+			name:    "subject case",
+			text:    "fix: Handle empty config\n",
+			opts:    commitOptions{subjectCase: commitmsg.SubjectCaseLower},
+			wantErr: true,
+			wantOut: "subject must start in lower case",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			var out bytes.Buffer
+			err := lintMessage(&out, tt.text, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("lintMessage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantOut == "" && out.Len() > 0 {
+				t.Errorf("unexpected output:\n%s", out.String())
+			}
+			if !strings.Contains(out.String(), tt.wantOut) {
+				t.Errorf("output does not contain %q:\n%s", tt.wantOut, out.String())
+			}
+		})
+	}
+}
+
+func TestCutScissors(t *testing.T) {
+	const scissors = "# ------------------------ >8 ------------------------\n"
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"no scissors", "fix: a\n\nbody\n", "fix: a\n\nbody\n"},
+		{"scissors line", "fix: a\n" + scissors + "diff\n", "fix: a\n"},
+		{"at the start", scissors + "diff\n", ""},
+		{"inside a line", "fix: a " + scissors + "diff\n", "fix: a " + scissors + "diff\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cutScissors(tt.text, "#"); got != tt.want {
+				t.Errorf("cutScissors() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLintMessageJSON(t *testing.T) {
+	tests := []struct {
+		name       string
+		text       string
+		wantErr    bool
+		wantType   string
+		wantIssues []string
+	}{
+		{name: "valid", text: "fix(config): handle empty file\n\n# comment\n", wantType: "fix"},
+		{name: "warning", text: "fix: handle empty file.\n", wantType: "fix", wantIssues: []string{"subject-period"}},
+		{name: "invalid", text: "handle empty file\n", wantErr: true, wantIssues: []string{"format"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			var out bytes.Buffer
+			err := lintMessageJSON(&out, tt.text, commitOptions{}, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("lintMessageJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var got commitmsg.Structured
+			if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+				t.Fatalf("output is not JSON: %v\n%s", err, out.String())
+			}
+			if got.Type != tt.wantType {
+				t.Errorf("type = %q, want %q", got.Type, tt.wantType)
+			}
+			var rules []string
+			for _, issue := range got.Issues {
+				rules = append(rules, issue.Rule)
+			}
+			if !slices.Equal(rules, tt.wantIssues) {
+				t.Errorf("issues = %q, want %q", rules, tt.wantIssues)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	commitmsg "github.com/yourorg/arc-commit/internal/message"
+)
+
+// printMenuHelp describes each action of the interactive menu and the
+// modes that change how they behave. It only prints. regenerationsLeft is
+// negative when regenerations are unlimited.
+func printMenuHelp(out io.Writer, opts commitOptions, hasAI bool, regenerationsLeft int) {
+	fmt.Fprintln(out, "\nActions:")
+	fmt.Fprintln(out, "  y  commit with the message shown")
+	switch {
+	case !hasAI:
+		fmt.Fprintln(out, "  n  regenerate the message (needs AI; not available now)")
+	case regenerationsLeft == 0:
+		fmt.Fprintln(out, "  n  regenerate the message (no regenerations left)")
+	default:
+		fmt.Fprintln(out, "  n  regenerate the message, optionally telling the model what to improve")
+	}
+	fmt.Fprintln(out, "  e  edit the message in $EDITOR, then commit it")
+	fmt.Fprintln(out, "  t  edit the trailers (co-authors, refs, sign-off)")
+	fmt.Fprintln(out, "  p  preview how the commit will look in git log and git log --oneline")
+	fmt.Fprintln(out, "  c  cancel without committing")
+	fmt.Fprintln(out, "  ?  show this help")
+
+	var modes []string
+	if opts.strict {
+		modes = append(modes, "--strict: messages with validation errors cannot be committed")
+	}
+	if opts.detectMismatch {
+		modes = append(modes, "--detect-intent-mismatch: types that contradict the diff are flagged")
+	}
+	if opts.annotateWhy {
+		modes = append(modes, "--annotate-why: the body must explain why")
+	}
+	if opts.requireEdit {
+		modes = append(modes, "--require-edit: [e] must change the message")
+	}
+	if regenerationsLeft > 0 {
+		modes = append(modes, fmt.Sprintf("--max-regenerations: %d regeneration(s) left", regenerationsLeft))
+	}
+	if hasAI && opts.diversify != diversifyOff {
+		modes = append(modes, "--diversify="+opts.diversify+": [n] asks for a different message")
+	}
+	if hasAI && opts.editFeedback {
+		modes = append(modes, "--interactive-edit-feedback: [n] opens the editor with your last feedback")
+	}
+	if opts.signKey != "" {
+		modes = append(modes, "--gpg-sign: the commit will be signed")
+	}
+	if opts.changelogFile != "" {
+		modes = append(modes, "--changelog-file: "+opts.changelogFile+" gets an entry on commit")
+	}
+	if len(opts.skipHooks) > 0 {
+		modes = append(modes, "--skip-hook: "+strings.Join(opts.skipHooks, ", "))
+	}
+	if len(modes) > 0 {
+		fmt.Fprintln(out, "\nActive modes:")
+		for _, m := range modes {
+			fmt.Fprintln(out, "  "+m)
+		}
+	}
+}
+
+// actionMenu lists the interactive actions for the arrow-key menu, in the
+// order of the letter prompt. regenLeft is false once --max-regenerations
+// is used up.
+func actionMenu(regenLeft bool) []menuItem {
+	items := []menuItem{{Key: "y", Label: "Commit with this message"}}
+	if regenLeft {
+		items = append(items, menuItem{Key: "n", Label: "Regenerate"})
+	}
+	return append(items,
+		menuItem{Key: "e", Label: "Edit in $EDITOR"},
+		menuItem{Key: "t", Label: "Edit trailers"},
+		menuItem{Key: "p", Label: "Preview in git log"},
+		menuItem{Key: "c", Label: "Cancel"},
+		menuItem{Key: "?", Label: "Help"},
+	)
+}
+
+// Styles accepted by --separator-style.
+const (
+	separatorEquals = "equals"
+	separatorRule   = "rule"
+	separatorBox    = "box"
+	separatorNone   = "none"
+)
+
+// separatorStyles lists the valid --separator-style values.
+var separatorStyles = []string{separatorEquals, separatorRule, separatorBox, separatorNone}
+
+// previewWidth is the width of the lines around the message preview.
+const previewWidth = 70
+
+// printPreview shows the message between separator lines in the given
+// style. The separators are display-only and never part of the message.
+func printPreview(out io.Writer, message, style string) {
+	var top, bottom string
+	switch style {
+	case separatorRule:
+		top = strings.Repeat("-", previewWidth)
+		bottom = top
+	case separatorBox:
+		top = "┌" + strings.Repeat("─", previewWidth-2) + "┐"
+		bottom = "└" + strings.Repeat("─", previewWidth-2) + "┘"
+	case separatorNone:
+	default:
+		top = strings.Repeat("=", previewWidth)
+		bottom = top
+	}
+
+	fmt.Fprintln(out)
+	if top != "" {
+		fmt.Fprintln(out, top)
+	}
+	fmt.Fprintln(out, message)
+	if bottom != "" {
+		fmt.Fprintln(out, bottom)
+	}
+}
+
+// onelineWidth is the terminal width the git log --oneline preview is
+// checked against.
+const onelineWidth = 80
+
+// previewHash stands in for the hash of the commit not yet created.
+const previewHash = "1234567"
+
+// printLogPreview shows how message will appear in "git log --oneline" and
+// "git log". It only prints.
+func printLogPreview(out io.Writer, message string) {
+	header := commitmsg.Parse(commitmsg.ToLF(message)).Header
+	oneline := previewHash
+	if branch := currentBranch(); branch != "" {
+		oneline += " (HEAD -> " + branch + ")"
+	}
+	oneline += " " + header
+
+	fmt.Fprintln(out, "\ngit log --oneline:")
+	fmt.Fprintln(out, "  "+oneline)
+	if width := utf8.RuneCountInString(oneline); width > onelineWidth {
+		fmt.Fprintf(out, "  (%d columns; cut off in an %d-column terminal after %q)\n",
+			width, onelineWidth, string([]rune(oneline)[:onelineWidth]))
+	}
+
+	name, _ := gitConfig("user.name")
+	email, _ := gitConfig("user.email")
+	fmt.Fprintln(out, "\ngit log:")
+	fmt.Fprintf(out, "  commit %s...\n", previewHash)
+	fmt.Fprintf(out, "  Author: %s <%s>\n", name, email)
+	fmt.Fprintf(out, "  Date:   %s\n\n", time.Now().Format("Mon Jan 2 15:04:05 2006 -0700"))
+	for _, line := range strings.Split(strings.TrimSpace(commitmsg.ToLF(message)), "\n") {
+		fmt.Fprintln(out, strings.TrimRight("      "+line, " "))
+	}
+}
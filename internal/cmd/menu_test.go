@@ -0,0 +1,157 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPrintMenuHelp(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     func(*commitOptions)
+		hasAI    bool
+		left     int
+		want     []string
+		dontWant []string
+	}{
+		{
+			name:     "defaults",
+			hasAI:    true,
+			left:     -1,
+			want:     []string{"  y  commit with the message shown", "optionally telling the model what to improve", "  ?  show this help", "--diversify=low"},
+			dontWant: []string{"--strict"},
+		},
+		{
+			name:     "without AI",
+			left:     -1,
+			want:     []string{"(needs AI; not available now)"},
+			dontWant: []string{"Active modes:"},
+		},
+		{
+			name:  "no regenerations left",
+			hasAI: true,
+			left:  0,
+			want:  []string{"(no regenerations left)"},
+		},
+		{
+			name: "modes",
+			opts: func(o *commitOptions) {
+				o.strict, o.annotateWhy, o.requireEdit, o.detectMismatch = true, true, true, true
+				o.skipHooks = []string{"pre-commit", "commit-msg"}
+			},
+			hasAI: true,
+			left:  2,
+			want: []string{
+				"Active modes:",
+				"--strict: messages with validation errors cannot be committed",
+				"--detect-intent-mismatch: types that contradict the diff are flagged",
+				"--annotate-why: the body must explain why",
+				"--require-edit: [e] must change the message",
+				"--max-regenerations: 2 regeneration(s) left",
+				"--skip-hook: pre-commit, commit-msg",
+			},
+		},
+		{
+			name:  "feedback in the editor",
+			opts:  func(o *commitOptions) { o.editFeedback = true },
+			hasAI: true,
+			left:  -1,
+			want:  []string{"--interactive-edit-feedback: [n] opens the editor with your last feedback"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := testCommitOptions(t)
+			if tt.opts != nil {
+				tt.opts(&opts)
+			}
+			var out strings.Builder
+			printMenuHelp(&out, opts, tt.hasAI, tt.left)
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("help does not contain %q:\n%s", want, out.String())
+				}
+			}
+			for _, dontWant := range tt.dontWant {
+				if strings.Contains(out.String(), dontWant) {
+					t.Errorf("help contains %q:\n%s", dontWant, out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestPrintPreview(t *testing.T) {
+	const message = "feat: add login page"
+	rule := func(s string) string { return strings.Repeat(s, previewWidth) }
+	tests := []struct {
+		style string
+		want  string
+	}{
+		{separatorEquals, "\n" + rule("=") + "\n" + message + "\n" + rule("=") + "\n"},
+		{separatorRule, "\n" + rule("-") + "\n" + message + "\n" + rule("-") + "\n"},
+		{separatorBox, "\n┌" + strings.Repeat("─", previewWidth-2) + "┐\n" + message + "\n└" + strings.Repeat("─", previewWidth-2) + "┘\n"},
+		{separatorNone, "\n" + message + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.style, func(t *testing.T) {
+			var out strings.Builder
+			printPreview(&out, message, tt.style)
+			if out.String() != tt.want {
+				t.Errorf("printPreview() =\n%s\nwant\n%s", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintLogPreview(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  string
+		want     []string
+		dontWant []string
+	}{
+		{
+			name:    "short header",
+			message: "feat: add login page\n\nAdds the form.  \n\nRefs: #12",
+			want: []string{
+				"  1234567 (HEAD -> main) feat: add login page\n",
+				"  Author: Test Author <author@example.com>\n",
+				"\n      feat: add login page\n\n      Adds the form.\n\n      Refs: #12\n",
+			},
+			dontWant: []string{"cut off"},
+		},
+		{
+			name:    "long header",
+			message: "feat: " + strings.Repeat("x", 70),
+			want:    []string{"(99 columns; cut off in an 80-column terminal after \"1234567 (HEAD -> main) feat: xxx"},
+		},
+		{
+			name:    "CRLF",
+			message: "fix: handle nil\r\n\r\nBody.",
+			want:    []string{"(HEAD -> main) fix: handle nil\n", "      Body.\n"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			git(t, "config", "user.name", "Test Author")
+			git(t, "config", "user.email", "author@example.com")
+			var out strings.Builder
+			printLogPreview(&out, tt.message)
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("preview does not contain %q:\n%s", want, out.String())
+				}
+			}
+			for _, dont := range tt.dontWant {
+				if strings.Contains(out.String(), dont) {
+					t.Errorf("preview contains %q:\n%s", dont, out.String())
+				}
+			}
+		})
+	}
+}
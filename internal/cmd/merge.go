@@ -0,0 +1,79 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/yourorg/arc-commit/internal/prompt"
+)
+
+// mergeInProgress describes the merge being concluded, or returns nil when
+// the commit is not finishing a merge.
+func mergeInProgress() *prompt.MergeInfo {
+	if err := exec.Command("git", "rev-parse", "-q", "--verify", "MERGE_HEAD").Run(); err != nil {
+		return nil
+	}
+
+	info := &prompt.MergeInfo{}
+	if data, err := os.ReadFile(gitPath("MERGE_MSG")); err == nil {
+		info.Header, info.Conflicts = parseMergeMessage(string(data), commentChar())
+	}
+	if info.Header == "" {
+		info.Header = "Merge commit"
+		if heads, err := gitLines("rev-parse", "--short", "MERGE_HEAD"); err == nil && len(heads) > 0 {
+			info.Header += " " + heads[0]
+		}
+	}
+
+	// Files still unmerged would block the commit, but list them anyway
+	unmerged, _ := gitLines("diff", "--name-only", "--diff-filter=U")
+	for _, path := range unmerged {
+		if !slices.Contains(info.Conflicts, path) {
+			info.Conflicts = append(info.Conflicts, path)
+		}
+	}
+	return info
+}
+
+// parseMergeMessage extracts the header and the conflicted files from the
+// MERGE_MSG git prepares, which lists conflicts as "Conflicts:" followed by
+// one tab-indented path per line, usually commented out.
+func parseMergeMessage(text, char string) (header string, conflicts []string) {
+	inConflicts := false
+	for _, line := range strings.Split(text, "\n") {
+		uncommented := strings.TrimPrefix(line, char)
+		switch {
+		case strings.TrimSpace(uncommented) == "Conflicts:":
+			inConflicts = true
+		case inConflicts && strings.HasPrefix(uncommented, "\t"):
+			conflicts = append(conflicts, strings.TrimSpace(uncommented))
+		case inConflicts && strings.TrimSpace(uncommented) == "":
+			// git separates the heading from the list with an empty comment
+		default:
+			inConflicts = false
+			if header == "" && !strings.HasPrefix(line, char) && strings.TrimSpace(line) != "" {
+				header = strings.TrimSpace(line)
+			}
+		}
+	}
+	return header, conflicts
+}
+
+// mergeHeuristicMessage builds a merge commit message without AI: git's
+// default header plus the files that had conflicts.
+func mergeHeuristicMessage(info *prompt.MergeInfo) string {
+	if len(info.Conflicts) == 0 {
+		return info.Header
+	}
+	var b strings.Builder
+	b.WriteString(info.Header + "\n\nConflicts resolved in:\n")
+	for _, path := range info.Conflicts {
+		b.WriteString("- " + path + "\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
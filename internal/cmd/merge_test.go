@@ -0,0 +1,111 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"os/exec"
+	"slices"
+	"testing"
+
+	"github.com/yourorg/arc-commit/internal/prompt"
+)
+
+func TestParseMergeMessage(t *testing.T) {
+	tests := []struct {
+		name          string
+		text          string
+		char          string
+		wantHeader    string
+		wantConflicts []string
+	}{
+		{
+			name:       "no conflicts",
+			text:       "Merge branch 'topic'\n",
+			char:       "#",
+			wantHeader: "Merge branch 'topic'",
+		},
+		{
+			name:          "commented conflicts",
+			text:          "Merge branch 'topic'\n\n# Conflicts:\n#\tapp.go\n#\tdocs/app.md\n#\n# It looks like you may be committing a merge.\n",
+			char:          "#",
+			wantHeader:    "Merge branch 'topic'",
+			wantConflicts: []string{"app.go", "docs/app.md"},
+		},
+		{
+			name:          "uncommented conflicts",
+			text:          "Merge branch 'topic' into main\n\nConflicts:\n\tapp.go\n",
+			char:          "#",
+			wantHeader:    "Merge branch 'topic' into main",
+			wantConflicts: []string{"app.go"},
+		},
+		{
+			name:          "custom comment char",
+			text:          "Merge branch 'topic'\n\n; Conflicts:\n;\tapp.go\n",
+			char:          ";",
+			wantHeader:    "Merge branch 'topic'",
+			wantConflicts: []string{"app.go"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header, conflicts := parseMergeMessage(tt.text, tt.char)
+			if header != tt.wantHeader {
+				t.Errorf("header = %q, want %q", header, tt.wantHeader)
+			}
+			if !slices.Equal(conflicts, tt.wantConflicts) {
+				t.Errorf("conflicts = %q, want %q", conflicts, tt.wantConflicts)
+			}
+		})
+	}
+}
+
+func TestMergeHeuristicMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		info prompt.MergeInfo
+		want string
+	}{
+		{
+			name: "no conflicts",
+			info: prompt.MergeInfo{Header: "Merge branch 'topic'"},
+			want: "Merge branch 'topic'",
+		},
+		{
+			name: "conflicts",
+			info: prompt.MergeInfo{Header: "Merge branch 'topic'", Conflicts: []string{"app.go", "go.sum"}},
+			want: "Merge branch 'topic'\n\nConflicts resolved in:\n- app.go\n- go.sum",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergeHeuristicMessage(&tt.info); got != tt.want {
+				t.Errorf("mergeHeuristicMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeInProgress(t *testing.T) {
+	testRepo(t)
+	commitFile(t, "app.go", "package app\n", "feat: add app")
+	if info := mergeInProgress(); info != nil {
+		t.Fatalf("mergeInProgress() = %+v outside a merge", info)
+	}
+
+	git(t, "checkout", "-q", "-b", "topic")
+	commitFile(t, "app.go", "package app // topic\n", "feat: topic change")
+	git(t, "checkout", "-q", "main")
+	commitFile(t, "app.go", "package app // main\n", "feat: main change")
+	if err := exec.Command("git", "merge", "-q", "topic").Run(); err == nil {
+		t.Fatal("merge did not conflict")
+	}
+
+	info := mergeInProgress()
+	if info == nil {
+		t.Fatal("mergeInProgress() = nil during a merge")
+	}
+	if info.Header != "Merge branch 'topic'" || !slices.Equal(info.Conflicts, []string{"app.go"}) {
+		t.Errorf("mergeInProgress() = %q %q, want \"Merge branch 'topic'\" [app.go]", info.Header, info.Conflicts)
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// modelCheckTTL is how long a successful --check-model result is trusted.
+const modelCheckTTL = 24 * time.Hour
+
+// modelCheckPath returns the file caching when each model last passed
+// --check-model.
+func modelCheckPath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "arc-commit", "models.json"), nil
+}
+
+// modelCheckKey returns the cache key for model served by p. The same name
+// can be served by one endpoint and missing from another, so the provider
+// and its URL are part of it.
+func modelCheckKey(p providerOptions, model string) string {
+	url := p.URL
+	if url == "" {
+		url = defaultProviderURLs[p.Name]
+	}
+	return p.Name + " " + url + " " + model
+}
+
+// modelNotFoundHints are fragments of the errors providers return for a
+// model they do not serve, e.g. OpenAI's model_not_found code, Anthropic's
+// not_found_error and Ollama's "model \"x\" not found".
+var modelNotFoundHints = []string{
+	"404",
+	"model_not_found",
+	"not_found_error",
+	"not found",
+	"does not exist",
+}
+
+// isModelNotFound reports whether err means the provider does not serve
+// the requested model, as opposed to failing for another reason such as a
+// bad key or a rate limit.
+func isModelNotFound(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, hint := range modelNotFoundHints {
+		if strings.Contains(msg, hint) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadModelChecks reads the cached check times, keyed by modelCheckKey. A
+// missing or unreadable cache is treated as empty.
+func loadModelChecks(path string) map[string]time.Time {
+	checks := map[string]time.Time{}
+	if data, err := os.ReadFile(path); err == nil {
+		json.Unmarshal(data, &checks)
+	}
+	return checks
+}
+
+// saveModelChecks writes the cached check times.
+func saveModelChecks(path string, checks map[string]time.Time) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(checks, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// checkModel verifies that provider can serve model before the workflow
+// starts, with a one-token request. Successes are cached for modelCheckTTL
+// so the check costs nothing on most runs. An unreachable provider is not
+// a model problem and only gets a warning; other failures, such as a
+// rejected key, are reported as they are.
+func checkModel(out io.Writer, gen Generator, provider providerOptions, model string) error {
+	key := modelCheckKey(provider, model)
+	path, err := modelCheckPath()
+	var checks map[string]time.Time
+	if err == nil {
+		checks = loadModelChecks(path)
+		if at, ok := checks[key]; ok && time.Since(at) < modelCheckTTL {
+			return nil
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	_, err = gen.Generate(ctx, GenerateRequest{Prompt: "ping", MaxTokens: 1})
+	switch {
+	case err != nil && isNetworkError(err):
+		fmt.Fprintf(out, "Warning: could not check model %s: %v\n", model, err)
+		return nil
+	case err != nil && isModelNotFound(err):
+		hint := "Check the model name, or pick another with --model"
+		if _, ok := lookupModel(model); !ok && provider.Name == providerAnthropic {
+			hint = "Known models: " + knownModelNames()
+		}
+		return errors.NewCLIError("model " + model + " is not available").WithCause(err).WithHint(hint)
+	case err != nil:
+		return errors.NewCLIError("failed to check model " + model).WithCause(err)
+	}
+
+	if checks != nil {
+		checks[key] = time.Now()
+		if err := saveModelChecks(path, checks); err != nil {
+			fmt.Fprintf(out, "Warning: failed to cache model check: %v\n", err)
+		}
+	}
+	return nil
+}
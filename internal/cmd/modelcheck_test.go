@@ -0,0 +1,41 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestIsModelNotFound(t *testing.T) {
+	tests := []struct {
+		err  string
+		want bool
+	}{
+		{`404 Not Found: The model "gpt-5-tiny" does not exist or you do not have access to it.`, true},
+		{`404 Not Found: model "llama9" not found, try pulling it first`, true},
+		{`not_found_error: model: claude-nope`, true},
+		{"401 Unauthorized: Incorrect API key provided", false},
+		{"429 Too Many Requests: Rate limit reached", false},
+		{"context deadline exceeded", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.err, func(t *testing.T) {
+			if got := isModelNotFound(errors.New(tt.err)); got != tt.want {
+				t.Errorf("isModelNotFound(%q) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModelCheckKey(t *testing.T) {
+	local := modelCheckKey(providerOptions{Name: providerOllama}, "llama3.2")
+	remote := modelCheckKey(providerOptions{Name: providerOllama, URL: "http://gpu-box:11434"}, "llama3.2")
+	if local == remote {
+		t.Errorf("the same model on different servers shares the key %q", local)
+	}
+	if other := modelCheckKey(providerOptions{Name: providerOpenAI, URL: "http://localhost:11434"}, "llama3.2"); other == local {
+		t.Errorf("different providers share the key %q", local)
+	}
+}
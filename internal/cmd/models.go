@@ -0,0 +1,111 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-commit/internal/prompt"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// knownModel describes a model offered for shell completion.
+type knownModel struct {
+	Name        string
+	Description string
+	// InputPrice and OutputPrice are list prices in USD per million tokens.
+	InputPrice, OutputPrice float64
+}
+
+// knownModels lists the models suggested when completing --model, with
+// their pricing.
+var knownModels = []knownModel{
+	{Name: "claude-haiku-4-5-20251001", Description: "Fast and inexpensive", InputPrice: 1, OutputPrice: 5},
+	{Name: "claude-sonnet-4-5-20250929", Description: "Balanced quality and speed", InputPrice: 3, OutputPrice: 15},
+	{Name: "claude-opus-4-1-20250805", Description: "Highest quality", InputPrice: 15, OutputPrice: 75},
+}
+
+// assumedOutputTokens estimates the length of a generated message when the
+// output is not capped.
+const assumedOutputTokens = 300
+
+// lookupModel returns the known model with the given name.
+func lookupModel(name string) (knownModel, bool) {
+	for _, m := range knownModels {
+		if m.Name == name {
+			return m, true
+		}
+	}
+	return knownModel{}, false
+}
+
+// knownModelNames returns the names of the known models, comma separated.
+func knownModelNames() string {
+	names := make([]string, len(knownModels))
+	for i, m := range knownModels {
+		names[i] = m.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// callCost estimates the cost in USD of one call to m.
+func callCost(m knownModel, inputTokens, outputTokens int) float64 {
+	return (float64(inputTokens)*m.InputPrice + float64(outputTokens)*m.OutputPrice) / 1e6
+}
+
+// confirmModelSwitch asks before generating with a model that costs more
+// per call than the default one. Unknown models cannot be priced and are
+// let through with a note.
+func confirmModelSwitch(reader *bufio.Reader, out io.Writer, events *eventWriter, model, base string, inputTokens, outputTokens int) error {
+	if model == base {
+		return nil
+	}
+	chosen, ok := lookupModel(model)
+	if !ok {
+		fmt.Fprintf(out, "Note: no pricing known for %s; cannot compare its cost.\n", model)
+		return nil
+	}
+	def, ok := lookupModel(base)
+	if !ok {
+		return nil
+	}
+	cost, baseCost := callCost(chosen, inputTokens, outputTokens), callCost(def, inputTokens, outputTokens)
+	if cost <= baseCost {
+		return nil
+	}
+
+	fmt.Fprintf(out, "\n%s costs about $%.4f per call for this diff (~%d tokens in), %.1fx the $%.4f of %s.\n",
+		model, cost, inputTokens, cost/baseCost, baseCost, base)
+	fmt.Fprint(out, "Use it anyway? [y/N]: ")
+	events.awaiting(inputConfirm, "y", "n")
+	answer, _ := reader.ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	if answer != "y" && answer != "yes" {
+		fmt.Fprintln(out)
+		return errors.NewCLIError("cancelled: " + model + " costs more than " + base).
+			WithHint("Drop --model to use " + base)
+	}
+	return nil
+}
+
+// completeModels suggests known model names for the --model flag, marking
+// the default one.
+func completeModels(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var suggestions []string
+	for _, m := range knownModels {
+		if !strings.HasPrefix(m.Name, toComplete) {
+			continue
+		}
+		description := m.Description
+		if m.Name == prompt.CommitMessageModel {
+			description += " (default)"
+		}
+		suggestions = append(suggestions, m.Name+"\t"+description)
+	}
+	return suggestions, cobra.ShellCompDirectiveNoFileComp
+}
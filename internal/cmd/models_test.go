@@ -0,0 +1,64 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestCallCost(t *testing.T) {
+	haiku, ok := lookupModel("claude-haiku-4-5-20251001")
+	if !ok {
+		t.Fatal("haiku is not a known model")
+	}
+	// 2000 tokens in at $1/M plus 300 out at $5/M.
+	if got, want := callCost(haiku, 2000, 300), 0.0035; math.Abs(got-want) > 1e-9 {
+		t.Errorf("callCost() = %v, want %v", got, want)
+	}
+	if _, ok := lookupModel("gpt-4"); ok {
+		t.Error("lookupModel() found an unknown model")
+	}
+}
+
+func TestConfirmModelSwitch(t *testing.T) {
+	const (
+		haiku  = "claude-haiku-4-5-20251001"
+		sonnet = "claude-sonnet-4-5-20250929"
+		opus   = "claude-opus-4-1-20250805"
+	)
+	tests := []struct {
+		name      string
+		model     string
+		base      string
+		input     string
+		wantErr   bool
+		wantAsked bool
+		wantOut   string
+	}{
+		{name: "same model", model: haiku, base: haiku},
+		{name: "cheaper model", model: haiku, base: sonnet},
+		{name: "unknown model", model: "local-llama", base: haiku, wantOut: "no pricing known for local-llama"},
+		{name: "unknown base", model: opus, base: "local-llama"},
+		{name: "dearer model accepted", model: opus, base: haiku, input: "y\n", wantAsked: true, wantOut: "15.0x"},
+		{name: "dearer model declined", model: sonnet, base: haiku, input: "\n", wantAsked: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			err := confirmModelSwitch(bufio.NewReader(strings.NewReader(tt.input)), &out, nil, tt.model, tt.base, 2000, 300)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("confirmModelSwitch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := strings.Contains(out.String(), "Use it anyway?"); got != tt.wantAsked {
+				t.Errorf("asked = %v, want %v:\n%s", got, tt.wantAsked, out.String())
+			}
+			if !strings.Contains(out.String(), tt.wantOut) {
+				t.Errorf("output does not contain %q:\n%s", tt.wantOut, out.String())
+			}
+		})
+	}
+}
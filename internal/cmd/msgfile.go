@@ -0,0 +1,48 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// writeMessageFile writes the message that would have been committed to
+// path, for tooling that creates the commit itself, e.g. with
+// "git commit -F path". An existing file is only replaced when overwrite is
+// set. The file is replaced atomically, so a reader never sees half a
+// message.
+func writeMessageFile(out io.Writer, path, message string, overwrite bool) error {
+	if _, err := os.Lstat(path); err == nil && !overwrite {
+		return errors.NewCLIError(path + " already exists").
+			WithHint("Pass --overwrite to replace it")
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".arc-commit-msg-*")
+	if err != nil {
+		return errors.NewCLIError("failed to write " + path).WithCause(err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(message); err != nil {
+		tmp.Close()
+		return errors.NewCLIError("failed to write " + path).WithCause(err)
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.NewCLIError("failed to write " + path).WithCause(err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return errors.NewCLIError("failed to write " + path).WithCause(err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return errors.NewCLIError("failed to write " + path).WithCause(err)
+	}
+
+	clearSession()
+	fmt.Fprintf(out, "\nWrote the message to %s (no commit created).\n", path)
+	return nil
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteMessageFile(t *testing.T) {
+	const message = "feat: add the app package\n"
+	tests := []struct {
+		name      string
+		existing  string
+		overwrite bool
+		missing   bool
+		want      string
+		wantErr   bool
+	}{
+		{name: "new file", want: message},
+		{name: "existing file kept", existing: "old\n", want: "old\n", wantErr: true},
+		{name: "existing file replaced", existing: "old\n", overwrite: true, want: message},
+		{name: "missing directory", missing: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			dir := t.TempDir()
+			if tt.missing {
+				dir = filepath.Join(dir, "missing")
+			}
+			path := filepath.Join(dir, "MSG")
+			if tt.existing != "" {
+				writeFile(t, path, tt.existing)
+			}
+
+			var out strings.Builder
+			err := writeMessageFile(&out, path, message, tt.overwrite)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("writeMessageFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got, _ := os.ReadFile(path); string(got) != tt.want {
+				t.Errorf("file = %q, want %q", got, tt.want)
+			}
+			if entries, _ := os.ReadDir(dir); len(entries) > 1 {
+				t.Errorf("temporary files left behind: %v", entries)
+			}
+		})
+	}
+}
+
+func TestCommitWrite(t *testing.T) {
+	testRepo(t)
+	writeFile(t, "app.go", "package app\n")
+	git(t, "add", "app.go")
+	path := filepath.Join(t.TempDir(), "MSG")
+
+	opts := testCommitOptions(t)
+	opts.autoYes, opts.writePath = true, path
+	var out bytes.Buffer
+	if err := runInteractiveCommit(&fakeGenerator{replies: []string{"feat: add the app package"}}, opts, strings.NewReader(""), &out); err != nil {
+		t.Fatalf("runInteractiveCommit() error = %v\n%s", err, out.String())
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(got), "feat: add the app package") {
+		t.Errorf("wrote %q", got)
+	}
+	if exec.Command("git", "rev-parse", "--verify", "-q", "HEAD").Run() == nil {
+		t.Error("a commit was created")
+	}
+	if staged := git(t, "diff", "--cached", "--name-only"); staged != "app.go" {
+		t.Errorf("staged %q, want the changes left staged", staged)
+	}
+}
+
+func TestExitAfterGenerate(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+		args     []string
+		want     string
+		wantErr  bool
+	}{
+		{name: "new file", args: []string{"--no-ai"}, want: "chore: update app.go"},
+		{name: "replaces the file", existing: "old message\n", args: []string{"--no-ai"}, want: "chore: update app.go"},
+		{name: "needs --write", args: []string{"--no-ai", "--write="}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			commitFile(t, "app.go", "package app\n", "Add app")
+			writeFile(t, "app.go", "package app // changed\n")
+			git(t, "add", "app.go")
+			path := filepath.Join(t.TempDir(), "COMMIT_EDITMSG")
+			if tt.existing != "" {
+				writeFile(t, path, tt.existing)
+			}
+
+			args := append([]string{"--exit-after-generate", "--write", path}, tt.args...)
+			// No input is given: any prompt would fail the run
+			out, err := runCommitCmd(t, "", args...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("commit error = %v, wantErr %v\n%s", err, tt.wantErr, out)
+			}
+			if err != nil {
+				return
+			}
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(string(got), tt.want) {
+				t.Errorf("wrote %q, want it to start with %q", got, tt.want)
+			}
+			if n := git(t, "rev-list", "--count", "HEAD"); n != "1" {
+				t.Errorf("%s commits, want no new commit", n)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+)
+
+// networkErrorHints are fragments of the messages of network failures, for
+// providers that do not wrap the underlying error.
+var networkErrorHints = []string{
+	"no such host",
+	"connection refused",
+	"connection reset",
+	"network is unreachable",
+	"i/o timeout",
+	"dial tcp",
+}
+
+// isNetworkError reports whether err means the AI provider could not be
+// reached, as opposed to the provider rejecting the request.
+func isNetworkError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ENETUNREACH) ||
+		errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	for _, hint := range networkErrorHints {
+		if strings.Contains(msg, hint) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,35 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"testing"
+)
+
+func TestIsNetworkError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "dial error", err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("refused")}, want: true},
+		{name: "wrapped refusal", err: fmt.Errorf("request failed: %w", syscall.ECONNREFUSED), want: true},
+		{name: "cut off", err: fmt.Errorf("reading reply: %w", io.ErrUnexpectedEOF), want: true},
+		{name: "unwrapped message", err: errors.New(`Post "https://api.example.com": dial tcp: lookup api.example.com: no such host`), want: true},
+		{name: "rejected request", err: errors.New("401 Unauthorized: invalid API key"), want: false},
+		{name: "rate limited", err: errors.New("429 Too Many Requests"), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isNetworkError(tt.err); got != tt.want {
+				t.Errorf("isNetworkError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ollamaGenerator talks to the HTTP API of an Ollama server, so messages
+// can be generated without the diff leaving the machine.
+type ollamaGenerator struct {
+	endpoint string
+	model    string
+	client   *http.Client
+}
+
+// newOllamaGenerator creates a Generator for the Ollama server at p.URL.
+func newOllamaGenerator(p providerOptions, model string) (Generator, error) {
+	base, err := parseProviderURL(p.URL)
+	if err != nil {
+		return nil, err
+	}
+	base.Path += "/api/chat"
+	return &ollamaGenerator{
+		endpoint: base.String(),
+		model:    model,
+		// Local models can be slow, especially while loading
+		client: &http.Client{Timeout: 5 * time.Minute},
+	}, nil
+}
+
+// ollamaRequest is the body of an /api/chat request.
+type ollamaRequest struct {
+	Model    string          `json:"model"`
+	Messages []openAIMessage `json:"messages"`
+	Stream   bool            `json:"stream"`
+	Options  ollamaOptions   `json:"options,omitempty"`
+}
+
+// ollamaOptions are the sampling options of an /api/chat request.
+type ollamaOptions struct {
+	NumPredict  int      `json:"num_predict,omitempty"`
+	Temperature *float64 `json:"temperature,omitempty"`
+}
+
+// ollamaResponse is the part of an /api/chat response, or of one streamed
+// line, that is used.
+type ollamaResponse struct {
+	Message openAIMessage `json:"message"`
+	Done    bool          `json:"done"`
+	// DoneReason is "length" when the reply hit num_predict; in a stream
+	// only the last line has one.
+	DoneReason string `json:"done_reason"`
+	Error      string `json:"error"`
+}
+
+// send posts req as a chat request and returns the response, which the
+// caller must close. Error statuses are returned as errors.
+func (g *ollamaGenerator) send(ctx context.Context, req GenerateRequest, stream bool) (*http.Response, error) {
+	data, err := json.Marshal(ollamaRequest{
+		Model:    g.model,
+		Messages: chatMessages(req),
+		Stream:   stream,
+		Options: ollamaOptions{
+			NumPredict:  req.MaxTokens,
+			Temperature: req.Temperature,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, g.endpoint, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	resp, err := g.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		raw, _ := io.ReadAll(resp.Body)
+		var parsed ollamaResponse
+		if json.Unmarshal(raw, &parsed) == nil && parsed.Error != "" {
+			// e.g. model "x" not found, try pulling it first
+			return nil, fmt.Errorf("%s: %s", resp.Status, parsed.Error)
+		}
+		return nil, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(raw)))
+	}
+	return resp, nil
+}
+
+// Generate sends req as a chat request and returns the reply.
+func (g *ollamaGenerator) Generate(ctx context.Context, req GenerateRequest) (string, error) {
+	resp, err := g.send(ctx, req, false)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var parsed ollamaResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return "", fmt.Errorf("invalid response: %w", err)
+	}
+	req.reportFinish(parsed.DoneReason)
+	return parsed.Message.Content, nil
+}
+
+// GenerateStream sends req as a streamed chat request, passing each chunk
+// of the reply to onText as the JSON lines arrive.
+func (g *ollamaGenerator) GenerateStream(ctx context.Context, req GenerateRequest, onText func(string)) (string, error) {
+	resp, err := g.send(ctx, req, true)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var text strings.Builder
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var chunk ollamaResponse
+		if err := decoder.Decode(&chunk); err == io.EOF {
+			return text.String(), errStreamEnded
+		} else if err != nil {
+			return text.String(), fmt.Errorf("invalid stream chunk: %w", err)
+		}
+		if chunk.Error != "" {
+			return text.String(), fmt.Errorf("stream failed: %s", chunk.Error)
+		}
+		if chunk.Message.Content != "" {
+			text.WriteString(chunk.Message.Content)
+			onText(chunk.Message.Content)
+		}
+		if chunk.Done {
+			req.reportFinish(chunk.DoneReason)
+			return text.String(), nil
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/yourorg/arc-commit/internal/codeowners"
+	"github.com/yourorg/arc-commit/internal/diff"
+)
+
+// ownershipScope picks a commit scope for the changes from CODEOWNERS: the
+// area owning most of the changed files. Without a CODEOWNERS file or a
+// matching rule it falls back to the directory shared by the changes, and
+// returns an empty string when there is none.
+func ownershipScope(changes *diff.Diff) string {
+	if rules := loadCodeowners(); len(rules) > 0 {
+		counts := make(map[string]int)
+		for _, f := range changes.Files {
+			if rule, ok := rules.Match(f.Path()); ok {
+				if area := rule.Area(); area != "" {
+					counts[area]++
+				}
+			}
+		}
+		best := ""
+		for area, n := range counts {
+			if n > counts[best] || (n == counts[best] && area < best) {
+				best = area
+			}
+		}
+		if best != "" {
+			return best
+		}
+	}
+
+	if dir := commonDir(changes); dir != "" {
+		return path.Base(dir)
+	}
+	return ""
+}
+
+// loadCodeowners reads the repository's CODEOWNERS file from the first of
+// the locations GitHub checks. It returns nil when there is none or it
+// cannot be read.
+func loadCodeowners() codeowners.Rules {
+	root, err := repoRoot()
+	if err != nil {
+		return nil
+	}
+	for _, loc := range codeowners.Locations {
+		f, err := os.Open(filepath.Join(root, filepath.FromSlash(loc)))
+		if err != nil {
+			continue
+		}
+		defer f.Close()
+		rules, err := codeowners.Parse(f)
+		if err != nil {
+			return nil
+		}
+		return rules
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yourorg/arc-commit/internal/diff"
+)
+
+func TestOwnershipScope(t *testing.T) {
+	const codeowners = "*  @acme/core\n/services/payments/  @acme/payments\n/services/billing/  @acme/billing\n"
+	changed := func(paths ...string) *diff.Diff {
+		var b strings.Builder
+		for _, p := range paths {
+			b.WriteString("diff --git a/" + p + " b/" + p + "\n--- a/" + p + "\n+++ b/" + p + "\n@@ -1 +1 @@\n-a\n+b\n")
+		}
+		return diff.Parse(b.String())
+	}
+	tests := []struct {
+		name       string
+		codeowners string
+		location   string
+		changes    *diff.Diff
+		want       string
+	}{
+		{
+			name:       "most files win",
+			codeowners: codeowners,
+			location:   ".github/CODEOWNERS",
+			changes:    changed("services/payments/a.go", "services/payments/b.go", "services/billing/c.go"),
+			want:       "payments",
+		},
+		{
+			name:       "ties broken by name",
+			codeowners: codeowners,
+			location:   "CODEOWNERS",
+			changes:    changed("services/payments/a.go", "services/billing/c.go"),
+			want:       "billing",
+		},
+		{
+			name:       "catch-all rule",
+			codeowners: codeowners,
+			location:   "docs/CODEOWNERS",
+			changes:    changed("main.go"),
+			want:       "core",
+		},
+		{
+			name:    "no CODEOWNERS, shared directory",
+			changes: changed("internal/cmd/a.go", "internal/cmd/b.go"),
+			want:    "cmd",
+		},
+		{
+			name:    "no CODEOWNERS, nothing shared",
+			changes: changed("main.go", "internal/cmd/b.go"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			if tt.location != "" {
+				writeFile(t, tt.location, tt.codeowners)
+			}
+			if got := ownershipScope(tt.changes); got != tt.want {
+				t.Errorf("ownershipScope() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	commitmsg "github.com/yourorg/arc-commit/internal/message"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// Values of split's --output-format.
+const (
+	splitOutputCommits     = "commits"
+	splitOutputPatchSeries = "patch-series"
+)
+
+// splitOutputFormats lists the accepted --output-format values.
+var splitOutputFormats = []string{splitOutputCommits, splitOutputPatchSeries}
+
+// maxPatchNameLength caps the subject part of a patch file name, as git
+// format-patch does.
+const maxPatchNameLength = 52
+
+// writePatchSeries writes each group, with a message generated for it, to
+// dir as a numbered .patch file in the format of git format-patch, which
+// git am applies in order. Nothing is staged or committed.
+func writePatchSeries(out io.Writer, gen Generator, groups []splitGroup, dir string) error {
+	author, err := gitIdent()
+	if err != nil {
+		return errors.NewCLIError("patch-series output needs your git identity").WithCause(err).
+			WithHint("Set it with: git config user.name \"Your Name\" && git config user.email you@example.com")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return errors.NewCLIError("failed to create " + dir).WithCause(err)
+	}
+
+	date := time.Now()
+	var written []string
+	for i, g := range groups {
+		fmt.Fprintf(out, "\n[%d/%d] %s\n", i+1, len(groups), g.summary)
+		message, err := groupMessage(out, gen, g)
+		if err != nil {
+			return errors.NewCLIError("failed to generate commit message").WithCause(err)
+		}
+		path := filepath.Join(dir, patchFileName(i+1, message))
+		patch := formatPatch(author, date, i+1, len(groups), message, g.changes.Patch())
+		if err := os.WriteFile(path, []byte(patch), 0o644); err != nil {
+			return errors.NewCLIError("failed to write " + path).WithCause(err)
+		}
+		written = append(written, path)
+	}
+
+	fmt.Fprintf(out, "\nWrote %d patch(es); apply them with: git am %s\n", len(written), strings.Join(written, " "))
+	return nil
+}
+
+// formatPatch renders one patch of a series of total as git format-patch
+// does: a mail with the message as subject and body, then the diff.
+func formatPatch(author string, date time.Time, n, total int, message, patch string) string {
+	header, body, _ := strings.Cut(strings.TrimSpace(commitmsg.ToLF(message)), "\n")
+	subject := "[PATCH]"
+	if total > 1 {
+		subject = fmt.Sprintf("[PATCH %d/%d]", n, total)
+	}
+
+	var b strings.Builder
+	b.WriteString("From 0000000000000000000000000000000000000000 Mon Sep 17 00:00:00 2001\n")
+	name, email, _ := strings.Cut(author, " <")
+	fmt.Fprintf(&b, "From: %s <%s\n", mime.QEncoding.Encode("UTF-8", name), email)
+	fmt.Fprintf(&b, "Date: %s\n", date.Format(time.RFC1123Z))
+	fmt.Fprintf(&b, "Subject: %s\n", mime.QEncoding.Encode("UTF-8", subject+" "+header))
+	b.WriteString("MIME-Version: 1.0\nContent-Type: text/plain; charset=UTF-8\nContent-Transfer-Encoding: 8bit\n\n")
+	if body = strings.TrimSpace(body); body != "" {
+		b.WriteString(body + "\n")
+	}
+	b.WriteString("---\n")
+	b.WriteString(patch)
+	if !strings.HasSuffix(patch, "\n") {
+		b.WriteString("\n")
+	}
+	b.WriteString("-- \narc-commit\n")
+	return b.String()
+}
+
+// patchFileName names patch n after the subject of message, as in
+// "0001-fix-retry-limit.patch".
+func patchFileName(n int, message string) string {
+	header, _, _ := strings.Cut(strings.TrimSpace(commitmsg.ToLF(message)), "\n")
+	name := slugify(header)
+	if len(name) > maxPatchNameLength {
+		name = name[:maxPatchNameLength]
+		if i := strings.LastIndex(name, "-"); i > 0 {
+			name = name[:i]
+		}
+		name = strings.ToValidUTF8(name, "")
+	}
+	return fmt.Sprintf("%04d-%s.patch", n, name)
+}
+
+// gitIdent returns the author identity git would record, "Name <email>".
+func gitIdent() (string, error) {
+	output, err := exec.Command("git", "var", "GIT_AUTHOR_IDENT").Output()
+	if err != nil {
+		return "", err
+	}
+	// "Name <email> 1700000000 +0100"
+	ident := strings.TrimSpace(string(output))
+	if i := strings.LastIndex(ident, ">"); i >= 0 {
+		ident = ident[:i+1]
+	}
+	return ident, nil
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourorg/arc-commit/internal/diff"
+)
+
+func TestPatchFileName(t *testing.T) {
+	tests := []struct {
+		name    string
+		n       int
+		message string
+		want    string
+	}{
+		{"subject", 1, "fix: retry limit\n\nBody.", "0001-fix-retry-limit.patch"},
+		{"scope", 12, "feat(cli): add --json", "0012-feat-cli-add-json.patch"},
+		{
+			"long subject cut at a word",
+			3,
+			"refactor: move the configuration loading into its own package for reuse",
+			"0003-refactor-move-the-configuration-loading-into-its.patch",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := patchFileName(tt.n, tt.message); got != tt.want {
+				t.Errorf("patchFileName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatPatch(t *testing.T) {
+	date := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	const patch = "diff --git a/app.go b/app.go\n"
+	tests := []struct {
+		name    string
+		n       int
+		total   int
+		message string
+		want    []string
+	}{
+		{
+			name:    "single",
+			n:       1,
+			total:   1,
+			message: "fix: retry limit",
+			want:    []string{"Subject: [PATCH] fix: retry limit\n", "\n\n---\n" + patch},
+		},
+		{
+			name:    "series with body",
+			n:       2,
+			total:   3,
+			message: "fix: retry limit\n\nThe limit was off by one.",
+			want:    []string{"Subject: [PATCH 2/3] fix: retry limit\n", "\n\nThe limit was off by one.\n---\n" + patch},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatPatch("Test User <test@example.com>", date, tt.n, tt.total, tt.message, patch)
+			want := append([]string{"From: Test User <test@example.com>\n", "Date: Sat, 01 Mar 2025 12:00:00 +0000\n"}, tt.want...)
+			for _, w := range want {
+				if !strings.Contains(got, w) {
+					t.Errorf("patch does not contain %q:\n%s", w, got)
+				}
+			}
+		})
+	}
+}
+
+func TestWritePatchSeriesAppliesWithGitAm(t *testing.T) {
+	testRepo(t)
+	commitFile(t, "app.go", "package app\n", "feat: add app")
+	commitFile(t, "README.md", "# App\n", "docs: add readme")
+	writeFile(t, "app.go", "package app // changed\n")
+	writeFile(t, "README.md", "# The app\n")
+	git(t, "add", ".")
+	staged := diff.Parse(git(t, "diff", "--staged") + "\n")
+
+	var groups []splitGroup
+	for _, f := range staged.Files {
+		groups = append(groups, splitGroup{summary: f.Path(), changes: &diff.Diff{Files: []*diff.File{f}}})
+	}
+	gen := &fakeGenerator{replies: []string{"docs: reword the readme title", "fix: mark the app as changed"}}
+	dir := filepath.Join(t.TempDir(), "patches")
+	if err := writePatchSeries(io.Discard, gen, groups, dir); err != nil {
+		t.Fatalf("writePatchSeries() error = %v", err)
+	}
+
+	// Nothing was committed; the patches apply on a clean tree
+	if got := git(t, "log", "-1", "--format=%s"); got != "docs: add readme" {
+		t.Fatalf("HEAD moved to %q", got)
+	}
+	git(t, "reset", "-q", "--hard")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := []string{"am", "-q"}
+	for _, e := range entries {
+		args = append(args, filepath.Join(dir, e.Name()))
+	}
+	if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+		t.Fatalf("git am failed: %v\n%s", err, output)
+	}
+
+	subjects := strings.Split(git(t, "log", "-2", "--reverse", "--format=%s"), "\n")
+	if want := []string{"docs: reword the readme title", "fix: mark the app as changed"}; !slices.Equal(subjects, want) {
+		t.Errorf("applied commits %q, want %q", subjects, want)
+	}
+	if data, _ := os.ReadFile("app.go"); string(data) != "package app // changed\n" {
+		t.Errorf("app.go after git am = %q", data)
+	}
+}
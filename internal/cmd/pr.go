@@ -0,0 +1,166 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-commit/internal/config"
+	"github.com/yourorg/arc-commit/internal/prompt"
+	"github.com/yourorg/arc-sdk/ai"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// prOptions holds the flag values of the pr subcommand.
+type prOptions struct {
+	base        string
+	create      bool
+	draft       bool
+	firstParent bool
+}
+
+// newPRCmd creates the pr subcommand.
+func newPRCmd(aiCfg *ai.Config) *cobra.Command {
+	var (
+		opts      prOptions
+		model     string
+		provider  providerOptions
+		redaction redactOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "pr",
+		Short: "Write a pull request title and description for the current branch",
+		Long: `Write a pull request title and description for the current branch.
+
+The commits between the base branch and HEAD, and their combined diff,
+are sent to the AI, which writes a title and a markdown description. The
+result is printed, or with --create used to open the pull request with
+the GitHub CLI (gh).
+
+With --first-parent only the branch's own commits are listed, and the
+branches merged into it are represented by their merge commits. This
+helps for a branch that merged other feature branches, whose commits
+would otherwise all be listed.`,
+		Example: `  # Print a title and description for the branch
+  arc-commit pr
+
+  # Compare against another base branch and open the pull request
+  arc-commit pr --base develop --create`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			settings, err := resolveConfig(cmd)
+			if err != nil {
+				return err
+			}
+			if settings.blocksProvider(provider) {
+				return errors.NewCLIError("pr is disabled in sensitive repositories").
+					WithHint("Use a local model, e.g. --provider ollama; sensitive-repo is set in " + config.FileName + " or the global config")
+			}
+			if err := validateProvider(provider); err != nil {
+				return err
+			}
+			if opts.draft && !opts.create {
+				return errors.NewCLIError("--draft requires --create")
+			}
+
+			cfg := *aiCfg
+			gen, _, err := newGenerator(&cfg, provider, model)
+			if err != nil {
+				return err
+			}
+			if gen, err = withRedaction(gen, settings, redaction, cmd.ErrOrStderr()); err != nil {
+				return err
+			}
+			return runPR(cmd.OutOrStdout(), cmd.ErrOrStderr(), gen, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.base, "base", "main", "Branch the pull request merges into")
+	cmd.Flags().BoolVar(&opts.create, "create", false, "Open the pull request with gh pr create instead of printing it")
+	cmd.Flags().BoolVar(&opts.draft, "draft", false, "With --create, open the pull request as a draft")
+	cmd.Flags().BoolVar(&opts.firstParent, "first-parent", false, "List only the branch's own commits and merges, not the commits merged into it")
+	cmd.Flags().StringVarP(&model, "model", "m", "", "Model to use (default: "+prompt.CommitMessageModel+")")
+	cmd.RegisterFlagCompletionFunc("model", completeModels)
+	addProviderFlags(cmd.Flags(), &provider)
+	addRedactFlags(cmd.Flags(), &redaction)
+
+	return cmd
+}
+
+// runPR writes a pull request for base...HEAD with gen, then prints it to
+// out or opens it with gh. Progress goes to status.
+func runPR(out, status io.Writer, gen Generator, opts prOptions) error {
+	// The commits and diff since the branch left base, as GitHub shows them
+	rangeSpec := opts.base + "...HEAD"
+	args := append([]string{"log", "--reverse"}, historyArgs(opts.firstParent)...)
+	log, err := exec.Command("git", append(args, "--format=- %s%n%w(0,2,2)%b", opts.base+"..HEAD")...).Output()
+	if err != nil {
+		return errors.NewCLIError("failed to list commits since " + opts.base).WithCause(err).
+			WithHint("Pass the branch to compare against with --base")
+	}
+	if strings.TrimSpace(string(log)) == "" {
+		return errors.NewCLIError("no commits between " + opts.base + " and HEAD")
+	}
+	changes, err := exec.Command("git", "diff", "--no-color", rangeSpec).Output()
+	if err != nil {
+		return errors.NewCLIError("failed to diff " + rangeSpec).WithCause(err)
+	}
+
+	fmt.Fprintln(status, "Generating pull request with AI...")
+	system, user := prompt.PullRequest(strings.TrimSpace(string(log)), string(changes))
+	text, err := gen.Generate(context.Background(), GenerateRequest{System: system, Prompt: user})
+	if err != nil {
+		return errors.NewCLIError("failed to generate pull request").WithCause(err)
+	}
+	title, body := splitPR(text)
+	if title == "" {
+		return errors.NewCLIError("the model returned an empty pull request")
+	}
+
+	if !opts.create {
+		fmt.Fprintf(out, "%s\n\n%s\n", title, body)
+		return nil
+	}
+	return createPR(out, title, body, opts)
+}
+
+// splitPR splits generated text into the title on its first line and the
+// description after it. A markdown heading marker or "Title:" label on the
+// title is dropped.
+func splitPR(text string) (title, body string) {
+	title, body, _ = strings.Cut(strings.TrimSpace(text), "\n")
+	title = strings.TrimSpace(strings.TrimLeft(title, "#"))
+	title = strings.TrimSpace(strings.TrimPrefix(title, "Title:"))
+	return title, strings.TrimSpace(body)
+}
+
+// createPR opens the pull request with gh, which prints its URL to out.
+func createPR(out io.Writer, title, body string, opts prOptions) error {
+	if _, err := exec.LookPath("gh"); err != nil {
+		return errors.NewCLIError("--create needs the GitHub CLI (gh)").
+			WithHint("Install it from https://cli.github.com, or drop --create and paste the output")
+	}
+	args := []string{"pr", "create", "--base", opts.base, "--title", title, "--body-file", "-"}
+	if opts.draft {
+		args = append(args, "--draft")
+	}
+	cmd := exec.Command("gh", args...)
+	cmd.Stdin = strings.NewReader(body)
+	cmd.Stdout = out
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return errors.NewCLIError("gh pr create failed").WithCause(err).
+			WithHint("Push the branch first and check gh auth status")
+	}
+	return nil
+}
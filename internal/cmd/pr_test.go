@@ -0,0 +1,116 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/arc-sdk/ai"
+)
+
+func TestSplitPR(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		wantTitle string
+		wantBody  string
+	}{
+		{name: "title and body", text: "Add dark mode\n\nA dark theme.\n", wantTitle: "Add dark mode", wantBody: "A dark theme."},
+		{name: "title only", text: "  Add dark mode  ", wantTitle: "Add dark mode"},
+		{name: "heading", text: "# Add dark mode\n\n## Summary", wantTitle: "Add dark mode", wantBody: "## Summary"},
+		{name: "label", text: "Title: Add dark mode\n\nA dark theme.", wantTitle: "Add dark mode", wantBody: "A dark theme."},
+		{name: "empty", text: "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			title, body := splitPR(tt.text)
+			if title != tt.wantTitle || body != tt.wantBody {
+				t.Errorf("splitPR() = %q, %q, want %q, %q", title, body, tt.wantTitle, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestRunPR(t *testing.T) {
+	tests := []struct {
+		name    string
+		reply   string
+		branch  bool
+		want    string
+		wantErr string
+	}{
+		{name: "prints the pull request", reply: "Add dark mode\n\nA dark theme.", branch: true, want: "Add dark mode\n\nA dark theme.\n"},
+		{name: "no commits", reply: "Add dark mode", wantErr: "no commits between main and HEAD"},
+		{name: "empty reply", reply: " \n", branch: true, wantErr: "the model returned an empty pull request"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			commitFile(t, "app.go", "package app\n", "feat: add app")
+			git(t, "checkout", "-q", "-b", "dark-mode")
+			if tt.branch {
+				commitFile(t, "theme.go", "package app\n", "feat: add a dark theme")
+			}
+
+			gen := &fakeGenerator{replies: []string{tt.reply}}
+			var out bytes.Buffer
+			err := runPR(&out, io.Discard, gen, prOptions{base: "main"})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("output = %q, want %q", out.String(), tt.want)
+			}
+			if prompt := gen.requests[0].Prompt; !strings.Contains(prompt, "- feat: add a dark theme") || !strings.Contains(prompt, "+++ b/theme.go") {
+				t.Errorf("the prompt is missing the branch's commits or diff:\n%s", prompt)
+			}
+		})
+	}
+}
+
+func TestPRDraftNeedsCreate(t *testing.T) {
+	testRepo(t)
+	cmd := newPRCmd(&ai.Config{})
+	cmd.SetArgs([]string{"--draft"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "--draft requires --create") {
+		t.Errorf("err = %v, want --draft requires --create", err)
+	}
+}
+
+func TestRunPRFirstParent(t *testing.T) {
+	for _, firstParent := range []bool{false, true} {
+		t.Run(fmt.Sprintf("firstParent=%v", firstParent), func(t *testing.T) {
+			base := mergeHistory(t)
+			git(t, "branch", "-q", "base", base)
+			commitFile(t, "docs.md", "dark mode\n", "docs: describe dark mode")
+
+			gen := &fakeGenerator{replies: []string{"Add dark mode\n\nA dark theme."}}
+			if err := runPR(io.Discard, io.Discard, gen, prOptions{base: "base", firstParent: firstParent}); err != nil {
+				t.Fatal(err)
+			}
+			prompt := gen.requests[0].Prompt
+			if !strings.Contains(prompt, "- docs: describe dark mode") {
+				t.Error("the prompt is missing the branch's own commit")
+			}
+			if got := strings.Contains(prompt, "- feat: add a dark theme"); got == firstParent {
+				t.Errorf("merged commit listed = %v", got)
+			}
+			if got := strings.Contains(prompt, "- feat: add dark mode"); got != firstParent {
+				t.Errorf("merge commit listed = %v", got)
+			}
+		})
+	}
+}
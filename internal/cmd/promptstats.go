@@ -0,0 +1,59 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/yourorg/arc-commit/internal/diff"
+	"github.com/yourorg/arc-commit/internal/prompt"
+)
+
+// printPromptStats reports what the model is sent, for --verbose-diff-stats:
+// how many staged files and hunks reach the prompt in full, how many are
+// reduced to stats or left out by --exclude-path, --select-hunks and
+// --ignore-whitespace, and the size of the prompt. summarized may be nil.
+func printPromptStats(w io.Writer, changes, promptDiff, summarized *diff.Diff, promptOpts prompt.CommitOptions) {
+	inPrompt := map[string]bool{}
+	for _, f := range promptDiff.Files {
+		inPrompt[f.Path()] = true
+	}
+	statsOnly := map[string]bool{}
+	if summarized != nil {
+		for _, f := range summarized.Files {
+			statsOnly[f.Path()] = true
+		}
+	}
+
+	omitted := 0
+	for _, f := range changes.Files {
+		if !inPrompt[f.Path()] && !statsOnly[f.Path()] {
+			omitted++
+		}
+	}
+	hunks := func(d *diff.Diff) int {
+		n := 0
+		if d != nil {
+			for _, f := range d.Files {
+				n += len(f.Hunks)
+			}
+		}
+		return n
+	}
+	droppedHunks := hunks(changes) - hunks(promptDiff) - hunks(summarized)
+
+	system, user := prompt.CommitMessage(diff.Format(promptDiff), "", promptOpts)
+	size := len(system) + len(user)
+
+	fmt.Fprintln(w, "Prompt composition (--verbose-diff-stats):")
+	fmt.Fprintf(w, "  staged:      %d file(s), %d hunk(s), +%d -%d lines\n", len(changes.Files), hunks(changes), changes.Added(), changes.Removed())
+	fmt.Fprintf(w, "  full diff:   %d file(s), %d hunk(s)\n", len(promptDiff.Files), hunks(promptDiff))
+	fmt.Fprintf(w, "  stats only:  %d file(s) (--max-files-in-prompt, --prompt-token-budget)\n", len(statsOnly))
+	fmt.Fprintf(w, "  left out:    %d file(s), %d hunk(s) (--exclude-path, --select-hunks, --ignore-whitespace; outlined parts of --prompt-token-budget)\n", omitted, max(droppedHunks, 0))
+	if promptOpts.WeightBySize {
+		fmt.Fprintln(w, "  order:       largest changes first (--weight-by-size)")
+	}
+	fmt.Fprintf(w, "  prompt:      %d bytes, ~%d tokens (diff %d bytes)\n", size, estimateTokens(system+user), len(diff.Format(promptDiff)))
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/arc-commit/internal/diff"
+	"github.com/yourorg/arc-commit/internal/prompt"
+)
+
+func TestPrintPromptStats(t *testing.T) {
+	changes := diff.Parse(testDiff)
+	only := func(keep func(path string) bool) *diff.Diff {
+		return diff.Filter(changes, func(f *diff.File, _ *diff.Hunk) bool { return keep(f.Path()) })
+	}
+
+	tests := []struct {
+		name       string
+		promptDiff *diff.Diff
+		summarized *diff.Diff
+		promptOpts prompt.CommitOptions
+		want       []string
+		dontWant   []string
+	}{
+		{
+			name:       "whole diff",
+			promptDiff: changes,
+			want: []string{
+				"staged:      4 file(s), 4 hunk(s), +8 -0 lines",
+				"full diff:   4 file(s), 4 hunk(s)",
+				"stats only:  0 file(s)",
+				"left out:    0 file(s), 0 hunk(s)",
+			},
+			dontWant: []string{"order:"},
+		},
+		{
+			name:       "excluded file",
+			promptDiff: only(func(path string) bool { return path != "go.sum" }),
+			want: []string{
+				"full diff:   3 file(s), 3 hunk(s)",
+				"left out:    1 file(s), 1 hunk(s)",
+			},
+		},
+		{
+			name:       "summarized file",
+			promptDiff: only(func(path string) bool { return path != "docs/app.md" }),
+			summarized: only(func(path string) bool { return path == "docs/app.md" }),
+			want: []string{
+				"full diff:   3 file(s), 3 hunk(s)",
+				"stats only:  1 file(s)",
+				"left out:    0 file(s), 0 hunk(s)",
+			},
+		},
+		{
+			name:       "weight by size",
+			promptDiff: changes,
+			promptOpts: prompt.CommitOptions{WeightBySize: true},
+			want:       []string{"order:       largest changes first"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			printPromptStats(&out, changes, tt.promptDiff, tt.summarized, tt.promptOpts)
+			got := out.String()
+			for _, want := range append(tt.want, "prompt:      ") {
+				if !strings.Contains(got, want) {
+					t.Errorf("report missing %q:\n%s", want, got)
+				}
+			}
+			for _, dontWant := range tt.dontWant {
+				if strings.Contains(got, dontWant) {
+					t.Errorf("report has %q:\n%s", dontWant, got)
+				}
+			}
+		})
+	}
+}
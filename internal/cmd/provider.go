@@ -0,0 +1,330 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/yourorg/arc-sdk/ai"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// Providers accepted by --provider.
+const (
+	providerAnthropic = "anthropic"
+	providerOpenAI    = "openai"
+	providerOllama    = "ollama"
+)
+
+// providers lists the valid --provider values.
+var providers = []string{providerAnthropic, providerOpenAI, providerOllama}
+
+// defaultProviderURLs are the API base URLs used without --provider-url.
+var defaultProviderURLs = map[string]string{
+	providerOpenAI: "https://api.openai.com/v1",
+	providerOllama: "http://localhost:11434",
+}
+
+// providerOptions selects and configures the backend messages are
+// generated with.
+type providerOptions struct {
+	// Name is one of providers.
+	Name string
+	// URL is the base URL of the API: for OpenAI-compatible APIs up to and
+	// including the version, e.g. https://api.openai.com/v1. Empty uses the
+	// provider's entry in defaultProviderURLs.
+	URL string
+	// KeyEnv names the environment variable holding the API key. The key
+	// itself is never read from flags or config files, and like URL, the
+	// variable cannot be chosen by the repository config.
+	KeyEnv string
+}
+
+// newGenerator creates the Generator for the selected provider and returns
+// it with the name of the model it uses. model is the --model value; the
+// Anthropic provider falls back to its configured default when it is
+// empty, others require one.
+func newGenerator(cfg *ai.Config, p providerOptions, model string) (Generator, string, error) {
+	if p.Name != providerAnthropic && model == "" {
+		return nil, "", errors.NewCLIError("--provider " + p.Name + " needs a model").
+			WithHint("Pass --model, e.g. --model " + exampleModels[p.Name] + ", or set model in .arc-commit.yaml")
+	}
+	if p.URL == "" {
+		p.URL = defaultProviderURLs[p.Name]
+	}
+	switch p.Name {
+	case providerOpenAI:
+		gen, err := newOpenAIGenerator(p, model)
+		return gen, model, err
+	case providerOllama:
+		gen, err := newOllamaGenerator(p, model)
+		return gen, model, err
+	default:
+		if model != "" {
+			cfg.DefaultModel = model
+		}
+		gen, err := newServiceGenerator(cfg)
+		return gen, cfg.DefaultModel, err
+	}
+}
+
+// exampleModels suggests a model for each provider in error hints.
+var exampleModels = map[string]string{
+	providerOpenAI: "gpt-4o-mini",
+	providerOllama: "llama3.2",
+}
+
+// local reports whether p keeps prompts on this machine: its API is served
+// from a loopback address, as Ollama is by default. The hosted Anthropic
+// service never is.
+func (p providerOptions) local() bool {
+	if p.Name == providerAnthropic {
+		return false
+	}
+	raw := p.URL
+	if raw == "" {
+		raw = defaultProviderURLs[p.Name]
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	host := u.Hostname()
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// parseProviderURL parses the --provider-url value.
+func parseProviderURL(raw string) (*url.URL, error) {
+	base, err := url.Parse(raw)
+	if err != nil || base.Scheme == "" || base.Host == "" {
+		return nil, errors.NewCLIError("invalid --provider-url: " + raw).
+			WithHint("Give the API base URL, e.g. " + defaultProviderURLs[providerOpenAI])
+	}
+	base.Path = strings.TrimRight(base.Path, "/")
+	return base, nil
+}
+
+// validateProvider checks the --provider value.
+func validateProvider(p providerOptions) error {
+	if !slices.Contains(providers, p.Name) {
+		return errors.NewCLIError("invalid --provider value: " + p.Name).
+			WithHint("Use one of: " + strings.Join(providers, ", "))
+	}
+	return nil
+}
+
+// addProviderFlags registers the flags selecting the provider.
+func addProviderFlags(flags *pflag.FlagSet, p *providerOptions) {
+	flags.StringVar(&p.Name, "provider", providerAnthropic, "AI provider: "+strings.Join(providers, ", ")+"; openai covers any OpenAI-compatible API, ollama runs models locally")
+	flags.StringVar(&p.URL, "provider-url", "", "Base URL of the provider's API, e.g. for Azure OpenAI, Together, Groq or a remote Ollama (default: "+defaultProviderURLs[providerOpenAI]+" for openai, "+defaultProviderURLs[providerOllama]+" for ollama)")
+	flags.StringVar(&p.KeyEnv, "provider-api-key-env", "OPENAI_API_KEY", "Environment variable holding the API key for --provider openai")
+}
+
+// openAIGenerator talks to an OpenAI-compatible chat completions API, such
+// as OpenAI, Azure OpenAI, Together or Groq.
+type openAIGenerator struct {
+	endpoint string
+	key      string
+	// azure selects Azure's api-key header instead of a bearer token.
+	azure  bool
+	model  string
+	client *http.Client
+}
+
+// newOpenAIGenerator creates a Generator for the OpenAI-compatible API at
+// p.URL, authenticating with the key in p.KeyEnv.
+func newOpenAIGenerator(p providerOptions, model string) (Generator, error) {
+	base, err := parseProviderURL(p.URL)
+	if err != nil {
+		return nil, err
+	}
+	key := os.Getenv(p.KeyEnv)
+	if key == "" {
+		return nil, errors.NewCLIError("no API key in $" + p.KeyEnv).
+			WithHint("Export the key, or name another variable with --provider-api-key-env")
+	}
+	// Keep the query, which Azure uses for the API version
+	base.Path += "/chat/completions"
+	return &openAIGenerator{
+		endpoint: base.String(),
+		key:      key,
+		azure:    strings.HasSuffix(base.Hostname(), ".openai.azure.com"),
+		model:    model,
+		client:   &http.Client{Timeout: 2 * time.Minute},
+	}, nil
+}
+
+// openAIMessage is one chat message of a completions request or response.
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// openAIRequest is the body of a chat completions request.
+type openAIRequest struct {
+	Model       string          `json:"model"`
+	Messages    []openAIMessage `json:"messages"`
+	MaxTokens   int             `json:"max_tokens,omitempty"`
+	Temperature *float64        `json:"temperature,omitempty"`
+	Stream      bool            `json:"stream,omitempty"`
+}
+
+// openAIResponse is the part of a chat completions response, or of one
+// streamed chunk, that is used.
+type openAIResponse struct {
+	Choices []struct {
+		Message openAIMessage `json:"message"`
+		Delta   openAIMessage `json:"delta"`
+		// FinishReason is "length" when the reply hit max_tokens; in a
+		// stream only the last chunk has one.
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// chatMessages returns the system message of req, the messages of its
+// earlier turns and its user message.
+func chatMessages(req GenerateRequest) []openAIMessage {
+	var messages []openAIMessage
+	if req.System != "" {
+		messages = append(messages, openAIMessage{Role: "system", Content: req.System})
+	}
+	for _, t := range req.History {
+		messages = append(messages,
+			openAIMessage{Role: "user", Content: t.Prompt},
+			openAIMessage{Role: "assistant", Content: t.Reply})
+	}
+	return append(messages, openAIMessage{Role: "user", Content: req.Prompt})
+}
+
+// send posts req as a chat completion and returns the response, which the
+// caller must close. Error statuses are returned as errors.
+func (g *openAIGenerator) send(ctx context.Context, req GenerateRequest, stream bool) (*http.Response, error) {
+	data, err := json.Marshal(openAIRequest{
+		Model:       g.model,
+		Messages:    chatMessages(req),
+		MaxTokens:   req.MaxTokens,
+		Temperature: req.Temperature,
+		Stream:      stream,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, g.endpoint, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if g.azure {
+		httpReq.Header.Set("api-key", g.key)
+	} else {
+		httpReq.Header.Set("Authorization", "Bearer "+g.key)
+	}
+
+	resp, err := g.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		raw, _ := io.ReadAll(resp.Body)
+		var parsed openAIResponse
+		if json.Unmarshal(raw, &parsed) == nil && parsed.Error != nil {
+			return nil, fmt.Errorf("%s: %s", resp.Status, parsed.Error.Message)
+		}
+		return nil, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(raw)))
+	}
+	return resp, nil
+}
+
+// Generate sends req as a chat completion and returns the reply.
+func (g *openAIGenerator) Generate(ctx context.Context, req GenerateRequest) (string, error) {
+	resp, err := g.send(ctx, req, false)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var parsed openAIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return "", fmt.Errorf("invalid response: %w", err)
+	}
+	if len(parsed.Choices) == 0 {
+		return "", fmt.Errorf("response has no choices")
+	}
+	req.reportFinish(parsed.Choices[0].FinishReason)
+	return parsed.Choices[0].Message.Content, nil
+}
+
+// GenerateStream sends req as a streamed chat completion, passing each
+// chunk of the reply to onText as server-sent events arrive.
+func (g *openAIGenerator) GenerateStream(ctx context.Context, req GenerateRequest, onText func(string)) (string, error) {
+	resp, err := g.send(ctx, req, true)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var text strings.Builder
+	done, reason := false, ""
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for !done && scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		data = strings.TrimSpace(data)
+		if !ok || data == "" {
+			continue
+		}
+		if data == "[DONE]" {
+			done = true
+			continue
+		}
+		var chunk openAIResponse
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return text.String(), fmt.Errorf("invalid stream chunk: %w", err)
+		}
+		if chunk.Error != nil {
+			return text.String(), fmt.Errorf("stream failed: %s", chunk.Error.Message)
+		}
+		if len(chunk.Choices) == 0 {
+			continue
+		}
+		if chunk.Choices[0].FinishReason != "" {
+			reason = chunk.Choices[0].FinishReason
+		}
+		if chunk.Choices[0].Delta.Content != "" {
+			text.WriteString(chunk.Choices[0].Delta.Content)
+			onText(chunk.Choices[0].Delta.Content)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return text.String(), err
+	}
+	if !done {
+		return text.String(), errStreamEnded
+	}
+	req.reportFinish(reason)
+	return text.String(), nil
+}
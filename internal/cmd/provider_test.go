@@ -0,0 +1,44 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestChatMessages(t *testing.T) {
+	tests := []struct {
+		name string
+		req  GenerateRequest
+		want []openAIMessage
+	}{
+		{
+			name: "prompt only",
+			req:  GenerateRequest{Prompt: "write it"},
+			want: []openAIMessage{{Role: "user", Content: "write it"}},
+		},
+		{
+			name: "conversation",
+			req: GenerateRequest{
+				System:  "be brief",
+				History: []Turn{{Prompt: "write it", Reply: "feat: add app"}},
+				Prompt:  "shorter",
+			},
+			want: []openAIMessage{
+				{Role: "system", Content: "be brief"},
+				{Role: "user", Content: "write it"},
+				{Role: "assistant", Content: "feat: add app"},
+				{Role: "user", Content: "shorter"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := chatMessages(tt.req); !slices.Equal(got, tt.want) {
+				t.Errorf("chatMessages() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"sync"
+
+	"github.com/spf13/pflag"
+	"github.com/yourorg/arc-commit/internal/config"
+	"github.com/yourorg/arc-commit/internal/redact"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// redactRulesKey is the config key holding extra secret patterns by name,
+// e.g. "redact-rules: {internal-token: 'itk_[a-z0-9]{32}'}".
+const redactRulesKey = "redact-rules"
+
+// redactOptions control which secrets are redacted from prompts.
+type redactOptions struct {
+	// disabled sends prompts as they are.
+	disabled bool
+	// allow holds patterns of values that are never redacted.
+	allow []string
+}
+
+// addRedactFlags registers the redaction flags on a command that sends
+// changes to a model.
+func addRedactFlags(flags *pflag.FlagSet, opts *redactOptions) {
+	flags.BoolVar(&opts.disabled, "no-redact", false, "Send the diff without redacting likely secrets such as keys and tokens")
+	flags.StringSliceVar(&opts.allow, "redact-allow", nil, "Regular expression for values never redacted, e.g. a known test fixture (repeatable)")
+}
+
+// redactor builds the Redactor from the redact-rules in the config files.
+// Repository rules replace global ones of the same name.
+func (s *settings) redactor(allow []string) (*redact.Redactor, error) {
+	rules := map[string]string{}
+	maps.Copy(rules, s.global.Strings(redactRulesKey))
+	maps.Copy(rules, s.repo.Strings(redactRulesKey))
+	r, err := redact.New(rules, allow)
+	if err != nil {
+		return nil, errors.NewCLIError("invalid secret redaction pattern").WithCause(err).
+			WithHint("Check " + redactRulesKey + " in " + config.FileName + " and --redact-allow")
+	}
+	return r, nil
+}
+
+// withRedaction wraps gen so secrets are redacted from every prompt, unless
+// opts disables it.
+func withRedaction(gen Generator, s *settings, opts redactOptions, out io.Writer) (Generator, error) {
+	if opts.disabled {
+		return gen, nil
+	}
+	r, err := s.redactor(opts.allow)
+	if err != nil {
+		return nil, err
+	}
+	return &redactingGenerator{gen: gen, redactor: r, out: out, seen: map[redact.Finding]bool{}}, nil
+}
+
+// redactingGenerator replaces likely secrets in prompts before they leave
+// the machine, warning once about each one found. The staged changes
+// themselves are left alone.
+type redactingGenerator struct {
+	gen      Generator
+	redactor *redact.Redactor
+	out      io.Writer
+
+	mu   sync.Mutex
+	seen map[redact.Finding]bool
+}
+
+// Generate runs req on the wrapped generator with its secrets redacted.
+func (g *redactingGenerator) Generate(ctx context.Context, req GenerateRequest) (string, error) {
+	return g.gen.Generate(ctx, g.redact(req))
+}
+
+// GenerateStream streams req from the wrapped generator with its secrets
+// redacted.
+func (g *redactingGenerator) GenerateStream(ctx context.Context, req GenerateRequest, onText func(string)) (string, error) {
+	streamer, ok := g.gen.(StreamingGenerator)
+	if !ok {
+		return g.Generate(ctx, req)
+	}
+	return streamer.GenerateStream(ctx, g.redact(req), onText)
+}
+
+// redact returns req with its secrets redacted, warning about new ones.
+func (g *redactingGenerator) redact(req GenerateRequest) GenerateRequest {
+	var found, more []redact.Finding
+	req.System, found = g.redactor.Redact(req.System)
+	req.Prompt, more = g.redactor.Redact(req.Prompt)
+	found = append(found, more...)
+	history := make([]Turn, len(req.History))
+	for i, t := range req.History {
+		history[i].Prompt, more = g.redactor.Redact(t.Prompt)
+		found = append(found, more...)
+		history[i].Reply, more = g.redactor.Redact(t.Reply)
+		found = append(found, more...)
+	}
+	req.History = history
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, f := range found {
+		if g.seen[f] {
+			continue
+		}
+		g.seen[f] = true
+		if f.Path == "" {
+			fmt.Fprintf(g.out, "Warning: redacted a likely secret (%s) from the prompt.\n", f.Rule)
+		} else {
+			fmt.Fprintf(g.out, "Warning: redacted a likely secret (%s) in %s from the prompt; it is still part of the commit.\n", f.Rule, f.Path)
+		}
+	}
+	return req
+}
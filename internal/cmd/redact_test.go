@@ -0,0 +1,105 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/arc-commit/internal/config"
+)
+
+// internalToken matches the redact-rules entry of redactSettings.
+const internalToken = "itk_abc123def456"
+
+// redactSettings returns settings whose repository config adds an
+// internal-token rule to the global one; the repository's pattern wins.
+func redactSettings() *settings {
+	return &settings{
+		global: &config.File{Values: map[string]any{redactRulesKey: map[string]any{"internal-token": `itk_[0-9]{12}`}}},
+		repo:   &config.File{Values: map[string]any{redactRulesKey: map[string]any{"internal-token": `itk_[a-z0-9]{12}`}}},
+	}
+}
+
+func TestRedactingGenerator(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       redactOptions
+		wantPrompt string
+		wantOut    string
+	}{
+		{
+			name:       "redacted",
+			wantPrompt: "+++ b/deploy.sh\n+curl -H 'Authorization: [REDACTED internal-token]'\n",
+			wantOut:    "Warning: redacted a likely secret (internal-token) in deploy.sh from the prompt; it is still part of the commit.\n",
+		},
+		{
+			name:       "allowed",
+			opts:       redactOptions{allow: []string{"^itk_abc"}},
+			wantPrompt: "+++ b/deploy.sh\n+curl -H 'Authorization: " + internalToken + "'\n",
+		},
+		{
+			name:       "disabled",
+			opts:       redactOptions{disabled: true},
+			wantPrompt: "+++ b/deploy.sh\n+curl -H 'Authorization: " + internalToken + "'\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeGenerator{replies: []string{"chore: add the deploy script"}}
+			var out bytes.Buffer
+			gen, err := withRedaction(fake, redactSettings(), tt.opts, &out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			prompt := "+++ b/deploy.sh\n+curl -H 'Authorization: " + internalToken + "'\n"
+			for range 2 {
+				if _, err := gen.Generate(context.Background(), GenerateRequest{Prompt: prompt}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := fake.requests[1].Prompt; got != tt.wantPrompt {
+				t.Errorf("prompt sent = %q, want %q", got, tt.wantPrompt)
+			}
+			if out.String() != tt.wantOut {
+				t.Errorf("warnings = %q, want %q (once)", out.String(), tt.wantOut)
+			}
+		})
+	}
+}
+
+func TestRedactingGeneratorHistory(t *testing.T) {
+	fake := &fakeGenerator{replies: []string{"fix: rotate the token"}}
+	var out bytes.Buffer
+	gen, err := withRedaction(fake, redactSettings(), redactOptions{}, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	history := []Turn{{Prompt: "token " + internalToken, Reply: "fix: stop logging " + internalToken}}
+	req := GenerateRequest{System: "Known token: " + internalToken, History: history}
+	if _, err := gen.Generate(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	sent := fake.requests[0]
+	for _, text := range []string{sent.System, sent.History[0].Prompt, sent.History[0].Reply} {
+		if strings.Contains(text, internalToken) {
+			t.Errorf("sent %q unredacted", text)
+		}
+	}
+	if history[0].Prompt != "token "+internalToken {
+		t.Error("redaction changed the caller's history")
+	}
+	if n := strings.Count(out.String(), "Warning:"); n != 1 {
+		t.Errorf("%d warnings for one secret, want 1:\n%s", n, out.String())
+	}
+}
+
+func TestRedactorInvalidRule(t *testing.T) {
+	s := &settings{repo: &config.File{Values: map[string]any{redactRulesKey: map[string]any{"broken": "itk_["}}}}
+	if _, err := s.redactor(nil); err == nil || !strings.Contains(err.Error(), "invalid secret redaction pattern") {
+		t.Errorf("err = %v, want an invalid pattern error", err)
+	}
+}
@@ -0,0 +1,12 @@
+//go:build !unix
+
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+// notifyResize does nothing where terminals do not signal a resize; the
+// size read at the start is kept.
+func notifyResize(onResize func()) (stop func()) {
+	return func() {}
+}
@@ -0,0 +1,34 @@
+//go:build unix
+
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestNotifyResize(t *testing.T) {
+	resized := make(chan struct{}, 1)
+	stop := notifyResize(func() { resized <- struct{}{} })
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGWINCH); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-resized:
+	case <-time.After(5 * time.Second):
+		t.Fatal("onResize was not called after SIGWINCH")
+	}
+
+	stop()
+	syscall.Kill(syscall.Getpid(), syscall.SIGWINCH)
+	select {
+	case <-resized:
+		t.Error("onResize was called after stop")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
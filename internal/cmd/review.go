@@ -0,0 +1,55 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/yourorg/arc-commit/internal/diff"
+	"github.com/yourorg/arc-commit/internal/prompt"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// reviewChanges asks the model for obvious problems in the staged changes,
+// such as leftover debug output, and prints them. When it finds any, the
+// user decides whether to go on to the commit message; with confirm false
+// the observations are only printed.
+func reviewChanges(reader *bufio.Reader, out io.Writer, events *eventWriter, gen Generator, changes *diff.Diff, confirm bool) error {
+	fmt.Fprintln(out, "Reviewing the diff...")
+	system, user := prompt.ReviewDiff(changes.Patch())
+	review, err := gen.Generate(context.Background(), GenerateRequest{System: system, Prompt: user})
+	if err != nil {
+		// The review is advisory; failing it must not block the commit
+		fmt.Fprintf(out, "Warning: review failed: %v\n", err)
+		return nil
+	}
+
+	review = strings.TrimSpace(review)
+	if review == "" || review == prompt.NoReviewIssues {
+		fmt.Fprintln(out, "Review: no obvious issues found.")
+		events.emit(event{Event: eventReviewComplete})
+		return nil
+	}
+	fmt.Fprintln(out, "\nReview:")
+	fmt.Fprintln(out, review)
+	events.emit(event{Event: eventReviewComplete, Message: review})
+	if !confirm {
+		return nil
+	}
+
+	fmt.Fprint(out, "\nContinue to the commit message? [Y/n]: ")
+	events.awaiting(inputConfirm, "y", "n")
+	answer, _ := reader.ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	if answer == "n" || answer == "no" {
+		fmt.Fprintln(out)
+		return errors.NewCLIError("commit cancelled after review").
+			WithHint("Fix the changes, stage them and run arc-commit again")
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/arc-commit/internal/diff"
+	"github.com/yourorg/arc-commit/internal/prompt"
+)
+
+func TestReviewChanges(t *testing.T) {
+	tests := []struct {
+		name       string
+		reply      string
+		err        error
+		confirm    bool
+		input      string
+		wantErr    bool
+		wantOut    string
+		wantPrompt bool
+	}{
+		{
+			name:    "no issues",
+			reply:   prompt.NoReviewIssues,
+			confirm: true,
+			wantOut: "Review: no obvious issues found.",
+		},
+		{
+			name:       "issues, continue",
+			reply:      "- app/app.go: leftover fmt.Println",
+			confirm:    true,
+			input:      "\n",
+			wantOut:    "Review:\n- app/app.go: leftover fmt.Println",
+			wantPrompt: true,
+		},
+		{
+			name:       "issues, stop",
+			reply:      "- app/app.go: leftover fmt.Println",
+			confirm:    true,
+			input:      "n\n",
+			wantErr:    true,
+			wantPrompt: true,
+		},
+		{
+			name:    "issues without confirmation",
+			reply:   "- app/app.go: leftover fmt.Println",
+			wantOut: "- app/app.go: leftover fmt.Println",
+		},
+		{
+			name:    "review failed",
+			err:     errors.New("connection refused"),
+			confirm: true,
+			wantOut: "Warning: review failed: connection refused",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := &fakeGenerator{replies: []string{tt.reply}, err: tt.err}
+			var out strings.Builder
+			err := reviewChanges(bufio.NewReader(strings.NewReader(tt.input)), &out, nil, gen, diff.Parse(testDiff), tt.confirm)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("reviewChanges() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !strings.Contains(out.String(), tt.wantOut) {
+				t.Errorf("output does not contain %q:\n%s", tt.wantOut, out.String())
+			}
+			if got := strings.Contains(out.String(), "Continue to the commit message?"); got != tt.wantPrompt {
+				t.Errorf("asked to continue = %v, want %v", got, tt.wantPrompt)
+			}
+			if !strings.Contains(gen.requests[0].Prompt, "+++ b/app/app.go") {
+				t.Errorf("the review prompt does not carry the diff:\n%s", gen.requests[0].Prompt)
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-commit/internal/config"
+	"github.com/yourorg/arc-commit/internal/diff"
+	commitmsg "github.com/yourorg/arc-commit/internal/message"
+	"github.com/yourorg/arc-commit/internal/prompt"
+	"github.com/yourorg/arc-sdk/ai"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// rewordOptions holds the flag values for reword-all.
+type rewordOptions struct {
+	autoYes   bool
+	dryRun    bool
+	force     bool
+	noVerify  bool
+	redaction redactOptions
+}
+
+// rewording is a commit and the message it will be given.
+type rewording struct {
+	Commit     string
+	OldMessage string
+	NewMessage string
+}
+
+// newRewordAllCmd creates the reword-all subcommand.
+func newRewordAllCmd(aiCfg *ai.Config) *cobra.Command {
+	var (
+		opts     rewordOptions
+		model    string
+		provider providerOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "reword-all <base>",
+		Short: "Regenerate the message of every commit since base",
+		Long: `Regenerate the message of every commit from base (exclusive) to HEAD.
+
+Each commit's message is regenerated from its own diff. A before/after
+summary is shown, and after confirmation history is rewritten with an
+automated interactive rebase. Commit contents are not changed.
+
+Each commit is amended with its new message, so the commit-msg hook checks
+it; --no-verify skips the hooks.
+
+This rewrites history. Commits that already exist on a remote are refused
+unless --force is given, and merge commits are not supported.`,
+		Example: `  # Preview new messages for the current feature branch
+  arc-commit reword-all main --dry-run
+
+  # Reword every commit since main after confirming the summary
+  arc-commit reword-all main`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			settings, err := resolveConfig(cmd)
+			if err != nil {
+				return err
+			}
+			if settings.blocksProvider(provider) {
+				return errors.NewCLIError("reword-all is disabled in sensitive repositories").
+					WithHint("Use a local model, e.g. --provider ollama; sensitive-repo is set in " + config.FileName + " or the global config")
+			}
+
+			if err := validateProvider(provider); err != nil {
+				return err
+			}
+
+			cfg := *aiCfg
+			gen, _, err := newGenerator(&cfg, provider, model)
+			if err != nil {
+				return err
+			}
+			if gen, err = withRedaction(gen, settings, opts.redaction, cmd.ErrOrStderr()); err != nil {
+				return err
+			}
+			return runRewordAll(gen, args[0], opts, cmd.InOrStdin(), cmd.OutOrStdout())
+		},
+	}
+
+	cmd.Flags().BoolVarP(&opts.autoYes, "yes", "y", false, "Rewrite without asking for confirmation")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Show the new messages without rewriting history")
+	cmd.Flags().BoolVar(&opts.force, "force", false, "Allow rewording commits that were already pushed")
+	cmd.Flags().BoolVar(&opts.noVerify, "no-verify", false, "Skip the pre-commit and commit-msg hooks when amending each commit")
+	cmd.Flags().StringVarP(&model, "model", "m", "", "Model to use (default: "+prompt.CommitMessageModel+")")
+	cmd.RegisterFlagCompletionFunc("model", completeModels)
+	addProviderFlags(cmd.Flags(), &provider)
+	addRedactFlags(cmd.Flags(), &opts.redaction)
+
+	return cmd
+}
+
+// runRewordAll regenerates the messages of base..HEAD with gen and
+// rewrites them, reading the confirmation from in and writing all output to
+// out.
+func runRewordAll(gen Generator, base string, opts rewordOptions, in io.Reader, out io.Writer) error {
+	// Rebasing onto a base that is not in HEAD's history would pull in or
+	// drop commits instead of only rewording them
+	if err := exec.Command("git", "merge-base", "--is-ancestor", base, "HEAD").Run(); err != nil {
+		return errors.NewCLIError(base + " is not an ancestor of HEAD").
+			WithHint("Pass a commit on the current branch, e.g. the merge-base: git merge-base " + base + " HEAD")
+	}
+
+	commits, err := gitLines("rev-list", "--reverse", base+"..HEAD")
+	if err != nil {
+		return errors.NewCLIError("failed to list commits since " + base).WithCause(err)
+	}
+	if len(commits) == 0 {
+		return errors.NewCLIError("no commits between " + base + " and HEAD")
+	}
+
+	if merges, _ := gitLines("rev-list", "--merges", base+"..HEAD"); len(merges) > 0 {
+		return errors.NewCLIError("the range contains merge commits").
+			WithHint("reword-all only supports linear history")
+	}
+
+	if !opts.dryRun {
+		if err := exec.Command("git", "diff", "--quiet", "HEAD").Run(); err != nil {
+			return errors.NewCLIError("the working tree has uncommitted changes").
+				WithHint("Commit or stash them before rewording history")
+		}
+
+		unpushed, err := gitLines("rev-list", base+"..HEAD", "--not", "--remotes")
+		if err != nil {
+			return errors.NewCLIError("failed to check for pushed commits").WithCause(err)
+		}
+		if len(unpushed) < len(commits) && !opts.force {
+			return errors.NewCLIError(fmt.Sprintf("%d of %d commits already exist on a remote",
+				len(commits)-len(unpushed), len(commits))).
+				WithHint("Rewriting pushed history affects collaborators; pass --force to do it anyway")
+		}
+	}
+
+	// Generate a new message for every commit from its own diff
+	var rewordings []rewording
+	for i, commit := range commits {
+		fmt.Fprintf(out, "Generating message %d/%d (%s)...\n", i+1, len(commits), commit[:7])
+
+		show, err := exec.Command("git", "show", "--no-color", "--format=", commit).Output()
+		if err != nil {
+			return errors.NewCLIError("failed to read commit " + commit[:7]).WithCause(err)
+		}
+		old, err := exec.Command("git", "log", "-1", "--format=%B", commit).Output()
+		if err != nil {
+			return errors.NewCLIError("failed to read commit " + commit[:7]).WithCause(err)
+		}
+
+		message, err := generateCommitMessage(out, gen, diff.Parse(string(show)), "", prompt.CommitOptions{}, GenerateRequest{})
+		if err != nil {
+			return errors.NewCLIError("failed to generate message for " + commit[:7]).WithCause(err)
+		}
+
+		rewordings = append(rewordings, rewording{
+			Commit:     commit,
+			OldMessage: strings.TrimSpace(string(old)),
+			NewMessage: commitmsg.Normalize(commitmsg.StripArtifacts(message), commitmsg.NormalizeOptions{}),
+		})
+	}
+
+	// Show before/after summary
+	fmt.Fprintln(out, "\n"+strings.Repeat("=", 70))
+	for _, r := range rewordings {
+		fmt.Fprintf(out, "%s\n  before: %s\n  after:  %s\n", r.Commit[:7],
+			commitmsg.Parse(r.OldMessage).Header, commitmsg.Parse(r.NewMessage).Header)
+	}
+	fmt.Fprintln(out, strings.Repeat("=", 70))
+
+	if opts.dryRun {
+		fmt.Fprintln(out, "\n(Dry run - history not rewritten)")
+		return nil
+	}
+
+	if !opts.autoYes {
+		fmt.Fprintf(out, "\nRewrite %d commits? This changes their hashes. [y/N]: ", len(rewordings))
+		answer, _ := bufio.NewReader(in).ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		if answer != "y" && answer != "yes" {
+			fmt.Fprintln(out, "\nReword cancelled.")
+			return nil
+		}
+	}
+
+	if err := rebaseWithMessages(out, base, rewordings, opts.noVerify); err != nil {
+		return errors.NewCLIError("failed to rewrite history").WithCause(err).
+			WithHint("If a rebase is still in progress, run: git rebase --abort")
+	}
+
+	fmt.Fprintf(out, "\nReworded %d commits.\n", len(rewordings))
+	return nil
+}
+
+// rebaseWithMessages rewrites base..HEAD with an interactive rebase whose
+// todo list is generated: each commit is picked and then amended with its
+// new message. The amend runs the commit hooks unless noVerify is set.
+func rebaseWithMessages(out io.Writer, base string, rewordings []rewording, noVerify bool) error {
+	dir, err := os.MkdirTemp("", "arc-commit-reword-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	amend := "git commit --amend --allow-empty --cleanup=strip"
+	if noVerify {
+		amend += " --no-verify"
+	}
+	var todo strings.Builder
+	for i, r := range rewordings {
+		msgFile := filepath.Join(dir, fmt.Sprintf("msg-%d.txt", i))
+		if err := os.WriteFile(msgFile, []byte(r.NewMessage+"\n"), 0o600); err != nil {
+			return fmt.Errorf("failed to write message file: %w", err)
+		}
+		fmt.Fprintf(&todo, "pick %s\n", r.Commit)
+		fmt.Fprintf(&todo, "exec %s -F %s\n", amend, shellQuote(msgFile))
+	}
+
+	todoFile := filepath.Join(dir, "todo")
+	if err := os.WriteFile(todoFile, []byte(todo.String()), 0o600); err != nil {
+		return fmt.Errorf("failed to write rebase todo: %w", err)
+	}
+
+	cmd := exec.Command("git", "rebase", "--interactive", "--no-autosquash", base)
+	cmd.Env = append(os.Environ(),
+		"GIT_SEQUENCE_EDITOR=cp "+shellQuote(todoFile),
+		"GIT_EDITOR=true",
+	)
+	cmd.Stdout = out
+	cmd.Stderr = out
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git rebase failed: %w", err)
+	}
+	return nil
+}
+
+// shellQuote quotes a path for the POSIX shell git uses to run editors and
+// exec lines.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
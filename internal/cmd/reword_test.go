@@ -0,0 +1,77 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRebaseWithMessages(t *testing.T) {
+	tests := []struct {
+		name string
+		// hook is the commit-msg hook; empty installs none.
+		hook     string
+		noVerify bool
+		wantErr  bool
+	}{
+		{name: "no hooks"},
+		{name: "hook accepts", hook: "#!/bin/sh\nexit 0\n"},
+		{name: "hook rejects", hook: "#!/bin/sh\nexit 1\n", wantErr: true},
+		{name: "hook skipped with no-verify", hook: "#!/bin/sh\nexit 1\n", noVerify: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			commitFile(t, "README.md", "app\n", "initial commit")
+			base := git(t, "rev-parse", "HEAD")
+			commitFile(t, "app.go", "package app\n", "wip")
+			first := git(t, "rev-parse", "HEAD")
+			commitFile(t, "app_test.go", "package app\n", "more wip")
+			second := git(t, "rev-parse", "HEAD")
+			if tt.hook != "" {
+				if err := os.WriteFile(filepath.Join(".git", "hooks", "commit-msg"), []byte(tt.hook), 0o755); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			rewordings := []rewording{
+				{Commit: first, NewMessage: "feat: add the app package"},
+				{Commit: second, NewMessage: "test: cover the app package\n\nAn empty test file to start from."},
+			}
+			err := rebaseWithMessages(io.Discard, base, rewordings, tt.noVerify)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("rebaseWithMessages() succeeded, want the hook to stop it")
+				}
+				git(t, "rebase", "--abort")
+				if got := git(t, "log", "-1", "--format=%s"); got != "more wip" {
+					t.Errorf("HEAD is %q after the abort, want the original history", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("rebaseWithMessages() error = %v", err)
+			}
+
+			got := strings.Split(git(t, "log", "--reverse", "--format=%B%x00", base+"..HEAD"), "\x00")
+			var messages []string
+			for _, m := range got {
+				if m = strings.TrimSpace(m); m != "" {
+					messages = append(messages, m)
+				}
+			}
+			if len(messages) != 2 || messages[0] != rewordings[0].NewMessage || messages[1] != rewordings[1].NewMessage {
+				t.Errorf("messages after the rebase = %q", messages)
+			}
+			// Only the messages change
+			if git(t, "rev-parse", "HEAD^{tree}") != git(t, "rev-parse", second+"^{tree}") {
+				t.Error("the rebase changed the tree")
+			}
+		})
+	}
+}
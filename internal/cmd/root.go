@@ -36,8 +36,21 @@ The workflow:
   arc-commit --model claude-sonnet-4-5-20250929`,
 	}
 
+	// Replace cobra's default completion command with our own
+	root.CompletionOptions.DisableDefaultCmd = true
+
 	root.AddCommand(
 		newCommitCmd(aiCfg),
+		newAmendCmd(aiCfg),
+		newSplitCmd(aiCfg),
+		newExplainCmd(aiCfg),
+		newRewordAllCmd(aiCfg),
+		newBranchCmd(aiCfg),
+		newPRCmd(aiCfg),
+		newChangelogCmd(aiCfg),
+		newLintCmd(),
+		newHookCmd(aiCfg),
+		newCompletionCmd(),
 	)
 
 	return root
@@ -0,0 +1,129 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// menuItem is one choice of the arrow-key menu. Key is the letter the
+// classic prompt accepts for it, and what selecting it returns.
+type menuItem struct {
+	Key   string
+	Label string
+}
+
+// arrowMenuUsable reports whether the arrow-key menu can be shown: stdin
+// and stdout are terminals that understand cursor movement. Dumb terminals,
+// pipes and editor integrations get the letter prompt.
+func arrowMenuUsable(in io.Reader, out io.Writer) bool {
+	if in != os.Stdin || out != os.Stdout {
+		return false
+	}
+	if term := os.Getenv("TERM"); term == "" || term == "dumb" {
+		return false
+	}
+	return isTerminal(os.Stdin) && isTerminal(os.Stdout)
+}
+
+// isTerminal reports whether f is a character device such as a terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// rawTerminal puts the terminal on stdin into raw mode with stty, so keys
+// arrive one at a time without echo. It returns a function restoring the
+// previous mode.
+func rawTerminal() (restore func(), err error) {
+	stty := func(args ...string) (string, error) {
+		cmd := exec.Command("stty", args...)
+		cmd.Stdin = os.Stdin
+		output, err := cmd.Output()
+		return strings.TrimSpace(string(output)), err
+	}
+	saved, err := stty("-g")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := stty("raw", "-echo"); err != nil {
+		return nil, err
+	}
+	return func() { stty(saved) }, nil
+}
+
+// selectMenuItem shows items as a menu navigated with the arrow keys (or
+// j/k) and chosen with Enter, and returns the Key of the chosen item.
+// Typing an item's key chooses it directly; Ctrl-C or Ctrl-D choose
+// cancelKey. An error means the terminal could not be set up, and the
+// caller should fall back to the letter prompt.
+func selectMenuItem(reader *bufio.Reader, out io.Writer, items []menuItem, cancelKey string) (string, error) {
+	restore, err := rawTerminal()
+	if err != nil {
+		return "", err
+	}
+	defer restore()
+	return chooseMenuItem(reader, out, items, cancelKey)
+}
+
+// chooseMenuItem runs the menu of selectMenuItem on a terminal already in
+// raw mode, reading keys from reader.
+func chooseMenuItem(reader *bufio.Reader, out io.Writer, items []menuItem, cancelKey string) (string, error) {
+	selected := 0
+	draw := func(redraw bool) {
+		if redraw {
+			fmt.Fprintf(out, "\x1b[%dA", len(items))
+		}
+		for i, item := range items {
+			marker := "  "
+			if i == selected {
+				marker = "> "
+			}
+			// Raw mode turns off newline translation, hence \r\n
+			fmt.Fprintf(out, "\r\x1b[K%s%s\r\n", marker, item.Label)
+		}
+	}
+
+	fmt.Fprint(out, "\r\n")
+	draw(false)
+	for {
+		b, err := reader.ReadByte()
+		if err != nil {
+			return "", err
+		}
+		switch b {
+		case '\r', '\n':
+			return items[selected].Key, nil
+		case 3, 4: // Ctrl-C, Ctrl-D
+			return cancelKey, nil
+		case 'k':
+			selected = (selected + len(items) - 1) % len(items)
+		case 'j':
+			selected = (selected + 1) % len(items)
+		case 0x1b:
+			// Arrow keys send ESC [ A (up) and ESC [ B (down)
+			if next, _ := reader.ReadByte(); next != '[' {
+				continue
+			}
+			switch key, _ := reader.ReadByte(); key {
+			case 'A':
+				selected = (selected + len(items) - 1) % len(items)
+			case 'B':
+				selected = (selected + 1) % len(items)
+			}
+		default:
+			for _, item := range items {
+				if strings.EqualFold(string(b), item.Key) {
+					return item.Key, nil
+				}
+			}
+		}
+		draw(true)
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestChooseMenuItem(t *testing.T) {
+	items := actionMenu(true)
+	tests := []struct {
+		name    string
+		keys    string
+		want    string
+		wantErr bool
+	}{
+		{name: "enter on the first item", keys: "\r", want: "y"},
+		{name: "down arrow", keys: "\x1b[B\x1b[B\r", want: "e"},
+		{name: "up arrow wraps", keys: "\x1b[A\r", want: "?"},
+		{name: "j and k", keys: "jjjk\r", want: "e"},
+		{name: "key chooses directly", keys: "T", want: "t"},
+		{name: "unknown escape ignored", keys: "\x1bOj\r", want: "n"},
+		{name: "ctrl-c cancels", keys: "j\x03", want: "c"},
+		{name: "ctrl-d cancels", keys: "\x04", want: "c"},
+		{name: "input ends", keys: "jj", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			got, err := chooseMenuItem(bufio.NewReader(strings.NewReader(tt.keys)), &out, items, "c")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("chooseMenuItem() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("chooseMenuItem() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChooseMenuItemDraw(t *testing.T) {
+	items := []menuItem{{Key: "y", Label: "Commit"}, {Key: "c", Label: "Cancel"}}
+	var out strings.Builder
+	if _, err := chooseMenuItem(bufio.NewReader(strings.NewReader("j\r")), &out, items, "c"); err != nil {
+		t.Fatal(err)
+	}
+	want := "\r\n" +
+		"\r\x1b[K> Commit\r\n\r\x1b[K  Cancel\r\n" +
+		"\x1b[2A\r\x1b[K  Commit\r\n\r\x1b[K> Cancel\r\n"
+	if out.String() != want {
+		t.Errorf("drew %q, want %q", out.String(), want)
+	}
+}
+
+func TestActionMenu(t *testing.T) {
+	keys := func(items []menuItem) string {
+		var b strings.Builder
+		for _, item := range items {
+			b.WriteString(item.Key)
+		}
+		return b.String()
+	}
+	if got := keys(actionMenu(true)); got != "ynetpc?" {
+		t.Errorf("actionMenu(true) keys = %q", got)
+	}
+	if got := keys(actionMenu(false)); got != "yetpc?" {
+		t.Errorf("actionMenu(false) keys = %q, want no regenerate", got)
+	}
+}
+
+func TestArrowMenuUsable(t *testing.T) {
+	t.Setenv("TERM", "xterm-256color")
+	if arrowMenuUsable(strings.NewReader(""), os.Stdout) {
+		t.Error("the arrow menu was offered for input that is not the terminal")
+	}
+	t.Setenv("TERM", "dumb")
+	if arrowMenuUsable(os.Stdin, os.Stdout) {
+		t.Error("the arrow menu was offered on a dumb terminal")
+	}
+}
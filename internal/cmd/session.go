@@ -0,0 +1,135 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// session is the in-progress state saved between generation and commit so
+// that an interrupted run can be picked up again with --resume.
+type session struct {
+	DiffHash string    `json:"diff_hash"`
+	Message  string    `json:"message"`
+	SavedAt  time.Time `json:"saved_at"`
+}
+
+// hashDiff returns a stable fingerprint of the staged diff.
+func hashDiff(diff string) string {
+	sum := sha256.Sum256([]byte(diff))
+	return hex.EncodeToString(sum[:])
+}
+
+// sessionPath returns the temp file used to persist the session for the
+// current repository. Each repository gets its own file.
+func sessionPath() (string, error) {
+	root, err := repoRoot()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(root))
+	name := hex.EncodeToString(sum[:8]) + ".json"
+	return filepath.Join(os.TempDir(), "arc-commit", name), nil
+}
+
+// loadSession reads the saved session. It returns nil when none exists.
+func loadSession() (*session, error) {
+	path, err := sessionPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session: %w", err)
+	}
+	var s session
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse session: %w", err)
+	}
+	return &s, nil
+}
+
+// saveSession writes the session to its temp file.
+func saveSession(s session) error {
+	path, err := sessionPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create session directory: %w", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode session: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write session: %w", err)
+	}
+	return nil
+}
+
+// clearSession removes the saved session, if any.
+func clearSession() {
+	path, err := sessionPath()
+	if err != nil {
+		return
+	}
+	os.Remove(path)
+}
+
+// persistSession saves the latest generated message. Failing to save is not
+// fatal; the user only loses the ability to resume.
+func persistSession(out io.Writer, diffHash, message string) {
+	err := saveSession(session{
+		DiffHash: diffHash,
+		Message:  message,
+		SavedAt:  time.Now(),
+	})
+	if err != nil {
+		fmt.Fprintf(out, "Warning: could not save session: %v\n", err)
+	}
+}
+
+// resumeSession returns the saved message when it was generated for the same
+// staged diff. A stale session is discarded with a warning. An empty string
+// means there is nothing to resume and a new message must be generated.
+func resumeSession(out io.Writer, diffHash string) string {
+	s, err := loadSession()
+	if err != nil {
+		fmt.Fprintf(out, "Warning: %v\n", err)
+		clearSession()
+		return ""
+	}
+	if s == nil {
+		fmt.Fprintln(out, "No interrupted session found.")
+		return ""
+	}
+	if s.DiffHash != diffHash {
+		fmt.Fprintln(out, "Warning: staged changes differ from the interrupted session; discarding it.")
+		clearSession()
+		return ""
+	}
+	fmt.Fprintf(out, "Resuming session from %s...\n", s.SavedAt.Format(time.Kitchen))
+	return s.Message
+}
+
+// commitAndClearSession creates the commit and drops the saved session once
+// it is no longer needed.
+func commitAndClearSession(out io.Writer, message string, args, env []string) error {
+	if err := createCommit(out, message, args, env); err != nil {
+		return err
+	}
+	clearSession()
+	return nil
+}
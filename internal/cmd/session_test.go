@@ -0,0 +1,113 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResumeSession(t *testing.T) {
+	tests := []struct {
+		name string
+		// saved is written as the session file; empty means none.
+		saved   string
+		want    string
+		wantOut string
+		// wantKept is whether the session file is still there afterwards.
+		wantKept bool
+	}{
+		{
+			name:    "none",
+			wantOut: "No interrupted session found.",
+		},
+		{
+			name:     "same diff",
+			saved:    `{"diff_hash":"` + hashDiff("diff") + `","message":"feat: add login page"}`,
+			want:     "feat: add login page",
+			wantOut:  "Resuming session",
+			wantKept: true,
+		},
+		{
+			name:    "stale",
+			saved:   `{"diff_hash":"` + hashDiff("other diff") + `","message":"feat: add login page"}`,
+			wantOut: "staged changes differ",
+		},
+		{
+			name:    "corrupt",
+			saved:   `{"diff_hash":`,
+			wantOut: "failed to parse session",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			path, err := sessionPath()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.saved != "" {
+				writeFile(t, path, tt.saved)
+			}
+
+			var out bytes.Buffer
+			if got := resumeSession(&out, hashDiff("diff")); got != tt.want {
+				t.Errorf("resumeSession() = %q, want %q", got, tt.want)
+			}
+			if !strings.Contains(out.String(), tt.wantOut) {
+				t.Errorf("output = %q, want it to contain %q", out.String(), tt.wantOut)
+			}
+			if _, err := os.Stat(path); (err == nil) != tt.wantKept {
+				t.Errorf("session file kept = %v, want %v", err == nil, tt.wantKept)
+			}
+		})
+	}
+}
+
+func TestSessionPathIsPerRepository(t *testing.T) {
+	testRepo(t)
+	first, err := sessionPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testRepo(t)
+	second, err := sessionPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first == second {
+		t.Errorf("two repositories share the session file %s", first)
+	}
+	if filepath.Dir(second) != filepath.Join(os.TempDir(), "arc-commit") {
+		t.Errorf("session file %s is not in the arc-commit temp directory", second)
+	}
+}
+
+func TestInteractiveCommitResumes(t *testing.T) {
+	testRepo(t)
+	writeFile(t, "app.go", "package app\n")
+	git(t, "add", "app.go")
+	staged := git(t, "diff", "--staged")
+	persistSession(io.Discard, hashDiff(staged+"\n"), "feat: add the app package")
+
+	gen := &fakeGenerator{replies: []string{"feat: something else"}}
+	opts := testCommitOptions(t)
+	opts.resume = true
+	if err := runInteractiveCommit(gen, opts, strings.NewReader("y\n"), io.Discard); err != nil {
+		t.Fatal(err)
+	}
+	if gen.calls() != 0 {
+		t.Errorf("%d generate requests, want the saved message to be used", gen.calls())
+	}
+	if got := git(t, "log", "-1", "--format=%B"); got != "feat: add the app package" {
+		t.Errorf("committed %q, want the saved message", got)
+	}
+	if s, err := loadSession(); err != nil || s != nil {
+		t.Error("the session was not cleared after the commit")
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// defaultSigningKey is the --gpg-sign value used when no key id is given.
+const defaultSigningKey = "default"
+
+// noSigningKey is the signing key of --no-gpg-sign, which overrides both
+// --gpg-sign and commit.gpgsign.
+const noSigningKey = "none"
+
+// signingFormat returns git's configured signature format ("openpgp",
+// "ssh" or "x509").
+func signingFormat() string {
+	format, err := gitConfig("gpg.format")
+	if err != nil || format == "" {
+		return "openpgp"
+	}
+	return format
+}
+
+// signingRequested reports whether the commit will be signed, either via
+// --gpg-sign or git's commit.gpgsign setting, unless --no-gpg-sign is set.
+func signingRequested(key string) bool {
+	if key != "" {
+		return key != noSigningKey
+	}
+	// --type=bool reads yes, on and 1 as git does
+	enabled, err := exec.Command("git", "config", "--type=bool", "--get", "commit.gpgsign").Output()
+	return err == nil && strings.TrimSpace(string(enabled)) == "true"
+}
+
+// signingKeyFromArgs returns the signing key that the git commit arguments
+// made by signingArgs select, or "" when they leave it to git's config.
+func signingKeyFromArgs(args []string) string {
+	key := ""
+	for _, arg := range args {
+		switch {
+		case arg == "--no-gpg-sign":
+			key = noSigningKey
+		case arg == "--gpg-sign":
+			key = defaultSigningKey
+		case strings.HasPrefix(arg, "--gpg-sign="):
+			key = strings.TrimPrefix(arg, "--gpg-sign=")
+		}
+	}
+	return key
+}
+
+// signingArgs returns the git commit arguments for --gpg-sign and
+// --no-gpg-sign.
+func signingArgs(key string) []string {
+	switch key {
+	case "":
+		return nil
+	case noSigningKey:
+		return []string{"--no-gpg-sign"}
+	case defaultSigningKey:
+		return []string{"--gpg-sign"}
+	default:
+		return []string{"--gpg-sign=" + key}
+	}
+}
+
+// checkSSHSigning verifies that SSH signing is usable before any work is
+// done, so a misconfiguration is reported up front rather than after the
+// message has been approved. Warnings that do not prevent signing are
+// written to out.
+func checkSSHSigning(out io.Writer, key string) error {
+	if key == "" || key == defaultSigningKey {
+		configured, err := gitConfig("user.signingkey")
+		if err != nil {
+			return err
+		}
+		key = configured
+	}
+
+	if key == "" {
+		return errors.NewCLIError("SSH signing is enabled but no signing key is configured").
+			WithHint("Set one with: git config user.signingkey ~/.ssh/id_ed25519.pub")
+	}
+
+	// Literal keys ("key::..." or "ssh-ed25519 ...") need no file
+	if !strings.HasPrefix(key, "key::") && !strings.HasPrefix(key, "ssh-") {
+		path := key
+		if rest, ok := strings.CutPrefix(path, "~/"); ok {
+			if home, err := os.UserHomeDir(); err == nil {
+				path = filepath.Join(home, rest)
+			}
+		}
+		if _, err := os.Stat(path); err != nil {
+			return errors.NewCLIError("SSH signing key not found: " + key).
+				WithHint("Point user.signingkey at an existing public key file")
+		}
+	}
+
+	allowed, _ := gitConfig("gpg.ssh.allowedSignersFile")
+	if allowed == "" {
+		fmt.Fprintln(out, "Warning: gpg.ssh.allowedSignersFile is not set; git will not be able to verify SSH signatures locally.")
+	}
+	return nil
+}
+
+// signingFailureLine matches the lines gpg, gpgsm, ssh-keygen and git's
+// signing code print when signing fails, e.g. "error: gpg failed to sign
+// the data" or "Load key \"~/.ssh/id\": invalid format".
+var signingFailureLine = regexp.MustCompile(`(?i)^(error: |fatal: )?(gpg|gpgsm|ssh-keygen)( failed|:)|failed to sign the data|^load key |couldn't load public key|cannot run gpg|user\.signingkey|gpg\.ssh\.`)
+
+// signingError turns a failed signed commit into an error with a hint
+// specific to the configured signature format, keeping err as its cause.
+// It returns nil when stderr has no signing failure, such as when a hook
+// rejected the commit.
+func signingError(stderr string, err error) error {
+	failed := false
+	for _, line := range strings.Split(stderr, "\n") {
+		if signingFailureLine.MatchString(strings.TrimSpace(line)) {
+			failed = true
+			break
+		}
+	}
+	if !failed {
+		return nil
+	}
+	lower := strings.ToLower(stderr)
+
+	if signingFormat() == "ssh" {
+		hint := "Check that user.signingkey points at your SSH public key and the private key is loaded (ssh-add -l)"
+		if strings.Contains(lower, "allowedsigners") || strings.Contains(lower, "allowed signers") {
+			hint = "Configure allowed signers: git config gpg.ssh.allowedSignersFile ~/.ssh/allowed_signers"
+		}
+		return errors.NewCLIError("failed to sign commit with SSH key").WithCause(err).WithHint(hint)
+	}
+
+	hint := "Check your signing setup with: git config --get-regexp '^(gpg|user\\.signingkey)'"
+	switch {
+	case strings.Contains(lower, "no secret key") || strings.Contains(lower, "secret key not available"):
+		hint = "Point user.signingkey at a key listed by: gpg --list-secret-keys --keyid-format=long"
+	case strings.Contains(lower, "inappropriate ioctl") || strings.Contains(lower, "no pinentry"):
+		hint = "gpg could not ask for your passphrase; run export GPG_TTY=$(tty) and retry, or check gpg-agent"
+	case strings.Contains(lower, "cannot run gpg"):
+		hint = "Install GnuPG or point gpg.program at it"
+	}
+	return errors.NewCLIError("failed to sign commit").WithCause(err).
+		WithHint(hint + "; pass --no-gpg-sign to commit unsigned")
+}
+
+// verifyCommitSignature checks with git verify-commit that HEAD carries a
+// good signature, so a signing step that silently did nothing is noticed.
+// The commit already exists either way; the error only reports the problem.
+func verifyCommitSignature(out io.Writer) error {
+	cmd := exec.Command("git", "verify-commit", "--raw", "HEAD")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err == nil {
+		fmt.Fprintln(out, "Signature on the new commit verified.")
+		return nil
+	}
+
+	if !headIsSigned() {
+		return errors.NewCLIError("the commit was created but is not signed").
+			WithHint("Check your signing setup, then re-sign with: git commit --amend --no-edit -S")
+	}
+	hint := "Check that git can verify your own signatures: git verify-commit HEAD"
+	if signingFormat() == "ssh" {
+		hint = "Add your key to the allowed signers: git config gpg.ssh.allowedSignersFile ~/.ssh/allowed_signers"
+	}
+	return errors.NewCLIError("the commit was created but its signature could not be verified").
+		WithCause(fmt.Errorf("%s", strings.TrimSpace(stderr.String()))).
+		WithHint(hint)
+}
+
+// headIsSigned reports whether HEAD carries a signature at all, verifiable
+// or not. git log's %G? cannot tell: it shows N for an SSH signature when
+// no allowed signers file is configured.
+func headIsSigned() bool {
+	raw, err := exec.Command("git", "cat-file", "commit", "HEAD").Output()
+	if err != nil {
+		return false
+	}
+	header, _, _ := strings.Cut(string(raw), "\n\n")
+	for _, line := range strings.Split(header, "\n") {
+		if strings.HasPrefix(line, "gpgsig ") || strings.HasPrefix(line, "gpgsig-sha256 ") {
+			return true
+		}
+	}
+	return false
+}
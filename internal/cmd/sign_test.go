@@ -0,0 +1,200 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSigningError(t *testing.T) {
+	tests := []struct {
+		name   string
+		stderr string
+		want   bool
+	}{
+		{"gpg", "error: gpg failed to sign the data\nfatal: failed to write commit object\n", true},
+		{"gpg no secret key", "gpg: skipped \"ABCD\": No secret key\ngpg: signing failed: No secret key\n", true},
+		{"missing gpg", "error: cannot run gpg: No such file or directory\n", true},
+		{"gpg without a tty", "gpg: signing failed: Inappropriate ioctl for device\nerror: gpg failed to sign the data\n", true},
+		{"gpg without pinentry", "gpg: signing failed: No pinentry\nerror: gpg failed to sign the data\n", true},
+		{"ssh key", "Load key \"/home/a/.ssh/id\": invalid format\n? ssh-keygen: signing failed\n", true},
+		{"hook mentioning design", "pre-commit: the design doc is out of date\n", false},
+		{"hook mentioning assign", "lint: cannot assign to a constant\n", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			err := signingError(tt.stderr, fmt.Errorf("exit status 128"))
+			if (err != nil) != tt.want {
+				t.Errorf("signingError(%q) = %v, want a signing error: %v", tt.stderr, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSigningRequested(t *testing.T) {
+	tests := []struct {
+		key, config string
+		want        bool
+	}{
+		{"", "", false},
+		{"", "true", true},
+		{"", "yes", true},
+		{"", "on", true},
+		{"", "1", true},
+		{"", "false", false},
+		{noSigningKey, "true", false},
+		{defaultSigningKey, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key+"/"+tt.config, func(t *testing.T) {
+			testRepo(t)
+			if tt.config != "" {
+				git(t, "config", "commit.gpgsign", tt.config)
+			}
+			if got := signingRequested(tt.key); got != tt.want {
+				t.Errorf("signingRequested(%q) with commit.gpgsign=%q = %v, want %v", tt.key, tt.config, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSigningKeyFromArgs(t *testing.T) {
+	for _, key := range []string{"", noSigningKey, defaultSigningKey, "ABCD1234"} {
+		if got := signingKeyFromArgs(signingArgs(key)); got != key {
+			t.Errorf("signingKeyFromArgs(signingArgs(%q)) = %q", key, got)
+		}
+	}
+}
+
+func TestCreateCommitHookFailureIsNotASigningError(t *testing.T) {
+	testRepo(t)
+	writeFile(t, ".git/hooks/pre-commit", "#!/bin/sh\necho 'the design doc must be updated; assign a reviewer' >&2\nexit 1\n")
+	if err := os.Chmod(".git/hooks/pre-commit", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, "app.go", "package app\n")
+	git(t, "add", "app.go")
+
+	err := createCommit(io.Discard, "feat: add the app package", nil, nil)
+	if err == nil {
+		t.Fatal("the commit succeeded despite the failing hook")
+	}
+	if !strings.HasPrefix(err.Error(), "failed to create commit") {
+		t.Errorf("err = %v, want git's failure, not a signing error", err)
+	}
+}
+
+// sshSigningKey generates an SSH key and configures git to sign with it,
+// skipping the test when ssh-keygen is not installed.
+func sshSigningKey(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen is not installed")
+	}
+	key := filepath.Join(t.TempDir(), "id_ed25519")
+	if output, err := exec.Command("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-C", "author@example.com", "-f", key).CombinedOutput(); err != nil {
+		t.Fatalf("ssh-keygen: %v\n%s", err, output)
+	}
+	git(t, "config", "gpg.format", "ssh")
+	git(t, "config", "user.signingkey", key+".pub")
+	return key
+}
+
+func TestVerifyCommitSignature(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(t *testing.T)
+		wantErr string
+	}{
+		{
+			name: "verified",
+			setup: func(t *testing.T) {
+				key := sshSigningKey(t)
+				public, err := os.ReadFile(key + ".pub")
+				if err != nil {
+					t.Fatal(err)
+				}
+				allowed := filepath.Join(t.TempDir(), "allowed_signers")
+				writeFile(t, allowed, "author@example.com "+string(public))
+				git(t, "config", "gpg.ssh.allowedSignersFile", allowed)
+				git(t, "commit", "-q", "-S", "--allow-empty", "-m", "Signed")
+			},
+		},
+		{
+			name: "unknown signer",
+			setup: func(t *testing.T) {
+				sshSigningKey(t)
+				git(t, "commit", "-q", "-S", "--allow-empty", "-m", "Signed")
+			},
+			wantErr: "its signature could not be verified",
+		},
+		{
+			name:    "not signed",
+			setup:   func(t *testing.T) { git(t, "commit", "-q", "--allow-empty", "-m", "Unsigned") },
+			wantErr: "the commit was created but is not signed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			tt.setup(t)
+			var out strings.Builder
+			err := verifyCommitSignature(&out)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("verifyCommitSignature() error = %v", err)
+				}
+				if !strings.Contains(out.String(), "Signature on the new commit verified.") {
+					t.Errorf("unexpected output:\n%s", out.String())
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("verifyCommitSignature() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNoGPGSign(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{name: "commit.gpgsign", wantErr: "failed to sign commit"},
+		{name: "no-gpg-sign", args: []string{"--no-gpg-sign"}},
+		{name: "overrides gpg-sign", args: []string{"--gpg-sign", "--no-gpg-sign"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			git(t, "config", "commit.gpgsign", "true")
+			git(t, "config", "gpg.program", filepath.Join(t.TempDir(), "no-such-gpg"))
+			writeFile(t, "app.go", "package app\n")
+			git(t, "add", "app.go")
+
+			out, err := runCommitCmd(t, "", append([]string{"--no-ai", "--yes"}, tt.args...)...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q\n%s", err, tt.wantErr, out)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("commit failed: %v\n%s", err, out)
+			}
+			if got := git(t, "log", "-1", "--format=%G?"); got != "N" {
+				t.Errorf("signature status = %q, want an unsigned commit", got)
+			}
+		})
+	}
+}
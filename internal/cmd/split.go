@@ -0,0 +1,413 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-commit/internal/config"
+	"github.com/yourorg/arc-commit/internal/diff"
+	commitmsg "github.com/yourorg/arc-commit/internal/message"
+	"github.com/yourorg/arc-commit/internal/prompt"
+	"github.com/yourorg/arc-sdk/ai"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// splitBackupFile is where split keeps the staged changes, under the git
+// directory, until every group is committed or staged again.
+const splitBackupFile = "ARC_COMMIT_SPLIT.patch"
+
+// splitOptions holds the flag values of the split subcommand.
+type splitOptions struct {
+	autoYes bool
+	dryRun  bool
+	// format is splitOutputCommits or splitOutputPatchSeries.
+	format    string
+	outputDir string
+}
+
+// splitUnit is one change the model places in a commit: a hunk, or a whole
+// file when its hunks cannot be staged apart, as for new, deleted, renamed
+// and binary files.
+type splitUnit struct {
+	file *diff.File
+	// hunk is nil for a whole file.
+	hunk *diff.Hunk
+}
+
+// splitGroup is one proposed commit.
+type splitGroup struct {
+	summary string
+	changes *diff.Diff
+}
+
+// newSplitCmd creates the split subcommand.
+func newSplitCmd(aiCfg *ai.Config) *cobra.Command {
+	var (
+		opts      splitOptions
+		model     string
+		provider  providerOptions
+		redaction redactOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "split",
+		Short: "Split the staged changes into several logical commits",
+		Long: `Split the staged changes into several logical commits.
+
+The staged hunks are sent to the AI, which groups them by concern, e.g. a
+fix apart from an unrelated refactor. After the proposed commits are
+confirmed, each group is staged on its own with git apply --cached and
+committed with a message generated for it, which can be accepted, edited
+or skipped. Skipped and unreached groups are staged again at the end, so
+nothing staged is lost.
+
+With --output-format patch-series nothing is committed: each group is
+written with its generated message as a .patch file, as git format-patch
+would, for review before applying them with git am. The staged changes
+are left as they are.
+
+Hunks of new, deleted, renamed and binary files are kept together.`,
+		Example: `  # Propose commits for what is staged, then commit them one by one
+  arc-commit split
+
+  # Only show how the changes would be grouped
+  arc-commit split --dry-run
+
+  # Write the proposed commits to patches/ instead of committing them
+  arc-commit split --output-format patch-series --output-dir patches`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			out := cmd.OutOrStdout()
+
+			settings, err := resolveConfig(cmd)
+			if err != nil {
+				return err
+			}
+			if settings.blocksProvider(provider) {
+				return errors.NewCLIError("split is disabled in sensitive repositories").
+					WithHint("Use a local model, e.g. --provider ollama; sensitive-repo is set in " + config.FileName + " or the global config")
+			}
+			if err := validateProvider(provider); err != nil {
+				return err
+			}
+			if !slices.Contains(splitOutputFormats, opts.format) {
+				return errors.NewCLIError("unknown --output-format: " + opts.format).
+					WithHint("Use one of " + strings.Join(splitOutputFormats, ", "))
+			}
+
+			cfg := *aiCfg
+			gen, _, err := newGenerator(&cfg, provider, model)
+			if err != nil {
+				return err
+			}
+			if gen, err = withRedaction(gen, settings, redaction, out); err != nil {
+				return err
+			}
+			return runSplit(bufio.NewReader(cmd.InOrStdin()), out, gen, opts)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&opts.autoYes, "yes", "y", false, "Commit every proposed group with its first generated message, without asking")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Show the proposed commits without committing anything")
+	cmd.Flags().StringVar(&opts.format, "output-format", splitOutputCommits, "What to do with the groups: "+splitOutputCommits+" commits them, "+splitOutputPatchSeries+" writes them as .patch files")
+	cmd.Flags().StringVarP(&opts.outputDir, "output-dir", "o", ".", "Directory the "+splitOutputPatchSeries+" patches are written to")
+	cmd.RegisterFlagCompletionFunc("output-format", cobra.FixedCompletions(splitOutputFormats, cobra.ShellCompDirectiveNoFileComp))
+	cmd.Flags().StringVarP(&model, "model", "m", "", "Model to use (default: "+prompt.CommitMessageModel+")")
+	cmd.RegisterFlagCompletionFunc("model", completeModels)
+	addProviderFlags(cmd.Flags(), &provider)
+	addRedactFlags(cmd.Flags(), &redaction)
+
+	return cmd
+}
+
+// runSplit groups the staged changes with gen and commits each group after
+// confirmation, or writes the groups as a patch series.
+func runSplit(reader *bufio.Reader, out io.Writer, gen Generator, opts splitOptions) error {
+	if err := exec.Command("git", "rev-parse", "--verify", "--quiet", "HEAD").Run(); err != nil {
+		return errors.NewCLIError("split needs an existing commit to build on").
+			WithHint("Make the first commit with arc-commit, then split later changes")
+	}
+	rawDiff, err := getStagedDiff("--binary", "--no-color")
+	if err != nil {
+		return errors.NewCLIError("failed to get diff").WithCause(err)
+	}
+	if strings.TrimSpace(rawDiff) == "" {
+		return errors.NewCLIError("no staged changes found").
+			WithHint("Stage changes first: git add <files>")
+	}
+	staged := diff.Parse(rawDiff)
+	units := splitUnits(staged)
+
+	fmt.Fprintf(out, "Grouping %d staged change(s) with AI...\n", len(units))
+	system, user := prompt.SplitCommits(formatUnits(units))
+	text, err := gen.Generate(context.Background(), GenerateRequest{System: system, Prompt: user})
+	if err != nil {
+		return errors.NewCLIError("failed to group the changes").WithCause(err)
+	}
+	groups := parseSplit(text, staged, units)
+	patches := opts.format == splitOutputPatchSeries
+	if len(groups) < 2 && !patches {
+		fmt.Fprintln(out, "The staged changes look like one logical commit; commit them with arc-commit.")
+		return nil
+	}
+
+	printSplit(out, groups)
+	if opts.dryRun {
+		return nil
+	}
+	if patches {
+		return writePatchSeries(out, gen, groups, opts.outputDir)
+	}
+	if !opts.autoYes {
+		fmt.Fprintf(out, "Commit these %d groups one by one? [y/N]: ", len(groups))
+		answer, _ := reader.ReadString('\n')
+		if answer = strings.ToLower(strings.TrimSpace(answer)); answer != "y" && answer != "yes" {
+			fmt.Fprintln(out, "Split cancelled; the staged changes are unchanged.")
+			return nil
+		}
+	}
+
+	// Keep the staged changes until every group is committed or restaged
+	backup := gitPath(splitBackupFile)
+	if err := os.WriteFile(backup, []byte(rawDiff), 0o644); err != nil {
+		return errors.NewCLIError("failed to back up the staged changes").WithCause(err)
+	}
+	if err := exec.Command("git", "reset", "--quiet").Run(); err != nil {
+		return errors.NewCLIError("failed to unstage the changes").WithCause(err)
+	}
+
+	var left []splitGroup
+	err = commitGroups(reader, out, gen, groups, opts, &left)
+	if restageErr := restage(left); restageErr != nil {
+		return errors.NewCLIError("failed to stage the remaining changes again").WithCause(restageErr).
+			WithHint("The changes staged before the split are saved in " + backup)
+	}
+	os.Remove(backup)
+	if len(left) > 0 {
+		fmt.Fprintf(out, "%d group(s) not committed are staged again.\n", len(left))
+	}
+	return err
+}
+
+// commitGroups stages and commits each group in turn. The groups skipped
+// or not reached are added to left.
+func commitGroups(reader *bufio.Reader, out io.Writer, gen Generator, groups []splitGroup, opts splitOptions, left *[]splitGroup) error {
+	for i, g := range groups {
+		fmt.Fprintf(out, "\n[%d/%d] %s\n", i+1, len(groups), g.summary)
+		message, err := groupMessage(out, gen, g)
+		if err != nil {
+			*left = append(*left, groups[i:]...)
+			return errors.NewCLIError("failed to generate commit message").WithCause(err)
+		}
+
+		answer := "y"
+		for !opts.autoYes {
+			fmt.Fprintf(out, "\n%s\n\nCommit this group? [y]es / [e]dit / [s]kip / [q]uit: ", message)
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				answer = "q"
+				break
+			}
+			if answer = strings.ToLower(strings.TrimSpace(line)); answer != "" {
+				answer = answer[:1]
+			}
+			if answer == "e" {
+				if message, err = editInEditor(message); err != nil {
+					*left = append(*left, groups[i:]...)
+					return errors.NewCLIError("failed to edit message").WithCause(err)
+				}
+				answer = "y"
+			}
+			if answer == "y" || answer == "s" || answer == "q" {
+				break
+			}
+		}
+		switch answer {
+		case "s":
+			*left = append(*left, g)
+			continue
+		case "q":
+			*left = append(*left, groups[i:]...)
+			return nil
+		}
+
+		if err := applyCached(g.changes); err != nil {
+			*left = append(*left, groups[i:]...)
+			return errors.NewCLIError("failed to stage the group").WithCause(err)
+		}
+		if err := createCommit(out, message, nil, nil); err != nil {
+			exec.Command("git", "reset", "--quiet").Run()
+			*left = append(*left, groups[i:]...)
+			return errors.NewCLIError("failed to create commit").WithCause(err)
+		}
+	}
+	return nil
+}
+
+// groupMessage generates the commit message for one group of a split.
+func groupMessage(out io.Writer, gen Generator, g splitGroup) (string, error) {
+	message, err := generateCommitMessage(out, gen, g.changes, splitFeedback(g.summary), prompt.CommitOptions{}, GenerateRequest{})
+	if err != nil {
+		return "", err
+	}
+	return commitmsg.StripArtifacts(message), nil
+}
+
+// splitUnits lists the changes of d that can be committed apart.
+func splitUnits(d *diff.Diff) []splitUnit {
+	var units []splitUnit
+	for _, f := range d.Files {
+		if len(f.Hunks) <= 1 || f.New || f.Deleted || f.Renamed || f.Binary || hasModeChange(f) {
+			units = append(units, splitUnit{file: f})
+			continue
+		}
+		for _, h := range f.Hunks {
+			units = append(units, splitUnit{file: f, hunk: h})
+		}
+	}
+	return units
+}
+
+// hasModeChange reports whether f changes the file mode, which must be
+// staged only once.
+func hasModeChange(f *diff.File) bool {
+	for _, line := range f.Header {
+		if strings.HasPrefix(line, "old mode ") {
+			return true
+		}
+	}
+	return false
+}
+
+// formatUnits renders the units for the prompt, numbered from 1.
+func formatUnits(units []splitUnit) string {
+	var b strings.Builder
+	for i, u := range units {
+		fmt.Fprintf(&b, "## Change %d\n%s\n", i+1, diff.FileHeading(u.file))
+		if u.hunk != nil {
+			b.WriteString(u.hunk.Patch())
+		} else {
+			for _, h := range u.file.Hunks {
+				b.WriteString(h.Patch())
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// splitLine matches a line of the model's answer: change numbers, a colon
+// and a description.
+var splitLine = regexp.MustCompile(`^[\s*-]*([\d,\s]+):\s*(.+)$`)
+
+// parseSplit turns the model's answer into groups of staged's changes, in
+// the order given. A change listed twice stays in its first group, and
+// changes the model left out form a last group.
+func parseSplit(text string, staged *diff.Diff, units []splitUnit) []splitGroup {
+	assigned := make([]int, len(units))
+	for i := range assigned {
+		assigned[i] = -1
+	}
+	var summaries []string
+	for _, line := range strings.Split(text, "\n") {
+		m := splitLine.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		group, used := len(summaries), false
+		for _, field := range strings.FieldsFunc(m[1], func(r rune) bool { return r == ',' || r == ' ' }) {
+			n, err := strconv.Atoi(field)
+			if err != nil || n < 1 || n > len(units) || assigned[n-1] >= 0 {
+				continue
+			}
+			assigned[n-1], used = group, true
+		}
+		if used {
+			summaries = append(summaries, strings.TrimSpace(m[2]))
+		}
+	}
+	for i := range assigned {
+		if assigned[i] < 0 {
+			assigned[i] = len(summaries)
+			summaries = append(summaries, "Remaining changes")
+			for j := i + 1; j < len(assigned); j++ {
+				if assigned[j] < 0 {
+					assigned[j] = assigned[i]
+				}
+			}
+			break
+		}
+	}
+
+	byFile, byHunk := map[*diff.File]int{}, map[*diff.Hunk]int{}
+	for i, u := range units {
+		if u.hunk == nil {
+			byFile[u.file] = assigned[i]
+		} else {
+			byHunk[u.hunk] = assigned[i]
+		}
+	}
+	groups := make([]splitGroup, len(summaries))
+	for g := range groups {
+		groups[g] = splitGroup{
+			summary: summaries[g],
+			changes: diff.Filter(staged, func(f *diff.File, h *diff.Hunk) bool {
+				if group, ok := byFile[f]; ok {
+					return group == g
+				}
+				return byHunk[h] == g
+			}),
+		}
+	}
+	return groups
+}
+
+// printSplit shows the proposed commits with the files, and hunks, in each.
+func printSplit(out io.Writer, groups []splitGroup) {
+	fmt.Fprintln(out, "\nProposed commits:")
+	for i, g := range groups {
+		fmt.Fprintf(out, "\n%d. %s\n", i+1, g.summary)
+		for _, f := range g.changes.Files {
+			fmt.Fprintln(out, "     "+strings.TrimPrefix(diff.FileHeading(f), "== "))
+		}
+	}
+	fmt.Fprintln(out)
+}
+
+// splitFeedback tells the model which part of a split the diff is.
+func splitFeedback(summary string) string {
+	return "This diff is one of several commits split from the staged changes. It was grouped as: " + summary
+}
+
+// applyCached stages the changes of d.
+func applyCached(d *diff.Diff) error {
+	cmd := exec.Command("git", "apply", "--cached", "--binary", "-")
+	cmd.Stdin = strings.NewReader(d.Patch())
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// restage stages the changes of groups again.
+func restage(groups []splitGroup) error {
+	for _, g := range groups {
+		if err := applyCached(g.changes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
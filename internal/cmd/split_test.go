@@ -0,0 +1,172 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/arc-commit/internal/diff"
+)
+
+// splitFile is 30 numbered lines, so changes at both ends are two hunks.
+var splitFile = func() string {
+	var b strings.Builder
+	for i := range 30 {
+		fmt.Fprintf(&b, "line %d\n", i+1)
+	}
+	return b.String()
+}()
+
+// stageSplitChanges commits splitFile as list.txt, then stages a change at
+// its top, one at its bottom and a new file: three units for split.
+func stageSplitChanges(t *testing.T) {
+	t.Helper()
+	testRepo(t)
+	commitFile(t, "list.txt", splitFile, "docs: add list")
+	writeFile(t, "list.txt", "top\n"+splitFile+"bottom\n")
+	writeFile(t, "bottom.go", "package bottom\n")
+	git(t, "add", "list.txt", "bottom.go")
+}
+
+func TestSplitUnits(t *testing.T) {
+	stageSplitChanges(t)
+	units := splitUnits(diff.Parse(git(t, "diff", "--staged") + "\n"))
+	var got []string
+	for _, u := range units {
+		kind := "file"
+		if u.hunk != nil {
+			kind = "hunk"
+		}
+		got = append(got, u.file.Path()+" "+kind)
+	}
+	if want := []string{"bottom.go file", "list.txt hunk", "list.txt hunk"}; !slices.Equal(got, want) {
+		t.Errorf("units = %q, want %q", got, want)
+	}
+}
+
+func TestParseSplit(t *testing.T) {
+	staged := diff.Parse(testDiff)
+	units := splitUnits(staged)
+	tests := []struct {
+		name string
+		text string
+		// want lists each group's summary and files.
+		want []string
+	}{
+		{
+			name: "every change placed",
+			text: "1, 2: Add the app package\n3: Bump deps\n4: Document the app",
+			want: []string{"Add the app package: app/app.go app/app_test.go", "Bump deps: go.sum", "Document the app: docs/app.md"},
+		},
+		{
+			name: "list markers and chatter",
+			text: "Here is the split:\n- 1,2,4: Add the app package\n* 3: Bump deps",
+			want: []string{"Add the app package: app/app.go app/app_test.go docs/app.md", "Bump deps: go.sum"},
+		},
+		{
+			name: "listed twice or out of range",
+			text: "1, 2: Add the app package\n2, 3, 9: Bump deps\n5: Nothing",
+			want: []string{"Add the app package: app/app.go app/app_test.go", "Bump deps: go.sum", "Remaining changes: docs/app.md"},
+		},
+		{
+			name: "unusable answer",
+			text: "These changes belong together.",
+			want: []string{"Remaining changes: app/app.go app/app_test.go go.sum docs/app.md"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, g := range parseSplit(tt.text, staged, units) {
+				got = append(got, g.summary+": "+strings.Join(filePaths(g.changes), " "))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("groups = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunSplit(t *testing.T) {
+	const grouping = "2: Put top first\n1, 3: Add the bottom package"
+	tests := []struct {
+		name  string
+		input string
+		opts  splitOptions
+		reply string
+		// wantLog lists the new commits, newest first.
+		wantLog    []string
+		wantStaged []string
+	}{
+		{
+			name:    "yes",
+			opts:    splitOptions{autoYes: true},
+			reply:   grouping,
+			wantLog: []string{"feat: add the bottom package", "docs: put top first"},
+		},
+		{
+			name:       "skip a group",
+			input:      "y\ns\ny\n",
+			reply:      grouping,
+			wantLog:    []string{"feat: add the bottom package"},
+			wantStaged: []string{"list.txt"},
+		},
+		{
+			name:       "quit",
+			input:      "y\ny\nq\n",
+			reply:      grouping,
+			wantLog:    []string{"docs: put top first"},
+			wantStaged: []string{"bottom.go", "list.txt"},
+		},
+		{
+			name:       "cancelled",
+			input:      "n\n",
+			reply:      grouping,
+			wantStaged: []string{"bottom.go", "list.txt"},
+		},
+		{
+			name:       "dry run",
+			opts:       splitOptions{dryRun: true},
+			reply:      grouping,
+			wantStaged: []string{"bottom.go", "list.txt"},
+		},
+		{
+			name:       "one group",
+			opts:       splitOptions{autoYes: true},
+			reply:      "1, 2, 3: Update everything",
+			wantStaged: []string{"bottom.go", "list.txt"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stageSplitChanges(t)
+			tt.opts.format = splitOutputCommits
+			gen := &fakeGenerator{replies: []string{tt.reply, "docs: put top first", "feat: add the bottom package"}}
+			if err := runSplit(bufio.NewReader(strings.NewReader(tt.input)), io.Discard, gen, tt.opts); err != nil {
+				t.Fatal(err)
+			}
+
+			log := strings.Split(git(t, "log", "--format=%s"), "\n")
+			if got := log[:len(log)-1]; !slices.Equal(got, tt.wantLog) {
+				t.Errorf("new commits = %q, want %q", got, tt.wantLog)
+			}
+			var staged []string
+			if names := git(t, "diff", "--staged", "--name-only"); names != "" {
+				staged = strings.Split(names, "\n")
+			}
+			if !slices.Equal(staged, tt.wantStaged) {
+				t.Errorf("staged = %q, want %q", staged, tt.wantStaged)
+			}
+			if data, _ := os.ReadFile("list.txt"); string(data) != "top\n"+splitFile+"bottom\n" {
+				t.Errorf("the working tree lost changes:\n%s", data)
+			}
+		})
+	}
+}
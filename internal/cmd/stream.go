@@ -0,0 +1,78 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// spinnerFrames are drawn in turn while waiting for a provider that cannot
+// stream.
+var spinnerFrames = []string{"|", "/", "-", "\\"}
+
+// liveGenerator shows a person at a terminal that generation is under way:
+// the text as it is written when the provider streams, else a spinner.
+// Either way Ctrl-C stops the run without waiting for the reply.
+type liveGenerator struct {
+	gen Generator
+	out io.Writer
+}
+
+// Generate runs req on the wrapped generator, showing its progress on out.
+func (g *liveGenerator) Generate(ctx context.Context, req GenerateRequest) (string, error) {
+	if streamer, ok := g.gen.(StreamingGenerator); ok && canStream(g.gen) {
+		fmt.Fprintln(g.out)
+		text, err := streamer.GenerateStream(ctx, req, func(chunk string) {
+			// Dimmed, as the preview shows the cleaned-up message next
+			fmt.Fprint(g.out, "\x1b[2m"+chunk+"\x1b[0m")
+		})
+		fmt.Fprintln(g.out)
+		return text, err
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		for i := 0; ; i++ {
+			fmt.Fprintf(g.out, "\r%s Waiting for the model...", spinnerFrames[i%len(spinnerFrames)])
+			select {
+			case <-done:
+				fmt.Fprint(g.out, "\r\x1b[K")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	text, err := g.gen.Generate(ctx, req)
+	close(done)
+	<-stopped
+	return text, err
+}
+
+// tokenGenerator passes the text of streamed replies on as token events,
+// for --emit-events front-ends that show the message as it is written.
+type tokenGenerator struct {
+	gen    Generator
+	events *eventWriter
+	// attempt points at the number of the generation under way.
+	attempt *int
+}
+
+// Generate runs req on the wrapped generator, emitting a token event for
+// each chunk when the provider streams.
+func (g *tokenGenerator) Generate(ctx context.Context, req GenerateRequest) (string, error) {
+	streamer, ok := g.gen.(StreamingGenerator)
+	if !ok || !canStream(g.gen) {
+		return g.gen.Generate(ctx, req)
+	}
+	return streamer.GenerateStream(ctx, req, func(chunk string) {
+		g.events.emit(event{Event: eventToken, Attempt: *g.attempt, Text: chunk})
+	})
+}
@@ -0,0 +1,283 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/arc-commit/internal/diff"
+	"github.com/yourorg/arc-commit/internal/prompt"
+)
+
+// stubStreamer streams chunks and then fails with err; Generate returns
+// full, as a provider answering a repeated request would.
+type stubStreamer struct {
+	chunks []string
+	err    error
+	full   string
+	// streams and generates count the calls made.
+	streams, generates int
+}
+
+func (s *stubStreamer) Generate(ctx context.Context, req GenerateRequest) (string, error) {
+	s.generates++
+	return s.full, nil
+}
+
+func (s *stubStreamer) GenerateStream(ctx context.Context, req GenerateRequest, onText func(string)) (string, error) {
+	s.streams++
+	var text strings.Builder
+	for _, chunk := range s.chunks {
+		text.WriteString(chunk)
+		onText(chunk)
+	}
+	return text.String(), s.err
+}
+
+// serve starts a server that writes body and then closes the connection.
+func serve(t *testing.T, body string) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, body)
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestOpenAIStreamEndedEarly(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "complete",
+			body: "data: {\"choices\":[{\"delta\":{\"content\":\"feat: add\"}}]}\n\n" +
+				"data: {\"choices\":[{\"delta\":{\"content\":\" login\"}}]}\n\n" +
+				"data: [DONE]\n\n",
+			want: "feat: add login",
+		},
+		{
+			name:    "no terminator",
+			body:    "data: {\"choices\":[{\"delta\":{\"content\":\"feat: add\"}}]}\n\n",
+			want:    "feat: add",
+			wantErr: true,
+		},
+		{
+			name:    "empty",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := &openAIGenerator{endpoint: serve(t, tt.body), client: http.DefaultClient}
+			text, err := gen.GenerateStream(context.Background(), GenerateRequest{Prompt: "x"}, func(string) {})
+			if text != tt.want {
+				t.Errorf("text = %q, want %q", text, tt.want)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !isNetworkError(err) {
+				t.Errorf("isNetworkError(%v) = false, want the truncation to be retryable", err)
+			}
+		})
+	}
+}
+
+func TestOllamaStreamEndedEarly(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "complete",
+			body: `{"message":{"content":"fix: handle"}}` + "\n" +
+				`{"message":{"content":" nil"},"done":true}` + "\n",
+			want: "fix: handle nil",
+		},
+		{
+			name:    "no done",
+			body:    `{"message":{"content":"fix: handle"}}` + "\n",
+			want:    "fix: handle",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := &ollamaGenerator{endpoint: serve(t, tt.body), client: http.DefaultClient}
+			text, err := gen.GenerateStream(context.Background(), GenerateRequest{Prompt: "x"}, func(string) {})
+			if text != tt.want {
+				t.Errorf("text = %q, want %q", text, tt.want)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, errStreamEnded) {
+				t.Errorf("err = %v, want errStreamEnded", err)
+			}
+		})
+	}
+}
+
+func TestRetryingGeneratorStreamFailsMidway(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		maxRetries    int
+		want          string
+		wantErr       bool
+		wantGenerates int
+	}{
+		{
+			name:          "broken stream is generated again",
+			err:           errStreamEnded,
+			maxRetries:    2,
+			want:          "feat: add login page",
+			wantGenerates: 1,
+		},
+		{
+			name:    "no retries",
+			err:     errStreamEnded,
+			wantErr: true,
+		},
+		{
+			name:       "provider error is returned",
+			err:        fmt.Errorf("stream failed: overloaded"),
+			maxRetries: 2,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubStreamer{chunks: []string{"feat: add", " log"}, err: tt.err, full: "feat: add login page"}
+			gen := &retryingGenerator{gen: stub, maxRetries: tt.maxRetries, budget: newRetryBudget(0), out: io.Discard}
+			var shown strings.Builder
+			text, err := gen.GenerateStream(context.Background(), GenerateRequest{Prompt: "x"}, func(chunk string) {
+				shown.WriteString(chunk)
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && text != tt.want {
+				t.Errorf("text = %q, want %q", text, tt.want)
+			}
+			if stub.streams != 1 {
+				t.Errorf("streamed %d times, want 1 so shown text is not repeated", stub.streams)
+			}
+			if stub.generates != tt.wantGenerates {
+				t.Errorf("generated %d times, want %d", stub.generates, tt.wantGenerates)
+			}
+			if shown.String() != "feat: add log" {
+				t.Errorf("shown %q, want the chunks once", shown.String())
+			}
+		})
+	}
+}
+
+// TestStreamedMessageIsNeverAFragment runs a commit message generation
+// through the live display and retries, as commit does with streaming, on a
+// stub stream that errors midway.
+func TestStreamedMessageIsNeverAFragment(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxRetries int
+		want       string
+		wantErr    bool
+	}{
+		{name: "generated again", maxRetries: 2, want: "feat: add login page"},
+		{name: "retries off", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubStreamer{chunks: []string{"feat: add", " log"}, err: errStreamEnded, full: "feat: add login page"}
+			retrying := &retryingGenerator{gen: stub, maxRetries: tt.maxRetries, budget: newRetryBudget(0), out: io.Discard}
+			gen := &liveGenerator{gen: retrying, out: io.Discard}
+
+			message, err := generateCommitMessage(io.Discard, gen, diff.Parse(testDiff), "", prompt.CommitOptions{}, GenerateRequest{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if message != tt.want {
+				t.Errorf("message = %q, want %q", message, tt.want)
+			}
+		})
+	}
+}
+
+func TestFinishReason(t *testing.T) {
+	tests := []struct {
+		name   string
+		gen    func(endpoint string) Generator
+		stream bool
+		body   string
+		want   string
+	}{
+		{
+			name: "openai length",
+			gen: func(endpoint string) Generator {
+				return &openAIGenerator{endpoint: endpoint, client: http.DefaultClient}
+			},
+			body: `{"choices":[{"message":{"content":"feat: add"},"finish_reason":"length"}]}`,
+			want: finishLength,
+		},
+		{
+			name: "openai stream stop",
+			gen: func(endpoint string) Generator {
+				return &openAIGenerator{endpoint: endpoint, client: http.DefaultClient}
+			},
+			stream: true,
+			body: "data: {\"choices\":[{\"delta\":{\"content\":\"feat: add\"}}]}\n\n" +
+				"data: {\"choices\":[{\"delta\":{},\"finish_reason\":\"stop\"}]}\n\n" +
+				"data: [DONE]\n\n",
+			want: "stop",
+		},
+		{
+			name: "ollama length",
+			gen: func(endpoint string) Generator {
+				return &ollamaGenerator{endpoint: endpoint, client: http.DefaultClient}
+			},
+			body: `{"message":{"content":"feat: add"},"done":true,"done_reason":"length"}`,
+			want: finishLength,
+		},
+		{
+			name: "ollama stream without a reason",
+			gen: func(endpoint string) Generator {
+				return &ollamaGenerator{endpoint: endpoint, client: http.DefaultClient}
+			},
+			stream: true,
+			body:   `{"message":{"content":"feat: add"},"done":true}` + "\n",
+			want:   "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reason string
+			req := GenerateRequest{Prompt: "x", FinishReason: &reason}
+			gen := tt.gen(serve(t, tt.body))
+			var err error
+			if tt.stream {
+				_, err = gen.(StreamingGenerator).GenerateStream(context.Background(), req, func(string) {})
+			} else {
+				_, err = gen.Generate(context.Background(), req)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if reason != tt.want {
+				t.Errorf("finish reason = %q, want %q", reason, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// defaultStyleExcludes are the --style-examples-exclude defaults: commits
+// made to be squashed away, reverts and work in progress, which say little
+// about the project's style.
+var defaultStyleExcludes = []string{`^(fixup|squash|amend)! `, `^Revert "`, `(?i)^wip\b`}
+
+// maxStyleExampleLines caps each example, so a few long messages do not
+// crowd out the rest.
+const maxStyleExampleLines = 8
+
+// styleExamples samples up to n recent commit messages as examples of the
+// project's style, newest first. Merges, duplicates and messages matching
+// one of the exclude patterns are skipped. A repository without commits
+// has no examples.
+func styleExamples(n int, exclude []string) ([]string, error) {
+	patterns := make([]*regexp.Regexp, len(exclude))
+	for i, p := range exclude {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --style-examples-exclude pattern %q: %w", p, err)
+		}
+		patterns[i] = re
+	}
+
+	// Read a few more than needed to make up for the ones filtered out
+	output, err := exec.Command("git", "log", "--no-merges", "-n", strconv.Itoa(n*4), "--format=%B%x00").Output()
+	if err != nil {
+		return nil, nil
+	}
+
+	var examples []string
+	seen := map[string]bool{}
+	for _, message := range strings.Split(string(output), "\x00") {
+		message = strings.TrimSpace(message)
+		if message == "" || seen[message] || matchesAny(patterns, message) {
+			continue
+		}
+		seen[message] = true
+		if lines := strings.Split(message, "\n"); len(lines) > maxStyleExampleLines {
+			message = strings.Join(lines[:maxStyleExampleLines], "\n") + "\n..."
+		}
+		if examples = append(examples, message); len(examples) == n {
+			break
+		}
+	}
+	return examples, nil
+}
+
+// matchesAny reports whether one of patterns matches text.
+func matchesAny(patterns []*regexp.Regexp, text string) bool {
+	for _, re := range patterns {
+		if re.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestStyleExamples(t *testing.T) {
+	long := "feat: add search\n\n" + strings.Repeat("More detail.\n", 10)
+	tests := []struct {
+		name string
+		// messages are committed in order, oldest first.
+		messages []string
+		// merge adds a merge commit on top of the messages.
+		merge   bool
+		n       int
+		exclude []string
+		want    []string
+	}{
+		{name: "no commits", n: 3},
+		{
+			name:     "newest first",
+			messages: []string{"feat: add app", "fix: handle nil", "docs: add readme"},
+			n:        2,
+			want:     []string{"docs: add readme", "fix: handle nil"},
+		},
+		{
+			name:     "default excludes",
+			messages: []string{"feat: add app", "fixup! feat: add app", `Revert "feat: add app"`, "WIP search", "wip: search"},
+			n:        5,
+			exclude:  defaultStyleExcludes,
+			want:     []string{"feat: add app"},
+		},
+		{
+			name:     "custom exclude",
+			messages: []string{"feat: add app", "chore(release): v1.0.0"},
+			n:        5,
+			exclude:  []string{`^chore\(release\)`},
+			want:     []string{"feat: add app"},
+		},
+		{
+			name:     "duplicates",
+			messages: []string{"chore: bump deps", "feat: add app", "chore: bump deps"},
+			n:        5,
+			want:     []string{"chore: bump deps", "feat: add app"},
+		},
+		{
+			name:     "merges",
+			messages: []string{"feat: add app"},
+			merge:    true,
+			n:        5,
+			want:     []string{"fix: handle nil", "feat: add app"},
+		},
+		{
+			name:     "long message cut",
+			messages: []string{long},
+			n:        1,
+			want:     []string{"feat: add search\n\n" + strings.Repeat("More detail.\n", 6) + "..."},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			for i, message := range tt.messages {
+				commitFile(t, "file.txt", strings.Repeat("x", i+1), message)
+			}
+			if tt.merge {
+				git(t, "switch", "-q", "-c", "topic")
+				// Commit later than the messages, so the order is fixed
+				t.Setenv("GIT_COMMITTER_DATE", "@2000000000 +0000")
+				commitFile(t, "topic.txt", "topic\n", "fix: handle nil")
+				git(t, "switch", "-q", "main")
+				git(t, "merge", "-q", "--no-ff", "-m", "Merge branch 'topic'", "topic")
+			}
+
+			got, err := styleExamples(tt.n, tt.exclude)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("styleExamples() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStyleExamplesInvalidExclude(t *testing.T) {
+	testRepo(t)
+	if _, err := styleExamples(3, []string{"("}); err == nil || !strings.Contains(err.Error(), "invalid --style-examples-exclude pattern") {
+		t.Errorf("err = %v, want an invalid pattern error", err)
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/yourorg/arc-commit/internal/diff"
+	"github.com/yourorg/arc-commit/internal/prompt"
+)
+
+// maxSubmoduleLogLines caps how many submodule commits are shown per bump.
+const maxSubmoduleLogLines = 50
+
+// submoduleUpdates describes the submodule pointer changes in the diff,
+// including the commits each bump pulls in when the submodule is checked
+// out locally.
+func submoduleUpdates(changes *diff.Diff) []prompt.SubmoduleUpdate {
+	root, err := repoRoot()
+	if err != nil {
+		return nil
+	}
+
+	var updates []prompt.SubmoduleUpdate
+	for _, f := range changes.Files {
+		oldCommit, newCommit, ok := f.Submodule()
+		if !ok {
+			continue
+		}
+
+		update := prompt.SubmoduleUpdate{
+			Path: f.Path(),
+			Old:  oldCommit,
+			New:  newCommit,
+		}
+		if newCommit != "" {
+			update.Log = submoduleLog(filepath.Join(root, f.Path()), oldCommit, newCommit)
+		}
+		updates = append(updates, update)
+	}
+	return updates
+}
+
+// submoduleLog lists the commits between two submodule commits. It returns
+// an empty string when the submodule worktree is missing or does not have
+// the commits.
+func submoduleLog(dir, oldCommit, newCommit string) string {
+	rangeArg := newCommit
+	if oldCommit != "" {
+		rangeArg = oldCommit + ".." + newCommit
+	}
+
+	cmd := exec.Command("git", "-C", dir, "log", "--oneline", "--no-decorate",
+		"-n", strconv.Itoa(maxSubmoduleLogLines), rangeArg)
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(output))
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/arc-commit/internal/diff"
+)
+
+func TestSubmoduleUpdates(t *testing.T) {
+	// A library with two commits, added to the repository at the first
+	dir := testRepo(t)
+	lib := filepath.Join(t.TempDir(), "lib")
+	git(t, "init", "-q", "-b", "main", lib)
+	git(t, "-C", lib, "commit", "-q", "--allow-empty", "-m", "feat: first release")
+	first := git(t, "-C", lib, "rev-parse", "HEAD")
+	git(t, "-C", lib, "commit", "-q", "--allow-empty", "-m", "fix: handle nil input")
+	second := git(t, "-C", lib, "rev-parse", "HEAD")
+
+	git(t, "-c", "protocol.file.allow=always", "submodule", "add", "-q", lib, "lib")
+	git(t, "-C", "lib", "checkout", "-q", first)
+	git(t, "add", "lib")
+	git(t, "commit", "-q", "-m", "chore: add lib")
+
+	// Bump it to the second commit
+	git(t, "-C", "lib", "checkout", "-q", second)
+	git(t, "add", "lib")
+	updates := submoduleUpdates(diff.Parse(git(t, "diff", "--staged") + "\n"))
+	if len(updates) != 1 {
+		t.Fatalf("found %d submodule updates, want 1", len(updates))
+	}
+	got := updates[0]
+	if got.Path != "lib" || got.Old != first || got.New != second {
+		t.Errorf("update = %s %s -> %s, want lib %s -> %s", got.Path, got.Old, got.New, first, second)
+	}
+	if !strings.HasSuffix(got.Log, "fix: handle nil input") || strings.Contains(got.Log, "first release") {
+		t.Errorf("log = %q, want only the commit the bump pulls in", got.Log)
+	}
+
+	// Without the checkout the included commits are unknown
+	if err := os.RemoveAll(filepath.Join(dir, "lib")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "lib"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	updates = submoduleUpdates(diff.Parse(git(t, "diff", "--staged") + "\n"))
+	if len(updates) != 1 || updates[0].Log != "" {
+		t.Errorf("updates without a checkout = %+v, want one without a log", updates)
+	}
+}
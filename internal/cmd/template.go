@@ -0,0 +1,109 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/yourorg/arc-commit/internal/prompt"
+)
+
+// loadCommitTemplate reads the file configured as git's commit.template with
+// comment lines removed. It returns an empty string when no template is
+// configured or the template has no content besides comments.
+func loadCommitTemplate() (string, error) {
+	path, err := gitConfig("commit.template")
+	if err != nil || path == "" {
+		return "", err
+	}
+	if path, err = repoPath(path); err != nil {
+		return "", fmt.Errorf("failed to expand commit.template: %w", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read commit.template: %w", err)
+	}
+
+	return strings.TrimSpace(stripComments(string(data), commentChar())), nil
+}
+
+// repoPath resolves a configured path: "~/" is the home directory and
+// relative paths are relative to the repository root.
+func repoPath(path string) (string, error) {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, rest), nil
+	}
+	if filepath.IsAbs(path) {
+		return path, nil
+	}
+	root, err := repoRoot()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, path), nil
+}
+
+// recentCommitCount is how many commit subjects custom prompts are given.
+const recentCommitCount = 10
+
+// loadPromptTemplate parses the --prompt-template file and gathers the
+// repository details its templates can use.
+func loadPromptTemplate(path string) (*prompt.CustomPrompt, error) {
+	path, err := repoPath(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := template.New(filepath.Base(path)).Option("missingkey=error").Parse(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	custom := &prompt.CustomPrompt{Template: tmpl, Branch: currentBranch()}
+	// A repository without commits has no history to show
+	custom.RecentCommits, _ = gitLines("log", "-"+strconv.Itoa(recentCommitCount), "--format=%s")
+	if custom.Repo.Root, err = repoRoot(); err != nil {
+		return nil, err
+	}
+	custom.Repo.Name = filepath.Base(custom.Repo.Root)
+	if remote, err := gitLines("remote", "get-url", "origin"); err == nil && len(remote) > 0 {
+		custom.Repo.Remote = remote[0]
+	}
+	return custom, nil
+}
+
+// bodyTemplatesKey is the config key holding per-type body structures for
+// --commit-template-per-type.
+const bodyTemplatesKey = "body-templates"
+
+// bodyTemplates returns the body structure for each commit type: the
+// defaults, overridden by the global and then the repository config. An
+// empty template drops the type.
+func (s *settings) bodyTemplates() map[string]string {
+	templates := maps.Clone(prompt.DefaultBodyTemplates)
+	for _, f := range []map[string]string{s.global.Strings(bodyTemplatesKey), s.repo.Strings(bodyTemplatesKey)} {
+		for t, body := range f {
+			if body = strings.TrimSpace(body); body == "" {
+				delete(templates, t)
+			} else {
+				templates[t] = body
+			}
+		}
+	}
+	return templates
+}
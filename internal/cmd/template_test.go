@@ -0,0 +1,208 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/arc-commit/internal/config"
+	"github.com/yourorg/arc-commit/internal/prompt"
+)
+
+func TestLoadCommitTemplate(t *testing.T) {
+	tests := []struct {
+		name string
+		// path is commit.template; empty leaves it unset.
+		path        string
+		content     string
+		commentChar string
+		want        string
+		wantErr     bool
+	}{
+		{name: "not configured"},
+		{
+			name:    "relative to the repository",
+			path:    ".gitmessage",
+			content: "# Say why\nWhy:\n\nTicket:\n",
+			want:    "Why:\n\nTicket:",
+		},
+		{
+			name:    "home directory",
+			path:    "~/.gitmessage",
+			content: "Why:\n",
+			want:    "Why:",
+		},
+		{
+			name:    "only comments",
+			path:    ".gitmessage",
+			content: "# Say why\n# and name the ticket\n",
+		},
+		{
+			name:        "core.commentChar",
+			path:        ".gitmessage",
+			content:     "; Say why\n# Why:\n",
+			commentChar: ";",
+			want:        "# Why:",
+		},
+		{
+			name:    "missing file",
+			path:    "no-such-file",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := testRepo(t)
+			if tt.path != "" {
+				git(t, "config", "commit.template", tt.path)
+			}
+			if tt.commentChar != "" {
+				git(t, "config", "core.commentChar", tt.commentChar)
+			}
+			if tt.content != "" {
+				path := filepath.Join(dir, tt.path)
+				if tt.path[0] == '~' {
+					home, _ := os.UserHomeDir()
+					path = filepath.Join(home, tt.path[2:])
+				}
+				writeFile(t, path, tt.content)
+			}
+
+			got, err := loadCommitTemplate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loadCommitTemplate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("loadCommitTemplate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBodyTemplates(t *testing.T) {
+	s := &settings{
+		global: &config.File{Values: map[string]any{bodyTemplatesKey: map[string]any{
+			"fix":    "Global fix structure.",
+			"design": "Global design structure.",
+		}}},
+		repo: &config.File{Values: map[string]any{bodyTemplatesKey: map[string]any{
+			"fix":   "Repo fix structure.",
+			"chore": "  ",
+		}}},
+	}
+	got := s.bodyTemplates()
+	tests := []struct {
+		commitType string
+		want       string
+		wantOK     bool
+	}{
+		{commitType: "fix", want: "Repo fix structure.", wantOK: true},
+		{commitType: "design", want: "Global design structure.", wantOK: true},
+		{commitType: "feat", want: prompt.DefaultBodyTemplates["feat"], wantOK: true},
+		{commitType: "chore"},
+	}
+	for _, tt := range tests {
+		if body, ok := got[tt.commitType]; body != tt.want || ok != tt.wantOK {
+			t.Errorf("bodyTemplates()[%q] = %q, %v; want %q, %v", tt.commitType, body, ok, tt.want, tt.wantOK)
+		}
+	}
+	if _, ok := prompt.DefaultBodyTemplates["chore"]; !ok {
+		t.Error("bodyTemplates() changed the defaults")
+	}
+}
+
+func TestLoadPromptTemplate(t *testing.T) {
+	tests := []struct {
+		name string
+		// path is --prompt-template; content is written to it when set.
+		path    string
+		content string
+		commits bool
+		wantErr bool
+	}{
+		{name: "relative to the repository", path: ".arc/prompt.tmpl", content: "Team rules.", commits: true},
+		{name: "no commits yet", path: ".arc/prompt.tmpl", content: "Team rules."},
+		{name: "missing file", path: "no-such-file", wantErr: true},
+		{name: "parse error", path: ".arc/prompt.tmpl", content: "{{.Diff", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := testRepo(t)
+			git(t, "remote", "add", "origin", "git@example.com:team/app.git")
+			if tt.commits {
+				commitFile(t, "README.md", "hello\n", "docs: add readme")
+				commitFile(t, "app.go", "package app\n", "feat: add app")
+			}
+			if tt.content != "" {
+				writeFile(t, filepath.Join(dir, tt.path), tt.content)
+			}
+
+			custom, err := loadPromptTemplate(tt.path)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("loadPromptTemplate() succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			root := git(t, "rev-parse", "--show-toplevel")
+			want := prompt.RepoInfo{Name: filepath.Base(root), Root: root, Remote: "git@example.com:team/app.git"}
+			if custom.Repo != want {
+				t.Errorf("Repo = %+v, want %+v", custom.Repo, want)
+			}
+			if custom.Branch != "main" {
+				t.Errorf("Branch = %q, want main", custom.Branch)
+			}
+			var wantCommits []string
+			if tt.commits {
+				wantCommits = []string{"feat: add app", "docs: add readme"}
+			}
+			if !slices.Equal(custom.RecentCommits, wantCommits) {
+				t.Errorf("RecentCommits = %q, want %q", custom.RecentCommits, wantCommits)
+			}
+		})
+	}
+}
+
+func TestCommitPromptTemplate(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		wantCalls int32
+		wantErr   string
+	}{
+		{name: "valid", content: `{{define "system"}}{{.DefaultSystem}} Name the service.{{end}}`, wantCalls: 1},
+		{name: "unknown field", content: `{{define "user"}}{{.Ticket}}{{end}}`, wantErr: "--prompt-template failed"},
+		{name: "parse error", content: "{{.Diff", wantErr: "failed to load --prompt-template"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			commitFile(t, "README.md", "hello\n", "docs: add readme")
+			writeFile(t, ".arc/prompt.tmpl", tt.content)
+			writeFile(t, "app.go", "package app\n")
+			git(t, "add", "app.go")
+
+			url, calls := fakeOllama(t, "feat: add the app package")
+			out, err := runCommitCmd(t, "", "--provider", "ollama", "--provider-url", url, "--model", "llama3.2",
+				"--prompt-template", ".arc/prompt.tmpl", "--write", "MSG", "--exit-after-generate")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("err = %v, want %q\n%s", err, tt.wantErr, out)
+				}
+			} else if err != nil {
+				t.Fatalf("commit failed: %v\n%s", err, out)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("the provider got %d requests, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
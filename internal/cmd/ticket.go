@@ -0,0 +1,223 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	commitmsg "github.com/yourorg/arc-commit/internal/message"
+	"github.com/yourorg/arc-commit/internal/prompt"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// defaultTicketPattern is the default --ticket-pattern. It finds tracker
+// keys such as "JIRA-1234" anywhere in the branch name, and GitHub issue
+// numbers at the start of a path segment, as in "feat/42-dark-mode".
+const defaultTicketPattern = `([A-Z][A-Z0-9]+-[0-9]+)|(?:^|/)([0-9]+)-`
+
+// defaultTicketFormat is used when --ticket is given without a
+// --ticket-format.
+const defaultTicketFormat = "Refs: {ticket}"
+
+// ticketLookupTimeout bounds the request for a ticket's title.
+const ticketLookupTimeout = 10 * time.Second
+
+// commitTicket works out the ticket a commit is for: --ticket, else the
+// one in the branch name. With --ticket-lookup and a model to tell, its
+// title is fetched; a failed lookup only warns. It returns nil when there
+// is no ticket or nothing to do with it.
+func commitTicket(out io.Writer, opts commitOptions, generated bool) (*prompt.Ticket, error) {
+	if opts.ticketFormat == "" && !opts.ticketLookup {
+		return nil, nil
+	}
+	id := opts.ticket
+	if id == "" {
+		var err error
+		if id, err = ticketFromBranch(currentBranch(), opts.ticketPattern); err != nil {
+			return nil, errors.NewCLIError("failed to find the ticket").WithCause(err)
+		}
+		if id == "" {
+			return nil, nil
+		}
+	}
+
+	ticket := &prompt.Ticket{ID: id, Added: opts.ticketFormat != ""}
+	if opts.ticketLookup && generated {
+		title, err := ticketTitle(id)
+		if err != nil {
+			fmt.Fprintf(out, "Warning: could not fetch the title of %s: %v\n", id, err)
+		} else {
+			ticket.Title = title
+		}
+	}
+	return ticket, nil
+}
+
+// ticketFromBranch finds the ticket in branch with pattern: the first
+// capture group that matched, else the whole match. Bare numbers are
+// GitHub issues and come back as "#42". It returns an empty string when
+// the pattern does not match.
+func ticketFromBranch(branch, pattern string) (string, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", fmt.Errorf("invalid --ticket-pattern: %w", err)
+	}
+	m := re.FindStringSubmatch(branch)
+	if m == nil {
+		return "", nil
+	}
+	ticket := m[0]
+	for _, group := range m[1:] {
+		if group != "" {
+			ticket = group
+			break
+		}
+	}
+	if strings.Trim(ticket, "0123456789") == "" {
+		ticket = "#" + ticket
+	}
+	return ticket, nil
+}
+
+// checkTicketFormat reports whether format can be applied: it must contain
+// {ticket}, and either {subject} or be a "Key: value" trailer.
+func checkTicketFormat(format string) error {
+	if !strings.Contains(format, "{ticket}") {
+		return fmt.Errorf("--ticket-format %q must contain {ticket}", format)
+	}
+	if strings.Contains(format, "{subject}") {
+		return nil
+	}
+	_, err := commitmsg.ParseTrailer(strings.ReplaceAll(format, "{ticket}", "X-1"))
+	return err
+}
+
+// applyTicket adds ticket to message as format says: in the header when
+// the format contains {subject}, else as a trailer. Messages that already
+// reference the ticket are left alone.
+func applyTicket(message, ticket, format string) string {
+	if ticket == "" || len(commitmsg.MissingIssueRefs(message, []string{ticket})) == 0 {
+		return message
+	}
+	text := strings.ReplaceAll(format, "{ticket}", ticket)
+	if strings.Contains(text, "{subject}") {
+		header, rest, _ := strings.Cut(message, "\n")
+		header = strings.ReplaceAll(text, "{subject}", header)
+		if rest == "" {
+			return header
+		}
+		return header + "\n" + rest
+	}
+	t, err := commitmsg.ParseTrailer(text)
+	if err != nil {
+		return message
+	}
+	rest, trailers := commitmsg.SplitTrailers(commitmsg.ToLF(message))
+	return commitmsg.WithTrailers(rest, append(trailers, t))
+}
+
+// ticketTitle fetches the title of ticket from its tracker: GitHub issues
+// with gh, other keys from Jira when $JIRA_BASE_URL is set, else from
+// Linear when $LINEAR_API_KEY is set.
+func ticketTitle(ticket string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), ticketLookupTimeout)
+	defer cancel()
+
+	switch {
+	case strings.HasPrefix(ticket, "#"):
+		output, err := exec.CommandContext(ctx, "gh", "issue", "view", ticket[1:], "--json", "title", "--jq", ".title").Output()
+		if err != nil {
+			return "", fmt.Errorf("gh issue view failed: %w", err)
+		}
+		return strings.TrimSpace(string(output)), nil
+	case os.Getenv("JIRA_BASE_URL") != "":
+		return jiraTitle(ctx, ticket)
+	case os.Getenv("LINEAR_API_KEY") != "":
+		return linearTitle(ctx, ticket)
+	default:
+		return "", fmt.Errorf("no tracker configured for %s; set JIRA_BASE_URL or LINEAR_API_KEY", ticket)
+	}
+}
+
+// jiraTitle reads an issue's summary from the Jira REST API at
+// $JIRA_BASE_URL, authenticating as $JIRA_EMAIL with $JIRA_API_TOKEN.
+func jiraTitle(ctx context.Context, key string) (string, error) {
+	endpoint := strings.TrimRight(os.Getenv("JIRA_BASE_URL"), "/") + "/rest/api/2/issue/" + key + "?fields=summary"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+	if token := os.Getenv("JIRA_API_TOKEN"); token != "" {
+		req.SetBasicAuth(os.Getenv("JIRA_EMAIL"), token)
+	}
+	var parsed struct {
+		Fields struct {
+			Summary string `json:"summary"`
+		} `json:"fields"`
+	}
+	if err := fetchJSON(req, &parsed); err != nil {
+		return "", err
+	}
+	return parsed.Fields.Summary, nil
+}
+
+// linearTitle reads an issue's title from the Linear GraphQL API with the
+// key in $LINEAR_API_KEY.
+func linearTitle(ctx context.Context, key string) (string, error) {
+	query, err := json.Marshal(map[string]any{
+		"query":     `query($id: String!) { issue(id: $id) { title } }`,
+		"variables": map[string]string{"id": key},
+	})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.linear.app/graphql", bytes.NewReader(query))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", os.Getenv("LINEAR_API_KEY"))
+	var parsed struct {
+		Data struct {
+			Issue *struct {
+				Title string `json:"title"`
+			} `json:"issue"`
+		} `json:"data"`
+	}
+	if err := fetchJSON(req, &parsed); err != nil {
+		return "", err
+	}
+	if parsed.Data.Issue == nil {
+		return "", fmt.Errorf("no Linear issue %s", key)
+	}
+	return parsed.Data.Issue.Title, nil
+}
+
+// fetchJSON sends req and decodes its JSON response into v. Error statuses
+// are returned as errors.
+func fetchJSON(req *http.Request, v any) error {
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(raw)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("invalid response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,274 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/yourorg/arc-commit/internal/prompt"
+)
+
+func TestTicketFromBranch(t *testing.T) {
+	tests := []struct {
+		name    string
+		branch  string
+		pattern string
+		want    string
+		wantErr bool
+	}{
+		{name: "tracker key", branch: "feat/JIRA-1234-dark-mode", want: "JIRA-1234"},
+		{name: "key anywhere", branch: "alice/fix-ABC-7", want: "ABC-7"},
+		{name: "issue number", branch: "feat/42-dark-mode", want: "#42"},
+		{name: "number at the start", branch: "42-dark-mode", want: "#42"},
+		{name: "number inside a word", branch: "feat/v2-dark-mode"},
+		{name: "no ticket", branch: "main"},
+		{name: "detached HEAD", branch: ""},
+		{name: "whole match without groups", branch: "team/T123/search", pattern: `T[0-9]+`, want: "T123"},
+		{name: "custom group", branch: "ops-991/search", pattern: `^ops-([0-9]+)/`, want: "#991"},
+		{name: "invalid pattern", branch: "main", pattern: "(", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pattern := tt.pattern
+			if pattern == "" {
+				pattern = defaultTicketPattern
+			}
+			got, err := ticketFromBranch(tt.branch, pattern)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ticketFromBranch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ticketFromBranch(%q) = %q, want %q", tt.branch, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckTicketFormat(t *testing.T) {
+	tests := []struct {
+		format  string
+		wantErr bool
+	}{
+		{format: "Refs: {ticket}"},
+		{format: "Jira: {ticket}"},
+		{format: "[{ticket}] {subject}"},
+		{format: "{subject} ({ticket})"},
+		{format: "Refs: ABC-1", wantErr: true},
+		{format: "see {ticket}", wantErr: true},
+	}
+	for _, tt := range tests {
+		if err := checkTicketFormat(tt.format); (err != nil) != tt.wantErr {
+			t.Errorf("checkTicketFormat(%q) = %v, wantErr %v", tt.format, err, tt.wantErr)
+		}
+	}
+}
+
+func TestApplyTicket(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		ticket  string
+		format  string
+		want    string
+	}{
+		{
+			name:    "trailer",
+			message: "feat: add dark mode\n\nUsers asked for it.",
+			ticket:  "JIRA-1234",
+			format:  "Refs: {ticket}",
+			want:    "feat: add dark mode\n\nUsers asked for it.\n\nRefs: JIRA-1234",
+		},
+		{
+			name:    "after existing trailers",
+			message: "feat: add dark mode\n\nSigned-off-by: Test Author <author@example.com>",
+			ticket:  "#42",
+			format:  "Closes: {ticket}",
+			want:    "feat: add dark mode\n\nSigned-off-by: Test Author <author@example.com>\nCloses: #42",
+		},
+		{
+			name:    "header",
+			message: "feat: add dark mode\n\nUsers asked for it.",
+			ticket:  "JIRA-1234",
+			format:  "[{ticket}] {subject}",
+			want:    "[JIRA-1234] feat: add dark mode\n\nUsers asked for it.",
+		},
+		{
+			name:    "header without body",
+			message: "feat: add dark mode",
+			ticket:  "#42",
+			format:  "{subject} ({ticket})",
+			want:    "feat: add dark mode (#42)",
+		},
+		{
+			name:    "already referenced",
+			message: "feat: add dark mode\n\nFor JIRA-1234.",
+			ticket:  "JIRA-1234",
+			format:  "Refs: {ticket}",
+			want:    "feat: add dark mode\n\nFor JIRA-1234.",
+		},
+		{
+			name:    "no ticket",
+			message: "feat: add dark mode",
+			format:  "Refs: {ticket}",
+			want:    "feat: add dark mode",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := applyTicket(tt.message, tt.ticket, tt.format); got != tt.want {
+				t.Errorf("applyTicket() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeJira serves title as the summary of every issue, or status when it
+// is an error status.
+func fakeJira(t *testing.T, title string, status int) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if status != http.StatusOK {
+			http.Error(w, "issue does not exist", status)
+			return
+		}
+		if !strings.HasSuffix(r.URL.Path, "/rest/api/2/issue/JIRA-1234") {
+			t.Errorf("unexpected request for %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"fields": {"summary": "` + title + `"}}`))
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("JIRA_BASE_URL", server.URL+"/")
+}
+
+func TestCommitTicket(t *testing.T) {
+	tests := []struct {
+		name   string
+		branch string
+		opts   commitOptions
+		// jiraStatus, when set, serves Jira lookups with this status.
+		jiraStatus  int
+		generated   bool
+		want        *prompt.Ticket
+		wantWarning string
+	}{
+		{name: "off", branch: "feat/JIRA-1234-dark-mode"},
+		{
+			name:   "from the branch",
+			branch: "feat/JIRA-1234-dark-mode",
+			opts:   commitOptions{ticketFormat: "Refs: {ticket}"},
+			want:   &prompt.Ticket{ID: "JIRA-1234", Added: true},
+		},
+		{
+			name:   "flag wins",
+			branch: "feat/JIRA-1234-dark-mode",
+			opts:   commitOptions{ticket: "#42", ticketFormat: "Refs: {ticket}"},
+			want:   &prompt.Ticket{ID: "#42", Added: true},
+		},
+		{name: "no ticket in the branch", branch: "main", opts: commitOptions{ticketFormat: "Refs: {ticket}"}},
+		{
+			name:       "lookup",
+			branch:     "feat/JIRA-1234-dark-mode",
+			opts:       commitOptions{ticketLookup: true},
+			jiraStatus: http.StatusOK,
+			generated:  true,
+			want:       &prompt.Ticket{ID: "JIRA-1234", Title: "Dark mode"},
+		},
+		{
+			name:       "no lookup without a model",
+			branch:     "feat/JIRA-1234-dark-mode",
+			opts:       commitOptions{ticketLookup: true},
+			jiraStatus: http.StatusOK,
+			want:       &prompt.Ticket{ID: "JIRA-1234"},
+		},
+		{
+			name:        "failed lookup",
+			branch:      "feat/JIRA-1234-dark-mode",
+			opts:        commitOptions{ticketLookup: true, ticketFormat: "Refs: {ticket}"},
+			jiraStatus:  http.StatusNotFound,
+			generated:   true,
+			want:        &prompt.Ticket{ID: "JIRA-1234", Added: true},
+			wantWarning: "Warning: could not fetch the title of JIRA-1234: 404 Not Found: issue does not exist",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			git(t, "switch", "-q", "-c", tt.branch)
+			if tt.jiraStatus != 0 {
+				fakeJira(t, "Dark mode", tt.jiraStatus)
+			}
+			tt.opts.ticketPattern = defaultTicketPattern
+
+			var out bytes.Buffer
+			got, err := commitTicket(&out, tt.opts, tt.generated)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (got == nil) != (tt.want == nil) || got != nil && *got != *tt.want {
+				t.Errorf("commitTicket() = %+v, want %+v", got, tt.want)
+			}
+			if !strings.Contains(out.String(), tt.wantWarning) || tt.wantWarning == "" && out.Len() > 0 {
+				t.Errorf("output = %q, want %q", out.String(), tt.wantWarning)
+			}
+		})
+	}
+}
+
+func TestTicketTitleWithoutTracker(t *testing.T) {
+	t.Setenv("JIRA_BASE_URL", "")
+	t.Setenv("LINEAR_API_KEY", "")
+	if _, err := ticketTitle("ABC-1"); err == nil || !strings.Contains(err.Error(), "no tracker configured for ABC-1") {
+		t.Errorf("err = %v, want no tracker configured", err)
+	}
+}
+
+func TestCommitWithTicket(t *testing.T) {
+	tests := []struct {
+		name        string
+		branch      string
+		args        []string
+		wantMessage string
+	}{
+		{
+			name:        "format from the branch",
+			branch:      "feat/JIRA-1234-app",
+			args:        []string{"--ticket-format", "[{ticket}] {subject}"},
+			wantMessage: "[JIRA-1234] chore: add app.go",
+		},
+		{
+			name:        "ticket flag adds a trailer",
+			branch:      "main",
+			args:        []string{"--ticket", "#42"},
+			wantMessage: "Refs: #42",
+		},
+		{name: "off by default", branch: "feat/JIRA-1234-app", wantMessage: "chore: add app.go"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			commitFile(t, "README.md", "hello\n", "docs: add readme")
+			if tt.branch != "main" {
+				git(t, "switch", "-q", "-c", tt.branch)
+			}
+			writeFile(t, "app.go", "package app\n")
+			git(t, "add", "app.go")
+
+			out, err := runCommitCmd(t, "", append([]string{"--no-ai", "--yes"}, tt.args...)...)
+			if err != nil {
+				t.Fatalf("commit failed: %v\n%s", err, out)
+			}
+			message := git(t, "log", "-1", "--format=%B")
+			if !strings.Contains(message, tt.wantMessage) {
+				t.Errorf("message = %q, want it to contain %q", message, tt.wantMessage)
+			}
+			if tt.args == nil && strings.Contains(message, "JIRA-1234") {
+				t.Errorf("message = %q, want no ticket", message)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yourorg/arc-commit/internal/classify"
+	commitmsg "github.com/yourorg/arc-commit/internal/message"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// trailerEditorHelp is shown at the top of the trailer editing buffer.
+const trailerEditorHelp = `# Edit the trailers for this commit, one "Key: value" per line.
+# Remove a line to drop a trailer. Lines starting with '#' are ignored.
+#
+# Examples:
+#   Co-authored-by: Jane Doe <jane@example.com>
+#   Refs: #123
+#   Signed-off-by: John Doe <john@example.com>
+`
+
+// editTrailers opens the message's trailers in the editor and returns the
+// message with the edited trailers. Every line must be a valid trailer;
+// otherwise an error is returned and the message is left unchanged.
+func editTrailers(message string) (string, error) {
+	rest, trailers := commitmsg.SplitTrailers(commitmsg.ToLF(message))
+
+	var buf strings.Builder
+	buf.WriteString(trailerEditorHelp)
+	for _, t := range trailers {
+		buf.WriteString(t.String() + "\n")
+	}
+
+	edited, err := editInEditor(buf.String())
+	if err != nil {
+		return "", err
+	}
+
+	var updated []commitmsg.Trailer
+	for _, line := range strings.Split(commitmsg.ToLF(edited), "\n") {
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		t, err := commitmsg.ParseTrailer(line)
+		if err != nil {
+			return "", err
+		}
+		updated = append(updated, t)
+	}
+
+	return commitmsg.WithTrailers(rest, updated), nil
+}
+
+// extraTrailers builds the trailers given with --co-author, --trailer and
+// --signoff.
+func extraTrailers(coAuthors, lines []string, signoff bool) ([]commitmsg.Trailer, error) {
+	var trailers []commitmsg.Trailer
+	for _, identity := range coAuthors {
+		t, err := commitmsg.CoAuthor(identity)
+		if err != nil {
+			return nil, errors.NewCLIError("invalid --co-author").WithCause(err)
+		}
+		trailers = append(trailers, t)
+	}
+	for _, line := range lines {
+		t, err := commitmsg.ParseTrailer(line)
+		if err != nil {
+			return nil, errors.NewCLIError("invalid --trailer").WithCause(err).
+				WithHint("Use \"Key: value\", e.g. --trailer \"Reviewed-by: Jane Doe <jane@example.com>\"")
+		}
+		trailers = append(trailers, t)
+	}
+	if signoff {
+		t, err := signoffTrailer()
+		if err != nil {
+			return nil, err
+		}
+		trailers = append(trailers, t)
+	}
+	return trailers, nil
+}
+
+// signoffTrailer returns the Signed-off-by trailer for the user's git
+// identity, as git commit --signoff writes it.
+func signoffTrailer() (commitmsg.Trailer, error) {
+	name, _ := gitConfig("user.name")
+	email, _ := gitConfig("user.email")
+	if name == "" || email == "" {
+		return commitmsg.Trailer{}, errors.NewCLIError("--signoff needs your git identity").
+			WithHint("Set it with: git config user.name \"Your Name\" && git config user.email you@example.com")
+	}
+	return commitmsg.Trailer{Key: "Signed-off-by", Value: name + " <" + email + ">"}, nil
+}
+
+// hasTrailer reports whether trailers include key, ignoring case as git does.
+func hasTrailer(trailers []commitmsg.Trailer, key string) bool {
+	for _, t := range trailers {
+		if strings.EqualFold(t.Key, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// basedOnTrailer returns a Based-on trailer naming the merge-base of HEAD
+// and base, so feature work can be traced back to where it started.
+func basedOnTrailer(base string) (commitmsg.Trailer, error) {
+	lines, err := gitLines("merge-base", "HEAD", base)
+	if err != nil || len(lines) == 0 {
+		return commitmsg.Trailer{}, fmt.Errorf("no merge-base with %s", base)
+	}
+	short, err := gitLines("rev-parse", "--short", lines[0])
+	if err != nil || len(short) == 0 {
+		return commitmsg.Trailer{}, fmt.Errorf("cannot abbreviate %s", lines[0])
+	}
+	return commitmsg.Trailer{Key: "Based-on", Value: short[0]}, nil
+}
+
+// maxTestPlanFiles caps the test files named in a local Test-plan trailer.
+const maxTestPlanFiles = 3
+
+// testPlanTrailer returns the Test-plan trailer used when the model did not
+// write one: it names the changed test files, or notes there are none.
+func testPlanTrailer(kinds []classify.File) commitmsg.Trailer {
+	var tests []string
+	for _, f := range kinds {
+		if f.Kind == classify.Test {
+			tests = append(tests, f.Path)
+		}
+	}
+	value := "no tests changed"
+	switch {
+	case len(tests) > maxTestPlanFiles:
+		value = fmt.Sprintf("see %s and %d more test files", strings.Join(tests[:maxTestPlanFiles], ", "), len(tests)-maxTestPlanFiles)
+	case len(tests) > 0:
+		value = "see " + strings.Join(tests, ", ")
+	}
+	return commitmsg.Trailer{Key: "Test-plan", Value: value}
+}
+
+// generatedByTrailer records which model generated a message and when, e.g.
+// "Generated-by: claude-haiku-4-5-20251001 on 2025-06-01T12:00:00Z".
+func generatedByTrailer(model string, at time.Time) commitmsg.Trailer {
+	return commitmsg.Trailer{
+		Key:   "Generated-by",
+		Value: model + " on " + at.UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,230 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourorg/arc-commit/internal/classify"
+	commitmsg "github.com/yourorg/arc-commit/internal/message"
+)
+
+func TestHasTrailer(t *testing.T) {
+	trailers := []commitmsg.Trailer{{Key: "Refs", Value: "#1"}, {Key: "Signed-off-by", Value: "Jane Doe <jane@example.com>"}}
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{"Refs", true},
+		{"signed-off-by", true},
+		{"Based-on", false},
+	}
+	for _, tt := range tests {
+		if got := hasTrailer(trailers, tt.key); got != tt.want {
+			t.Errorf("hasTrailer(%q) = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestBasedOnTrailer(t *testing.T) {
+	testRepo(t)
+	commitFile(t, "app.go", "package app\n", "feat: add app")
+	base := git(t, "rev-parse", "--short", "HEAD")
+	git(t, "checkout", "-q", "-b", "topic")
+	commitFile(t, "app.go", "package app // topic\n", "feat: topic change")
+
+	got, err := basedOnTrailer("main")
+	if err != nil {
+		t.Fatalf("basedOnTrailer() error = %v", err)
+	}
+	if want := (commitmsg.Trailer{Key: "Based-on", Value: base}); got != want {
+		t.Errorf("basedOnTrailer() = %v, want %v", got, want)
+	}
+
+	if _, err := basedOnTrailer("no-such-branch"); err == nil {
+		t.Error("basedOnTrailer() found a merge-base with a missing branch")
+	}
+}
+
+func TestDraftGeneratorBasedOnFooter(t *testing.T) {
+	testRepo(t)
+	commitFile(t, "app.go", "package app\n", "feat: add app")
+	base := git(t, "rev-parse", "--short", "HEAD")
+
+	tests := []struct {
+		name  string
+		reply string
+		base  string
+		want  string
+	}{
+		{
+			name:  "added",
+			reply: "feat: add login page",
+			base:  "main",
+			want:  "feat: add login page\n\nBased-on: " + base,
+		},
+		{
+			name:  "already there",
+			reply: "feat: add login page\n\nBased-on: " + base,
+			base:  "main",
+			want:  "feat: add login page\n\nBased-on: " + base,
+		},
+		{
+			name:  "no merge-base",
+			reply: "feat: add login page",
+			base:  "no-such-branch",
+			want:  "feat: add login page",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := testCommitOptions(t)
+			opts.basedOnFooter, opts.footerBase = true, tt.base
+			message, err := testDraftGenerator(t, &fakeGenerator{replies: []string{tt.reply}}, opts).generate("")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if message != tt.want {
+				t.Errorf("message = %q, want %q", message, tt.want)
+			}
+		})
+	}
+}
+
+func TestGeneratedByTrailer(t *testing.T) {
+	at := time.Date(2025, 3, 1, 13, 30, 0, 0, time.FixedZone("CET", 3600))
+	want := commitmsg.Trailer{Key: "Generated-by", Value: "llama3.2 on 2025-03-01T12:30:00Z"}
+	if got := generatedByTrailer("llama3.2", at); got != want {
+		t.Errorf("generatedByTrailer() = %v, want %v", got, want)
+	}
+}
+
+func TestAssembleMessageRunMetadata(t *testing.T) {
+	generatedBy := regexp.MustCompile(`\n\nGenerated-by: llama3\.2 on \d{4}-\d\d-\d\dT\d\d:\d\d:\d\dZ$`)
+	tests := []struct {
+		name        string
+		runMetadata bool
+		generated   bool
+		want        bool
+	}{
+		{name: "generated", runMetadata: true, generated: true, want: true},
+		{name: "written without AI", runMetadata: true},
+		{name: "not asked for", generated: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := testCommitOptions(t)
+			opts.runMetadata, opts.model = tt.runMetadata, "llama3.2"
+			got := assembleMessage("feat: add login page", opts, tt.generated)
+			if generatedBy.MatchString(got) != tt.want {
+				t.Errorf("assembleMessage() = %q, want a Generated-by trailer: %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTestPlanTrailer(t *testing.T) {
+	test := func(path string) classify.File { return classify.File{Path: path, Kind: classify.Test} }
+	tests := []struct {
+		name  string
+		kinds []classify.File
+		want  string
+	}{
+		{name: "no files", want: "no tests changed"},
+		{name: "no tests", kinds: []classify.File{{Path: "app.go", Kind: classify.Source}}, want: "no tests changed"},
+		{
+			name:  "tests named",
+			kinds: []classify.File{{Path: "app.go", Kind: classify.Source}, test("app_test.go")},
+			want:  "see app_test.go",
+		},
+		{
+			name:  "capped",
+			kinds: []classify.File{test("a_test.go"), test("b_test.go"), test("c_test.go"), test("d_test.go"), test("e_test.go")},
+			want:  "see a_test.go, b_test.go, c_test.go and 2 more test files",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := testPlanTrailer(tt.kinds)
+			if got.Key != "Test-plan" || got.Value != tt.want {
+				t.Errorf("testPlanTrailer() = %s: %s, want Test-plan: %s", got.Key, got.Value, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtraTrailers(t *testing.T) {
+	tests := []struct {
+		name      string
+		coAuthors []string
+		lines     []string
+		signoff   bool
+		// identity sets user.name and user.email.
+		identity bool
+		want     []commitmsg.Trailer
+		wantErr  string
+	}{
+		{name: "none"},
+		{
+			name:      "in flag order",
+			coAuthors: []string{"Jane Doe <jane@example.com>"},
+			lines:     []string{"Reviewed-by: John Roe <john@example.com>"},
+			signoff:   true,
+			identity:  true,
+			want: []commitmsg.Trailer{
+				{Key: "Co-authored-by", Value: "Jane Doe <jane@example.com>"},
+				{Key: "Reviewed-by", Value: "John Roe <john@example.com>"},
+				{Key: "Signed-off-by", Value: "Git User <git@example.com>"},
+			},
+		},
+		{name: "invalid co-author", coAuthors: []string{"Jane Doe"}, wantErr: "invalid --co-author"},
+		{name: "invalid trailer", lines: []string{"reviewed by John"}, wantErr: "invalid --trailer"},
+		{name: "signoff without identity", signoff: true, wantErr: "--signoff needs your git identity"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			if tt.identity {
+				git(t, "config", "user.name", "Git User")
+				git(t, "config", "user.email", "git@example.com")
+			}
+			got, err := extraTrailers(tt.coAuthors, tt.lines, tt.signoff)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("extraTrailers() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommitExtraTrailers(t *testing.T) {
+	testRepo(t)
+	git(t, "config", "user.name", "Git User")
+	git(t, "config", "user.email", "git@example.com")
+	writeFile(t, "app.go", "package app\n")
+	git(t, "add", "app.go")
+
+	out, err := runCommitCmd(t, "", "--no-ai", "--yes", "--co-author", "Jane Doe <jane@example.com>",
+		"--trailer", "Reviewed-by: John Roe <john@example.com>", "-s")
+	if err != nil {
+		t.Fatalf("commit failed: %v\n%s", err, out)
+	}
+	got := git(t, "log", "-1", "--format=%(trailers:only,unfold)")
+	want := "Co-authored-by: Jane Doe <jane@example.com>\nReviewed-by: John Roe <john@example.com>\nSigned-off-by: Git User <git@example.com>"
+	if got != want {
+		t.Errorf("trailers = %q, want %q", got, want)
+	}
+}
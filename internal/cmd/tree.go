@@ -0,0 +1,167 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/yourorg/arc-commit/internal/diff"
+)
+
+// treeNode is a file or directory in the listing built by fileTree.
+type treeNode struct {
+	children map[string]*treeNode
+	// hidden counts files below the depth limit, shown as one line.
+	hidden int
+	// status is the kind of change for changed files, e.g. "added".
+	status string
+}
+
+func (n *treeNode) child(name string) *treeNode {
+	if n.children == nil {
+		n.children = make(map[string]*treeNode)
+	}
+	c, ok := n.children[name]
+	if !ok {
+		c = &treeNode{}
+		n.children[name] = c
+	}
+	return c
+}
+
+// fileTree renders the staged contents of the directories holding changed
+// files as an indented tree, so the model can see where new files sit in
+// the project. Each directory is listed maxDepth levels deep, deeper
+// directories are collapsed to a file count, and at most maxEntries lines
+// are returned. It returns an empty string when the listing is unavailable.
+func fileTree(changes *diff.Diff, maxDepth, maxEntries int) string {
+	root, err := repoRoot()
+	if err != nil || len(changes.Files) == 0 {
+		return ""
+	}
+
+	changed := make(map[string]string)
+	dirSet := make(map[string]bool)
+	for _, f := range changes.Files {
+		changed[f.Path()] = f.Status()
+		dirSet[path.Dir(f.Path())] = true
+	}
+	dirs := make([]string, 0, len(dirSet))
+	for dir := range dirSet {
+		dirs = append(dirs, dir)
+	}
+	// Deepest first, so each file is placed relative to the closest
+	// changed directory
+	sort.Slice(dirs, func(i, j int) bool { return len(dirs[i]) > len(dirs[j]) })
+
+	// The index holds exactly what will be committed
+	cmd := exec.Command("git", append([]string{"ls-files", "-z", "--"}, dirs...)...)
+	cmd.Dir = root
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	files := strings.Split(strings.TrimRight(string(output), "\x00"), "\x00")
+	for p := range changed {
+		// Deleted files are no longer in the index
+		if changed[p] == "deleted" {
+			files = append(files, p)
+		}
+	}
+
+	top := &treeNode{}
+	for _, p := range files {
+		if p == "" {
+			continue
+		}
+		base := closestDir(p, dirs)
+		rel := strings.Split(strings.TrimPrefix(p, base+"/"), "/")
+		if base == "." {
+			rel = strings.Split(p, "/")
+		}
+
+		node := top
+		if base != "." {
+			for _, part := range strings.Split(base, "/") {
+				node = node.child(part + "/")
+			}
+		}
+		for i, part := range rel {
+			if i == maxDepth {
+				node.hidden++
+				break
+			}
+			if i < len(rel)-1 {
+				part += "/"
+			}
+			node = node.child(part)
+		}
+		if len(rel) <= maxDepth {
+			node.status = changed[p]
+		}
+	}
+
+	var lines []string
+	renderTree(top, 0, &lines)
+	if len(lines) > maxEntries {
+		more := len(lines) - maxEntries
+		lines = append(lines[:maxEntries], fmt.Sprintf("... (%d more entries)", more))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// closestDir returns the deepest of dirs, which are sorted deepest first,
+// that contains p.
+func closestDir(p string, dirs []string) string {
+	for _, dir := range dirs {
+		if dir != "." && strings.HasPrefix(p, dir+"/") {
+			return dir
+		}
+	}
+	return "."
+}
+
+// renderTree appends a line per entry below n, directories first.
+func renderTree(n *treeNode, depth int, lines *[]string) {
+	names := make([]string, 0, len(n.children))
+	for name := range n.children {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		di, dj := strings.HasSuffix(names[i], "/"), strings.HasSuffix(names[j], "/")
+		if di != dj {
+			return di
+		}
+		return names[i] < names[j]
+	})
+
+	indent := strings.Repeat("  ", depth)
+	for _, name := range names {
+		c := n.children[name]
+		line := indent + name
+		switch {
+		case c.status != "":
+			line += " (" + c.status + ")"
+		case c.hidden > 0 && len(c.children) == 0:
+			line += " (" + fileCount(c.hidden) + ")"
+		}
+		*lines = append(*lines, line)
+		renderTree(c, depth+1, lines)
+		if c.hidden > 0 && len(c.children) > 0 {
+			*lines = append(*lines, indent+"  ... ("+fileCount(c.hidden)+" more)")
+		}
+	}
+}
+
+// fileCount renders n as "1 file" or "n files".
+func fileCount(n int) string {
+	if n == 1 {
+		return "1 file"
+	}
+	return fmt.Sprintf("%d files", n)
+}
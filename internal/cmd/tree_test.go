@@ -0,0 +1,71 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/yourorg/arc-commit/internal/diff"
+)
+
+func TestFileTree(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxDepth   int
+		maxEntries int
+		want       string
+	}{
+		{
+			name:       "two levels",
+			maxDepth:   2,
+			maxEntries: 80,
+			want: `app/
+  util/
+    deep/ (1 file)
+    helper.go
+  app.go
+  new.go (added)
+  old.go (deleted)`,
+		},
+		{
+			name:       "one level",
+			maxDepth:   1,
+			maxEntries: 80,
+			want: `app/
+  util/ (2 files)
+  app.go
+  new.go (added)
+  old.go (deleted)`,
+		},
+		{
+			name:       "entry limit",
+			maxDepth:   2,
+			maxEntries: 3,
+			want: `app/
+  util/
+    deep/ (1 file)
+... (4 more entries)`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			writeFile(t, "README.md", "# App\n")
+			writeFile(t, "app/app.go", "package app\n")
+			writeFile(t, "app/old.go", "package app\n\nfunc Old() int { return 1 }\n")
+			writeFile(t, "app/util/helper.go", "package util\n")
+			writeFile(t, "app/util/deep/x.go", "package deep\n")
+			git(t, "add", ".")
+			git(t, "commit", "-q", "-m", "feat: add app")
+			writeFile(t, "app/new.go", "package app\n\nfunc New() {}\n")
+			git(t, "add", "app/new.go")
+			git(t, "rm", "-q", "app/old.go")
+
+			changes := diff.Parse(git(t, "diff", "--staged") + "\n")
+			if got := fileTree(changes, tt.maxDepth, tt.maxEntries); got != tt.want {
+				t.Errorf("fileTree() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,441 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	commitmsg "github.com/yourorg/arc-commit/internal/message"
+)
+
+// ANSI sequences used by the full-screen review.
+const (
+	ansiReset   = "\x1b[0m"
+	ansiBold    = "\x1b[1m"
+	ansiDim     = "\x1b[2m"
+	ansiInverse = "\x1b[7m"
+	ansiRed     = "\x1b[31m"
+	ansiGreen   = "\x1b[32m"
+	ansiYellow  = "\x1b[33m"
+	ansiCyan    = "\x1b[36m"
+
+	// The alternate screen keeps the review out of the scrollback
+	enterFullScreen = "\x1b[?1049h\x1b[?25l"
+	leaveFullScreen = "\x1b[?25h\x1b[?1049l"
+)
+
+// tuiView is what the full-screen review shows and allows.
+type tuiView struct {
+	// diff is the staged change, shown in the scrollable upper pane.
+	diff string
+	// message is the suggestion, shown highlighted in the lower pane with
+	// notes, the validation issues and any type mismatch.
+	message string
+	notes   string
+
+	// noRegenerate says why [n] is unavailable; empty when it is.
+	noRegenerate string
+	// noAccept says why [y] is refused, e.g. under --strict; empty when the
+	// message can be committed.
+	noAccept string
+	// inlineFeedback makes [n] ask for feedback on the status line; without
+	// it the caller asks, e.g. in the editor.
+	inlineFeedback bool
+	// feedbackQuestion labels the feedback input.
+	feedbackQuestion string
+
+	// help and preview are shown over the message pane by [?] and [p].
+	help    string
+	preview string
+}
+
+// reviewView builds the full-screen review of message for the interactive
+// loop, with the same rules as the letter prompt: [n] needs AI and a
+// regeneration left, and --strict refuses invalid messages.
+func reviewView(stagedDiff, message string, issues []commitmsg.Issue, mismatch string, opts commitOptions, hasAI, regenLeft bool, regenerations int) tuiView {
+	var notes, help, preview strings.Builder
+	printIssues(&notes, issues)
+	if mismatch != "" {
+		fmt.Fprintf(&notes, "\nWarning: the type may not match the change: %s\n", mismatch)
+	}
+	left := -1
+	if opts.maxRegenerations > 0 {
+		left = opts.maxRegenerations - regenerations
+	}
+	printMenuHelp(&help, opts, hasAI, left)
+	printLogPreview(&preview, assembleMessage(message, opts, hasAI))
+
+	view := tuiView{
+		diff:             stagedDiff,
+		message:          message,
+		notes:            notes.String(),
+		inlineFeedback:   inlineFeedback(opts),
+		feedbackQuestion: opts.feedbackQuestion,
+		help:             help.String(),
+		preview:          preview.String(),
+	}
+	switch {
+	case !hasAI:
+		view.noRegenerate = "Regeneration needs AI; edit the message instead."
+	case !regenLeft:
+		view.noRegenerate = fmt.Sprintf("No regenerations left (--max-regenerations %d); accept, edit or cancel.", opts.maxRegenerations)
+	}
+	switch {
+	case opts.strict && commitmsg.HasErrors(issues):
+		view.noAccept = "The message has validation errors (--strict); regenerate or edit it."
+	case opts.strict && mismatch != "":
+		view.noAccept = "The type does not match the change (--strict); regenerate or edit it."
+	}
+	return view
+}
+
+// inlineFeedback reports whether the review asks for [n] feedback on its
+// status line. The editor of --interactive-edit-feedback and the quick picks
+// of --feedback-option need the regular prompt.
+func inlineFeedback(opts commitOptions) bool {
+	return !opts.editFeedback && len(opts.feedbackOptions) == 0
+}
+
+// tuiState is the review's position and mode between key presses.
+type tuiState struct {
+	scroll  int
+	overlay string
+	notice  string
+	// typing is set while feedback is entered into input.
+	typing bool
+	input  []rune
+}
+
+// reviewInTUI shows the diff and message full screen until an action is
+// chosen, and returns it as the letter the classic prompt uses: "y", "n",
+// "e", "t" or "c". feedback is the text typed for "n" when the view asks
+// for it inline. An error means the terminal could not be set up, and the
+// caller should fall back to the menu.
+func reviewInTUI(reader *bufio.Reader, out io.Writer, view tuiView) (choice, feedback string, err error) {
+	restore, err := rawTerminal()
+	if err != nil {
+		return "", "", err
+	}
+	defer restore()
+	fmt.Fprint(out, enterFullScreen)
+	defer fmt.Fprint(out, leaveFullScreen)
+
+	diffLines := strings.Split(strings.TrimRight(view.diff, "\n"), "\n")
+	var state tuiState
+
+	// The size is read once and again when the terminal is resized, which
+	// redraws the review at once. mu is held except while waiting for a
+	// key, so a redraw never sees the state half updated.
+	var mu sync.Mutex
+	rows, cols := terminalSize()
+	stop := notifyResize(func() {
+		r, c := terminalSize()
+		mu.Lock()
+		defer mu.Unlock()
+		rows, cols = r, c
+		drawReview(out, view, &state, diffLines, rows, cols)
+	})
+	// Runs after mu is unlocked, so a redraw waiting for it can finish
+	defer stop()
+	mu.Lock()
+	defer mu.Unlock()
+
+	for {
+		diffRows := drawReview(out, view, &state, diffLines, rows, cols)
+		maxScroll := max(0, len(diffLines)-diffRows)
+
+		mu.Unlock()
+		b, err := reader.ReadByte()
+		mu.Lock()
+		if err != nil {
+			return "", "", err
+		}
+		state.notice = ""
+		if state.typing {
+			switch {
+			case b == '\r' || b == '\n':
+				return "n", strings.TrimSpace(string(state.input)), nil
+			case b == 3 || b == 0x1b:
+				// Ctrl-C or Esc leave the input, not the review
+				discardEscape(reader, b)
+				state.typing, state.input = false, nil
+			case b == 0x7f || b == 8:
+				if len(state.input) > 0 {
+					state.input = state.input[:len(state.input)-1]
+				}
+			case b == 0x15: // Ctrl-U
+				state.input = nil
+			case b >= 0x20:
+				reader.UnreadByte()
+				r, _, _ := reader.ReadRune()
+				state.input = append(state.input, r)
+			}
+			continue
+		}
+		if state.overlay != "" && b != 'p' && b != '?' {
+			// Any key closes help or the preview
+			discardEscape(reader, b)
+			state.overlay = ""
+			continue
+		}
+
+		page := max(1, diffRows-1)
+		switch b {
+		case 'y', '\r', '\n':
+			if view.noAccept != "" {
+				state.notice = view.noAccept
+				continue
+			}
+			return "y", "", nil
+		case 'n':
+			if view.noRegenerate != "" {
+				state.notice = view.noRegenerate
+				continue
+			}
+			if !view.inlineFeedback {
+				return "n", "", nil
+			}
+			state.typing = true
+		case 'e', 't', 'c':
+			return string(b), "", nil
+		case 'q', 3, 4: // Ctrl-C, Ctrl-D
+			return "c", "", nil
+		case 'p':
+			state.overlay = toggle(state.overlay, view.preview)
+		case '?':
+			state.overlay = toggle(state.overlay, view.help)
+		case 'j':
+			state.scroll++
+		case 'k':
+			state.scroll--
+		case ' ', 6: // Ctrl-F
+			state.scroll += page
+		case 'b', 2: // Ctrl-B
+			state.scroll -= page
+		case 'g':
+			state.scroll = 0
+		case 'G':
+			state.scroll = maxScroll
+		case 0x1b:
+			state.scroll += escapeScroll(reader, page, maxScroll)
+		}
+		state.scroll = min(max(state.scroll, 0), maxScroll)
+	}
+}
+
+// toggle returns text, or nothing when it is already shown.
+func toggle(current, text string) string {
+	if current == text {
+		return ""
+	}
+	return text
+}
+
+// escapeScroll reads the rest of an escape sequence after ESC and returns
+// how far it scrolls the diff: arrows by a line, Page Up and Page Down by
+// page, Home and End to either end.
+func escapeScroll(reader *bufio.Reader, page, maxScroll int) int {
+	if reader.Buffered() == 0 {
+		return 0
+	}
+	if next, _ := reader.ReadByte(); next != '[' {
+		return 0
+	}
+	key, _ := reader.ReadByte()
+	switch key {
+	case 'A':
+		return -1
+	case 'B':
+		return 1
+	case 'H':
+		return -maxScroll
+	case 'F':
+		return maxScroll
+	case '5', '6':
+		// Page Up is ESC [ 5 ~, Page Down ESC [ 6 ~
+		reader.ReadByte()
+		if key == '5' {
+			return -page
+		}
+		return page
+	}
+	return 0
+}
+
+// discardEscape drops the rest of an escape sequence when b starts one, so
+// keys such as arrows count as one key press. A lone Esc arrives with
+// nothing after it.
+func discardEscape(reader *bufio.Reader, b byte) {
+	if b != 0x1b || reader.Buffered() == 0 {
+		return
+	}
+	if next, _ := reader.ReadByte(); next != '[' {
+		return
+	}
+	for reader.Buffered() > 0 {
+		if c, _ := reader.ReadByte(); c >= 0x40 && c <= 0x7e {
+			return
+		}
+	}
+}
+
+// drawReview renders one frame of the review: a title bar, the diff pane,
+// the message pane and the status line. It returns the height of the diff
+// pane.
+func drawReview(out io.Writer, view tuiView, state *tuiState, diffLines []string, rows, cols int) int {
+	var lower []string
+	if state.overlay != "" {
+		for _, line := range strings.Split(strings.Trim(state.overlay, "\n"), "\n") {
+			lower = append(lower, clip(line, cols))
+		}
+	} else {
+		lower = highlightMessage(view.message, cols)
+		if notes := strings.Trim(view.notes, "\n"); notes != "" {
+			lower = append(lower, "")
+			for _, line := range strings.Split(notes, "\n") {
+				lower = append(lower, ansiYellow+clip(line, cols)+ansiReset)
+			}
+		}
+	}
+	// The message gets at least half the screen, more when the diff is short
+	lower = lower[:min(len(lower), max(1, rows-3-min(len(diffLines), (rows-3)/2)))]
+	diffRows := max(1, rows-3-len(lower))
+
+	end := min(len(diffLines), state.scroll+diffRows)
+	title := fmt.Sprintf(" Staged changes  lines %d-%d of %d ", state.scroll+1, end, len(diffLines))
+	label := " Message "
+	if state.overlay != "" {
+		label = " Press any key to return "
+	}
+
+	var frame strings.Builder
+	frame.WriteString("\x1b[H")
+	line := func(text string) {
+		frame.WriteString("\x1b[K" + text + "\r\n")
+	}
+	line(ansiInverse + pad(title, cols) + ansiReset)
+	for i := 0; i < diffRows; i++ {
+		if n := state.scroll + i; n < len(diffLines) {
+			line(highlightDiffLine(clip(diffLines[n], cols)))
+		} else {
+			line("")
+		}
+	}
+	line(ansiInverse + pad(label, cols) + ansiReset)
+	for _, text := range lower {
+		line(text)
+	}
+	frame.WriteString("\x1b[J" + statusLine(view, state, cols))
+	fmt.Fprint(out, frame.String())
+	return diffRows
+}
+
+// statusLine is the bottom line: the feedback input while typing, a notice
+// after a refused key, or the key bindings.
+func statusLine(view tuiView, state *tuiState, cols int) string {
+	switch {
+	case state.typing:
+		question := strings.TrimSpace(view.feedbackQuestion)
+		return ansiBold + clip(question+" "+string(state.input), cols-1) + ansiReset + "\x1b[?25h"
+	case state.notice != "":
+		return "\x1b[?25l" + ansiRed + clip(state.notice, cols) + ansiReset
+	}
+	keys := "y accept  n regenerate  e edit  t trailers  p preview  c cancel  ? help  j/k scroll"
+	if view.noRegenerate != "" {
+		keys = strings.Replace(keys, "n regenerate  ", "", 1)
+	}
+	return "\x1b[?25l" + ansiDim + clip(keys, cols) + ansiReset
+}
+
+// highlightMessage colors a commit message for the message pane: the type,
+// scope and breaking marker of the header, and the trailers.
+func highlightMessage(message string, cols int) []string {
+	rest, trailers := commitmsg.SplitTrailers(commitmsg.ToLF(message))
+	lines := strings.Split(rest, "\n")
+	header := commitmsg.Parse(lines[0])
+
+	var out []string
+	if header.Type == "" {
+		out = append(out, ansiBold+clip(lines[0], cols)+ansiReset)
+	} else {
+		prefix := header.Type
+		colored := ansiYellow + header.Type + ansiReset
+		if header.Scope != "" {
+			prefix += "(" + header.Scope + ")"
+			colored += "(" + ansiCyan + header.Scope + ansiReset + ")"
+		}
+		if header.Breaking {
+			prefix += "!"
+			colored += ansiRed + "!" + ansiReset
+		}
+		subject := strings.TrimPrefix(lines[0], prefix)
+		out = append(out, colored+ansiBold+clip(subject, max(0, cols-utf8.RuneCountInString(prefix)))+ansiReset)
+	}
+	for _, line := range lines[1:] {
+		out = append(out, clip(line, cols))
+	}
+	if len(trailers) > 0 {
+		out = append(out, "")
+		for _, t := range trailers {
+			out = append(out, ansiDim+clip(t.String(), cols)+ansiReset)
+		}
+	}
+	return out
+}
+
+// highlightDiffLine colors a line of diff.Format output.
+func highlightDiffLine(line string) string {
+	switch {
+	case strings.HasPrefix(line, "== "):
+		return ansiBold + line + ansiReset
+	case strings.HasPrefix(line, "@@"):
+		return ansiCyan + line + ansiReset
+	case strings.HasPrefix(line, "+"):
+		return ansiGreen + line + ansiReset
+	case strings.HasPrefix(line, "-"):
+		return ansiRed + line + ansiReset
+	}
+	return line
+}
+
+// clip expands tabs in line and cuts it to width columns.
+func clip(line string, width int) string {
+	line = strings.ReplaceAll(line, "\t", "    ")
+	if utf8.RuneCountInString(line) <= width {
+		return line
+	}
+	return string([]rune(line)[:max(0, width)])
+}
+
+// pad clips text to width columns and fills the rest with spaces.
+func pad(text string, width int) string {
+	text = clip(text, width)
+	return text + strings.Repeat(" ", max(0, width-utf8.RuneCountInString(text)))
+}
+
+// terminalSize returns the rows and columns of the terminal on stdin, or
+// 24 by 80 when stty cannot tell.
+func terminalSize() (rows, cols int) {
+	cmd := exec.Command("stty", "size")
+	cmd.Stdin = os.Stdin
+	output, err := cmd.Output()
+	if err == nil {
+		if fields := strings.Fields(string(output)); len(fields) == 2 {
+			r, errRows := strconv.Atoi(fields[0])
+			c, errCols := strconv.Atoi(fields[1])
+			if errRows == nil && errCols == nil && r > 4 && c > 10 {
+				return r, c
+			}
+		}
+	}
+	return 24, 80
+}
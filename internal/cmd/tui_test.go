@@ -0,0 +1,314 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"regexp"
+	"strings"
+	"testing"
+
+	commitmsg "github.com/yourorg/arc-commit/internal/message"
+)
+
+func TestReviewView(t *testing.T) {
+	errorIssue := commitmsg.Issue{Rule: "format", Severity: commitmsg.Error, Message: "header is not a conventional commit"}
+	warningIssue := commitmsg.Issue{Rule: "body", Severity: commitmsg.Warning, Message: "body line is long"}
+	tests := []struct {
+		name          string
+		issues        []commitmsg.Issue
+		mismatch      string
+		opts          commitOptions
+		hasAI         bool
+		regenLeft     bool
+		regenerations int
+
+		wantNoRegenerate string
+		wantNoAccept     string
+		wantNotes        []string
+		wantInline       bool
+	}{
+		{name: "everything allowed", hasAI: true, regenLeft: true, wantInline: true},
+		{name: "no AI", regenLeft: true, wantNoRegenerate: "Regeneration needs AI", wantInline: true},
+		{
+			name:             "no regenerations left",
+			opts:             commitOptions{maxRegenerations: 2},
+			hasAI:            true,
+			regenerations:    2,
+			wantNoRegenerate: "No regenerations left (--max-regenerations 2)",
+			wantInline:       true,
+		},
+		{
+			name:         "strict with errors",
+			issues:       []commitmsg.Issue{errorIssue},
+			opts:         commitOptions{strict: true},
+			hasAI:        true,
+			regenLeft:    true,
+			wantNoAccept: "validation errors (--strict)",
+			wantNotes:    []string{"error: header is not a conventional commit (format)"},
+			wantInline:   true,
+		},
+		{
+			name:       "strict with warnings",
+			issues:     []commitmsg.Issue{warningIssue},
+			opts:       commitOptions{strict: true},
+			hasAI:      true,
+			regenLeft:  true,
+			wantNotes:  []string{"warning: body line is long (body)"},
+			wantInline: true,
+		},
+		{
+			name:         "strict with mismatch",
+			mismatch:     "feat: only deletes code",
+			opts:         commitOptions{strict: true},
+			hasAI:        true,
+			regenLeft:    true,
+			wantNoAccept: "does not match the change (--strict)",
+			wantNotes:    []string{"Warning: the type may not match the change: feat: only deletes code"},
+			wantInline:   true,
+		},
+		{name: "editor feedback", opts: commitOptions{editFeedback: true}, hasAI: true, regenLeft: true},
+		{name: "feedback options", opts: commitOptions{feedbackOptions: []string{"shorter"}}, hasAI: true, regenLeft: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testRepo(t)
+			view := reviewView("== app.go\n+x := 1\n", "feat: add app", tt.issues, tt.mismatch, tt.opts, tt.hasAI, tt.regenLeft, tt.regenerations)
+			if !strings.Contains(view.noRegenerate, tt.wantNoRegenerate) || (tt.wantNoRegenerate == "") != (view.noRegenerate == "") {
+				t.Errorf("noRegenerate = %q, want %q", view.noRegenerate, tt.wantNoRegenerate)
+			}
+			if !strings.Contains(view.noAccept, tt.wantNoAccept) || (tt.wantNoAccept == "") != (view.noAccept == "") {
+				t.Errorf("noAccept = %q, want %q", view.noAccept, tt.wantNoAccept)
+			}
+			for _, want := range tt.wantNotes {
+				if !strings.Contains(view.notes, want) {
+					t.Errorf("notes = %q, want %q", view.notes, want)
+				}
+			}
+			if view.inlineFeedback != tt.wantInline {
+				t.Errorf("inlineFeedback = %v, want %v", view.inlineFeedback, tt.wantInline)
+			}
+			if !strings.Contains(view.preview, "feat: add app") || !strings.Contains(view.help, "Actions:") {
+				t.Errorf("preview %q or help %q is missing", view.preview, view.help)
+			}
+		})
+	}
+}
+
+// ansiSequence matches the escape sequences of a drawn frame.
+var ansiSequence = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+
+func TestDrawReview(t *testing.T) {
+	var diffLines []string
+	for i := range 30 {
+		diffLines = append(diffLines, "+line "+string(rune('a'+i%26)))
+	}
+	view := tuiView{
+		message:      "feat(cli): add review\n\nShows the diff.\n\nRefs: #1",
+		notes:        "\nValidation:\n  warning: body line is long (body)\n",
+		noRegenerate: "Regeneration needs AI; edit the message instead.",
+		help:         "\nActions:\n  y  commit\n",
+	}
+	tests := []struct {
+		name         string
+		state        tuiState
+		rows         int
+		wantDiffRows int
+		want         []string
+		notWant      []string
+	}{
+		{
+			name:         "diff and message",
+			rows:         24,
+			wantDiffRows: 13,
+			want:         []string{" Staged changes  lines 1-13 of 30 ", " Message ", "feat(cli): add review", "Refs: #1", "warning: body line is long", "y accept  e edit"},
+			notWant:      []string{"n regenerate", "+line n"},
+		},
+		{
+			name:         "scrolled",
+			state:        tuiState{scroll: 17},
+			rows:         24,
+			wantDiffRows: 13,
+			want:         []string{" lines 18-30 of 30 ", "+line r", "+line d"},
+			notWant:      []string{"+line q"},
+		},
+		{
+			name:         "overlay",
+			state:        tuiState{overlay: view.help},
+			rows:         24,
+			wantDiffRows: 19,
+			want:         []string{" Press any key to return ", "y  commit"},
+			notWant:      []string{"feat(cli): add review"},
+		},
+		{
+			name:         "typing feedback",
+			state:        tuiState{typing: true, input: []rune("shorter")},
+			rows:         24,
+			wantDiffRows: 13,
+			want:         []string{"Feedback? shorter"},
+			notWant:      []string{"y accept"},
+		},
+		{
+			name:         "notice",
+			state:        tuiState{notice: "No regenerations left"},
+			rows:         24,
+			wantDiffRows: 13,
+			want:         []string{"No regenerations left"},
+		},
+		{
+			name:         "small terminal",
+			rows:         6,
+			wantDiffRows: 1,
+			want:         []string{" lines 1-1 of 30 ", "feat(cli): add review"},
+			notWant:      []string{"Shows the diff."},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := view
+			v.feedbackQuestion = "Feedback?"
+			var out strings.Builder
+			state := tt.state
+			if got := drawReview(&out, v, &state, diffLines, tt.rows, 80); got != tt.wantDiffRows {
+				t.Errorf("drawReview() = %d diff rows, want %d", got, tt.wantDiffRows)
+			}
+			frame := ansiSequence.ReplaceAllString(out.String(), "")
+			if lines := strings.Count(frame, "\r\n") + 1; lines > tt.rows {
+				t.Errorf("frame has %d lines, more than the %d rows", lines, tt.rows)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(frame, want) {
+					t.Errorf("frame does not show %q:\n%s", want, frame)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(frame, notWant) {
+					t.Errorf("frame shows %q:\n%s", notWant, frame)
+				}
+			}
+		})
+	}
+}
+
+func TestEscapeScroll(t *testing.T) {
+	tests := []struct {
+		name string
+		// input follows the ESC already read.
+		input string
+		want  int
+		// wantRest is left unread.
+		wantRest string
+	}{
+		{name: "up", input: "[A", want: -1},
+		{name: "down", input: "[B", want: 1},
+		{name: "page up", input: "[5~", want: -10},
+		{name: "page down", input: "[6~x", want: 10, wantRest: "x"},
+		{name: "home", input: "[H", want: -25},
+		{name: "end", input: "[F", want: 25},
+		{name: "lone escape", input: "", want: 0},
+		{name: "alt key", input: "x", want: 0},
+		{name: "unknown sequence", input: "[Cy", want: 0, wantRest: "y"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := bufio.NewReader(strings.NewReader(tt.input))
+			reader.Peek(len(tt.input))
+			if got := escapeScroll(reader, 10, 25); got != tt.want {
+				t.Errorf("escapeScroll() = %d, want %d", got, tt.want)
+			}
+			if rest, _ := reader.Peek(reader.Buffered()); string(rest) != tt.wantRest {
+				t.Errorf("left %q unread, want %q", rest, tt.wantRest)
+			}
+		})
+	}
+}
+
+func TestDiscardEscape(t *testing.T) {
+	tests := []struct {
+		name     string
+		b        byte
+		input    string
+		wantRest string
+	}{
+		{name: "arrow", b: 0x1b, input: "[Dy", wantRest: "y"},
+		{name: "page down", b: 0x1b, input: "[6~y", wantRest: "y"},
+		{name: "lone escape", b: 0x1b},
+		{name: "plain key", b: 'x', input: "[Dy", wantRest: "[Dy"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := bufio.NewReader(strings.NewReader(tt.input))
+			reader.Peek(len(tt.input))
+			discardEscape(reader, tt.b)
+			if rest, _ := reader.Peek(reader.Buffered()); string(rest) != tt.wantRest {
+				t.Errorf("left %q unread, want %q", rest, tt.wantRest)
+			}
+		})
+	}
+}
+
+func TestHighlightMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		cols    int
+		want    []string
+	}{
+		{
+			name:    "type scope and breaking marker",
+			message: "feat(api)!: drop v1",
+			cols:    80,
+			want:    []string{ansiYellow + "feat" + ansiReset + "(" + ansiCyan + "api" + ansiReset + ")" + ansiRed + "!" + ansiReset + ansiBold + ": drop v1" + ansiReset},
+		},
+		{
+			name:    "not conventional",
+			message: "Drop v1\n\nIt was deprecated.",
+			cols:    80,
+			want:    []string{ansiBold + "Drop v1" + ansiReset, "", "It was deprecated."},
+		},
+		{
+			name:    "trailers",
+			message: "fix: handle nil\n\nRefs: #1",
+			cols:    80,
+			want:    []string{ansiYellow + "fix" + ansiReset + ansiBold + ": handle nil" + ansiReset, "", ansiDim + "Refs: #1" + ansiReset},
+		},
+		{
+			name:    "clipped",
+			message: "fix: handle nil maps",
+			cols:    10,
+			want:    []string{ansiYellow + "fix" + ansiReset + ansiBold + ": handl" + ansiReset},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := highlightMessage(tt.message, tt.cols)
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("highlightMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClipAndPad(t *testing.T) {
+	tests := []struct {
+		text     string
+		width    int
+		wantClip string
+		wantPad  string
+	}{
+		{text: "abc", width: 5, wantClip: "abc", wantPad: "abc  "},
+		{text: "abcdef", width: 3, wantClip: "abc", wantPad: "abc"},
+		{text: "\tx", width: 8, wantClip: "    x", wantPad: "    x   "},
+		{text: "héllo", width: 2, wantClip: "hé", wantPad: "hé"},
+		{text: "abc", width: 0, wantClip: "", wantPad: ""},
+	}
+	for _, tt := range tests {
+		if got := clip(tt.text, tt.width); got != tt.wantClip {
+			t.Errorf("clip(%q, %d) = %q, want %q", tt.text, tt.width, got, tt.wantClip)
+		}
+		if got := pad(tt.text, tt.width); got != tt.wantPad {
+			t.Errorf("pad(%q, %d) = %q, want %q", tt.text, tt.width, got, tt.wantPad)
+		}
+	}
+}
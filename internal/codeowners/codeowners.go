@@ -0,0 +1,117 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+// Package codeowners parses CODEOWNERS files and finds the rule that owns a
+// path, following GitHub's rules: patterns use gitignore syntax and the last
+// matching line wins.
+package codeowners
+
+import (
+	"bufio"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Locations lists where CODEOWNERS files are looked for, relative to the
+// repository root, in the order GitHub checks them.
+var Locations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// Rule is one line of a CODEOWNERS file.
+type Rule struct {
+	Pattern string
+	Owners  []string
+	re      *regexp.Regexp
+}
+
+// Rules are the rules of a CODEOWNERS file, in file order.
+type Rules []Rule
+
+// Parse reads a CODEOWNERS file. Comments, blank lines and lines without
+// owners are skipped.
+func Parse(r io.Reader) (Rules, error) {
+	var rules Rules
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		rules = append(rules, Rule{
+			Pattern: fields[0],
+			Owners:  fields[1:],
+			re:      compile(fields[0]),
+		})
+	}
+	return rules, scanner.Err()
+}
+
+// Match returns the rule owning p, a slash-separated path relative to the
+// repository root.
+func (rs Rules) Match(p string) (Rule, bool) {
+	for i := len(rs) - 1; i >= 0; i-- {
+		if rs[i].re.MatchString(p) {
+			return rs[i], true
+		}
+	}
+	return Rule{}, false
+}
+
+// Area names the part of the project a rule covers: the last literal
+// directory or file name of its pattern, e.g. "payments" for
+// "/services/payments/", or else the owner's team name without its org.
+func (r Rule) Area() string {
+	parts := strings.Split(strings.Trim(r.Pattern, "/"), "/")
+	for i := len(parts) - 1; i >= 0; i-- {
+		part := parts[i]
+		if part != "" && !strings.ContainsAny(part, "*?[") {
+			return strings.ToLower(strings.TrimSuffix(part, path.Ext(part)))
+		}
+	}
+	if len(r.Owners) > 0 {
+		owner := strings.TrimPrefix(r.Owners[0], "@")
+		if i := strings.LastIndex(owner, "/"); i >= 0 {
+			owner = owner[i+1:]
+		}
+		return strings.ToLower(owner)
+	}
+	return ""
+}
+
+// compile turns a gitignore-style pattern into a regular expression over
+// the whole path. A pattern containing a slash other than at its end is
+// anchored at the root; otherwise it matches at any depth. A trailing slash
+// or a directory match covers everything below it.
+func compile(pattern string) *regexp.Regexp {
+	anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	p := strings.TrimPrefix(strings.TrimSuffix(pattern, "/"), "/")
+
+	var b strings.Builder
+	b.WriteString("^")
+	if !anchored {
+		b.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(p); i++ {
+		switch c := p[i]; {
+		case strings.HasPrefix(p[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(p[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("(?:/.*)?$")
+	return regexp.MustCompile(b.String())
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package codeowners
+
+import (
+	"strings"
+	"testing"
+)
+
+const testFile = `# Default owners
+*                    @acme/core
+
+/services/payments/  @acme/payments # money
+docs/**/*.md         @acme/docs
+*.proto              @acme/api
+/build/              # no owners, skipped
+`
+
+func TestParse(t *testing.T) {
+	rules, err := Parse(strings.NewReader(testFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var patterns []string
+	for _, r := range rules {
+		patterns = append(patterns, r.Pattern)
+	}
+	if got, want := strings.Join(patterns, " "), "* /services/payments/ docs/**/*.md *.proto"; got != want {
+		t.Errorf("patterns = %q, want %q", got, want)
+	}
+	if got := rules[1].Owners; len(got) != 1 || got[0] != "@acme/payments" {
+		t.Errorf("owners = %q, want [@acme/payments]", got)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	rules, err := Parse(strings.NewReader(testFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path     string
+		wantRule string
+		wantArea string
+	}{
+		{path: "main.go", wantRule: "*", wantArea: "core"},
+		{path: "services/payments/charge.go", wantRule: "/services/payments/", wantArea: "payments"},
+		{path: "lib/services/payments/charge.go", wantRule: "*", wantArea: "core"},
+		{path: "docs/guide/setup.md", wantRule: "docs/**/*.md", wantArea: "docs"},
+		{path: "docs/setup.md", wantRule: "docs/**/*.md", wantArea: "docs"},
+		{path: "api/v1/users.proto", wantRule: "*.proto", wantArea: "api"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rule, ok := rules.Match(tt.path)
+			if !ok {
+				t.Fatalf("Match(%q) found no rule", tt.path)
+			}
+			if rule.Pattern != tt.wantRule {
+				t.Errorf("Match(%q) = %q, want %q", tt.path, rule.Pattern, tt.wantRule)
+			}
+			if got := rule.Area(); got != tt.wantArea {
+				t.Errorf("Area() = %q, want %q", got, tt.wantArea)
+			}
+		})
+	}
+}
+
+func TestMatchNoRules(t *testing.T) {
+	if _, ok := Rules(nil).Match("main.go"); ok {
+		t.Error("Match() found a rule in an empty file")
+	}
+}
@@ -0,0 +1,301 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+// Package config loads arc-commit settings from YAML files.
+//
+// Keys in a config file match the long names of command-line flags, so any
+// flag can be given a global or repository default:
+//
+//	model: claude-sonnet-4-5-20250929
+//	weight-by-size: true
+//	sensitive-repo: true
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the repository-local config file, read from the repo root.
+const FileName = ".arc-commit.yaml"
+
+// File is a parsed config file.
+type File struct {
+	// Path is where the file was loaded from.
+	Path string `yaml:"-"`
+	// Values maps flag names to their configured values.
+	Values map[string]any `yaml:",inline"`
+}
+
+// Load reads a config file. It returns nil without error when the file does
+// not exist.
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	f := &File{Path: path}
+	if err := yaml.Unmarshal(data, f); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return f, nil
+}
+
+// Bool reports whether key is set to true. A nil file has no values.
+func (f *File) Bool(key string) bool {
+	if f == nil {
+		return false
+	}
+	v, ok := f.Values[key].(bool)
+	return ok && v
+}
+
+// StringLists returns a mapping of names to string lists, such as
+// classify-rules. Entries that are not lists are ignored. A nil file has no
+// values.
+func (f *File) StringLists(key string) map[string][]string {
+	if f == nil {
+		return nil
+	}
+	m, ok := f.Values[key].(map[string]any)
+	if !ok {
+		return nil
+	}
+	lists := make(map[string][]string, len(m))
+	for name, v := range m {
+		list, ok := v.([]any)
+		if !ok {
+			continue
+		}
+		for _, item := range list {
+			lists[name] = append(lists[name], fmt.Sprint(item))
+		}
+	}
+	return lists
+}
+
+// Strings returns a mapping of names to strings, such as body-templates.
+// Entries that are not scalars are ignored. A nil file has no values.
+func (f *File) Strings(key string) map[string]string {
+	if f == nil {
+		return nil
+	}
+	m, ok := f.Values[key].(map[string]any)
+	if !ok {
+		return nil
+	}
+	values := make(map[string]string, len(m))
+	for name, v := range m {
+		switch v.(type) {
+		case map[string]any, []any, nil:
+			continue
+		}
+		values[name] = fmt.Sprint(v)
+	}
+	return values
+}
+
+// Source identifies where a setting's effective value came from.
+type Source string
+
+// Sources in increasing order of precedence.
+const (
+	SourceDefault Source = "default"
+	SourceGlobal  Source = "global"
+	SourceRepo    Source = "repo"
+	SourceProfile Source = "profile"
+	SourceEnv     Source = "env"
+	SourceFlag    Source = "flag"
+)
+
+// EnvPrefix starts the environment variable for each flag, e.g. the
+// weight-by-size flag reads ARC_COMMIT_WEIGHT_BY_SIZE.
+const EnvPrefix = "ARC_COMMIT_"
+
+// EnvName returns the environment variable consulted for a flag.
+func EnvName(flag string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(flag, "-", "_"))
+}
+
+// GlobalPath returns the location of the user's global config file.
+func GlobalPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	return filepath.Join(dir, "arc-commit", "config.yaml"), nil
+}
+
+// Layers are the settings consulted for flags not given on the command
+// line. Precedence is environment, then the selected profile, then
+// repository, then global file. Any file may be nil.
+type Layers struct {
+	Global *File
+	Repo   *File
+	// Profile holds the values of the profile selected with --profile.
+	Profile *File
+	// Getenv looks up environment variables; nil disables them.
+	Getenv func(string) string
+}
+
+// Lookup returns the highest-precedence value for key, where it came from,
+// and a description of that location for error messages.
+func (l Layers) Lookup(key string) (value any, source Source, from string, ok bool) {
+	if v := lookupEnv(l.Getenv, key); v != "" {
+		return v, SourceEnv, EnvName(key), true
+	}
+	for _, layer := range []struct {
+		file   *File
+		source Source
+	}{{l.Profile, SourceProfile}, {l.Repo, SourceRepo}, {l.Global, SourceGlobal}} {
+		if v, ok := layer.file.lookup(key); ok {
+			return v, layer.source, layer.file.Path, true
+		}
+	}
+	return nil, "", "", false
+}
+
+// ProfilesKey is the config key holding named presets of flag values:
+//
+//	profiles:
+//	  ci:
+//	    temperature: 0
+//	    strict: true
+const ProfilesKey = "profiles"
+
+// Profiles lists the profile names defined in any of the files.
+func Profiles(files ...*File) []string {
+	var names []string
+	for _, f := range files {
+		for name := range f.profiles() {
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Profile merges the named profile from the files, with values from later
+// files taking precedence. It returns an error when no file defines it.
+func Profile(name string, files ...*File) (*File, error) {
+	profile := &File{Path: "profile " + name, Values: map[string]any{}}
+	found := false
+	for _, f := range files {
+		values, ok := f.profiles()[name].(map[string]any)
+		if !ok {
+			continue
+		}
+		found = true
+		for key, v := range values {
+			profile.Values[key] = v
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("unknown profile %q", name)
+	}
+	return profile, nil
+}
+
+// Sets reports whether f sets key, at the top level or in one of its
+// profiles. A nil file sets nothing.
+func (f *File) Sets(key string) bool {
+	if _, ok := f.lookup(key); ok {
+		return true
+	}
+	for _, values := range f.profiles() {
+		if values, ok := values.(map[string]any); ok {
+			if _, ok := values[key]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// profiles returns the file's profile definitions. A nil file has none.
+func (f *File) profiles() map[string]any {
+	if f == nil {
+		return nil
+	}
+	m, _ := f.Values[ProfilesKey].(map[string]any)
+	return m
+}
+
+// Resolve sets every flag that was not given on the command line from the
+// highest-precedence layer that has a value for it, and reports where each
+// flag's effective value came from. Keys that do not name a flag are
+// ignored, since one file serves several subcommands.
+func Resolve(flags *pflag.FlagSet, layers Layers) (map[string]Source, error) {
+	sources := make(map[string]Source)
+	var errs []string
+
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if flag.Changed {
+			sources[flag.Name] = SourceFlag
+			return
+		}
+		sources[flag.Name] = SourceDefault
+
+		value, source, from, ok := layers.Lookup(flag.Name)
+		if !ok {
+			return
+		}
+
+		if err := setFlag(flag, value); err != nil {
+			errs = append(errs, fmt.Sprintf("%s (from %s): %v", flag.Name, from, err))
+			return
+		}
+		sources[flag.Name] = source
+	})
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid config values: %s", strings.Join(errs, "; "))
+	}
+	return sources, nil
+}
+
+// lookup returns the value configured for key. A nil file has no values.
+func (f *File) lookup(key string) (any, bool) {
+	if f == nil {
+		return nil, false
+	}
+	v, ok := f.Values[key]
+	return v, ok
+}
+
+// lookupEnv reads a flag's environment variable.
+func lookupEnv(getenv func(string) string, flag string) string {
+	if getenv == nil {
+		return ""
+	}
+	return getenv(EnvName(flag))
+}
+
+// setFlag assigns a YAML value to a flag without marking it as changed on
+// the command line.
+func setFlag(flag *pflag.Flag, value any) error {
+	if list, ok := value.([]any); ok {
+		items := make([]string, len(list))
+		for i, item := range list {
+			items[i] = fmt.Sprint(item)
+		}
+		if slice, ok := flag.Value.(pflag.SliceValue); ok {
+			return slice.Replace(items)
+		}
+		return flag.Value.Set(strings.Join(items, ","))
+	}
+	return flag.Value.Set(fmt.Sprint(value))
+}
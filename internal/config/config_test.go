@@ -0,0 +1,213 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	f, err := Load(filepath.Join(dir, "missing.yaml"))
+	if f != nil || err != nil {
+		t.Errorf("Load(missing) = %v, %v; want nil, nil", f, err)
+	}
+
+	if _, err := Load(write("bad.yaml", "sensitive-repo: [\n")); err == nil {
+		t.Error("Load accepted invalid YAML")
+	}
+
+	path := write("ok.yaml", "sensitive-repo: true\nno-ai: false\nmodel: llama3.2\n")
+	f, err = Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Path != path {
+		t.Errorf("Path = %q, want %q", f.Path, path)
+	}
+	for key, want := range map[string]bool{"sensitive-repo": true, "no-ai": false, "model": false, "missing": false} {
+		if got := f.Bool(key); got != want {
+			t.Errorf("Bool(%q) = %v, want %v", key, got, want)
+		}
+	}
+
+	var none *File
+	if none.Bool("sensitive-repo") {
+		t.Error("a nil file has no values")
+	}
+}
+
+func TestLayersLookup(t *testing.T) {
+	layers := Layers{
+		Global:  &File{Path: "global.yaml", Values: map[string]any{"model": "global-model", "temperature": 0.5, "scopes": []any{"cli"}}},
+		Repo:    &File{Path: "repo.yaml", Values: map[string]any{"model": "repo-model", "temperature": 0.2, "max-subject-length": 72}},
+		Profile: &File{Path: "profile ci", Values: map[string]any{"model": "profile-model", "max-subject-length": 50}},
+		Getenv: func(name string) string {
+			if name == "ARC_COMMIT_MODEL" {
+				return "env-model"
+			}
+			return ""
+		},
+	}
+	tests := []struct {
+		key        string
+		want       any
+		wantSource Source
+		wantFrom   string
+	}{
+		{key: "model", want: "env-model", wantSource: SourceEnv, wantFrom: "ARC_COMMIT_MODEL"},
+		{key: "temperature", want: 0.2, wantSource: SourceRepo, wantFrom: "repo.yaml"},
+		{key: "max-subject-length", want: 50, wantSource: SourceProfile, wantFrom: "profile ci"},
+		{key: "scopes", want: []any{"cli"}, wantSource: SourceGlobal, wantFrom: "global.yaml"},
+		{key: "strict"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			value, source, from, ok := layers.Lookup(tt.key)
+			if ok != (tt.want != nil) {
+				t.Fatalf("Lookup(%q) found = %v, want %v", tt.key, ok, tt.want != nil)
+			}
+			if fmt.Sprint(value) != fmt.Sprint(tt.want) || source != tt.wantSource || from != tt.wantFrom {
+				t.Errorf("Lookup(%q) = %v from %s (%s), want %v from %s (%s)", tt.key, value, source, from, tt.want, tt.wantSource, tt.wantFrom)
+			}
+		})
+	}
+}
+
+func TestProfile(t *testing.T) {
+	global := &File{Values: map[string]any{ProfilesKey: map[string]any{
+		"ci":   map[string]any{"temperature": 0, "strict": true},
+		"fast": map[string]any{"model": "llama3.2"},
+	}}}
+	repo := &File{Values: map[string]any{ProfilesKey: map[string]any{
+		"ci":     map[string]any{"strict": false, "scopes": []any{"cli"}},
+		"review": "not a profile",
+	}}}
+
+	tests := []struct {
+		name    string
+		profile string
+		want    map[string]any
+		wantErr bool
+	}{
+		{name: "merged, later files win", profile: "ci", want: map[string]any{"temperature": 0, "strict": false, "scopes": []any{"cli"}}},
+		{name: "one file", profile: "fast", want: map[string]any{"model": "llama3.2"}},
+		{name: "not a mapping", profile: "review", wantErr: true},
+		{name: "unknown", profile: "nightly", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Profile(tt.profile, global, nil, repo)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Profile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && fmt.Sprint(got.Values) != fmt.Sprint(tt.want) {
+				t.Errorf("Profile() = %v, want %v", got.Values, tt.want)
+			}
+		})
+	}
+
+	if got, want := Profiles(global, nil, repo), []string{"ci", "fast", "review"}; !slices.Equal(got, want) {
+		t.Errorf("Profiles() = %v, want %v", got, want)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	model := flags.String("model", "default-model", "")
+	temperature := flags.Float64("temperature", 0, "")
+	scopes := flags.StringSlice("scopes", nil, "")
+	strict := flags.Bool("strict", false, "")
+	if err := flags.Parse([]string{"--model", "flag-model"}); err != nil {
+		t.Fatal(err)
+	}
+
+	layers := Layers{Repo: &File{Path: "repo.yaml", Values: map[string]any{
+		"model":       "repo-model",
+		"temperature": 0.2,
+		"scopes":      []any{"cli", "config"},
+		"not-a-flag":  true,
+	}}}
+	sources, err := Resolve(flags, layers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *model != "flag-model" || *temperature != 0.2 || !slices.Equal(*scopes, []string{"cli", "config"}) || *strict {
+		t.Errorf("model, temperature, scopes, strict = %q, %v, %q, %v", *model, *temperature, *scopes, *strict)
+	}
+	want := map[string]Source{"model": SourceFlag, "temperature": SourceRepo, "scopes": SourceRepo, "strict": SourceDefault}
+	if !maps.Equal(sources, want) {
+		t.Errorf("sources = %v, want %v", sources, want)
+	}
+	if flags.Changed("temperature") {
+		t.Error("a configured flag is reported as given on the command line")
+	}
+
+	// Bad values name the flag and the file they came from
+	layers.Repo.Values["strict"] = "sometimes"
+	_, err = Resolve(flags, layers)
+	if err == nil || !strings.Contains(err.Error(), "strict (from repo.yaml)") {
+		t.Errorf("Resolve() error = %v, want one naming strict and repo.yaml", err)
+	}
+}
+
+func TestStringLists(t *testing.T) {
+	f := &File{Values: map[string]any{
+		"classify-rules": map[string]any{
+			"test": []any{"*_spec.rb", "spec/"},
+			"docs": "not a list",
+		},
+		"model": "a-model",
+	}}
+	got := f.StringLists("classify-rules")
+	want := map[string][]string{"test": {"*_spec.rb", "spec/"}}
+	if !maps.EqualFunc(got, want, slices.Equal[[]string]) {
+		t.Errorf("StringLists() = %v, want %v", got, want)
+	}
+	if got := f.StringLists("model"); got != nil {
+		t.Errorf("StringLists(scalar) = %v, want nil", got)
+	}
+	if got := (*File)(nil).StringLists("classify-rules"); got != nil {
+		t.Errorf("nil file StringLists() = %v, want nil", got)
+	}
+}
+
+func TestStrings(t *testing.T) {
+	f := &File{Values: map[string]any{
+		"body-templates": map[string]any{
+			"fix":   "Root cause, then fix.",
+			"perf":  42,
+			"docs":  []any{"a list"},
+			"chore": nil,
+		},
+		"model": "a-model",
+	}}
+	got := f.Strings("body-templates")
+	want := map[string]string{"fix": "Root cause, then fix.", "perf": "42"}
+	if !maps.Equal(got, want) {
+		t.Errorf("Strings() = %v, want %v", got, want)
+	}
+	if got := f.Strings("model"); got != nil {
+		t.Errorf("Strings(scalar) = %v, want nil", got)
+	}
+	if got := (*File)(nil).Strings("body-templates"); got != nil {
+		t.Errorf("nil file Strings() = %v, want nil", got)
+	}
+}
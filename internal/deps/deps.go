@@ -0,0 +1,109 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+// Package deps extracts dependency version changes from manifest files such
+// as go.mod and package.json, so dependency updates can be summarized.
+package deps
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Bump is a change to one dependency. Old is empty for an added dependency
+// and New is empty for a removed one.
+type Bump struct {
+	Name string
+	Old  string
+	New  string
+}
+
+// Parser reads the dependency versions declared in a manifest, keyed by
+// dependency name.
+type Parser func(content []byte) (map[string]string, error)
+
+// Parsers maps manifest file names to their parsers. Register additional
+// formats here.
+var Parsers = map[string]Parser{
+	"go.mod":       parseGoMod,
+	"package.json": parsePackageJSON,
+}
+
+// For returns the parser for the manifest at p, chosen by file name.
+func For(p string) (Parser, bool) {
+	parser, ok := Parsers[path.Base(p)]
+	return parser, ok
+}
+
+// Compare lists the dependencies whose versions differ between two parsed
+// manifests, sorted by name.
+func Compare(before, after map[string]string) []Bump {
+	var bumps []Bump
+	for name, old := range before {
+		if updated := after[name]; updated != old {
+			bumps = append(bumps, Bump{Name: name, Old: old, New: updated})
+		}
+	}
+	for name, added := range after {
+		if _, ok := before[name]; !ok {
+			bumps = append(bumps, Bump{Name: name, New: added})
+		}
+	}
+	sort.Slice(bumps, func(i, j int) bool { return bumps[i].Name < bumps[j].Name })
+	return bumps
+}
+
+// parseGoMod reads the require directives of a go.mod file.
+func parseGoMod(content []byte) (map[string]string, error) {
+	versions := make(map[string]string)
+	inBlock := false
+	for _, line := range strings.Split(string(content), "\n") {
+		line, _, _ = strings.Cut(line, "//")
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+		case inBlock && fields[0] == ")":
+			inBlock = false
+		case inBlock && len(fields) >= 2:
+			versions[fields[0]] = fields[1]
+		case fields[0] == "require" && len(fields) == 2 && fields[1] == "(":
+			inBlock = true
+		case fields[0] == "require" && len(fields) >= 3:
+			versions[fields[1]] = fields[2]
+		}
+	}
+	return versions, nil
+}
+
+// packageJSONSections are the package.json objects that declare
+// dependencies.
+var packageJSONSections = []string{
+	"dependencies", "devDependencies", "peerDependencies", "optionalDependencies",
+}
+
+// parsePackageJSON reads the dependency sections of a package.json file.
+func parsePackageJSON(content []byte) (map[string]string, error) {
+	var manifest map[string]json.RawMessage
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid package.json: %w", err)
+	}
+
+	versions := make(map[string]string)
+	for _, section := range packageJSONSections {
+		raw, ok := manifest[section]
+		if !ok {
+			continue
+		}
+		var deps map[string]string
+		if err := json.Unmarshal(raw, &deps); err != nil {
+			return nil, fmt.Errorf("invalid %s in package.json: %w", section, err)
+		}
+		for name, version := range deps {
+			versions[name] = version
+		}
+	}
+	return versions, nil
+}
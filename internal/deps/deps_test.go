@@ -0,0 +1,103 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package deps
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestParseGoMod(t *testing.T) {
+	const gomod = `module example.com/app
+
+go 1.23
+
+require github.com/spf13/cobra v1.8.0
+
+require (
+	github.com/spf13/pflag v1.0.5 // indirect
+	golang.org/x/sys v0.20.0
+)
+`
+	got, err := parseGoMod([]byte(gomod))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"github.com/spf13/cobra": "v1.8.0",
+		"github.com/spf13/pflag": "v1.0.5",
+		"golang.org/x/sys":       "v0.20.0",
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("parseGoMod() = %v, want %v", got, want)
+	}
+}
+
+func TestParsePackageJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name:    "sections",
+			content: `{"name": "app", "dependencies": {"react": "^18.2.0"}, "devDependencies": {"vitest": "1.6.0"}}`,
+			want:    map[string]string{"react": "^18.2.0", "vitest": "1.6.0"},
+		},
+		{
+			name:    "no dependencies",
+			content: `{"name": "app"}`,
+			want:    map[string]string{},
+		},
+		{name: "not JSON", content: `{"name": `, wantErr: true},
+		{name: "bad section", content: `{"dependencies": ["react"]}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePackageJSON([]byte(tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePackageJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !maps.Equal(got, tt.want) {
+				t.Errorf("parsePackageJSON() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	before := map[string]string{"cobra": "v1.7.0", "pflag": "v1.0.5", "yaml": "v3.0.0"}
+	after := map[string]string{"cobra": "v1.8.0", "pflag": "v1.0.5", "sys": "v0.20.0"}
+	want := []Bump{
+		{Name: "cobra", Old: "v1.7.0", New: "v1.8.0"},
+		{Name: "sys", New: "v0.20.0"},
+		{Name: "yaml", Old: "v3.0.0"},
+	}
+	if got := Compare(before, after); !slices.Equal(got, want) {
+		t.Errorf("Compare() = %v, want %v", got, want)
+	}
+	if got := Compare(nil, nil); got != nil {
+		t.Errorf("Compare(nil, nil) = %v, want nil", got)
+	}
+}
+
+func TestFor(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"go.mod", true},
+		{"tools/go.mod", true},
+		{"web/package.json", true},
+		{"go.sum", false},
+		{"package-lock.json", false},
+	}
+	for _, tt := range tests {
+		if _, ok := For(tt.path); ok != tt.want {
+			t.Errorf("For(%q) found = %v, want %v", tt.path, ok, tt.want)
+		}
+	}
+}
@@ -0,0 +1,318 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+// Package diff parses unified git diffs into a structured form and renders
+// compact representations of them for prompts.
+package diff
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Diff is a parsed unified diff.
+type Diff struct {
+	Files []*File
+}
+
+// File is the part of a diff that touches a single path.
+type File struct {
+	// OldPath and NewPath are the paths before and after the change.
+	// Either is empty when the file was added or deleted.
+	OldPath string
+	NewPath string
+
+	New     bool
+	Deleted bool
+	Renamed bool
+	Binary  bool
+
+	// Header holds the raw header lines, from "diff --git" up to the first
+	// hunk, so the file's patch can be reproduced exactly.
+	Header []string
+	Hunks  []*Hunk
+}
+
+// Hunk is a single "@@" section of a file diff.
+type Hunk struct {
+	OldStart, OldLines int
+	NewStart, NewLines int
+	// Section is the optional context git prints after the range,
+	// usually the enclosing function.
+	Section string
+	// Lines holds the hunk body, each line keeping its ' ', '+', '-' or '\'
+	// prefix.
+	Lines []string
+
+	// header is the "@@" line as it appeared in the input.
+	header string
+}
+
+// Parse parses the output of git diff. Input that does not look like a diff
+// yields an empty Diff.
+func Parse(raw string) *Diff {
+	d := &Diff{}
+	var file *File
+	var hunk *Hunk
+
+	lines := strings.Split(raw, "\n")
+	if n := len(lines); n > 0 && lines[n-1] == "" {
+		lines = lines[:n-1]
+	}
+
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			file = &File{Header: []string{line}}
+			file.OldPath, file.NewPath = splitGitPaths(strings.TrimPrefix(line, "diff --git "))
+			d.Files = append(d.Files, file)
+			hunk = nil
+
+		case file == nil:
+			// Preamble before the first file (e.g. git show headers)
+
+		case strings.HasPrefix(line, "@@"):
+			hunk = parseHunkHeader(line)
+			file.Hunks = append(file.Hunks, hunk)
+
+		case hunk != nil:
+			if line == "" {
+				// Some tools strip the space from empty context lines
+				line = " "
+			}
+			hunk.Lines = append(hunk.Lines, line)
+
+		default:
+			file.Header = append(file.Header, line)
+			parseHeaderLine(file, line)
+		}
+	}
+
+	return d
+}
+
+// splitGitPaths splits the "a/old b/new" part of a diff --git line.
+func splitGitPaths(s string) (oldPath, newPath string) {
+	if i := strings.LastIndex(s, " b/"); i >= 0 && strings.HasPrefix(s, "a/") {
+		return s[2:i], s[i+3:]
+	}
+	old, new, _ := strings.Cut(s, " ")
+	return old, new
+}
+
+// parseHeaderLine records what an extended header line says about a file.
+func parseHeaderLine(f *File, line string) {
+	switch {
+	case strings.HasPrefix(line, "new file mode"):
+		f.New = true
+		f.OldPath = ""
+	case strings.HasPrefix(line, "deleted file mode"):
+		f.Deleted = true
+		f.NewPath = ""
+	case strings.HasPrefix(line, "rename from "):
+		f.Renamed = true
+		f.OldPath = strings.TrimPrefix(line, "rename from ")
+	case strings.HasPrefix(line, "rename to "):
+		f.Renamed = true
+		f.NewPath = strings.TrimPrefix(line, "rename to ")
+	case strings.HasPrefix(line, "Binary files "), line == "GIT binary patch":
+		f.Binary = true
+	case strings.HasPrefix(line, "--- "):
+		f.OldPath = headerPath(strings.TrimPrefix(line, "--- "), "a/")
+	case strings.HasPrefix(line, "+++ "):
+		f.NewPath = headerPath(strings.TrimPrefix(line, "+++ "), "b/")
+	}
+}
+
+// headerPath extracts the path from a ---/+++ line.
+func headerPath(s, prefix string) string {
+	if s == "/dev/null" {
+		return ""
+	}
+	s, _, _ = strings.Cut(s, "\t")
+	return strings.TrimPrefix(s, prefix)
+}
+
+// parseHunkHeader parses "@@ -a,b +c,d @@ section".
+func parseHunkHeader(line string) *Hunk {
+	h := &Hunk{header: line}
+	rest := strings.TrimPrefix(line, "@@ ")
+	ranges, section, _ := strings.Cut(rest, " @@")
+	h.Section = strings.TrimSpace(section)
+
+	for _, part := range strings.Fields(ranges) {
+		start, count := parseRange(part[1:])
+		switch part[0] {
+		case '-':
+			h.OldStart, h.OldLines = start, count
+		case '+':
+			h.NewStart, h.NewLines = start, count
+		}
+	}
+	return h
+}
+
+// parseRange parses "start,count" where the count defaults to 1.
+func parseRange(s string) (start, count int) {
+	a, b, found := strings.Cut(s, ",")
+	start, _ = strconv.Atoi(a)
+	count = 1
+	if found {
+		count, _ = strconv.Atoi(b)
+	}
+	return start, count
+}
+
+// Path returns the path that best identifies the file: the new path, or the
+// old one for deletions.
+func (f *File) Path() string {
+	if f.NewPath != "" {
+		return f.NewPath
+	}
+	return f.OldPath
+}
+
+// Status describes the kind of change, e.g. "added" or "renamed".
+func (f *File) Status() string {
+	switch {
+	case f.New:
+		return "added"
+	case f.Deleted:
+		return "deleted"
+	case f.Renamed:
+		return "renamed"
+	default:
+		return "modified"
+	}
+}
+
+// Added returns the number of added lines.
+func (f *File) Added() int {
+	n := 0
+	for _, h := range f.Hunks {
+		n += h.Added()
+	}
+	return n
+}
+
+// Removed returns the number of removed lines.
+func (f *File) Removed() int {
+	n := 0
+	for _, h := range f.Hunks {
+		n += h.Removed()
+	}
+	return n
+}
+
+// Changes returns the number of added plus removed lines.
+func (f *File) Changes() int {
+	return f.Added() + f.Removed()
+}
+
+// Patch reproduces the file's part of the diff in git's format.
+func (f *File) Patch() string {
+	var b strings.Builder
+	for _, line := range f.Header {
+		b.WriteString(line + "\n")
+	}
+	for _, h := range f.Hunks {
+		b.WriteString(h.Patch())
+	}
+	return b.String()
+}
+
+// Header returns the hunk's "@@" line, as parsed when available.
+func (h *Hunk) Header() string {
+	if h.header != "" {
+		return h.header
+	}
+	header := fmt.Sprintf("@@ -%d,%d +%d,%d @@", h.OldStart, h.OldLines, h.NewStart, h.NewLines)
+	if h.Section != "" {
+		header += " " + h.Section
+	}
+	return header
+}
+
+// Patch reproduces the hunk in git's format.
+func (h *Hunk) Patch() string {
+	var b strings.Builder
+	b.WriteString(h.Header() + "\n")
+	for _, line := range h.Lines {
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+// Added returns the number of added lines.
+func (h *Hunk) Added() int {
+	return h.count('+')
+}
+
+// Removed returns the number of removed lines.
+func (h *Hunk) Removed() int {
+	return h.count('-')
+}
+
+func (h *Hunk) count(prefix byte) int {
+	n := 0
+	for _, line := range h.Lines {
+		if len(line) > 0 && line[0] == prefix {
+			n++
+		}
+	}
+	return n
+}
+
+// Added returns the number of added lines across all files.
+func (d *Diff) Added() int {
+	n := 0
+	for _, f := range d.Files {
+		n += f.Added()
+	}
+	return n
+}
+
+// Removed returns the number of removed lines across all files.
+func (d *Diff) Removed() int {
+	n := 0
+	for _, f := range d.Files {
+		n += f.Removed()
+	}
+	return n
+}
+
+// Patch reproduces the whole diff in git's format.
+func (d *Diff) Patch() string {
+	var b strings.Builder
+	for _, f := range d.Files {
+		b.WriteString(f.Patch())
+	}
+	return b.String()
+}
+
+// Submodule reports whether the file is a submodule pointer change and, if
+// so, the old and new commits. Either commit is empty when the submodule was
+// added or removed.
+func (f *File) Submodule() (oldCommit, newCommit string, ok bool) {
+	for _, h := range f.Hunks {
+		for _, line := range h.Lines {
+			if len(line) == 0 {
+				continue
+			}
+			commit, found := strings.CutPrefix(line[1:], "Subproject commit ")
+			if !found {
+				continue
+			}
+			ok = true
+			commit = strings.TrimSuffix(commit, "-dirty")
+			switch line[0] {
+			case '-':
+				oldCommit = commit
+			case '+':
+				newCommit = commit
+			}
+		}
+	}
+	return oldCommit, newCommit, ok
+}
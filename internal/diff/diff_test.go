@@ -0,0 +1,197 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package diff
+
+import "testing"
+
+// sample covers the kinds of file change git diff reports.
+const sample = `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1,4 +1,5 @@ package main
+ import "fmt"
++import "os"
+ 
+ func main() {
+ 	fmt.Println("hi")
+@@ -20,3 +21,2 @@ func helper() {
+-	// old comment
+-- a removed line that looks like a header
++	return
+diff --git a/old name.txt b/new name.txt
+similarity index 90%
+rename from old name.txt
+rename to new name.txt
+index 3333333..4444444 100644
+--- a/old name.txt
++++ b/new name.txt
+@@ -1 +1 @@
+-hello
++hello, world
+diff --git a/logo.png b/logo.png
+index 5555555..6666666 100644
+Binary files a/logo.png and b/logo.png differ
+diff --git a/added.go b/added.go
+new file mode 100644
+index 0000000..7777777
+--- /dev/null
++++ b/added.go
+@@ -0,0 +1,2 @@
++package added
++
+diff --git a/gone.go b/gone.go
+deleted file mode 100644
+index 8888888..0000000
+--- a/gone.go
++++ /dev/null
+@@ -1 +0,0 @@
+-package gone
+`
+
+func TestParse(t *testing.T) {
+	d := Parse(sample)
+
+	tests := []struct {
+		path             string
+		oldPath, newPath string
+		status           string
+		renamed, binary  bool
+		hunks            int
+		added, removed   int
+	}{
+		{path: "main.go", oldPath: "main.go", newPath: "main.go", status: "modified", hunks: 2, added: 2, removed: 2},
+		{path: "new name.txt", oldPath: "old name.txt", newPath: "new name.txt", status: "renamed", renamed: true, hunks: 1, added: 1, removed: 1},
+		{path: "logo.png", oldPath: "logo.png", newPath: "logo.png", status: "modified", binary: true},
+		{path: "added.go", newPath: "added.go", status: "added", hunks: 1, added: 2},
+		{path: "gone.go", oldPath: "gone.go", status: "deleted", hunks: 1, removed: 1},
+	}
+	if len(d.Files) != len(tests) {
+		t.Fatalf("Parse() found %d files, want %d", len(d.Files), len(tests))
+	}
+	for i, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			f := d.Files[i]
+			if f.Path() != tt.path || f.OldPath != tt.oldPath || f.NewPath != tt.newPath {
+				t.Errorf("paths = %q (%q -> %q), want %q (%q -> %q)", f.Path(), f.OldPath, f.NewPath, tt.path, tt.oldPath, tt.newPath)
+			}
+			if f.Status() != tt.status {
+				t.Errorf("Status() = %q, want %q", f.Status(), tt.status)
+			}
+			if f.Renamed != tt.renamed || f.Binary != tt.binary {
+				t.Errorf("Renamed, Binary = %v, %v, want %v, %v", f.Renamed, f.Binary, tt.renamed, tt.binary)
+			}
+			if len(f.Hunks) != tt.hunks {
+				t.Errorf("%d hunks, want %d", len(f.Hunks), tt.hunks)
+			}
+			if f.Added() != tt.added || f.Removed() != tt.removed {
+				t.Errorf("+%d -%d, want +%d -%d", f.Added(), f.Removed(), tt.added, tt.removed)
+			}
+		})
+	}
+
+	if got, want := d.Added(), 5; got != want {
+		t.Errorf("Diff.Added() = %d, want %d", got, want)
+	}
+	if got, want := d.Removed(), 4; got != want {
+		t.Errorf("Diff.Removed() = %d, want %d", got, want)
+	}
+}
+
+func TestParseHunkHeaders(t *testing.T) {
+	hunks := Parse(sample).Files[0].Hunks
+	tests := []struct {
+		oldStart, oldLines int
+		newStart, newLines int
+		section            string
+	}{
+		{1, 4, 1, 5, "package main"},
+		{20, 3, 21, 2, "func helper() {"},
+	}
+	for i, tt := range tests {
+		h := hunks[i]
+		if h.OldStart != tt.oldStart || h.OldLines != tt.oldLines || h.NewStart != tt.newStart || h.NewLines != tt.newLines {
+			t.Errorf("hunk %d range = -%d,%d +%d,%d, want -%d,%d +%d,%d", i, h.OldStart, h.OldLines, h.NewStart, h.NewLines,
+				tt.oldStart, tt.oldLines, tt.newStart, tt.newLines)
+		}
+		if h.Section != tt.section {
+			t.Errorf("hunk %d section = %q, want %q", i, h.Section, tt.section)
+		}
+	}
+
+	// A count left out means one line
+	h := Parse(sample).Files[1].Hunks[0]
+	if h.OldStart != 1 || h.OldLines != 1 || h.NewStart != 1 || h.NewLines != 1 {
+		t.Errorf("short range = -%d,%d +%d,%d, want -1,1 +1,1", h.OldStart, h.OldLines, h.NewStart, h.NewLines)
+	}
+}
+
+func TestParsePatchRoundTrip(t *testing.T) {
+	if got := Parse(sample).Patch(); got != sample {
+		t.Errorf("Patch() does not reproduce the input:\n%s", got)
+	}
+}
+
+func TestParseNotADiff(t *testing.T) {
+	for _, raw := range []string{"", "hello\nworld\n", "commit abc\nAuthor: x\n"} {
+		if d := Parse(raw); len(d.Files) != 0 {
+			t.Errorf("Parse(%q) found %d files, want none", raw, len(d.Files))
+		}
+	}
+}
+
+func TestFileSubmodule(t *testing.T) {
+	const (
+		oldCommit = "1111111111111111111111111111111111111111"
+		newCommit = "2222222222222222222222222222222222222222"
+	)
+	tests := []struct {
+		name     string
+		patch    string
+		wantOld  string
+		wantNew  string
+		wantBump bool
+	}{
+		{
+			name: "bump",
+			patch: "diff --git a/lib b/lib\nindex 1111111..2222222 160000\n--- a/lib\n+++ b/lib\n@@ -1 +1 @@\n" +
+				"-Subproject commit " + oldCommit + "\n+Subproject commit " + newCommit + "\n",
+			wantOld: oldCommit, wantNew: newCommit, wantBump: true,
+		},
+		{
+			name: "dirty worktree",
+			patch: "diff --git a/lib b/lib\n--- a/lib\n+++ b/lib\n@@ -1 +1 @@\n" +
+				"-Subproject commit " + oldCommit + "\n+Subproject commit " + newCommit + "-dirty\n",
+			wantOld: oldCommit, wantNew: newCommit, wantBump: true,
+		},
+		{
+			name: "added",
+			patch: "diff --git a/lib b/lib\nnew file mode 160000\n--- /dev/null\n+++ b/lib\n@@ -0,0 +1 @@\n" +
+				"+Subproject commit " + newCommit + "\n",
+			wantNew: newCommit, wantBump: true,
+		},
+		{
+			name: "removed",
+			patch: "diff --git a/lib b/lib\ndeleted file mode 160000\n--- a/lib\n+++ /dev/null\n@@ -1 +0,0 @@\n" +
+				"-Subproject commit " + oldCommit + "\n",
+			wantOld: oldCommit, wantBump: true,
+		},
+		{
+			name:  "regular file",
+			patch: "diff --git a/notes.txt b/notes.txt\n--- a/notes.txt\n+++ b/notes.txt\n@@ -1 +1 @@\n-old\n+new\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Parse(tt.patch)
+			if len(d.Files) != 1 {
+				t.Fatalf("Parse() found %d files, want 1", len(d.Files))
+			}
+			oldCommit, newCommit, ok := d.Files[0].Submodule()
+			if oldCommit != tt.wantOld || newCommit != tt.wantNew || ok != tt.wantBump {
+				t.Errorf("Submodule() = %q, %q, %v; want %q, %q, %v", oldCommit, newCommit, ok, tt.wantOld, tt.wantNew, tt.wantBump)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package diff
+
+// Filter returns a copy of the diff with only the hunks keep accepts, in
+// order. Files without hunks, such as binary files and pure renames, are
+// passed to keep with a nil hunk. Files left with nothing are dropped.
+func Filter(d *Diff, keep func(f *File, h *Hunk) bool) *Diff {
+	filtered := &Diff{}
+	for _, f := range d.Files {
+		if len(f.Hunks) == 0 {
+			if keep(f, nil) {
+				filtered.Files = append(filtered.Files, f)
+			}
+			continue
+		}
+
+		copied := *f
+		copied.Hunks = nil
+		for _, h := range f.Hunks {
+			if keep(f, h) {
+				copied.Hunks = append(copied.Hunks, h)
+			}
+		}
+		if len(copied.Hunks) > 0 {
+			filtered.Files = append(filtered.Files, &copied)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package diff
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFilter(t *testing.T) {
+	tests := []struct {
+		name      string
+		keep      func(f *File, h *Hunk) bool
+		wantPaths []string
+		// wantHunks is the number of hunks left in main.go.
+		wantHunks int
+	}{
+		{
+			name:      "everything",
+			keep:      func(*File, *Hunk) bool { return true },
+			wantPaths: []string{"main.go", "new name.txt", "logo.png", "added.go", "gone.go"},
+			wantHunks: 2,
+		},
+		{
+			name:      "one hunk dropped",
+			keep:      func(f *File, h *Hunk) bool { return f.Path() != "main.go" || h.OldStart != 1 },
+			wantPaths: []string{"main.go", "new name.txt", "logo.png", "added.go", "gone.go"},
+			wantHunks: 1,
+		},
+		{
+			name:      "files without hunks",
+			keep:      func(_ *File, h *Hunk) bool { return h == nil },
+			wantPaths: []string{"logo.png"},
+		},
+		{
+			name: "nothing",
+			keep: func(*File, *Hunk) bool { return false },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Parse(sample)
+			filtered := Filter(d, tt.keep)
+			if got := paths(filtered); !slices.Equal(got, tt.wantPaths) {
+				t.Fatalf("Filter() kept %q, want %q", got, tt.wantPaths)
+			}
+			if tt.wantHunks > 0 && len(filtered.Files[0].Hunks) != tt.wantHunks {
+				t.Errorf("main.go kept %d hunks, want %d", len(filtered.Files[0].Hunks), tt.wantHunks)
+			}
+			if len(d.Files[0].Hunks) != 2 {
+				t.Errorf("Filter() changed the original diff")
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Format renders the diff compactly for a prompt: a summary line, then each
+// file with its status and line counts followed by its hunks. Index lines,
+// modes and a/ b/ prefixes are dropped since they carry no meaning for the
+// model.
+func Format(d *Diff) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d file(s) changed, +%d -%d\n", len(d.Files), d.Added(), d.Removed())
+
+	for _, f := range d.Files {
+		b.WriteString("\n" + FileHeading(f) + "\n")
+		for _, h := range f.Hunks {
+			b.WriteString(h.Patch())
+		}
+	}
+
+	return b.String()
+}
+
+// FileHeading renders the one-line summary Format prints above a file.
+func FileHeading(f *File) string {
+	var details []string
+	details = append(details, f.Status())
+	if f.Renamed && f.OldPath != "" {
+		details = append(details, "from "+f.OldPath)
+	}
+	if f.Binary {
+		details = append(details, "binary")
+	} else {
+		details = append(details, fmt.Sprintf("+%d -%d", f.Added(), f.Removed()))
+	}
+	return fmt.Sprintf("== %s (%s)", f.Path(), strings.Join(details, ", "))
+}
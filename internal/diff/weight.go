@@ -0,0 +1,55 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package diff
+
+import (
+	"cmp"
+	"slices"
+)
+
+// SortBySize returns a copy of the diff with files ordered by the number of
+// changed lines, largest first, and each file's hunks ordered the same way.
+// The result is meant for prompts; its patch no longer applies in order.
+func SortBySize(d *Diff) *Diff {
+	sorted := &Diff{Files: make([]*File, len(d.Files))}
+	for i, f := range d.Files {
+		copied := *f
+		copied.Hunks = slices.Clone(f.Hunks)
+		slices.SortStableFunc(copied.Hunks, func(a, b *Hunk) int {
+			return cmp.Compare(b.Added()+b.Removed(), a.Added()+a.Removed())
+		})
+		sorted.Files[i] = &copied
+	}
+	slices.SortStableFunc(sorted.Files, func(a, b *File) int {
+		return cmp.Compare(b.Changes(), a.Changes())
+	})
+	return sorted
+}
+
+// Largest splits the diff into the n files with the most changed lines and
+// the rest, both in their original order, so a prompt can show the most
+// informative files in full and only summarize the others.
+func Largest(d *Diff, n int) (top, rest *Diff) {
+	if n >= len(d.Files) {
+		return d, &Diff{}
+	}
+	bySize := slices.Clone(d.Files)
+	slices.SortStableFunc(bySize, func(a, b *File) int {
+		return cmp.Compare(b.Changes(), a.Changes())
+	})
+	keep := make(map[*File]bool, n)
+	for _, f := range bySize[:max(n, 0)] {
+		keep[f] = true
+	}
+
+	top, rest = &Diff{}, &Diff{}
+	for _, f := range d.Files {
+		if keep[f] {
+			top.Files = append(top.Files, f)
+		} else {
+			rest.Files = append(rest.Files, f)
+		}
+	}
+	return top, rest
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package diff
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// paths returns the paths of the diff's files in order.
+func paths(d *Diff) []string {
+	var names []string
+	for _, f := range d.Files {
+		names = append(names, f.Path())
+	}
+	return names
+}
+
+func TestSortBySize(t *testing.T) {
+	d := Parse(sample)
+	sorted := SortBySize(d)
+
+	want := []string{"main.go", "new name.txt", "added.go", "gone.go", "logo.png"}
+	if got := paths(sorted); !slices.Equal(got, want) {
+		t.Errorf("SortBySize() ordered %v, want %v", got, want)
+	}
+	if hunks := sorted.Files[0].Hunks; hunks[0].OldStart != 20 || hunks[1].OldStart != 1 {
+		t.Errorf("main.go hunks start at %d, %d; want the larger hunk at 20 first", hunks[0].OldStart, hunks[1].OldStart)
+	}
+
+	// The parsed diff keeps its order, for committing and display
+	if got := paths(d); !slices.Equal(got, []string{"main.go", "new name.txt", "logo.png", "added.go", "gone.go"}) {
+		t.Errorf("SortBySize() reordered its input to %v", got)
+	}
+	if d.Files[0].Hunks[0].OldStart != 1 {
+		t.Error("SortBySize() reordered the hunks of its input")
+	}
+}
+
+func TestLargest(t *testing.T) {
+	tests := []struct {
+		n        int
+		wantTop  []string
+		wantRest []string
+	}{
+		{n: 1, wantTop: []string{"main.go"}, wantRest: []string{"new name.txt", "logo.png", "added.go", "gone.go"}},
+		// Ties go to the file listed first
+		{n: 2, wantTop: []string{"main.go", "new name.txt"}, wantRest: []string{"logo.png", "added.go", "gone.go"}},
+		{n: 3, wantTop: []string{"main.go", "new name.txt", "added.go"}, wantRest: []string{"logo.png", "gone.go"}},
+		{n: 5, wantTop: []string{"main.go", "new name.txt", "logo.png", "added.go", "gone.go"}},
+		{n: 0, wantRest: []string{"main.go", "new name.txt", "logo.png", "added.go", "gone.go"}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.n), func(t *testing.T) {
+			top, rest := Largest(Parse(sample), tt.n)
+			if got := paths(top); !slices.Equal(got, tt.wantTop) {
+				t.Errorf("top = %v, want %v", got, tt.wantTop)
+			}
+			if got := paths(rest); !slices.Equal(got, tt.wantRest) {
+				t.Errorf("rest = %v, want %v", got, tt.wantRest)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package message
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Subject case modes accepted by ApplySubjectCase.
+const (
+	SubjectCaseLower    = "lower"
+	SubjectCaseSentence = "sentence"
+	SubjectCasePreserve = "preserve"
+)
+
+// SubjectCases lists the valid subject case modes.
+var SubjectCases = []string{SubjectCaseLower, SubjectCaseSentence, SubjectCasePreserve}
+
+// ApplySubjectCase sets the case of the first letter of a conventional
+// subject, e.g. "feat: Add x" becomes "feat: add x" in lower mode. Words
+// whose case carries meaning, such as "API", "iOS" or "go.mod", are left
+// alone, as are headers that are not conventional.
+func ApplySubjectCase(text, mode string) string {
+	m := Parse(text)
+	if !m.Conventional() || !caseable(m.Subject) {
+		return text
+	}
+	r, size := utf8.DecodeRuneInString(m.Subject)
+	var subject string
+	switch mode {
+	case SubjectCaseLower:
+		subject = string(unicode.ToLower(r)) + m.Subject[size:]
+	case SubjectCaseSentence:
+		subject = string(unicode.ToUpper(r)) + m.Subject[size:]
+	default:
+		return text
+	}
+
+	text = strings.TrimSpace(text)
+	header, rest, _ := strings.Cut(text, "\n")
+	header = strings.TrimSuffix(strings.TrimSpace(header), m.Subject) + subject
+	if rest == "" {
+		return header
+	}
+	return header + "\n" + rest
+}
+
+// subjectCaseMismatch reports whether subject's first word is caseable and
+// does not follow mode.
+func subjectCaseMismatch(subject, mode string) bool {
+	if !caseable(subject) {
+		return false
+	}
+	r, _ := utf8.DecodeRuneInString(subject)
+	switch mode {
+	case SubjectCaseLower:
+		return unicode.IsUpper(r)
+	case SubjectCaseSentence:
+		return unicode.IsLower(r)
+	}
+	return false
+}
+
+// caseable reports whether the first word of subject is an ordinary word
+// whose first letter may change case: letters only, with none but the
+// first in upper case.
+func caseable(subject string) bool {
+	word, _, _ := strings.Cut(subject, " ")
+	if word == "" {
+		return false
+	}
+	for i, r := range word {
+		if !unicode.IsLetter(r) || (i > 0 && unicode.IsUpper(r)) {
+			return false
+		}
+	}
+	return true
+}
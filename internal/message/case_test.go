@@ -0,0 +1,32 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package message
+
+import "testing"
+
+func TestApplySubjectCase(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		mode string
+		want string
+	}{
+		{name: "lower", text: "feat: Add retries", mode: SubjectCaseLower, want: "feat: add retries"},
+		{name: "sentence", text: "feat(api): add retries", mode: SubjectCaseSentence, want: "feat(api): Add retries"},
+		{name: "preserve", text: "feat: Add retries", mode: SubjectCasePreserve, want: "feat: Add retries"},
+		{name: "body kept", text: "fix: Handle nil\n\nThe Config may be nil.", mode: SubjectCaseLower, want: "fix: handle nil\n\nThe Config may be nil."},
+		{name: "acronym", text: "feat: API keys in headers", mode: SubjectCaseLower, want: "feat: API keys in headers"},
+		{name: "mixed case name", text: "fix: iOS share sheet", mode: SubjectCaseSentence, want: "fix: iOS share sheet"},
+		{name: "file name", text: "chore: go.mod tidy", mode: SubjectCaseSentence, want: "chore: go.mod tidy"},
+		{name: "not conventional", text: "Add retries", mode: SubjectCaseLower, want: "Add retries"},
+		{name: "non-ASCII", text: "docs: Évite les doublons", mode: SubjectCaseLower, want: "docs: évite les doublons"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ApplySubjectCase(tt.text, tt.mode); got != tt.want {
+				t.Errorf("ApplySubjectCase(%q, %q) = %q, want %q", tt.text, tt.mode, got, tt.want)
+			}
+		})
+	}
+}
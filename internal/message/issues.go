@@ -0,0 +1,48 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package message
+
+import (
+	"regexp"
+	"strings"
+)
+
+// issueRefPattern matches issue references: "#123", "owner/repo#123" and
+// tracker keys such as "ABC-123".
+var issueRefPattern = regexp.MustCompile(`(?:^|[^\w/#-])((?:[\w.-]+/[\w.-]+)?#\d+|[A-Z][A-Z0-9]+-\d+)\b`)
+
+// IssueRefs returns the distinct issue references in text, in the order
+// they first appear.
+func IssueRefs(text string) []string {
+	var refs []string
+	for _, m := range issueRefPattern.FindAllStringSubmatch(text, -1) {
+		ref := m[1]
+		if !containsRef(refs, ref) {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// MissingIssueRefs returns the refs that text does not mention.
+func MissingIssueRefs(text string, refs []string) []string {
+	present := IssueRefs(text)
+	var missing []string
+	for _, ref := range refs {
+		if !containsRef(present, ref) {
+			missing = append(missing, ref)
+		}
+	}
+	return missing
+}
+
+// containsRef reports whether refs includes ref, ignoring case.
+func containsRef(refs []string, ref string) bool {
+	for _, r := range refs {
+		if strings.EqualFold(r, ref) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+// Package message parses and validates commit messages.
+package message
+
+import (
+	"regexp"
+	"strings"
+)
+
+// headerPattern matches a conventional commit header: type(scope)!: subject.
+var headerPattern = regexp.MustCompile(`^([a-zA-Z]+)(?:\(([^()]*)\))?(!)?: (.*)$`)
+
+// Message is a commit message split into its conventional commit parts.
+type Message struct {
+	// Header is the full first line.
+	Header string
+	// Type is the conventional commit type, e.g. "feat". Empty when the
+	// header does not follow the conventional format.
+	Type string
+	// Scope is the optional scope between parentheses.
+	Scope string
+	// Breaking reports whether the header carries the "!" marker.
+	Breaking bool
+	// Subject is the description after "type(scope): ", or the whole header
+	// when it is not conventional.
+	Subject string
+	// Body is everything after the header and its separating blank line.
+	Body string
+	// Separated reports whether the header is followed by a blank line
+	// (or nothing at all).
+	Separated bool
+}
+
+// Conventional reports whether the header follows the conventional format.
+func (m Message) Conventional() bool {
+	return m.Type != ""
+}
+
+// Merge reports whether the header is one git writes for merge commits,
+// such as "Merge branch 'topic'".
+func (m Message) Merge() bool {
+	return strings.HasPrefix(m.Header, "Merge ")
+}
+
+// Parse splits a commit message into its parts.
+func Parse(text string) Message {
+	text = strings.TrimSpace(text)
+	header, rest, _ := strings.Cut(text, "\n")
+
+	m := Message{
+		Header:    strings.TrimSpace(header),
+		Separated: rest == "" || strings.TrimSpace(strings.SplitN(rest, "\n", 2)[0]) == "",
+		Body:      strings.TrimSpace(rest),
+	}
+
+	if match := headerPattern.FindStringSubmatch(m.Header); match != nil {
+		m.Type = match[1]
+		m.Scope = match[2]
+		m.Breaking = match[3] == "!"
+		m.Subject = match[4]
+	} else {
+		m.Subject = m.Header
+	}
+
+	return m
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package message
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want Message
+	}{
+		{
+			name: "header only",
+			text: "feat: add login page\n",
+			want: Message{Header: "feat: add login page", Type: "feat", Subject: "add login page", Separated: true},
+		},
+		{
+			name: "scope, breaking and body",
+			text: "feat(api)!: drop v1 endpoints\n\nClients must move to v2.",
+			want: Message{
+				Header: "feat(api)!: drop v1 endpoints", Type: "feat", Scope: "api", Breaking: true,
+				Subject: "drop v1 endpoints", Body: "Clients must move to v2.", Separated: true,
+			},
+		},
+		{
+			name: "body without a blank line",
+			text: "fix: handle empty config\nIt crashed.",
+			want: Message{Header: "fix: handle empty config", Type: "fix", Subject: "handle empty config", Body: "It crashed."},
+		},
+		{
+			name: "not conventional",
+			text: "Update the readme",
+			want: Message{Header: "Update the readme", Subject: "Update the readme", Separated: true},
+		},
+		{
+			name: "no space after the colon",
+			text: "fix:handle empty config",
+			want: Message{Header: "fix:handle empty config", Subject: "fix:handle empty config", Separated: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.text); got != tt.want {
+				t.Errorf("Parse(%q) =\n%+v\nwant\n%+v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package message
+
+import "strings"
+
+// NormalizeOptions controls Normalize.
+type NormalizeOptions struct {
+	// KeepBlankLines preserves runs of blank lines inside the body instead
+	// of collapsing each run to a single blank line.
+	KeepBlankLines bool
+}
+
+// Normalize tidies a message's whitespace: trailing spaces are trimmed,
+// leading and trailing blank lines dropped, the header is followed by
+// exactly one blank line, and runs of blank lines in the body are collapsed
+// unless KeepBlankLines is set.
+func Normalize(text string, opts NormalizeOptions) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+
+	header := lines[0]
+	var body []string
+	for _, line := range lines[1:] {
+		blank := line == ""
+		if blank && len(body) == 0 {
+			// Blank lines between header and body are replaced below
+			continue
+		}
+		if blank && !opts.KeepBlankLines && body[len(body)-1] == "" {
+			continue
+		}
+		body = append(body, line)
+	}
+
+	if len(body) == 0 {
+		return header
+	}
+	return header + "\n\n" + strings.Join(body, "\n")
+}
+
+// Line ending modes accepted by ConvertLineEndings.
+const (
+	LineEndingsLF       = "lf"
+	LineEndingsCRLF     = "crlf"
+	LineEndingsPreserve = "preserve"
+)
+
+// LineEndingModes lists the valid line ending modes.
+var LineEndingModes = []string{LineEndingsLF, LineEndingsCRLF, LineEndingsPreserve}
+
+// HasCRLF reports whether text uses Windows line endings.
+func HasCRLF(text string) bool {
+	return strings.Contains(text, "\r\n")
+}
+
+// ToLF converts CRLF and lone CR line endings to LF.
+func ToLF(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.ReplaceAll(text, "\r", "\n")
+}
+
+// ConvertLineEndings rewrites an LF message for the given mode. For
+// LineEndingsPreserve the message is converted to CRLF only when original
+// used CRLF.
+func ConvertLineEndings(text, original, mode string) string {
+	if mode == LineEndingsCRLF || (mode == LineEndingsPreserve && HasCRLF(original)) {
+		return strings.ReplaceAll(text, "\n", "\r\n")
+	}
+	return text
+}
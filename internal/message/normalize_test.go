@@ -0,0 +1,99 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package message
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		keep bool
+		want string
+	}{
+		{
+			name: "header only",
+			text: "\n\nfeat: add login page  \n\n",
+			want: "feat: add login page",
+		},
+		{
+			name: "missing blank line",
+			text: "feat: add login page\nAdds the form.",
+			want: "feat: add login page\n\nAdds the form.",
+		},
+		{
+			name: "extra blank lines after the header",
+			text: "feat: add login page\n\n\n\nAdds the form.",
+			want: "feat: add login page\n\nAdds the form.",
+		},
+		{
+			name: "trailing whitespace",
+			text: "feat: add login page \t\n\nAdds the form.  \n- and its handler\t",
+			want: "feat: add login page\n\nAdds the form.\n- and its handler",
+		},
+		{
+			name: "runs of blank lines collapsed",
+			text: "feat: add login page\n\nAdds the form.\n\n\n\nRefs: #42",
+			want: "feat: add login page\n\nAdds the form.\n\nRefs: #42",
+		},
+		{
+			name: "runs of blank lines kept",
+			text: "feat: add login page\n\nAdds the form.\n\n\n\nRefs: #42",
+			keep: true,
+			want: "feat: add login page\n\nAdds the form.\n\n\n\nRefs: #42",
+		},
+		{
+			name: "whitespace-only lines are blank",
+			text: "feat: add login page\n  \nAdds the form.\n \t \n\nRefs: #42",
+			want: "feat: add login page\n\nAdds the form.\n\nRefs: #42",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Normalize(tt.text, NormalizeOptions{KeepBlankLines: tt.keep}); got != tt.want {
+				t.Errorf("Normalize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToLF(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: "feat: a\n\nbody", want: "feat: a\n\nbody"},
+		{text: "feat: a\r\n\r\nbody\r\n", want: "feat: a\n\nbody\n"},
+		{text: "feat: a\r\rbody", want: "feat: a\n\nbody"},
+		{text: "feat: a\r\n\nbody\rmore", want: "feat: a\n\nbody\nmore"},
+	}
+	for _, tt := range tests {
+		if got := ToLF(tt.text); got != tt.want {
+			t.Errorf("ToLF(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestConvertLineEndings(t *testing.T) {
+	const text = "feat: a\n\nbody"
+	tests := []struct {
+		name     string
+		original string
+		mode     string
+		want     string
+	}{
+		{name: "lf", original: "feat: a\r\n\r\nbody", mode: LineEndingsLF, want: text},
+		{name: "crlf", original: text, mode: LineEndingsCRLF, want: "feat: a\r\n\r\nbody"},
+		{name: "preserve lf", original: text, mode: LineEndingsPreserve, want: text},
+		{name: "preserve crlf", original: "feat: a\r\n\r\nbody", mode: LineEndingsPreserve, want: "feat: a\r\n\r\nbody"},
+		{name: "preserve mixed", original: "feat: a\n\r\nbody", mode: LineEndingsPreserve, want: "feat: a\r\n\r\nbody"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ConvertLineEndings(text, tt.original, tt.mode); got != tt.want {
+				t.Errorf("ConvertLineEndings() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package message
+
+import (
+	"regexp"
+	"strings"
+)
+
+// preambles start lines models put before the message despite being told
+// to output only the message, e.g. "Here's a commit message:".
+var preambles = []string{
+	"here is", "here's", "here are", "sure", "certainly", "okay", "ok,",
+	"below is", "the commit message", "commit message", "suggested commit message",
+}
+
+// StripArtifacts removes wrapping that models sometimes add around a commit
+// message: an introductory line such as "Here's a commit message:", a
+// markdown code fence (and any commentary after it), and quotes around the
+// whole message.
+func StripArtifacts(text string) string {
+	text = strings.TrimSpace(ToLF(text))
+	for {
+		stripped := stripQuotes(stripFence(stripPreamble(text)))
+		if stripped == text {
+			return text
+		}
+		text = stripped
+	}
+}
+
+// stripPreamble drops a first line that introduces the message rather than
+// being part of it. A "Commit message: feat: ..." label on the header line
+// is removed as well.
+func stripPreamble(text string) string {
+	first, rest, _ := strings.Cut(text, "\n")
+	label := strings.ToLower(strings.Trim(first, "*_# "))
+
+	for _, p := range preambles {
+		if !strings.HasPrefix(label, p) {
+			continue
+		}
+		switch {
+		case strings.HasSuffix(label, ":") || strings.HasSuffix(label, "!") || strings.HasSuffix(label, "."):
+			if strings.TrimSpace(rest) != "" {
+				return strings.TrimSpace(rest)
+			}
+		case strings.Contains(p, "message"):
+			// "Commit message: feat: add x" keeps the header after the label
+			if _, after, ok := strings.Cut(first, ":"); ok {
+				return strings.TrimSpace(strings.TrimLeft(after, "*_ ") + "\n" + rest)
+			}
+		}
+	}
+	return text
+}
+
+// stripFence unwraps a message that starts with a markdown code fence,
+// dropping anything after the closing fence.
+func stripFence(text string) string {
+	if !strings.HasPrefix(text, "```") {
+		return text
+	}
+	_, inner, ok := strings.Cut(text, "\n")
+	if !ok {
+		return text
+	}
+	if i := strings.Index("\n"+inner, "\n```"); i >= 0 {
+		return strings.TrimSpace(inner[:max(i-1, 0)])
+	}
+	return strings.TrimSpace(inner)
+}
+
+// stripQuotes removes quotes or backticks around the whole message.
+func stripQuotes(text string) string {
+	for _, q := range []string{`"""`, `"`, "'", "`"} {
+		if len(text) > 2*len(q) && strings.HasPrefix(text, q) && strings.HasSuffix(text, q) {
+			inner := text[len(q) : len(text)-len(q)]
+			if !strings.Contains(inner, q) {
+				return strings.TrimSpace(inner)
+			}
+		}
+	}
+	return text
+}
+
+// Inline markdown that reads badly in a plain-text subject. Underscores are
+// left alone, as they are common in identifiers such as __init__.
+var (
+	strongPattern   = regexp.MustCompile(`\*\*(\S(?:.*?\S)?)\*\*`)
+	emphasisPattern = regexp.MustCompile(`\*(\S(?:[^*]*?\S)?)\*`)
+)
+
+// StripSubjectMarkdown removes inline markdown (code backticks, bold and
+// italic asterisks) from the header line, so the subject reads cleanly in
+// git log. The body is left as is, since forges render it as markdown.
+func StripSubjectMarkdown(text string) string {
+	header, rest, found := strings.Cut(text, "\n")
+	header = strings.ReplaceAll(header, "`", "")
+	header = strongPattern.ReplaceAllString(header, "$1")
+	header = emphasisPattern.ReplaceAllString(header, "$1")
+	if !found {
+		return header
+	}
+	return header + "\n" + rest
+}
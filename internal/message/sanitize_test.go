@@ -0,0 +1,138 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package message
+
+import "testing"
+
+func TestStripSubjectMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "plain subject",
+			text: "fix(api): handle empty body",
+			want: "fix(api): handle empty body",
+		},
+		{
+			name: "code span",
+			text: "fix: handle nil in `Parse`",
+			want: "fix: handle nil in Parse",
+		},
+		{
+			name: "bold and italic",
+			text: "feat: **always** retry *idempotent* requests",
+			want: "feat: always retry idempotent requests",
+		},
+		{
+			name: "mixed markers",
+			text: "refactor: rename `**cfg**` to *`config`*",
+			want: "refactor: rename cfg to config",
+		},
+		{
+			name: "identifiers with underscores and asterisks",
+			text: "fix: keep __init__ and a * b",
+			want: "fix: keep __init__ and a * b",
+		},
+		{
+			name: "unbalanced asterisk",
+			text: "docs: explain *glob patterns",
+			want: "docs: explain *glob patterns",
+		},
+		{
+			name: "body keeps its markdown",
+			text: "feat: add `--dry-run`\n\nPrints what **would** be done with `--dry-run`.",
+			want: "feat: add --dry-run\n\nPrints what **would** be done with `--dry-run`.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StripSubjectMarkdown(tt.text); got != tt.want {
+				t.Errorf("StripSubjectMarkdown() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripArtifacts(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "clean message",
+			text: "feat: add login page\n\nAdds the form.",
+			want: "feat: add login page\n\nAdds the form.",
+		},
+		{
+			name: "code fence",
+			text: "```\nfix: handle nil config\n```",
+			want: "fix: handle nil config",
+		},
+		{
+			name: "labeled code fence with commentary after it",
+			text: "```text\nfix: handle nil config\n\nThe loader returned nil.\n```\n\nLet me know if you want changes.",
+			want: "fix: handle nil config\n\nThe loader returned nil.",
+		},
+		{
+			name: "preamble",
+			text: "Here's a commit message for these changes:\n\nfeat: add retries",
+			want: "feat: add retries",
+		},
+		{
+			name: "bold preamble and fence",
+			text: "**Sure!**\n```\nchore: bump deps\n```",
+			want: "chore: bump deps",
+		},
+		{
+			name: "label on the header line",
+			text: "Commit message: feat(api): add pagination\n\nPages hold 50 items.",
+			want: "feat(api): add pagination\n\nPages hold 50 items.",
+		},
+		{
+			name: "double quotes",
+			text: "\"fix: typo in README\"",
+			want: "fix: typo in README",
+		},
+		{
+			name: "quotes inside the message are kept",
+			text: "docs: quote the \"why\" section",
+			want: "docs: quote the \"why\" section",
+		},
+		{
+			name: "triple quotes",
+			text: "\"\"\"\nrefactor: split parser\n\"\"\"",
+			want: "refactor: split parser",
+		},
+		{
+			name: "backticks around the whole message",
+			text: "`test: cover empty input`",
+			want: "test: cover empty input",
+		},
+		{
+			name: "preamble, fence and quotes together",
+			text: "Here is the commit message:\n```\n'perf: cache lookups'\n```",
+			want: "perf: cache lookups",
+		},
+		{
+			name: "CRLF and surrounding space",
+			text: "\r\n  ```\r\nfix: trim input\r\n```  \r\n",
+			want: "fix: trim input",
+		},
+		{
+			name: "header that only starts like a preamble",
+			text: "sure-footed: keep header",
+			want: "sure-footed: keep header",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StripArtifacts(tt.text); got != tt.want {
+				t.Errorf("StripArtifacts() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package message
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Structured is the JSON form of a commit message and its validation
+// issues. Schema is derived from this type, so the two cannot drift apart;
+// every field needs a json and a desc tag.
+type Structured struct {
+	Header   string              `json:"header" desc:"The full first line"`
+	Type     string              `json:"type" desc:"Conventional commit type, e.g. feat; empty when the header is not conventional"`
+	Scope    string              `json:"scope" desc:"Scope between parentheses; empty when there is none"`
+	Breaking bool                `json:"breaking" desc:"Whether the header carries the ! marker"`
+	Subject  string              `json:"subject" desc:"Description after the type and scope, or the whole header when it is not conventional"`
+	Body     string              `json:"body" desc:"Text between the header and the trailers"`
+	Trailers []StructuredTrailer `json:"trailers" desc:"Key: value lines ending the message, in order"`
+	Issues   []StructuredIssue   `json:"issues" desc:"Validation findings"`
+}
+
+// StructuredTrailer is the JSON form of a Trailer.
+type StructuredTrailer struct {
+	Key   string `json:"key" desc:"Trailer key, e.g. Signed-off-by"`
+	Value string `json:"value" desc:"Trailer value"`
+}
+
+// StructuredIssue is the JSON form of an Issue.
+type StructuredIssue struct {
+	Rule     string `json:"rule" desc:"Name of the rule, as accepted by --disable-rule"`
+	Severity string `json:"severity" desc:"error or warning" enum:"error,warning"`
+	Message  string `json:"message" desc:"What is wrong"`
+}
+
+// Structure splits text into its structured form, with the given issues.
+func Structure(text string, issues []Issue) Structured {
+	rest, trailers := SplitTrailers(text)
+	m := Parse(rest)
+	s := Structured{
+		Header:   m.Header,
+		Type:     m.Type,
+		Scope:    m.Scope,
+		Breaking: m.Breaking,
+		Subject:  m.Subject,
+		Body:     m.Body,
+		Trailers: []StructuredTrailer{},
+		Issues:   []StructuredIssue{},
+	}
+	for _, t := range trailers {
+		s.Trailers = append(s.Trailers, StructuredTrailer{Key: t.Key, Value: t.Value})
+	}
+	for _, i := range issues {
+		s.Issues = append(s.Issues, StructuredIssue{Rule: i.Rule, Severity: i.Severity.String(), Message: i.Message})
+	}
+	return s
+}
+
+// Schema returns the JSON Schema of Structured.
+func Schema() map[string]any {
+	schema := schemaFor(reflect.TypeOf(Structured{}))
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["title"] = "arc-commit message"
+	return schema
+}
+
+// schemaFor describes a Go type as a JSON Schema. Only the kinds used by
+// Structured are supported.
+func schemaFor(t reflect.Type) map[string]any {
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": schemaFor(t.Elem())}
+	case reflect.Struct:
+		properties := map[string]any{}
+		var required []string
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			prop := schemaFor(f.Type)
+			if desc := f.Tag.Get("desc"); desc != "" {
+				prop["description"] = desc
+			}
+			if enum := f.Tag.Get("enum"); enum != "" {
+				prop["enum"] = strings.Split(enum, ",")
+			}
+			properties[name] = prop
+			required = append(required, name)
+		}
+		return map[string]any{
+			"type":                 "object",
+			"properties":           properties,
+			"required":             required,
+			"additionalProperties": false,
+		}
+	}
+	return map[string]any{"type": "string"}
+}
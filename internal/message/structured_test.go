@@ -0,0 +1,80 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package message
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestStructure(t *testing.T) {
+	text := "feat(api)!: drop v1 endpoints\n\nClients moved to v2 last year.\n\nRefs: #12\nSigned-off-by: Jane Doe <jane@example.com>"
+	issues := []Issue{{Rule: "subject-length", Severity: Warning, Message: "too long"}}
+	got := Structure(text, issues)
+	want := Structured{
+		Header:   "feat(api)!: drop v1 endpoints",
+		Type:     "feat",
+		Scope:    "api",
+		Breaking: true,
+		Subject:  "drop v1 endpoints",
+		Body:     "Clients moved to v2 last year.",
+		Trailers: []StructuredTrailer{{Key: "Refs", Value: "#12"}, {Key: "Signed-off-by", Value: "Jane Doe <jane@example.com>"}},
+		Issues:   []StructuredIssue{{Rule: "subject-length", Severity: "warning", Message: "too long"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Structure() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestStructureEmptyListsEncodeAsArrays(t *testing.T) {
+	data, err := json.Marshal(Structure("Add login page", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"trailers", "issues"} {
+		if _, ok := decoded[key].([]any); !ok {
+			t.Errorf("%s = %v, want an empty array", key, decoded[key])
+		}
+	}
+	if decoded["subject"] != "Add login page" || decoded["type"] != "" {
+		t.Errorf("non-conventional header decoded as %v", decoded)
+	}
+}
+
+func TestSchema(t *testing.T) {
+	schema := Schema()
+	properties := schema["properties"].(map[string]any)
+
+	// Every field of the output is described and required
+	typ := reflect.TypeOf(Structured{})
+	for i := 0; i < typ.NumField(); i++ {
+		name := typ.Field(i).Tag.Get("json")
+		prop, ok := properties[name].(map[string]any)
+		if !ok {
+			t.Errorf("schema has no property %q", name)
+			continue
+		}
+		if prop["description"] == "" || prop["description"] == nil {
+			t.Errorf("property %q has no description", name)
+		}
+		if !slices.Contains(schema["required"].([]string), name) {
+			t.Errorf("property %q is not required", name)
+		}
+	}
+
+	issues := properties["issues"].(map[string]any)
+	severity := issues["items"].(map[string]any)["properties"].(map[string]any)["severity"].(map[string]any)
+	if !slices.Equal(severity["enum"].([]string), []string{"error", "warning"}) {
+		t.Errorf("severity enum = %v", severity["enum"])
+	}
+	if properties["breaking"].(map[string]any)["type"] != "boolean" {
+		t.Errorf("breaking = %v, want a boolean", properties["breaking"])
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package message
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// trailerKeyPattern matches a valid git trailer token.
+var trailerKeyPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]*$`)
+
+// Trailer is a "Key: value" line at the end of a commit message, such as
+// Co-authored-by or Signed-off-by.
+type Trailer struct {
+	Key   string
+	Value string
+}
+
+// String renders the trailer as it appears in a message.
+func (t Trailer) String() string {
+	return t.Key + ": " + t.Value
+}
+
+// ParseTrailer parses a single "Key: value" line.
+func ParseTrailer(line string) (Trailer, error) {
+	key, value, found := strings.Cut(strings.TrimSpace(line), ":")
+	if !found {
+		return Trailer{}, fmt.Errorf("trailer %q must look like \"Key: value\"", line)
+	}
+	key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+	if !trailerKeyPattern.MatchString(key) {
+		return Trailer{}, fmt.Errorf("trailer key %q may only contain letters, digits and dashes", key)
+	}
+	if value == "" {
+		return Trailer{}, fmt.Errorf("trailer %q has no value", key)
+	}
+	return Trailer{Key: key, Value: value}, nil
+}
+
+// SplitTrailers separates the trailer block from the rest of a message. The
+// trailer block is the final paragraph when every line in it is a trailer;
+// the header is never treated as one.
+func SplitTrailers(text string) (rest string, trailers []Trailer) {
+	text = strings.TrimSpace(text)
+	i := strings.LastIndex(text, "\n\n")
+	if i < 0 {
+		return text, nil
+	}
+
+	for _, line := range strings.Split(text[i+2:], "\n") {
+		t, err := ParseTrailer(line)
+		if err != nil {
+			return text, nil
+		}
+		trailers = append(trailers, t)
+	}
+	return strings.TrimSpace(text[:i]), trailers
+}
+
+// WithTrailers appends a trailer block to a message that has none.
+func WithTrailers(rest string, trailers []Trailer) string {
+	if len(trailers) == 0 {
+		return rest
+	}
+	lines := make([]string, len(trailers))
+	for i, t := range trailers {
+		lines[i] = t.String()
+	}
+	return strings.TrimSpace(rest) + "\n\n" + strings.Join(lines, "\n")
+}
+
+// identityPattern matches a git identity, "Name <email>".
+var identityPattern = regexp.MustCompile(`^[^<>\n]*[^<>\s][^<>\n]* <[^<>\s@]+@[^<>\s@]+>$`)
+
+// CoAuthor returns the Co-authored-by trailer crediting identity, which
+// must look like "Jane Doe <jane@example.com>".
+func CoAuthor(identity string) (Trailer, error) {
+	identity = strings.Join(strings.Fields(identity), " ")
+	if !identityPattern.MatchString(identity) {
+		return Trailer{}, fmt.Errorf("co-author %q must look like \"Name <email>\"", identity)
+	}
+	return Trailer{Key: "Co-authored-by", Value: identity}, nil
+}
+
+// AppendTrailers adds trailers to the trailer block of message, starting
+// one when there is none. Trailers the message already has, with the same
+// key ignoring case and the same value, are not repeated.
+func AppendTrailers(message string, extra []Trailer) string {
+	if len(extra) == 0 {
+		return message
+	}
+	rest, trailers := SplitTrailers(ToLF(message))
+	for _, t := range extra {
+		if !containsTrailer(trailers, t) {
+			trailers = append(trailers, t)
+		}
+	}
+	return WithTrailers(rest, trailers)
+}
+
+// containsTrailer reports whether trailers include t.
+func containsTrailer(trailers []Trailer, t Trailer) bool {
+	for _, existing := range trailers {
+		if strings.EqualFold(existing.Key, t.Key) && existing.Value == t.Value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,166 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package message
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseTrailer(t *testing.T) {
+	tests := []struct {
+		line    string
+		want    Trailer
+		wantErr bool
+	}{
+		{line: "Refs: #123", want: Trailer{Key: "Refs", Value: "#123"}},
+		{line: "  Co-authored-by:   Jane Doe <jane@example.com> ", want: Trailer{Key: "Co-authored-by", Value: "Jane Doe <jane@example.com>"}},
+		{line: "Link: https://example.com/a:b", want: Trailer{Key: "Link", Value: "https://example.com/a:b"}},
+		{line: "no colon here", wantErr: true},
+		{line: "Reviewed by: Jane", wantErr: true},
+		{line: "-Refs: #1", wantErr: true},
+		{line: "Refs:", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseTrailer(tt.line)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseTrailer(%q) error = %v, wantErr %v", tt.line, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseTrailer(%q) = %+v, want %+v", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestSplitTrailers(t *testing.T) {
+	tests := []struct {
+		name         string
+		text         string
+		wantRest     string
+		wantTrailers []Trailer
+	}{
+		{
+			name:     "header only",
+			text:     "Refs: #123",
+			wantRest: "Refs: #123",
+		},
+		{
+			name:         "trailer block",
+			text:         "feat: add login\n\nAdds the form.\n\nRefs: #123\nSigned-off-by: Jane <jane@example.com>\n",
+			wantRest:     "feat: add login\n\nAdds the form.",
+			wantTrailers: []Trailer{{"Refs", "#123"}, {"Signed-off-by", "Jane <jane@example.com>"}},
+		},
+		{
+			name:         "trailers right after the header",
+			text:         "feat: add login\n\nRefs: #123",
+			wantRest:     "feat: add login",
+			wantTrailers: []Trailer{{"Refs", "#123"}},
+		},
+		{
+			name:     "last paragraph is prose",
+			text:     "feat: add login\n\nNote: the form is new.\nIt replaces the old one.",
+			wantRest: "feat: add login\n\nNote: the form is new.\nIt replaces the old one.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rest, trailers := SplitTrailers(tt.text)
+			if rest != tt.wantRest || !slices.Equal(trailers, tt.wantTrailers) {
+				t.Errorf("SplitTrailers() = %q, %v; want %q, %v", rest, trailers, tt.wantRest, tt.wantTrailers)
+			}
+			if got := WithTrailers(rest, trailers); got != WithTrailers(tt.wantRest, tt.wantTrailers) {
+				t.Errorf("WithTrailers() = %q", got)
+			}
+		})
+	}
+}
+
+func TestWithTrailers(t *testing.T) {
+	if got := WithTrailers("feat: add login\n", nil); got != "feat: add login\n" {
+		t.Errorf("WithTrailers() without trailers = %q, want the message unchanged", got)
+	}
+	got := WithTrailers("feat: add login\n\nAdds the form.\n", []Trailer{{"Refs", "#123"}, {"Acked-by", "Jane"}})
+	if want := "feat: add login\n\nAdds the form.\n\nRefs: #123\nAcked-by: Jane"; got != want {
+		t.Errorf("WithTrailers() = %q, want %q", got, want)
+	}
+}
+
+func TestCoAuthor(t *testing.T) {
+	tests := []struct {
+		identity string
+		want     string
+		wantErr  bool
+	}{
+		{identity: "Jane Doe <jane@example.com>", want: "Jane Doe <jane@example.com>"},
+		{identity: "  Jane   Doe  <jane@example.com> ", want: "Jane Doe <jane@example.com>"},
+		{identity: "jane <jane@example.com>", want: "jane <jane@example.com>"},
+		{identity: "<jane@example.com>", wantErr: true},
+		{identity: "Jane Doe", wantErr: true},
+		{identity: "Jane Doe <jane>", wantErr: true},
+		{identity: "Jane Doe jane@example.com", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := CoAuthor(tt.identity)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("CoAuthor(%q) error = %v, wantErr %v", tt.identity, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != (Trailer{"Co-authored-by", tt.want}) {
+			t.Errorf("CoAuthor(%q) = %+v, want Co-authored-by: %s", tt.identity, got, tt.want)
+		}
+	}
+}
+
+func TestAppendTrailers(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		extra   []Trailer
+		want    string
+	}{
+		{
+			name:    "nothing to add",
+			message: "feat: add login\n",
+			want:    "feat: add login\n",
+		},
+		{
+			name:    "new trailer block",
+			message: "feat: add login\n\nAdds the form.",
+			extra:   []Trailer{{"Co-authored-by", "Jane Doe <jane@example.com>"}},
+			want:    "feat: add login\n\nAdds the form.\n\nCo-authored-by: Jane Doe <jane@example.com>",
+		},
+		{
+			name:    "after existing trailers",
+			message: "feat: add login\n\nRefs: #1",
+			extra:   []Trailer{{"Signed-off-by", "Test Author <author@example.com>"}},
+			want:    "feat: add login\n\nRefs: #1\nSigned-off-by: Test Author <author@example.com>",
+		},
+		{
+			name:    "same key and value not repeated",
+			message: "feat: add login\n\nco-authored-by: Jane Doe <jane@example.com>",
+			extra:   []Trailer{{"Co-authored-by", "Jane Doe <jane@example.com>"}},
+			want:    "feat: add login\n\nco-authored-by: Jane Doe <jane@example.com>",
+		},
+		{
+			name:    "same key with another value",
+			message: "feat: add login\n\nCo-authored-by: Jane Doe <jane@example.com>",
+			extra:   []Trailer{{"Co-authored-by", "John Roe <john@example.com>"}},
+			want:    "feat: add login\n\nCo-authored-by: Jane Doe <jane@example.com>\nCo-authored-by: John Roe <john@example.com>",
+		},
+		{
+			name:    "CRLF message",
+			message: "feat: add login\r\n\r\nAdds the form.\r\n",
+			extra:   []Trailer{{"Refs", "#1"}},
+			want:    "feat: add login\n\nAdds the form.\n\nRefs: #1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AppendTrailers(tt.message, tt.extra); got != tt.want {
+				t.Errorf("AppendTrailers() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
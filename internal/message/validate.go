@@ -0,0 +1,180 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package message
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DefaultMaxSubjectLength is the longest header accepted by default.
+const DefaultMaxSubjectLength = 72
+
+// DefaultMinWhyLength is the shortest body accepted as a rationale.
+const DefaultMinWhyLength = 40
+
+// whyIndicators are words that suggest a body explains motivation rather
+// than restating the diff.
+var whyIndicators = []string{
+	"because", "so that", "so we", "in order to", "since", "otherwise",
+	"prevent", "avoid", "allow", "enable", "need", "required", "problem",
+	"issue", "fix", "why", "instead", "reason", "without", "ensure",
+}
+
+// DefaultTypes are the conventional commit types accepted by default.
+var DefaultTypes = []string{
+	"feat", "fix", "refactor", "perf", "docs", "test",
+	"style", "build", "ci", "chore", "revert",
+}
+
+// Severity classifies how serious a validation issue is.
+type Severity int
+
+const (
+	// Warning marks a style problem that does not block a commit.
+	Warning Severity = iota
+	// Error marks a violation of the commit conventions.
+	Error
+)
+
+// String returns the lower-case severity name.
+func (s Severity) String() string {
+	if s == Error {
+		return "error"
+	}
+	return "warning"
+}
+
+// Issue is a single validation finding.
+type Issue struct {
+	Rule     string
+	Severity Severity
+	Message  string
+}
+
+// String formats the issue for display.
+func (i Issue) String() string {
+	return fmt.Sprintf("%s: %s (%s)", i.Severity, i.Message, i.Rule)
+}
+
+// Rules configures validation.
+type Rules struct {
+	// MaxSubjectLength caps the header length. Zero uses the default.
+	MaxSubjectLength int
+	// Types lists the accepted commit types. Nil uses DefaultTypes.
+	Types []string
+	// Scopes lists the accepted scopes. Empty accepts any scope; a header
+	// without a scope is always accepted.
+	Scopes []string
+	// RequireWhy demands a body that explains the reason for the change.
+	RequireWhy bool
+	// MinWhyLength is the shortest acceptable body when RequireWhy is set.
+	// Zero uses the default.
+	MinWhyLength int
+	// SubjectCase is the required case of the subject's first letter, one
+	// of SubjectCases. Empty or SubjectCasePreserve accepts either.
+	SubjectCase string
+	// Disabled lists rule names whose issues are not reported.
+	Disabled []string
+}
+
+// RuleNames lists every rule Validate can report, for use with
+// Rules.Disabled.
+var RuleNames = []string{
+	"empty", "format", "type", "scope", "subject-empty", "subject-length",
+	"subject-period", "subject-case", "blank-line", "why",
+}
+
+// Validate checks a commit message against the conventional commit format.
+func Validate(text string, rules Rules) []Issue {
+	if rules.MaxSubjectLength == 0 {
+		rules.MaxSubjectLength = DefaultMaxSubjectLength
+	}
+	if rules.Types == nil {
+		rules.Types = DefaultTypes
+	}
+	if rules.MinWhyLength == 0 {
+		rules.MinWhyLength = DefaultMinWhyLength
+	}
+
+	m := Parse(text)
+	var issues []Issue
+	add := func(rule string, sev Severity, format string, args ...any) {
+		if slices.Contains(rules.Disabled, rule) {
+			return
+		}
+		issues = append(issues, Issue{Rule: rule, Severity: sev, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if m.Header == "" {
+		add("empty", Error, "message is empty")
+		return issues
+	}
+
+	switch {
+	case m.Merge():
+		// git's own merge headers are accepted as they are
+	case !m.Conventional():
+		add("format", Error, "header must look like \"type(scope): subject\"")
+	case !slices.Contains(rules.Types, m.Type):
+		add("type", Error, "unknown type %q (expected one of %s)", m.Type, strings.Join(rules.Types, ", "))
+	}
+	if m.Conventional() && m.Scope != "" && len(rules.Scopes) > 0 && !slices.Contains(rules.Scopes, m.Scope) {
+		add("scope", Error, "unknown scope %q (expected one of %s)", m.Scope, strings.Join(rules.Scopes, ", "))
+	}
+
+	if strings.TrimSpace(m.Subject) == "" {
+		add("subject-empty", Error, "subject is empty")
+	}
+	if n := len([]rune(m.Header)); n > rules.MaxSubjectLength {
+		add("subject-length", Error, "header is %d characters (max %d)", n, rules.MaxSubjectLength)
+	}
+	if strings.HasSuffix(m.Subject, ".") {
+		add("subject-period", Warning, "subject should not end with a period")
+	}
+	if m.Conventional() && subjectCaseMismatch(m.Subject, rules.SubjectCase) {
+		add("subject-case", Error, "subject must start in %s case", rules.SubjectCase)
+	}
+	if !m.Separated {
+		add("blank-line", Error, "header must be followed by a blank line")
+	}
+
+	if rules.RequireWhy {
+		// Trailers are not part of the rationale
+		rest, _ := SplitTrailers(text)
+		body := Parse(rest).Body
+		switch {
+		case body == "":
+			add("why", Error, "body must explain why the change was made")
+		case len([]rune(body)) < rules.MinWhyLength:
+			add("why", Error, "body is %d characters; a rationale needs at least %d", len([]rune(body)), rules.MinWhyLength)
+		case !explainsWhy(body):
+			add("why", Warning, "body seems to describe what changed rather than why")
+		}
+	}
+
+	return issues
+}
+
+// HasErrors reports whether any issue has Error severity.
+func HasErrors(issues []Issue) bool {
+	for _, issue := range issues {
+		if issue.Severity == Error {
+			return true
+		}
+	}
+	return false
+}
+
+// explainsWhy reports whether text uses language that suggests a rationale.
+func explainsWhy(text string) bool {
+	lower := strings.ToLower(text)
+	for _, word := range whyIndicators {
+		if strings.Contains(lower, word) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package message
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// rules returns the names of the rules issues were reported for.
+func rules(issues []Issue) []string {
+	var names []string
+	for _, issue := range issues {
+		names = append(names, issue.Rule)
+	}
+	return names
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		rules Rules
+		want  []string
+	}{
+		{name: "valid", text: "feat(api): add login endpoint\n\nIt replaces the form post."},
+		{name: "empty", text: "  \n", want: []string{"empty"}},
+		{name: "not conventional", text: "Add login endpoint", want: []string{"format"}},
+		{name: "merge header", text: "Merge branch 'topic'"},
+		{name: "unknown type", text: "feature: add login endpoint", want: []string{"type"}},
+		{name: "custom types", text: "feature: add login endpoint", rules: Rules{Types: []string{"feature"}}},
+		{name: "colon without a subject", text: "feat:  ", want: []string{"format"}},
+		{name: "long header", text: "feat: " + strings.Repeat("a", 70), want: []string{"subject-length"}},
+		{name: "custom length", text: "feat: add login endpoint", rules: Rules{MaxSubjectLength: 10}, want: []string{"subject-length"}},
+		{name: "length counts runes", text: "docs: " + strings.Repeat("é", 66)},
+		{name: "trailing period", text: "fix: handle empty config.", want: []string{"subject-period"}},
+		{name: "no blank line", text: "fix: handle empty config\nIt crashed.", want: []string{"blank-line"}},
+		{name: "lower case subject", text: "fix: Handle empty config", rules: Rules{SubjectCase: SubjectCaseLower}, want: []string{"subject-case"}},
+		{name: "sentence case subject", text: "fix: handle empty config", rules: Rules{SubjectCase: SubjectCaseSentence}, want: []string{"subject-case"}},
+		{name: "subject case acronym", text: "fix: YAML parsing of empty config", rules: Rules{SubjectCase: SubjectCaseLower}},
+		{name: "allowed scope", text: "feat(cli): add login command", rules: Rules{Scopes: []string{"cli", "config"}}},
+		{name: "unknown scope", text: "feat(api): add login endpoint", rules: Rules{Scopes: []string{"cli", "config"}}, want: []string{"scope"}},
+		{name: "no scope with scope list", text: "feat: add login endpoint", rules: Rules{Scopes: []string{"cli"}}},
+		{
+			name:  "disabled rule",
+			text:  "fix: handle empty config.\nIt crashed.",
+			rules: Rules{Disabled: []string{"blank-line"}},
+			want:  []string{"subject-period"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rules(Validate(tt.text, tt.rules)); !slices.Equal(got, tt.want) {
+				t.Errorf("Validate(%q) reported %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasErrors(t *testing.T) {
+	if HasErrors(Validate("fix: handle empty config.", Rules{})) {
+		t.Error("a trailing period is only a warning")
+	}
+	if !HasErrors(Validate("Add login endpoint", Rules{})) {
+		t.Error("a header that is not conventional is an error")
+	}
+}
+
+func TestValidateWhy(t *testing.T) {
+	tests := []struct {
+		name         string
+		text         string
+		minLength    int
+		want         []string
+		wantSeverity Severity
+	}{
+		{
+			name: "rationale",
+			text: "fix: retry uploads\n\nUploads failed for good on a dropped connection because nothing retried them.",
+		},
+		{
+			name:         "no body",
+			text:         "fix: retry uploads",
+			want:         []string{"why"},
+			wantSeverity: Error,
+		},
+		{
+			name:         "only trailers",
+			text:         "fix: retry uploads\n\nRefs: #42",
+			want:         []string{"why"},
+			wantSeverity: Error,
+		},
+		{
+			name:         "too short",
+			text:         "fix: retry uploads\n\nBecause of drops.",
+			want:         []string{"why"},
+			wantSeverity: Error,
+		},
+		{
+			name:      "custom length",
+			text:      "fix: retry uploads\n\nBecause of drops.",
+			minLength: 10,
+		},
+		{
+			name:         "what, not why",
+			text:         "fix: retry uploads\n\nThe upload loop now wraps each request in a retry with backoff.",
+			want:         []string{"why"},
+			wantSeverity: Warning,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := Validate(tt.text, Rules{RequireWhy: true, MinWhyLength: tt.minLength})
+			if got := rules(issues); !slices.Equal(got, tt.want) {
+				t.Fatalf("Validate() reported %v, want %v", got, tt.want)
+			}
+			if len(issues) > 0 && issues[0].Severity != tt.wantSeverity {
+				t.Errorf("severity = %s, want %s", issues[0].Severity, tt.wantSeverity)
+			}
+		})
+	}
+
+	if issues := Validate("fix: retry uploads", Rules{}); len(issues) != 0 {
+		t.Errorf("a missing body is reported without RequireWhy: %v", issues)
+	}
+}
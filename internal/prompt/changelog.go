@@ -0,0 +1,33 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package prompt
+
+// ChangelogEntries returns the system and user prompts for summarizing a
+// range of commits as changelog entries, from their messages and the diff
+// stat of the range.
+func ChangelogEntries(log, stat string) (system, user string) {
+	system = `You are an expert developer who writes release notes for the users of a project.
+
+Your task is to turn a range of commits into changelog entries. Follow these principles:
+
+1. **Users first**: Describe changes by their effect on users, not by the code touched
+2. **One entry per change**: Merge commits that make up one change, e.g. a feature and its follow-up fixes
+3. **Leave out** internal changes users do not notice: tests, CI, refactors without visible effect, chores
+4. **Breaking changes**: Any change users must act on is a breaking change
+
+Write one entry per line as "Category: text", where Category is one of Added, Changed, Deprecated, Removed, Fixed, Security or Breaking, e.g.:
+Added: Export reports as CSV
+Fixed: Crash when the config file is empty
+
+Output ONLY the entry lines, no headings or additional commentary.`
+
+	user = `Summarize these commits (oldest first) as changelog entries:
+
+` + log + `
+
+Files changed in the range:
+` + stat
+
+	return system, user
+}
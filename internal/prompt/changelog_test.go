@@ -0,0 +1,22 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package prompt
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChangelogEntries(t *testing.T) {
+	system, user := ChangelogEntries("feat: add dark mode\n---\nfix: keep the toggle in sync", " theme.go | 1 +\n")
+	if !strings.Contains(system, `"Category: text"`) {
+		t.Errorf("system prompt does not ask for one entry per line:\n%s", system)
+	}
+	if !strings.Contains(user, "feat: add dark mode\n---\nfix: keep the toggle in sync") {
+		t.Errorf("user prompt does not contain the commits:\n%s", user)
+	}
+	if !strings.HasSuffix(user, "Files changed in the range:\n theme.go | 1 +\n") {
+		t.Errorf("user prompt does not end with the diff stat:\n%s", user)
+	}
+}
@@ -3,38 +3,545 @@
 
 package prompt
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
 // CommitMessageModel is the default model for commit message generation.
 const CommitMessageModel = "claude-haiku-4-5-20251001"
 
+// Language styles accepted in CommitOptions.LanguageStyle.
+const (
+	StyleFormal    = "formal"
+	StyleCasual    = "casual"
+	StyleTerse     = "terse"
+	StyleTechnical = "technical"
+)
+
+// LanguageStyles lists the valid language styles.
+var LanguageStyles = []string{StyleFormal, StyleCasual, StyleTerse, StyleTechnical}
+
+// styleGuidelines holds the tone guidance for each language style. The
+// empty style gets the default guidelines.
+var styleGuidelines = map[string]string{
+	"": `- Clear and professional tone
+- No unnecessary words or filler
+- Focus on user impact and intent
+- Group related changes logically`,
+	StyleFormal: `- Formal, professional tone in complete sentences
+- No contractions, slang or exclamations
+- Focus on user impact and intent
+- Group related changes logically`,
+	StyleCasual: `- Friendly, conversational tone, as if explaining to a teammate
+- Contractions are fine; keep it clear rather than clever
+- Focus on user impact and intent
+- Group related changes logically`,
+	StyleTerse: `- As few words as possible; fragments are fine
+- No articles, filler or repetition of the subject in the body
+- Omit the body unless the subject cannot carry the intent
+- Body, if any, as short bullet points`,
+	StyleTechnical: `- Precise technical tone using the exact domain terms, identifiers and names from the diff
+- Name the affected functions, types, flags or endpoints rather than describing them loosely
+- Focus on behavior and its effects
+- Group related changes logically`,
+}
+
+// DefaultBodyTemplates is the body structure asked for per commit type
+// with CommitOptions.BodyTemplates, before teams override entries.
+var DefaultBodyTemplates = map[string]string{
+	"feat":     "Motivation: why the feature is needed.\nBehavior: what it lets users do and how to use it.",
+	"fix":      "Root cause: what was wrong and when it showed.\nFix: how the change resolves it.",
+	"refactor": "Why the old structure was a problem, and what the new one makes easier. State that behavior is unchanged.",
+	"perf":     "What was slow, what changed, and the measured or expected improvement.",
+	"docs":     "What readers could not find or got wrong before.",
+	"test":     "What the tests cover that was not covered before.",
+	"build":    "What changed in the build and why it was needed.",
+	"ci":       "What changed in the pipeline and why it was needed.",
+	"chore":    "Why the housekeeping was needed, in a sentence.",
+	"revert":   "Why the reverted change is being undone.",
+}
+
+// SubmoduleUpdate describes a submodule pointer change in the diff.
+type SubmoduleUpdate struct {
+	Path string
+	// Old and New are the submodule commits before and after the change.
+	Old, New string
+	// Log lists the commits pulled in by the bump, one per line. It is empty
+	// when the submodule is not checked out locally.
+	Log string
+}
+
+// CodeContext is a region of a changed file included for context.
+type CodeContext struct {
+	Path               string
+	StartLine, EndLine int
+	Code               string
+}
+
+// FileKind pairs a changed file with its broad role, e.g. "test" or "docs".
+type FileKind struct {
+	Path string
+	Kind string
+}
+
+// DependencyBump is a dependency version change in a manifest. Old is empty
+// for an added dependency and New for a removed one.
+type DependencyBump struct {
+	Manifest string
+	Name     string
+	Old, New string
+}
+
+// DependencyLine renders a bump as "name old -> new (manifest)".
+func DependencyLine(b DependencyBump) string {
+	switch {
+	case b.Old == "":
+		return fmt.Sprintf("%s %s (added, %s)", b.Name, b.New, b.Manifest)
+	case b.New == "":
+		return fmt.Sprintf("%s %s (removed, %s)", b.Name, b.Old, b.Manifest)
+	default:
+		return fmt.Sprintf("%s %s -> %s (%s)", b.Name, b.Old, b.New, b.Manifest)
+	}
+}
+
+// MergeInfo describes the merge a commit concludes.
+type MergeInfo struct {
+	// Header is git's default merge header, e.g. "Merge branch 'topic'".
+	Header string
+	// Conflicts lists the files that had merge conflicts.
+	Conflicts []string
+}
+
+// CommitOptions carries optional context that shapes the commit prompt.
+type CommitOptions struct {
+	// Template is the team's commit template (git's commit.template) with
+	// comment lines removed. The model fills it in instead of free-forming.
+	Template string
+
+	// WeightBySize tells the model the diff is ordered by change size and
+	// that the message should lead with the largest changes.
+	WeightBySize bool
+
+	// RequireWhy makes an explanation of the motivation mandatory.
+	RequireWhy bool
+
+	// TestPlan asks for a Test-plan trailer saying how the change was or
+	// can be verified.
+	TestPlan bool
+
+	// Submodules lists submodule bumps with the commits they include.
+	Submodules []SubmoduleUpdate
+
+	// Context holds the code surrounding small changes, so the model can
+	// tell what the changed lines belong to.
+	Context []CodeContext
+
+	// FormattingOnly marks a diff that only changes whitespace.
+	FormattingOnly bool
+
+	// FileKinds classifies the changed files, hinting at the type and scope.
+	FileKinds []FileKind
+
+	// Summarized lists the changed files left out of the diff to save
+	// tokens, one "== path (status, +added -removed)" line each.
+	Summarized []string
+
+	// PartOutlines outline the parts of a diff too large for the prompt,
+	// one per part, worked out separately. The diff shown is the rest.
+	PartOutlines []string
+
+	// Scope is the scope the header must use, e.g. the owning team's area.
+	Scope string
+
+	// FileTree is an indented listing of the directories holding changed
+	// files, showing where new files sit in the project.
+	FileTree string
+
+	// Dependencies lists the dependency version changes in the manifests.
+	Dependencies []DependencyBump
+
+	// Outline lists the key changes, worked out in a first pass over the
+	// diff, for the message to be written from.
+	Outline string
+
+	// Avoid lists earlier suggestions the new message must differ from.
+	Avoid []string
+
+	// Variation is a random nonce that keeps regenerations from being
+	// answered identically.
+	Variation string
+
+	// Merge is set when the commit concludes a merge, so the message can
+	// summarize the merge and its conflict resolutions.
+	Merge *MergeInfo
+
+	// LanguageStyle sets the tone of the message, one of LanguageStyles.
+	// Empty uses the default guidelines.
+	LanguageStyle string
+
+	// BodyTemplates maps commit types to the body structure wanted for
+	// them, e.g. root cause and fix for "fix".
+	BodyTemplates map[string]string
+
+	// Type is the commit type the files point to, e.g. "test" when only
+	// tests changed. With BodyTemplates, only its template is given.
+	Type string
+
+	// SubjectCase is the case the subject must start in after the type:
+	// "lower" or "sentence". Anything else leaves it to the model.
+	SubjectCase string
+
+	// MaxSubjectLength caps the header length. Zero uses 72.
+	MaxSubjectLength int
+
+	// Scopes lists the scopes the header may use.
+	Scopes []string
+
+	// Instructions are the team's own guidelines, added to the system
+	// prompt as given.
+	Instructions string
+
+	// Invalid is an earlier suggestion that failed validation, and
+	// Problems says why, so the model can correct it.
+	Invalid  string
+	Problems []string
+
+	// StyleExamples are recent commit messages of the repository, newest
+	// first, whose conventions the message should follow.
+	StyleExamples []string
+
+	// Ticket is the ticket the change is for, if known.
+	Ticket *Ticket
+
+	// Custom replaces the prompts with the team's own templates.
+	Custom *CustomPrompt
+}
+
+// Ticket is an issue-tracker ticket a change is for.
+type Ticket struct {
+	// ID is the ticket, e.g. "JIRA-1234" or "#42".
+	ID string
+	// Title is the ticket's title from the tracker, if it was fetched.
+	Title string
+	// Added is set when the ID is added to the message afterwards, so the
+	// model should leave it out.
+	Added bool
+}
+
 // CommitMessage returns the system and user prompts for generating a commit message.
-func CommitMessage(diff, feedback string) (system, user string) {
+func CommitMessage(diff, feedback string, opts CommitOptions) (system, user string) {
+	maxSubject := opts.MaxSubjectLength
+	if maxSubject == 0 {
+		maxSubject = 72
+	}
 	system = `You are an expert developer who writes clear, professional commit messages following conventional commits format.
 
 Your task is to generate a commit message based on git diff output. Follow these principles:
 
 1. **Format**: Use conventional commits (feat:, fix:, refactor:, docs:, test:, chore:)
-2. **Subject line**: Concise summary (max 72 chars), imperative mood ("add" not "added")
+2. **Subject line**: Concise summary (max ` + fmt.Sprint(maxSubject) + ` chars), imperative mood ("add" not "added")
 3. **Body**: Explain WHY, not WHAT (the diff shows what changed)
 4. **Scope**: Add scope when helpful (e.g., "feat(cli):", "fix(database):")
 5. **Breaking changes**: Use "!" for breaking changes (e.g., "feat!:")
 
 Style guidelines:
-- Clear and professional tone
-- No unnecessary words or filler
-- Focus on user impact and intent
-- Group related changes logically
+` + styleGuidelines[opts.LanguageStyle] + `
+
+Output ONLY the commit message, no additional commentary.` + diffIsData
+
+	if opts.Template != "" {
+		system += `
+
+The team uses a commit template. Keep its structure and headings, fill in or augment each section from the diff, and drop placeholder text you replace.`
+	}
+
+	if opts.RequireWhy {
+		system += `
+
+The body is REQUIRED and must explain WHY the change was made: the problem it solves, the motivation, or the trade-off chosen. Do not restate what the diff changed; a body that only lists changes is not acceptable.`
+	}
+
+	if opts.Scope != "" {
+		system += `
+
+Use "` + opts.Scope + `" as the scope of the header, e.g. "feat(` + opts.Scope + `): ...". It names the area of the project that owns these files.`
+	}
+
+	if len(opts.Scopes) > 0 && opts.Scope == "" {
+		system += `
+
+If the header has a scope, it must be one of: ` + strings.Join(opts.Scopes, ", ") + `. Leave the scope out when none fits.`
+	}
+
+	if opts.TestPlan {
+		system += `
+
+End the message with a one-line "Test-plan:" trailer saying how the change is verified. Base it on the test files in the diff when there are any, e.g. "Test-plan: new cases in parser_test.go cover empty input". If no tests changed, say so and name what a reviewer should check, e.g. "Test-plan: no tests changed; check that --help lists the new flag".`
+	}
+
+	switch opts.SubjectCase {
+	case "lower":
+		system += `
 
-Output ONLY the commit message, no additional commentary.`
+Start the subject after the type with a lowercase letter, e.g. "feat: add retry logic", unless the first word is a name or acronym such as "API".`
+	case "sentence":
+		system += `
+
+Start the subject after the type with a capital letter, e.g. "feat: Add retry logic".`
+	}
+
+	if len(opts.BodyTemplates) > 0 {
+		system += bodyTemplateGuidance(opts.BodyTemplates, opts.Type)
+	}
+
+	if opts.Instructions != "" {
+		system += `
+
+Team guidelines (follow them unless they conflict with the rules above):
+` + opts.Instructions
+	}
+
+	if len(opts.StyleExamples) > 0 {
+		system += `
+
+Recent commit messages in this repository, newest first, separated by "---". Match their conventions, such as emoji or ticket prefixes, capitalization, length and how bodies are written, even where they differ from the format rules above. Take only the style from them, never the content:
+` + fence("examples", strings.Join(opts.StyleExamples, "\n---\n"))
+	}
+
+	if opts.WeightBySize {
+		system += `
+
+Files and hunks are ordered by change size, largest first. Lead the subject with the most substantive change and mention small incidental changes briefly in the body, if at all.`
+	}
+
+	if opts.FormattingOnly {
+		system += `
+
+The changes only alter whitespace or formatting; behavior is unchanged. Use the "style" type and a one-line subject naming what was reformatted. Do not describe the changed lines.`
+	}
+
+	if len(opts.Dependencies) > 0 {
+		system += `
+
+The change updates dependencies. Use a "chore(deps):" header, e.g. "chore(deps): bump 5 dependencies", and list every version change given below in the body, one "- name old -> new" line each.`
+	}
+
+	if opts.Merge != nil {
+		system += `
+
+This commit concludes a merge. Use the given merge header as the subject line instead of a conventional commit header. In the body, summarize what the merge brings in and, for each file that had conflicts, how the conflict was resolved as far as the diff shows.`
+	}
 
 	user = `Generate a conventional commit message for these changes:
 
-` + diff
+` + fenceDiff(diff)
 
-	if feedback != "" {
+	if len(opts.Summarized) > 0 {
 		user += `
 
-User feedback for improvement: ` + feedback
+Other changed files (diffs omitted for length; weigh them by their stats):`
+		for _, line := range opts.Summarized {
+			user += "\n" + line
+		}
+	}
+
+	if len(opts.PartOutlines) > 0 {
+		user += `
+
+The diff above is only part of the change; it was too large to show whole. Outlines of the other parts, worked out separately (weigh them as fully as the diff):`
+		for i, outline := range opts.PartOutlines {
+			user += fmt.Sprintf("\n\nPart %d:\n%s", i+1, outline)
+		}
+	}
+
+	if opts.Template != "" {
+		user += `
+
+Commit template to follow:
+` + opts.Template
+	}
+
+	if len(opts.FileKinds) > 0 {
+		user += `
+
+Changed files by kind (let this guide the type and scope, e.g. only test files suggest "test:"):`
+		for _, f := range opts.FileKinds {
+			user += "\n- " + f.Path + ": " + f.Kind
+		}
+	}
+
+	if opts.FileTree != "" {
+		user += `
+
+Layout of the directories containing the changed files (staged version, for context only; use it to name new modules and moves accurately):
+` + opts.FileTree
+	}
+
+	if len(opts.Dependencies) > 0 {
+		user += `
+
+Dependency changes:`
+		for _, b := range opts.Dependencies {
+			user += "\n- " + DependencyLine(b)
+		}
+	}
+
+	if len(opts.Submodules) > 0 {
+		user += `
+
+Submodule updates (describe what each bump includes, e.g. "chore(deps): bump <name> to include ..."):`
+		for _, sub := range opts.Submodules {
+			user += "\n\n" + submoduleSummary(sub)
+		}
+	}
+
+	if len(opts.Context) > 0 {
+		user += `
+
+Surrounding code of the changed lines (staged version, for context only):`
+		for _, c := range opts.Context {
+			user += fmt.Sprintf("\n\n%s (lines %d-%d):\n%s", c.Path, c.StartLine, c.EndLine, c.Code)
+		}
+	}
+
+	if opts.Merge != nil {
+		user += `
+
+Merge header: ` + opts.Merge.Header
+		if len(opts.Merge.Conflicts) == 0 {
+			user += "\nThe merge had no conflicts."
+		} else {
+			user += "\nFiles that had conflicts:"
+			for _, path := range opts.Merge.Conflicts {
+				user += "\n- " + path
+			}
+		}
+	}
+
+	if t := opts.Ticket; t != nil && (t.Title != "" || t.Added) {
+		user += "\n\nThe change is for ticket " + t.ID
+		if t.Title != "" {
+			user += fmt.Sprintf(" titled %q; use it to understand why the change was made", t.Title)
+		}
+		user += "."
+		if t.Added {
+			user += " Do not mention the ticket in the message; it is added separately."
+		}
+	}
+
+	if opts.Outline != "" {
+		user += `
+
+Outline of the key changes (write the message from this, checking details against the diff):
+` + opts.Outline
+	}
+
+	user += revisionNotes(feedback, opts)
+
+	// A template that fails here was already reported by CheckCustom
+	if opts.Custom != nil {
+		if s, u, err := opts.Custom.render(diff, feedback, system, user); err == nil {
+			system, user = s, u
+		}
 	}
 
 	return system, user
 }
+
+// CommitFollowUp returns the user prompt for a new message in an ongoing
+// conversation, after the model's earlier messages were rejected. The diff
+// and the system prompt of CommitMessage were given in its first turn.
+func CommitFollowUp(feedback string, opts CommitOptions) string {
+	notes := `The user rejected your last message.`
+	if feedback == "" {
+		notes += ` Write a different commit message for the same diff.`
+	} else {
+		notes += ` Write an improved commit message for the same diff, taking all of their feedback in this conversation into account, not just the latest.`
+	}
+	return notes + revisionNotes(feedback, opts) + "\n\nOutput only the commit message."
+}
+
+// revisionNotes renders what a new message must do differently: earlier
+// suggestions to avoid, validation problems to fix and the user's feedback.
+func revisionNotes(feedback string, opts CommitOptions) string {
+	var notes string
+	if len(opts.Avoid) > 0 {
+		notes += `
+
+Earlier suggestions the user rejected (write a genuinely different message, e.g. another angle or emphasis, not a rewording):`
+		for _, m := range opts.Avoid {
+			notes += "\n---\n" + m
+		}
+		notes += "\n---"
+	}
+
+	if opts.Variation != "" {
+		notes += "\n\nVariation: " + opts.Variation
+	}
+
+	if opts.Invalid != "" {
+		notes += `
+
+Your previous message failed validation:
+---
+` + opts.Invalid + `
+---
+Problems:`
+		for _, p := range opts.Problems {
+			notes += "\n- " + p
+		}
+		notes += "\nWrite a corrected message that fixes every problem and keeps what was right."
+	}
+
+	if feedback != "" {
+		notes += `
+
+User feedback for improvement: ` + feedback
+	}
+	return notes
+}
+
+// submoduleSummary renders a submodule update for the user prompt.
+func submoduleSummary(sub SubmoduleUpdate) string {
+	switch {
+	case sub.New == "":
+		return sub.Path + ": submodule removed"
+	case sub.Log == "":
+		return sub.Path + ": " + shortCommit(sub.Old) + " -> " + shortCommit(sub.New) +
+			" (submodule not checked out; included commits unknown)"
+	default:
+		return sub.Path + ": " + shortCommit(sub.Old) + " -> " + shortCommit(sub.New) +
+			"\n" + sub.Log
+	}
+}
+
+// shortCommit abbreviates a commit hash for display.
+func shortCommit(commit string) string {
+	if commit == "" {
+		return "(new)"
+	}
+	if len(commit) > 12 {
+		return commit[:12]
+	}
+	return commit
+}
+
+// bodyTemplateGuidance asks for the body structure of commitType, or of
+// whichever type the model picks when commitType has no template.
+func bodyTemplateGuidance(templates map[string]string, commitType string) string {
+	if t, ok := templates[commitType]; ok {
+		return "\n\nStructure the body of this " + commitType + " commit like this:\n" + t
+	}
+	types := make([]string, 0, len(templates))
+	for t := range templates {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	guidance := "\n\nStructure the body according to the type you choose:"
+	for _, t := range types {
+		guidance += "\n\n" + t + ":\n" + templates[t]
+	}
+	return guidance
+}
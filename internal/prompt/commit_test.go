@@ -0,0 +1,312 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package prompt
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCommitMessageOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		opts CommitOptions
+		// wantSystem and wantUser must be in the system and user prompts.
+		wantSystem []string
+		wantUser   []string
+	}{
+		{
+			name:       "commit template",
+			opts:       CommitOptions{Template: "Why:\n\nTicket:"},
+			wantSystem: []string{"The team uses a commit template."},
+			wantUser:   []string{"Commit template to follow:\nWhy:\n\nTicket:"},
+		},
+		{
+			name:       "weight by size",
+			opts:       CommitOptions{WeightBySize: true},
+			wantSystem: []string{"ordered by change size, largest first"},
+		},
+		{
+			name:       "require why",
+			opts:       CommitOptions{RequireWhy: true},
+			wantSystem: []string{"The body is REQUIRED and must explain WHY"},
+		},
+		{
+			name: "surrounding code",
+			opts: CommitOptions{Context: []CodeContext{
+				{Path: "app.go", StartLine: 3, EndLine: 5, Code: "func Add(a, b int) int {"},
+			}},
+			wantUser: []string{"Surrounding code of the changed lines", "app.go (lines 3-5):\nfunc Add(a, b int) int {"},
+		},
+		{
+			name:       "merge",
+			opts:       CommitOptions{Merge: &MergeInfo{Header: "Merge branch 'topic'", Conflicts: []string{"app.go"}}},
+			wantSystem: []string{"This commit concludes a merge."},
+			wantUser:   []string{"Merge header: Merge branch 'topic'", "Files that had conflicts:\n- app.go"},
+		},
+		{
+			name:     "file kinds",
+			opts:     CommitOptions{FileKinds: []FileKind{{Path: "app_test.go", Kind: "test"}}},
+			wantUser: []string{"Changed files by kind", "- app_test.go: test"},
+		},
+		{
+			name:       "formatting only",
+			opts:       CommitOptions{FormattingOnly: true},
+			wantSystem: []string{"The changes only alter whitespace or formatting", `Use the "style" type`},
+		},
+		{
+			name:     "earlier suggestions",
+			opts:     CommitOptions{Avoid: []string{"feat: add login", "feat: add the login page"}},
+			wantUser: []string{"Earlier suggestions the user rejected", "\n---\nfeat: add login\n---\nfeat: add the login page\n---"},
+		},
+		{
+			name:     "variation",
+			opts:     CommitOptions{Variation: "1a2b3c4d"},
+			wantUser: []string{"Variation: 1a2b3c4d"},
+		},
+		{
+			name:       "dependencies",
+			opts:       CommitOptions{Dependencies: []DependencyBump{{Manifest: "go.mod", Name: "cobra", Old: "v1.7.0", New: "v1.8.0"}}},
+			wantSystem: []string{`Use a "chore(deps):" header`},
+			wantUser:   []string{"Dependency changes:\n- cobra v1.7.0 -> v1.8.0 (go.mod)"},
+		},
+		{
+			name:     "file tree",
+			opts:     CommitOptions{FileTree: "app/\n  app.go\n  new.go (added)"},
+			wantUser: []string{"Layout of the directories containing the changed files", "app/\n  app.go\n  new.go (added)"},
+		},
+		{
+			name:       "test plan",
+			opts:       CommitOptions{TestPlan: true},
+			wantSystem: []string{`one-line "Test-plan:" trailer`, "If no tests changed, say so"},
+		},
+		{
+			name:       "scope",
+			opts:       CommitOptions{Scope: "payments"},
+			wantSystem: []string{`Use "payments" as the scope of the header, e.g. "feat(payments): ..."`},
+		},
+		{
+			name:     "outline",
+			opts:     CommitOptions{Outline: "- add retries"},
+			wantUser: []string{"Outline of the key changes (write the message from this, checking details against the diff):\n- add retries"},
+		},
+		{
+			name:     "summarized files",
+			opts:     CommitOptions{Summarized: []string{"== go.sum (modified, +40 -12)", "== docs/app.md (added, +1 -0)"}},
+			wantUser: []string{"Other changed files (diffs omitted for length; weigh them by their stats):\n== go.sum (modified, +40 -12)\n== docs/app.md (added, +1 -0)"},
+		},
+		{
+			name:       "lower case subject",
+			opts:       CommitOptions{SubjectCase: "lower"},
+			wantSystem: []string{`Start the subject after the type with a lowercase letter`},
+		},
+		{
+			name:       "sentence case subject",
+			opts:       CommitOptions{SubjectCase: "sentence"},
+			wantSystem: []string{`Start the subject after the type with a capital letter`},
+		},
+		{
+			name:       "terse style",
+			opts:       CommitOptions{LanguageStyle: StyleTerse},
+			wantSystem: []string{"As few words as possible", "Omit the body unless the subject cannot carry the intent"},
+		},
+		{
+			name:       "body template for the type",
+			opts:       CommitOptions{BodyTemplates: DefaultBodyTemplates, Type: "fix"},
+			wantSystem: []string{"Structure the body of this fix commit like this:\n" + DefaultBodyTemplates["fix"]},
+		},
+		{
+			name:       "body templates to choose from",
+			opts:       CommitOptions{BodyTemplates: map[string]string{"fix": "Root cause.", "feat": "Motivation."}},
+			wantSystem: []string{"Structure the body according to the type you choose:\n\nfeat:\nMotivation.\n\nfix:\nRoot cause."},
+		},
+		{
+			name:       "max subject length",
+			opts:       CommitOptions{MaxSubjectLength: 50},
+			wantSystem: []string{"Concise summary (max 50 chars)"},
+		},
+		{
+			name:       "scopes",
+			opts:       CommitOptions{Scopes: []string{"cli", "config"}},
+			wantSystem: []string{"If the header has a scope, it must be one of: cli, config."},
+		},
+		{
+			name:       "team instructions",
+			opts:       CommitOptions{Instructions: "Name the affected service in the subject"},
+			wantSystem: []string{"Team guidelines (follow them unless they conflict with the rules above):\nName the affected service in the subject"},
+		},
+		{
+			name:     "failed validation",
+			opts:     CommitOptions{Invalid: "Added the app package", Problems: []string{"header is not a conventional commit (format)"}},
+			wantUser: []string{"Your previous message failed validation:\n---\nAdded the app package\n---\nProblems:\n- header is not a conventional commit (format)"},
+		},
+		{
+			name:     "part outlines",
+			opts:     CommitOptions{PartOutlines: []string{"- adds the parser", "- adds the printer"}},
+			wantUser: []string{"The diff above is only part of the change", "Part 1:\n- adds the parser\n\nPart 2:\n- adds the printer"},
+		},
+		{
+			name:       "style examples",
+			opts:       CommitOptions{StyleExamples: []string{":sparkles: Add search", ":bug: Fix login\n\nThe redirect looped."}},
+			wantSystem: []string{"Take only the style from them, never the content:\n```examples\n:sparkles: Add search\n---\n:bug: Fix login\n\nThe redirect looped.\n```"},
+		},
+		{
+			name:     "ticket added afterwards",
+			opts:     CommitOptions{Ticket: &Ticket{ID: "JIRA-1234", Added: true}},
+			wantUser: []string{"The change is for ticket JIRA-1234. Do not mention the ticket in the message; it is added separately."},
+		},
+		{
+			name:     "ticket title",
+			opts:     CommitOptions{Ticket: &Ticket{ID: "#42", Title: "Dark mode"}},
+			wantUser: []string{`The change is for ticket #42 titled "Dark mode"; use it to understand why the change was made.`},
+		},
+		{
+			name:     "submodules",
+			opts:     CommitOptions{Submodules: []SubmoduleUpdate{{Path: "lib", Old: "1111111", New: "2222222", Log: "2222222 fix: handle nil"}}},
+			wantUser: []string{"Submodule updates", "lib: 1111111 -> 2222222\n2222222 fix: handle nil"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			system, user := CommitMessage("+x := 1\n", "", tt.opts)
+			for _, want := range tt.wantSystem {
+				if !strings.Contains(system, want) {
+					t.Errorf("system prompt does not contain %q", want)
+				}
+			}
+			for _, want := range tt.wantUser {
+				if !strings.Contains(user, want) {
+					t.Errorf("user prompt does not contain %q", want)
+				}
+			}
+
+			// Without the option none of it is asked for
+			plainSystem, plainUser := CommitMessage("+x := 1\n", "", CommitOptions{})
+			for _, want := range tt.wantSystem {
+				if strings.Contains(plainSystem, want) {
+					t.Errorf("system prompt without the option contains %q", want)
+				}
+			}
+			for _, want := range tt.wantUser {
+				if strings.Contains(plainUser, want) {
+					t.Errorf("user prompt without the option contains %q", want)
+				}
+			}
+		})
+	}
+}
+
+func TestLanguageStyles(t *testing.T) {
+	seen := map[string]string{}
+	for _, style := range append([]string{""}, LanguageStyles...) {
+		guidelines, ok := styleGuidelines[style]
+		if !ok || guidelines == "" {
+			t.Errorf("style %q has no guidelines", style)
+			continue
+		}
+		if other, ok := seen[guidelines]; ok {
+			t.Errorf("styles %q and %q share their guidelines", style, other)
+		}
+		seen[guidelines] = style
+
+		system, _ := CommitMessage("+x := 1\n", "", CommitOptions{LanguageStyle: style})
+		if !strings.Contains(system, "Style guidelines:\n"+guidelines+"\n") {
+			t.Errorf("system prompt for style %q lacks its guidelines:\n%s", style, system)
+		}
+	}
+}
+
+func TestSubmoduleSummary(t *testing.T) {
+	const (
+		oldCommit = "1111111111111111111111111111111111111111"
+		newCommit = "2222222222222222222222222222222222222222"
+	)
+	tests := []struct {
+		name string
+		sub  SubmoduleUpdate
+		want string
+	}{
+		{
+			name: "bump with log",
+			sub:  SubmoduleUpdate{Path: "lib", Old: oldCommit, New: newCommit, Log: "2222222 fix: handle nil"},
+			want: "lib: 111111111111 -> 222222222222\n2222222 fix: handle nil",
+		},
+		{
+			name: "not checked out",
+			sub:  SubmoduleUpdate{Path: "lib", Old: oldCommit, New: newCommit},
+			want: "lib: 111111111111 -> 222222222222 (submodule not checked out; included commits unknown)",
+		},
+		{
+			name: "added",
+			sub:  SubmoduleUpdate{Path: "lib", New: newCommit, Log: "2222222 feat: first"},
+			want: "lib: (new) -> 222222222222\n2222222 feat: first",
+		},
+		{
+			name: "removed",
+			sub:  SubmoduleUpdate{Path: "lib", Old: oldCommit},
+			want: "lib: submodule removed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := submoduleSummary(tt.sub); got != tt.want {
+				t.Errorf("submoduleSummary() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScopesGiveWayToOwnershipScope(t *testing.T) {
+	system, _ := CommitMessage("+x := 1\n", "", CommitOptions{Scope: "cli", Scopes: []string{"cli", "config"}})
+	if !strings.Contains(system, `Use "cli" as the scope of the header`) {
+		t.Error("system prompt does not name the ownership scope")
+	}
+	if strings.Contains(system, "it must be one of") {
+		t.Error("system prompt lists the allowed scopes although the scope is fixed")
+	}
+}
+
+func TestCommitFollowUp(t *testing.T) {
+	tests := []struct {
+		name     string
+		feedback string
+		opts     CommitOptions
+		want     []string
+		dontWant []string
+	}{
+		{
+			name:     "without feedback",
+			want:     []string{"Write a different commit message for the same diff."},
+			dontWant: []string{"User feedback"},
+		},
+		{
+			name:     "with feedback",
+			feedback: "mention the scope",
+			want:     []string{"taking all of their feedback in this conversation into account", "User feedback for improvement: mention the scope"},
+		},
+		{
+			name: "avoid earlier suggestions",
+			opts: CommitOptions{Avoid: []string{"feat: add app"}},
+			want: []string{"Earlier suggestions the user rejected", "---\nfeat: add app\n---"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CommitFollowUp(tt.feedback, tt.opts)
+			for _, want := range append(tt.want, "The user rejected your last message.") {
+				if !strings.Contains(got, want) {
+					t.Errorf("follow-up does not contain %q:\n%s", want, got)
+				}
+			}
+			for _, dontWant := range append(tt.dontWant, "```diff") {
+				if strings.Contains(got, dontWant) {
+					t.Errorf("follow-up contains %q:\n%s", dontWant, got)
+				}
+			}
+			if !strings.HasSuffix(got, "Output only the commit message.") {
+				t.Errorf("follow-up does not end with the output instruction:\n%s", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package prompt
+
+import (
+	"strings"
+	"text/template"
+)
+
+// CustomPrompt is a team's own commit prompt, a Go text/template file that
+// defines a "system" template, a "user" template or both. A file defining
+// neither is the system prompt as a whole. Prompts without a template keep
+// the built-in text.
+type CustomPrompt struct {
+	// Template is the parsed template file.
+	Template *template.Template
+
+	// Branch is the current branch, empty on a detached HEAD.
+	Branch string
+
+	// RecentCommits lists the subjects of the latest commits, newest first.
+	RecentCommits []string
+
+	// Repo describes the repository.
+	Repo RepoInfo
+}
+
+// RepoInfo describes the repository for custom prompts.
+type RepoInfo struct {
+	// Name is the base name of the repository's top-level directory.
+	Name string
+	// Root is the path of the top-level directory.
+	Root string
+	// Remote is the URL of the origin remote, empty without one.
+	Remote string
+}
+
+// PromptData is what custom prompt templates are executed with.
+type PromptData struct {
+	// Diff is the staged diff in a fenced block labeled "diff".
+	Diff string
+	// Feedback is the user's feedback on the last suggestion, if any.
+	Feedback      string
+	Branch        string
+	RecentCommits []string
+	Repo          RepoInfo
+	// DefaultSystem and DefaultUser are the built-in prompts, for templates
+	// that add to them rather than replace them.
+	DefaultSystem string
+	DefaultUser   string
+}
+
+// CheckCustom executes opts.Custom for diff, returning the template errors
+// CommitMessage falls back to the built-in prompts on.
+func CheckCustom(diff string, opts CommitOptions) error {
+	custom := opts.Custom
+	opts.Custom = nil
+	system, user := CommitMessage(diff, "", opts)
+	_, _, err := custom.render(diff, "", system, user)
+	return err
+}
+
+// render executes the custom templates, keeping system and user where
+// there is no template for them.
+func (c *CustomPrompt) render(diff, feedback, system, user string) (string, string, error) {
+	data := PromptData{
+		Diff:          fenceDiff(diff),
+		Feedback:      feedback,
+		Branch:        c.Branch,
+		RecentCommits: c.RecentCommits,
+		Repo:          c.Repo,
+		DefaultSystem: system,
+		DefaultUser:   user,
+	}
+	execute := func(t *template.Template, fallback string) (string, error) {
+		if t == nil {
+			return fallback, nil
+		}
+		var b strings.Builder
+		if err := t.Execute(&b, data); err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(b.String()), nil
+	}
+
+	systemTmpl, userTmpl := c.Template.Lookup("system"), c.Template.Lookup("user")
+	if systemTmpl == nil && userTmpl == nil {
+		systemTmpl = c.Template
+	}
+	system, err := execute(systemTmpl, system)
+	if err != nil {
+		return "", "", err
+	}
+	user, err = execute(userTmpl, user)
+	if err != nil {
+		return "", "", err
+	}
+	return system, user, nil
+}
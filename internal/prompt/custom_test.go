@@ -0,0 +1,93 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package prompt
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestCustomPrompt(t *testing.T) {
+	const diff = "+x := 1\n"
+	defaultSystem, defaultUser := CommitMessage(diff, "shorter", CommitOptions{})
+	tests := []struct {
+		name       string
+		text       string
+		wantSystem string
+		wantUser   string
+	}{
+		{
+			name:       "whole file is the system prompt",
+			text:       "Write commits for {{.Repo.Name}} on {{.Branch}}.\n",
+			wantSystem: "Write commits for app on main.",
+			wantUser:   defaultUser,
+		},
+		{
+			name:       "user template only",
+			text:       `{{define "user"}}{{.Diff}}{{"\n"}}Feedback: {{.Feedback}}{{end}}`,
+			wantSystem: defaultSystem,
+			wantUser:   "```diff\n+x := 1\n```\nFeedback: shorter",
+		},
+		{
+			name:       "both templates",
+			text:       `{{define "system"}}Team rules.{{end}}{{define "user"}}{{range .RecentCommits}}- {{.}}{{"\n"}}{{end}}{{end}}`,
+			wantSystem: "Team rules.",
+			wantUser:   "- feat: add app\n- docs: add readme",
+		},
+		{
+			name:       "adding to the default",
+			text:       `{{define "system"}}{{.DefaultSystem}}{{"\n\n"}}Remote: {{.Repo.Remote}}{{end}}`,
+			wantSystem: defaultSystem + "\n\nRemote: git@example.com:team/app.git",
+			wantUser:   defaultUser,
+		},
+		{
+			name:       "failing template keeps the defaults",
+			text:       `{{define "system"}}{{.Nope}}{{end}}`,
+			wantSystem: defaultSystem,
+			wantUser:   defaultUser,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			custom := &CustomPrompt{
+				Template:      template.Must(template.New("prompt.tmpl").Option("missingkey=error").Parse(tt.text)),
+				Branch:        "main",
+				RecentCommits: []string{"feat: add app", "docs: add readme"},
+				Repo:          RepoInfo{Name: "app", Root: "/src/app", Remote: "git@example.com:team/app.git"},
+			}
+			system, user := CommitMessage(diff, "shorter", CommitOptions{Custom: custom})
+			if system != tt.wantSystem {
+				t.Errorf("system = %q, want %q", system, tt.wantSystem)
+			}
+			if user != tt.wantUser {
+				t.Errorf("user = %q, want %q", user, tt.wantUser)
+			}
+		})
+	}
+}
+
+func TestCheckCustom(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		wantErr string
+	}{
+		{name: "valid", text: `{{define "user"}}{{.Diff}}{{end}}`},
+		{name: "unknown field", text: `{{define "user"}}{{.Ticket}}{{end}}`, wantErr: "can't evaluate field Ticket"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			custom := &CustomPrompt{Template: template.Must(template.New("prompt.tmpl").Parse(tt.text))}
+			err := CheckCustom("+x := 1\n", CommitOptions{Custom: custom})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("CheckCustom() = %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("CheckCustom() = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
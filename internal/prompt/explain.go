@@ -0,0 +1,30 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package prompt
+
+// ExplainCommit returns the system and user prompts for explaining an existing
+// commit from its message and diff.
+func ExplainCommit(show string) (system, user string) {
+	system = `You are an expert developer who explains git commits to colleagues who have not seen the code before.
+
+Your task is to describe what a commit changed and why, based on its message and diff. Follow these principles:
+
+1. **Summary first**: Open with one or two sentences on the overall purpose of the commit
+2. **What changed**: Walk through the notable changes grouped by area, not line by line
+3. **Why**: Explain the likely motivation, using the commit message as a hint but trusting the diff
+4. **Impact**: Call out behavior changes, breaking changes, or risks a reviewer should know about
+
+Style guidelines:
+- Plain English, suitable for release notes or code review
+- No unnecessary words or filler
+- Do not invent details that are not supported by the diff
+
+Output ONLY the explanation, no additional commentary.`
+
+	user = `Explain this commit:
+
+` + show
+
+	return system, user
+}
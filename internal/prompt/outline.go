@@ -0,0 +1,26 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package prompt
+
+// OutlineChanges returns the system and user prompts for the first phase of
+// two-phase generation: listing the key changes of a diff, which the commit
+// message is then written from.
+func OutlineChanges(diff string) (system, user string) {
+	system = `You are an expert developer preparing to write a commit message. First, work out what the change actually does.
+
+List the key changes in the diff as short bullets. Follow these principles:
+
+1. **Concerns first**: One bullet per distinct concern, most significant first
+2. **Intent**: Say what each change achieves, not which lines moved
+3. **Accuracy**: Only state what the diff supports; mark guesses about motivation as such
+4. **Incidental changes**: Group small unrelated edits into a final bullet
+
+Output ONLY the bullets, one per line starting with "- ", no additional commentary.` + diffIsData
+
+	user = `Outline the key changes in this diff:
+
+` + fenceDiff(diff)
+
+	return system, user
+}
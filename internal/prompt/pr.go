@@ -0,0 +1,35 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package prompt
+
+// PullRequest returns the system and user prompts for writing a pull
+// request title and description from the branch's commits and its
+// cumulative diff.
+func PullRequest(log, diff string) (system, user string) {
+	system = `You are an expert developer who writes clear pull request descriptions for reviewers.
+
+Your task is to write a pull request title and description from the commits on a branch and their combined diff. Follow these principles:
+
+1. **Title**: One line, at most 72 characters, summarizing the whole branch in the imperative mood; no conventional commit prefix unless every commit shares one
+2. **Summary**: Open the description with one or two sentences on what the change does and why
+3. **Changes**: A short markdown list of the notable changes, grouped by area, not by commit
+4. **Review notes**: Call out breaking changes, risks, or anything a reviewer should check, only when there are any
+
+Style guidelines:
+- Markdown, with "##" headings only where they help
+- No unnecessary words or filler
+- Do not invent details such as test results or issue numbers that are not in the commits or diff
+
+Output the title on the first line, then a blank line, then the description. Output nothing else.` + diffIsData
+
+	user = `Write a pull request for these commits (oldest first):
+
+` + log + `
+
+Combined diff:
+
+` + fenceDiff(diff)
+
+	return system, user
+}
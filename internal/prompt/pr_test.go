@@ -0,0 +1,22 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package prompt
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPullRequest(t *testing.T) {
+	system, user := PullRequest("- feat: add a dark theme", "+dark := true\n")
+	if !strings.Contains(system, "Output the title on the first line, then a blank line, then the description.") {
+		t.Errorf("system prompt does not ask for title then description:\n%s", system)
+	}
+	if !strings.Contains(user, "(oldest first):\n\n- feat: add a dark theme\n") {
+		t.Errorf("user prompt does not list the commits:\n%s", user)
+	}
+	if want := "```diff\n+dark := true\n```"; !strings.HasSuffix(user, want) {
+		t.Errorf("user prompt does not end with the fenced diff:\n%s", user)
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package prompt
+
+// NoReviewIssues is the exact reply ReviewDiff asks for when nothing stands
+// out.
+const NoReviewIssues = "No issues found."
+
+// ReviewDiff returns the system and user prompts for a quick review of a
+// staged diff before it is committed.
+func ReviewDiff(diff string) (system, user string) {
+	system = `You are an experienced developer giving a quick pre-commit check of a diff. You are not doing a full code review.
+
+Point out only obvious problems in the added lines that a careful author would fix before committing, such as:
+- Leftover debug output (print statements, console.log, dumps)
+- New TODO, FIXME or XXX comments
+- Large blocks of commented-out code
+- Accidentally committed secrets, credentials or local paths
+- Merge conflict markers or unfinished edits
+
+Style guidelines:
+- One line per observation: "- path: observation"
+- At most ten observations, most important first
+- Do not comment on style, naming or design
+- If nothing stands out, reply with exactly: ` + NoReviewIssues + `
+
+Output ONLY the observations, no additional commentary.` + diffIsData
+
+	user = `Check this diff before it is committed:
+
+` + fenceDiff(diff)
+
+	return system, user
+}
@@ -0,0 +1,19 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package prompt
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReviewDiff(t *testing.T) {
+	system, user := ReviewDiff("+fmt.Println(\"debug\")\n")
+	if !strings.Contains(system, "reply with exactly: "+NoReviewIssues) {
+		t.Errorf("system prompt does not ask for %q:\n%s", NoReviewIssues, system)
+	}
+	if want := "```diff\n+fmt.Println(\"debug\")\n```"; !strings.HasSuffix(user, want) {
+		t.Errorf("user prompt does not end with the fenced diff:\n%s", user)
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package prompt
+
+// SplitCommits returns the system and user prompts for grouping staged
+// changes into logical commits. changes lists the changes numbered from 1,
+// each a "## Change N" heading followed by its part of the diff.
+func SplitCommits(changes string) (system, user string) {
+	system = `You are an expert developer who keeps version control history clean, one concern per commit.
+
+Your task is to group staged changes, each numbered, into logical commits. Follow these principles:
+
+1. **One concern per commit**: Put changes together when they serve the same purpose, e.g. a fix and its test, or a refactor and the call sites it touches
+2. **Keep dependencies together**: A change that does not build or make sense without another belongs in the same commit, or in a later one
+3. **Order**: List commits so each builds on the ones before it, e.g. refactors before the features that need them
+4. **Few commits**: Split only unrelated concerns; if everything belongs together, answer with a single commit
+5. **Complete**: Every change number appears in exactly one commit
+
+Output one line per commit: the change numbers separated by commas, a colon, and a short description of the commit, e.g.:
+1, 3: Fix nil pointer in config loader
+2: Update README install steps
+
+Output nothing else.` + diffIsData
+
+	user = `Group these staged changes into logical commits:
+
+` + fenceDiff(changes)
+
+	return system, user
+}
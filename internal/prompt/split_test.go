@@ -0,0 +1,19 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package prompt
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplitCommits(t *testing.T) {
+	system, user := SplitCommits("## Change 1\n== app.go (modified, +1 -0)\n+x := 1\n")
+	if !strings.Contains(system, "Every change number appears in exactly one commit") {
+		t.Errorf("system prompt does not ask for every change:\n%s", system)
+	}
+	if want := "```diff\n## Change 1\n== app.go (modified, +1 -0)\n+x := 1\n```"; !strings.HasSuffix(user, want) {
+		t.Errorf("user prompt does not end with the fenced changes:\n%s", user)
+	}
+}
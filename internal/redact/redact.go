@@ -0,0 +1,178 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+// Package redact finds likely secrets, such as cloud keys, private keys
+// and API tokens, in text about to be sent to a model and replaces them
+// with placeholders.
+package redact
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Rule finds one kind of secret. When Pattern has a capture group, only
+// the first group is the secret, e.g. the value of a password= line;
+// otherwise the whole match is.
+type Rule struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// DefaultRules are the built-in secret patterns.
+var DefaultRules = []Rule{
+	{"private-key", regexp.MustCompile(`(?s)-----BEGIN[A-Z ]*PRIVATE KEY(?: BLOCK)?-----.*?-----END[A-Z ]*PRIVATE KEY(?: BLOCK)?-----`)},
+	{"aws-access-key", regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"aws-secret-key", regexp.MustCompile(`(?i)aws.{0,20}?secret.{0,20}?['"=:]\s*['"]?([A-Za-z0-9/+]{40})\b`)},
+	{"github-token", regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+	{"gitlab-token", regexp.MustCompile(`\bglpat-[A-Za-z0-9_\-]{20,}\b`)},
+	{"slack-token", regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9\-]{10,}`)},
+	{"stripe-key", regexp.MustCompile(`\b[rs]k_live_[A-Za-z0-9]{20,}\b`)},
+	{"google-api-key", regexp.MustCompile(`\bAIza[0-9A-Za-z_\-]{35}\b`)},
+	{"api-key", regexp.MustCompile(`\bsk-(?:ant-|proj-)?[A-Za-z0-9_\-]{20,}`)},
+	{"jwt", regexp.MustCompile(`\beyJ[A-Za-z0-9_\-]{10,}\.[A-Za-z0-9_\-]{10,}\.[A-Za-z0-9_\-]{10,}`)},
+	{"url-password", regexp.MustCompile(`\b[a-z][a-z0-9+.\-]*://[^/\s:@]+:([^/\s:@]{3,})@`)},
+	{"password", regexp.MustCompile(`(?i)(?:password|passwd|pwd|secret|token|api_?key|access_?key|client_?secret)["']?\s*[:=]\s*["']([^"'\s$<{][^"'\s]{7,})["']`)},
+}
+
+// entropyToken matches the strings checked for randomness: long runs of
+// base64 and URL-safe characters.
+var entropyToken = regexp.MustCompile(`[A-Za-z0-9+/=_\-]{32,}`)
+
+// minEntropy is the Shannon entropy, in bits per character, above which a
+// token mixing digits and upper and lower case letters looks random rather
+// than like a long name. Hex strings such as commit hashes stay below it.
+const minEntropy = 4.5
+
+// entropyRule names secrets found by their randomness alone.
+const entropyRule = "high-entropy-string"
+
+// Finding is a secret that was redacted.
+type Finding struct {
+	// Rule names the rule that found it.
+	Rule string
+	// Path is the file of the diff it was in, if known.
+	Path string
+	// Secret is the redacted text.
+	Secret string
+}
+
+// Redactor replaces secrets in text.
+type Redactor struct {
+	rules []Rule
+	allow []*regexp.Regexp
+}
+
+// New returns a Redactor using the default rules plus the named extra
+// patterns. Secrets matching any allow pattern are left alone, for known
+// false positives such as test fixtures.
+func New(extra map[string]string, allow []string) (*Redactor, error) {
+	r := &Redactor{rules: DefaultRules}
+	names := make([]string, 0, len(extra))
+	for name := range extra {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		re, err := regexp.Compile(extra[name])
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %w", name, err)
+		}
+		r.rules = append(r.rules, Rule{Name: name, Pattern: re})
+	}
+	for _, pattern := range allow {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("allow pattern %q: %w", pattern, err)
+		}
+		r.allow = append(r.allow, re)
+	}
+	return r, nil
+}
+
+// Redact returns text with every secret replaced by a placeholder naming
+// its rule, such as [REDACTED aws-access-key], and the secrets found.
+// Secrets in a diff are reported with the path of their file.
+func (r *Redactor) Redact(text string) (string, []Finding) {
+	var findings []Finding
+	for _, rule := range r.rules {
+		text = r.replace(text, rule.Name, rule.Pattern, nil, &findings)
+	}
+	return r.replace(text, entropyRule, entropyToken, looksRandom, &findings), findings
+}
+
+// replace redacts the secrets pattern finds in text, skipping those that
+// fail keep, and adds them to findings.
+func (r *Redactor) replace(text, rule string, pattern *regexp.Regexp, keep func(string) bool, findings *[]Finding) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range pattern.FindAllStringSubmatchIndex(text, -1) {
+		start, end := m[0], m[1]
+		if len(m) > 2 && m[2] >= 0 {
+			start, end = m[2], m[3]
+		}
+		secret := text[start:end]
+		if (keep != nil && !keep(secret)) || r.allowed(secret) {
+			continue
+		}
+		*findings = append(*findings, Finding{Rule: rule, Path: pathAt(text, start), Secret: secret})
+		b.WriteString(text[last:start])
+		b.WriteString("[REDACTED " + rule + "]")
+		last = end
+	}
+	if last == 0 {
+		return text
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// allowed reports whether secret matches an allow pattern.
+func (r *Redactor) allowed(secret string) bool {
+	for _, re := range r.allow {
+		if re.MatchString(secret) {
+			return true
+		}
+	}
+	return false
+}
+
+// fileHeader matches the line naming the new version of a file in a diff.
+var fileHeader = regexp.MustCompile(`(?m)^\+\+\+ b/(.+)$`)
+
+// pathAt returns the path of the diff file containing offset i of text, or
+// "" outside a diff.
+func pathAt(text string, i int) string {
+	path := ""
+	for _, m := range fileHeader.FindAllStringSubmatchIndex(text[:i], -1) {
+		path = text[m[2]:m[3]]
+	}
+	return path
+}
+
+// looksRandom reports whether token mixes digits and both letter cases
+// with high entropy, as keys and tokens do.
+func looksRandom(token string) bool {
+	if !strings.ContainsAny(token, "0123456789") ||
+		strings.ToLower(token) == token || strings.ToUpper(token) == token {
+		return false
+	}
+	return entropy(token) >= minEntropy
+}
+
+// entropy returns the Shannon entropy of s in bits per character.
+func entropy(s string) float64 {
+	counts := map[rune]int{}
+	for _, c := range s {
+		counts[c]++
+	}
+	var bits float64
+	for _, n := range counts {
+		p := float64(n) / float64(len(s))
+		bits -= p * math.Log2(p)
+	}
+	return bits
+}
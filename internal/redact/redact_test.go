@@ -0,0 +1,159 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package redact
+
+import (
+	"strings"
+	"testing"
+)
+
+// Secrets are assembled at run time so the file itself does not trip
+// secret scanners.
+var (
+	githubToken = "ghp_" + strings.Repeat("a1B2", 9)
+	randomToken = "Zx9Qa7Lm2Pw4Rt6Yu8Io1As3Df5Gh7Jk9Lz0XcVb"
+	privateKey  = "-----BEGIN RSA " + "PRIVATE KEY-----\nMIIEow\n-----END RSA " + "PRIVATE KEY-----"
+)
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		want     string
+		wantRule string
+	}{
+		{
+			name:     "aws access key",
+			text:     "key := \"AKIA" + "IOSFODNN7EXAMPLE\"",
+			want:     "key := \"[REDACTED aws-access-key]\"",
+			wantRule: "aws-access-key",
+		},
+		{
+			name:     "github token",
+			text:     "token: " + githubToken,
+			want:     "token: [REDACTED github-token]",
+			wantRule: "github-token",
+		},
+		{
+			name:     "private key",
+			text:     "const key = `" + privateKey + "`",
+			want:     "const key = `[REDACTED private-key]`",
+			wantRule: "private-key",
+		},
+		{
+			name:     "password value only",
+			text:     `password = "hunter2hunter2"`,
+			want:     `password = "[REDACTED password]"`,
+			wantRule: "password",
+		},
+		{
+			name:     "password in a URL",
+			text:     "dsn: postgres://app:s3cretpw@db:5432/app",
+			want:     "dsn: postgres://app:[REDACTED url-password]@db:5432/app",
+			wantRule: "url-password",
+		},
+		{
+			name:     "random string",
+			text:     "seed = " + randomToken,
+			want:     "seed = [REDACTED high-entropy-string]",
+			wantRule: entropyRule,
+		},
+		{
+			name: "commit hash",
+			text: "fixes 3f786850e387550fdab836ed7e6dc881de23001b",
+			want: "fixes 3f786850e387550fdab836ed7e6dc881de23001b",
+		},
+		{
+			name: "long name",
+			text: "func TestRedactorLeavesOrdinaryCodeAloneEvenWhenLong() {}",
+			want: "func TestRedactorLeavesOrdinaryCodeAloneEvenWhenLong() {}",
+		},
+		{
+			name: "password from a variable",
+			text: `password = "$DB_PASSWORD"`,
+			want: `password = "$DB_PASSWORD"`,
+		},
+	}
+	r, err := New(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, findings := r.Redact(tt.text)
+			if got != tt.want {
+				t.Errorf("Redact() = %q, want %q", got, tt.want)
+			}
+			switch {
+			case tt.wantRule == "" && len(findings) > 0:
+				t.Errorf("findings = %+v, want none", findings)
+			case tt.wantRule != "" && (len(findings) != 1 || findings[0].Rule != tt.wantRule):
+				t.Errorf("findings = %+v, want one for %s", findings, tt.wantRule)
+			}
+		})
+	}
+}
+
+func TestRedactReportsDiffPaths(t *testing.T) {
+	r, err := New(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff := "+++ b/README.md\n+Setup notes\n+++ b/config/prod.env\n+GITHUB_TOKEN=" + githubToken + "\n"
+	_, findings := r.Redact(diff)
+	if len(findings) != 1 || findings[0].Path != "config/prod.env" || findings[0].Secret != githubToken {
+		t.Errorf("findings = %+v, want the token in config/prod.env", findings)
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		extra   map[string]string
+		allow   []string
+		text    string
+		want    string
+		wantErr string
+	}{
+		{
+			name:  "extra rule",
+			extra: map[string]string{"internal-token": `itk_[a-z0-9]{12}`},
+			text:  "auth itk_abc123def456",
+			want:  "auth [REDACTED internal-token]",
+		},
+		{
+			name:  "allowed value",
+			allow: []string{`^Zx9Qa7`},
+			text:  "seed = " + randomToken,
+			want:  "seed = " + randomToken,
+		},
+		{
+			name:    "invalid rule",
+			extra:   map[string]string{"broken": `itk_[`},
+			wantErr: "rule broken",
+		},
+		{
+			name:    "invalid allow pattern",
+			allow:   []string{`(`},
+			wantErr: `allow pattern "("`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := New(tt.extra, tt.allow)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, _ := r.Redact(tt.text); got != tt.want {
+				t.Errorf("Redact() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}